@@ -6,8 +6,11 @@ package otellambda // import "go.opentelemetry.io/contrib/instrumentation/github
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda/internal/redact"
 )
 
 // A Flusher dictates how the instrumentation will attempt to flush
@@ -60,6 +63,12 @@ type config struct {
 	// returned by otel.GetTracerProvider()
 	TracerProvider trace.TracerProvider
 
+	// MeterProvider is the MeterProvider which will be used
+	// to create the instruments of the invocation metrics
+	// The default value of MeterProvider the global otel MeterProvider
+	// returned by otel.GetMeterProvider()
+	MeterProvider metric.MeterProvider
+
 	// Flusher is the mechanism used to flush any unexported spans
 	// each Lambda Invocation to avoid spans being unexported for long
 	// when periods of time if Lambda freezes the execution environment
@@ -80,6 +89,20 @@ type config struct {
 	// The default value of Propagator the global otel Propagator
 	// returned by otel.GetTextMapPropagator()
 	Propagator propagation.TextMapPropagator
+
+	// EventCapture enables the recording of invocation events
+	// The default value of EventCapture is false
+	EventCapture bool
+
+	// EventSizeLimit is the maximum number of bytes of the recorded
+	// invocation events
+	// The default value of EventSizeLimit is DefaultEventSizeLimit
+	EventSizeLimit int
+
+	// EventScrubPaths are the JSON paths of the values redacted in the
+	// recorded invocation events of each EventSource
+	// The default value of EventScrubPaths is DefaultEventScrubJSONPaths
+	EventScrubPaths map[EventSource][]redact.JSONPath
 }
 
 // WithTracerProvider configures the TracerProvider used by the
@@ -92,6 +115,19 @@ func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
 	})
 }
 
+// WithMeterProvider configures the MeterProvider used by the
+// instrumentation to record the faas.invoke_duration, faas.invocations,
+// faas.errors, faas.coldstarts and faas.active_invocations metrics.
+//
+// By default, the global MeterProvider is used. The Flusher should flush
+// the MeterProvider too, for the metrics to be exported before Lambda
+// freezes the execution environment.
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return optionFunc(func(c *config) {
+		c.MeterProvider = meterProvider
+	})
+}
+
 // WithFlusher sets the used flusher.
 func WithFlusher(flusher Flusher) Option {
 	return optionFunc(func(c *config) {
@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+
+import (
+	"encoding/json"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda/internal/redact"
+)
+
+// Attribute keys of the span event recording the invocation event, see
+// WithEventCapture.
+const (
+	// EventKey is the name of the span event, and the key of the attribute
+	// holding the JSON of the invocation event.
+	EventKey = attribute.Key("aws.lambda.event")
+	// EventSourceKey is the key of the attribute holding the EventSource of
+	// the invocation event, if it is known.
+	EventSourceKey = attribute.Key("aws.lambda.event.source")
+	// EventTruncatedKey is the key of the attribute set if the recorded
+	// invocation event was truncated, see WithEventSizeLimit.
+	EventTruncatedKey = attribute.Key("aws.lambda.event.truncated")
+)
+
+// DefaultEventSizeLimit is the default maximum number of bytes of an
+// invocation event recorded when event capture is enabled, see
+// WithEventCapture.
+const DefaultEventSizeLimit = 4096
+
+// EventSource is the AWS service whose event invoked a function.
+type EventSource string
+
+// Event sources detected by the instrumentation.
+const (
+	// EventSourceAny selects the events of any source, see
+	// WithEventScrubJSONPaths.
+	EventSourceAny EventSource = ""
+	// EventSourceAPIGateway is an Amazon API Gateway REST or HTTP API
+	// request.
+	EventSourceAPIGateway EventSource = "aws:apigateway"
+	// EventSourceSQS is a batch of Amazon SQS messages.
+	EventSourceSQS EventSource = "aws:sqs"
+	// EventSourceSNS is an Amazon SNS notification.
+	EventSourceSNS EventSource = "aws:sns"
+	// EventSourceS3 is an Amazon S3 event notification.
+	EventSourceS3 EventSource = "aws:s3"
+)
+
+// DefaultEventScrubJSONPaths are the JSON paths of the values of the
+// invocation events of each source redacted by default when event capture
+// is enabled, the credentials of API Gateway requests.
+var DefaultEventScrubJSONPaths = map[EventSource][]string{
+	EventSourceAPIGateway: {
+		"$.headers.Authorization",
+		"$.headers.authorization",
+		"$.headers.Cookie",
+		"$.headers.cookie",
+		"$.multiValueHeaders.Authorization",
+		"$.multiValueHeaders.authorization",
+		"$.multiValueHeaders.Cookie",
+		"$.multiValueHeaders.cookie",
+		"$.cookies",
+	},
+}
+
+// eventSource returns the source of the invocation event eventJSON, or
+// EventSourceAny if it is unknown.
+func eventSource(eventJSON []byte) EventSource {
+	var event struct {
+		// Records of SQS, SNS and S3 events, whose eventSource
+		// member is capitalized for SNS.
+		Records []struct {
+			EventSource EventSource `json:"eventSource"`
+		} `json:"Records"`
+		RequestContext *struct {
+			APIID string `json:"apiId"`
+		} `json:"requestContext"`
+	}
+	if err := json.Unmarshal(eventJSON, &event); err != nil {
+		return EventSourceAny
+	}
+
+	switch {
+	case len(event.Records) > 0:
+		switch s := event.Records[0].EventSource; s {
+		case EventSourceSQS, EventSourceSNS, EventSourceS3:
+			return s
+		}
+	case event.RequestContext != nil && event.RequestContext.APIID != "":
+		return EventSourceAPIGateway
+	}
+	return EventSourceAny
+}
+
+// recordEvent adds the span event recording the invocation event eventJSON,
+// redacted by the rules of its source, to span.
+func (i *instrumentor) recordEvent(span trace.Span, eventJSON []byte) {
+	if len(eventJSON) == 0 {
+		return
+	}
+
+	source := eventSource(eventJSON)
+	var paths []redact.JSONPath
+	paths = append(paths, i.configuration.EventScrubPaths[EventSourceAny]...)
+	if source != EventSourceAny {
+		paths = append(paths, i.configuration.EventScrubPaths[source]...)
+	}
+	data := redact.JSON(string(eventJSON), paths)
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	if source != EventSourceAny {
+		attrs = append(attrs, EventSourceKey.String(string(source)))
+	}
+	if limit := i.configuration.EventSizeLimit; limit > 0 && len(data) > limit {
+		data = redact.TrimPartialRune(data[:limit])
+		attrs = append(attrs, EventTruncatedKey.Bool(true))
+	}
+	attrs = append(attrs, EventKey.String(data))
+	span.AddEvent(string(EventKey), trace.WithAttributes(attrs...))
+}
+
+// parseEventScrubJSONPaths adds the parsed paths to the rules of source in
+// c. Invalid paths are reported to the global error handler and ignored.
+func (c *config) parseEventScrubJSONPaths(source EventSource, paths []string) {
+	for _, p := range paths {
+		path, err := redact.ParseJSONPath(p)
+		if err != nil {
+			otel.Handle(err)
+			continue
+		}
+		if c.EventScrubPaths == nil {
+			c.EventScrubPaths = map[EventSource][]redact.JSONPath{}
+		}
+		c.EventScrubPaths[source] = append(c.EventScrubPaths[source], path)
+	}
+}
+
+// WithEventCapture configures the instrumentation to record the event
+// invoking the function, e.g. an API Gateway request, SQS messages or an S3
+// event notification, as a span event named after the aws.lambda.event
+// attribute holding its JSON. The aws.lambda.event.source attribute of the
+// span event is the EventSource of the invocation event, if it is known.
+// Events exceeding the limit set by WithEventSizeLimit are truncated and the
+// span event is annotated with the aws.lambda.event.truncated attribute.
+//
+// Events may contain sensitive data, event capture is disabled by default.
+// The values selected by DefaultEventScrubJSONPaths and
+// WithEventScrubJSONPaths are redacted.
+func WithEventCapture() Option {
+	return optionFunc(func(c *config) {
+		c.EventCapture = true
+	})
+}
+
+// WithEventSizeLimit sets the maximum number of bytes of an invocation event
+// recorded by WithEventCapture. If n is less than or equal to zero, events
+// are recorded in full. The default is DefaultEventSizeLimit.
+func WithEventSizeLimit(n int) Option {
+	return optionFunc(func(c *config) {
+		c.EventSizeLimit = n
+	})
+}
+
+// WithEventScrubJSONPaths configures the instrumentation to replace the
+// values selected by paths in the invocation events of source recorded by
+// WithEventCapture with "REDACTED", in addition to DefaultEventScrubJSONPaths,
+// e.g. "$.Records[*].body" to redact the bodies of SQS messages. The paths
+// of EventSourceAny apply to the events of any source.
+//
+// Paths have the format of the WithPayloadScrubJSONPaths option of otelgrpc:
+// they start with "$" followed by member names (".body" or "['body']"),
+// array indices ("[0]") and wildcards (".*" or "[*]"). Invalid paths are
+// reported to the global error handler and ignored.
+//
+// Redacted events are re-encoded, which sorts object members by name.
+// Passing this option multiple times adds all the paths.
+func WithEventScrubJSONPaths(source EventSource, paths ...string) Option {
+	return optionFunc(func(c *config) {
+		c.parseEventScrubJSONPaths(source, paths)
+	})
+}
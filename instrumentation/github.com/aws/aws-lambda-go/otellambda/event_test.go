@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		event string
+		want  EventSource
+	}{
+		{name: "APIGateway", event: `{"httpMethod":"GET","requestContext":{"apiId":"id"}}`, want: EventSourceAPIGateway},
+		{name: "SQS", event: `{"Records":[{"eventSource":"aws:sqs","body":"b"}]}`, want: EventSourceSQS},
+		{name: "SNS", event: `{"Records":[{"EventSource":"aws:sns","Sns":{}}]}`, want: EventSourceSNS},
+		{name: "S3", event: `{"Records":[{"eventSource":"aws:s3","s3":{}}]}`, want: EventSourceS3},
+		{name: "OtherRecords", event: `{"Records":[{"eventSource":"aws:kinesis"}]}`, want: EventSourceAny},
+		{name: "Custom", event: `{"name":"value"}`, want: EventSourceAny},
+		{name: "NotObject", event: `"value"`, want: EventSourceAny},
+		{name: "Invalid", event: `{`, want: EventSourceAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, eventSource([]byte(tt.event)))
+		})
+	}
+}
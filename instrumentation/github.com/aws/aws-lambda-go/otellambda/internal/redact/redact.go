@@ -0,0 +1,204 @@
+// Code created by gotmpl. DO NOT MODIFY.
+// source: internal/shared/redact/redact.go.tmpl
+
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redact redacts the values of recorded payloads and bodies selected
+// by JSON paths or form field names.
+package redact // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda/internal/redact"
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redacted replaces the redacted values.
+const Redacted = "REDACTED"
+
+// pathSegment is a segment of a JSONPath. It selects the object member
+// name, the array element index, or every member or element if wildcard is
+// set.
+type pathSegment struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// JSONPath is a parsed JSONPath expression of the supported subset: "$"
+// followed by member names (".credentials" or "['credentials']"), array
+// indices ("[0]") and wildcards (".*" or "[*]").
+type JSONPath []pathSegment
+
+// ParseJSONPath parses a JSONPath expression such as "$.credentials.*" or
+// "$.items[*].token".
+func ParseJSONPath(p string) (JSONPath, error) {
+	rest, ok := strings.CutPrefix(p, "$")
+	if !ok {
+		return nil, fmt.Errorf("JSON path %q does not start with $", p)
+	}
+
+	var path JSONPath
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			rest = rest[end:]
+			if name == "" {
+				return nil, fmt.Errorf("JSON path %q has an empty member name", p)
+			}
+			path = append(path, pathSegment{name: name, wildcard: name == "*"})
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("JSON path %q has an unterminated bracket", p)
+			}
+			sel := rest[1:end]
+			rest = rest[end+1:]
+			switch {
+			case sel == "*":
+				path = append(path, pathSegment{wildcard: true})
+			case len(sel) >= 2 && (sel[0] == '\'' || sel[0] == '"') && sel[len(sel)-1] == sel[0]:
+				path = append(path, pathSegment{name: sel[1 : len(sel)-1]})
+			default:
+				i, err := strconv.Atoi(sel)
+				if err != nil || i < 0 {
+					return nil, fmt.Errorf("JSON path %q has an invalid index %q", p, sel)
+				}
+				path = append(path, pathSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("JSON path %q has an unexpected character %q", p, rest[0])
+		}
+	}
+	if len(path) == 0 {
+		return nil, fmt.Errorf("JSON path %q selects the whole payload", p)
+	}
+	return path, nil
+}
+
+// redact replaces the values selected by p in v with Redacted and reports
+// whether any value was replaced.
+func (p JSONPath) redact(v any) bool {
+	seg, rest := p[0], p[1:]
+	var redacted bool
+	visit := func(child any, replace func(any)) {
+		if len(rest) == 0 {
+			replace(Redacted)
+			redacted = true
+			return
+		}
+		if rest.redact(child) {
+			redacted = true
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		if seg.isIndex {
+			return false
+		}
+		for k, child := range v {
+			if seg.wildcard || k == seg.name {
+				k := k
+				visit(child, func(r any) { v[k] = r })
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if seg.wildcard || (seg.isIndex && i == seg.index) {
+				i := i
+				visit(child, func(r any) { v[i] = r })
+			}
+		}
+	}
+	return redacted
+}
+
+// JSON replaces the values selected by paths in the JSON document data with
+// Redacted. Redacted documents are re-encoded, which sorts object members by
+// name, and indented if data spans several lines. If data is not valid JSON,
+// Redacted is returned instead of data.
+func JSON(data string, paths []JSONPath) string {
+	if len(paths) == 0 {
+		return data
+	}
+
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return Redacted
+	}
+
+	var redacted bool
+	for _, p := range paths {
+		if p.redact(v) {
+			redacted = true
+		}
+	}
+	if !redacted {
+		return data
+	}
+
+	var b []byte
+	var err error
+	if strings.Contains(data, "\n") {
+		b, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		b, err = json.Marshal(v)
+	}
+	if err != nil {
+		return Redacted
+	}
+	return string(b)
+}
+
+// Form replaces the values of the fields of the URL-encoded form data whose
+// name is one of names, ignoring case, with Redacted. The other fields are
+// kept as they are.
+func Form(data string, names []string) string {
+	if len(names) == 0 {
+		return data
+	}
+
+	fields := strings.Split(data, "&")
+	for i, field := range fields {
+		key, _, _ := strings.Cut(field, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		for _, n := range names {
+			if strings.EqualFold(n, name) {
+				fields[i] = key + "=" + Redacted
+				break
+			}
+		}
+	}
+	return strings.Join(fields, "&")
+}
+
+// TrimPartialRune removes a UTF-8 encoded rune split by truncation from the
+// end of s.
+func TrimPartialRune(s string) string {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if !utf8.FullRuneInString(s[i:]) {
+				return s[:i]
+			}
+			break
+		}
+	}
+	return s
+}
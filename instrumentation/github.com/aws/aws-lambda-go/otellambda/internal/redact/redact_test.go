@@ -0,0 +1,163 @@
+// Code created by gotmpl. DO NOT MODIFY.
+// source: internal/shared/redact/redact_test.go.tmpl
+
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want JSONPath
+		err  bool
+	}{
+		{path: "$.credentials", want: JSONPath{{name: "credentials"}}},
+		{path: "$.credentials.*", want: JSONPath{{name: "credentials"}, {name: "*", wildcard: true}}},
+		{path: "$.items[*].token", want: JSONPath{{name: "items"}, {wildcard: true}, {name: "token"}}},
+		{path: "$['a.b'][2]", want: JSONPath{{name: "a.b"}, {index: 2, isIndex: true}}},
+		{path: "credentials", err: true},
+		{path: "$", err: true},
+		{path: "$.", err: true},
+		{path: "$[x]", err: true},
+		{path: "$[0", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseJSONPath(tt.path)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSON(t *testing.T) {
+	const doc = `{"user":"alice","credentials":{"token":"t0k3n","key":"k"},"items":[{"token":"a","id":1},{"token":"b","id":2}]}`
+
+	tests := []struct {
+		name  string
+		paths []string
+		data  string
+		want  string
+	}{
+		{
+			name:  "Member",
+			paths: []string{"$.user"},
+			data:  `{"user":"alice","id":1}`,
+			want:  `{"id":1,"user":"REDACTED"}`,
+		},
+		{
+			name:  "Wildcard",
+			paths: []string{"$.credentials.*", "$.items[*].token"},
+			data:  doc,
+			want:  `{"credentials":{"key":"REDACTED","token":"REDACTED"},"items":[{"id":1,"token":"REDACTED"},{"id":2,"token":"REDACTED"}],"user":"alice"}`,
+		},
+		{
+			name:  "Index",
+			paths: []string{"$.items[1]"},
+			data:  `{"items":[1,2,3]}`,
+			want:  `{"items":[1,"REDACTED",3]}`,
+		},
+		{
+			name:  "NoMatch",
+			paths: []string{"$.password"},
+			data:  "{\n  \"user\": \"alice\"\n}",
+			want:  "{\n  \"user\": \"alice\"\n}",
+		},
+		{
+			name:  "Indented",
+			paths: []string{"$.user"},
+			data:  "{\n  \"user\": \"alice\"\n}",
+			want:  "{\n  \"user\": \"REDACTED\"\n}",
+		},
+		{
+			name:  "InvalidJSON",
+			paths: []string{"$.user"},
+			data:  `{"user":"ali`,
+			want:  "REDACTED",
+		},
+		{
+			name: "NoPaths",
+			data: "not JSON",
+			want: "not JSON",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []JSONPath
+			for _, p := range tt.paths {
+				path, err := ParseJSONPath(p)
+				require.NoError(t, err)
+				paths = append(paths, path)
+			}
+			assert.Equal(t, tt.want, JSON(tt.data, paths))
+		})
+	}
+}
+
+func TestForm(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		data  string
+		want  string
+	}{
+		{
+			name:  "Field",
+			names: []string{"password"},
+			data:  "user=alice&password=s3cr3t",
+			want:  "user=alice&password=REDACTED",
+		},
+		{
+			name:  "IgnoreCase",
+			names: []string{"password"},
+			data:  "Password=s3cr3t&user=alice",
+			want:  "Password=REDACTED&user=alice",
+		},
+		{
+			name:  "Repeated",
+			names: []string{"token"},
+			data:  "token=a&token=b&id=1",
+			want:  "token=REDACTED&token=REDACTED&id=1",
+		},
+		{
+			name:  "Escaped",
+			names: []string{"api key"},
+			data:  "api+key=k&api%20key=k",
+			want:  "api+key=REDACTED&api%20key=REDACTED",
+		},
+		{
+			name:  "NoValue",
+			names: []string{"password"},
+			data:  "password&user=alice",
+			want:  "password=REDACTED&user=alice",
+		},
+		{
+			name: "NoNames",
+			data: "password=s3cr3t",
+			want: "password=s3cr3t",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Form(tt.data, tt.names))
+		})
+	}
+}
+
+func TestTrimPartialRune(t *testing.T) {
+	assert.Equal(t, "ab", TrimPartialRune("ab\xe2\x82"))
+	assert.Equal(t, "ab€", TrimPartialRune("ab€"))
+	assert.Equal(t, "", TrimPartialRune("\xe2"))
+}
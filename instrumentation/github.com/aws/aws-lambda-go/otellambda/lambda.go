@@ -8,11 +8,14 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -24,18 +27,30 @@ const (
 
 var errorLogger = log.New(log.Writer(), "OTel Lambda Error: ", 0)
 
+type invocationStartKey struct{}
+
 type instrumentor struct {
 	configuration config
 	resAttrs      []attribute.KeyValue
 	tracer        trace.Tracer
+	metrics       *invocationMetrics
+
+	// invoked is set once the first invocation, the cold start of the
+	// execution environment, begins.
+	invoked *atomic.Bool
 }
 
 func newInstrumentor(opts ...Option) instrumentor {
 	cfg := config{
 		TracerProvider: otel.GetTracerProvider(),
+		MeterProvider:  otel.GetMeterProvider(),
 		Flusher:        &noopFlusher{},
 		EventToCarrier: emptyEventToCarrier,
 		Propagator:     otel.GetTextMapPropagator(),
+		EventSizeLimit: DefaultEventSizeLimit,
+	}
+	for source, paths := range DefaultEventScrubJSONPaths {
+		cfg.parseEventScrubJSONPaths(source, paths)
 	}
 	for _, opt := range opts {
 		opt.apply(&cfg)
@@ -44,12 +59,18 @@ func newInstrumentor(opts ...Option) instrumentor {
 	return instrumentor{
 		configuration: cfg,
 		tracer:        cfg.TracerProvider.Tracer(ScopeName, trace.WithInstrumentationVersion(Version())),
-		resAttrs:      []attribute.KeyValue{},
+		metrics: newInvocationMetrics(cfg.MeterProvider.Meter(ScopeName,
+			metric.WithInstrumentationVersion(Version()))),
+		resAttrs: []attribute.KeyValue{},
+		invoked:  &atomic.Bool{},
 	}
 }
 
 // Logic to start OTel Tracing.
 func (i *instrumentor) tracingBegin(ctx context.Context, eventJSON []byte) (context.Context, trace.Span) {
+	ctx = context.WithValue(ctx, invocationStartKey{}, time.Now())
+	i.metrics.active.Add(ctx, 1)
+
 	// Add trace id to context
 	mc := i.configuration.EventToCarrier(eventJSON)
 	ctx = i.configuration.Propagator.Extract(ctx, mc)
@@ -81,18 +102,40 @@ func (i *instrumentor) tracingBegin(ctx context.Context, eventJSON []byte) (cont
 		attributes = append(attributes, i.resAttrs...)
 	}
 
+	if !i.invoked.Swap(true) {
+		attributes = append(attributes, semconv.FaaSColdstart(true))
+		i.metrics.coldstarts.Add(ctx, 1)
+	}
+
 	ctx, span = i.tracer.Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(attributes...))
+	if i.configuration.EventCapture {
+		i.recordEvent(span, eventJSON)
+	}
 
 	return ctx, span
 }
 
-// Logic to wrap up OTel Tracing.
-func (i *instrumentor) tracingEnd(ctx context.Context, span trace.Span) {
+// Logic to wrap up OTel Tracing. handlerErr is the error returned by the
+// handler, if any.
+func (i *instrumentor) tracingEnd(ctx context.Context, span trace.Span, handlerErr error) {
 	span.End()
 
+	if start, ok := ctx.Value(invocationStartKey{}).(time.Time); ok {
+		i.metrics.duration.Record(ctx, time.Since(start).Seconds())
+	}
+	i.metrics.invocations.Add(ctx, 1)
+	if handlerErr != nil {
+		i.metrics.errors.Add(ctx, 1)
+	}
+
 	// force flush any tracing data since lambda may freeze
 	err := i.configuration.Flusher.ForceFlush(ctx)
 	if err != nil {
 		errorLogger.Println("failed to force a flush, lambda may freeze before instrumentation exported: ", err)
 	}
+
+	// The invocation is still active when flushed, so each execution
+	// environment reports it until its next export, and the sum over the
+	// environments estimates the concurrent executions.
+	i.metrics.active.Add(ctx, -1)
 }
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// invocationMetrics are the instruments of the metrics of the invocations of
+// the instrumented handler, as defined by the semantic conventions of FaaS.
+type invocationMetrics struct {
+	duration    metric.Float64Histogram
+	invocations metric.Int64Counter
+	errors      metric.Int64Counter
+	coldstarts  metric.Int64Counter
+	active      metric.Int64UpDownCounter
+}
+
+func newInvocationMetrics(meter metric.Meter) *invocationMetrics {
+	m := &invocationMetrics{}
+	var err error
+	m.duration, err = meter.Float64Histogram(
+		"faas.invoke_duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Measures the duration of the function's logic execution."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.duration = noop.Float64Histogram{}
+	}
+
+	m.invocations, err = meter.Int64Counter(
+		"faas.invocations",
+		metric.WithUnit("{invocation}"),
+		metric.WithDescription("Number of invocations."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.invocations = noop.Int64Counter{}
+	}
+
+	m.errors, err = meter.Int64Counter(
+		"faas.errors",
+		metric.WithUnit("{error}"),
+		metric.WithDescription("Number of invocation errors."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.errors = noop.Int64Counter{}
+	}
+
+	m.coldstarts, err = meter.Int64Counter(
+		"faas.coldstarts",
+		metric.WithUnit("{coldstart}"),
+		metric.WithDescription("Number of invocation cold starts."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.coldstarts = noop.Int64Counter{}
+	}
+
+	m.active, err = meter.Int64UpDownCounter(
+		"faas.active_invocations",
+		metric.WithUnit("{invocation}"),
+		metric.WithDescription("Number of invocations in progress, whose sum over the execution environments estimates the concurrent executions."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.active = noop.Int64UpDownCounter{}
+	}
+	return m
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otellambda // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys of the span of an invocation streaming its response.
+const (
+	// StreamBytesKey is the number of bytes of the response streamed by
+	// the invocation.
+	StreamBytesKey = attribute.Key("aws.lambda.stream.bytes")
+	// StreamTimeToFirstByteKey is the time in seconds from the start of
+	// the invocation to the first byte of the response streamed.
+	StreamTimeToFirstByteKey = attribute.Key("aws.lambda.stream.time_to_first_byte")
+)
+
+// streamResponse returns the io.Reader returned by a handler, whose return
+// values are response, if the Lambda runtime streams it, that is if the
+// handler did not fail and the reader is not serialized to a non-empty JSON
+// object.
+func streamResponse(response []reflect.Value) (io.Reader, bool) {
+	if len(response) != 2 || responseError(response) != nil {
+		return nil, false
+	}
+	val := response[0].Interface()
+	r, ok := val.(io.Reader)
+	if !ok || !eventExists(val) {
+		return nil, false
+	}
+	if b, err := json.Marshal(val); err == nil && !bytes.HasPrefix(b, []byte("{}")) {
+		return nil, false
+	}
+	return r, true
+}
+
+// streamReader is a response streamed by an invocation, whose span is ended
+// once the response is read in full or closed.
+//
+// The reader has no exported fields, so that the Lambda runtime serializes it
+// to an empty JSON object and streams it like the response of the handler.
+type streamReader struct {
+	reader       io.Reader
+	instrumentor *instrumentor
+	ctx          context.Context
+	span         trace.Span
+	start        time.Time
+
+	n    int64
+	once sync.Once
+}
+
+// newStreamReader returns the reader streaming r, the response of the
+// invocation whose context is ctx and whose span is span. The reader has the
+// ContentType method of r, if any, used by the Lambda runtime.
+func (i *instrumentor) newStreamReader(ctx context.Context, span trace.Span, r io.Reader) io.ReadCloser {
+	start, ok := ctx.Value(invocationStartKey{}).(time.Time)
+	if !ok {
+		start = time.Now()
+	}
+	sr := &streamReader{reader: r, instrumentor: i, ctx: ctx, span: span, start: start}
+
+	if ct, ok := r.(interface{ ContentType() string }); ok {
+		return &contentTypeStreamReader{streamReader: sr, contentType: ct.ContentType}
+	}
+	return sr
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if r.n == 0 {
+			r.span.SetAttributes(StreamTimeToFirstByteKey.Float64(time.Since(r.start).Seconds()))
+		}
+		r.n += int64(n)
+	}
+	if errors.Is(err, io.EOF) {
+		r.end(nil)
+	} else if err != nil {
+		r.end(err)
+	}
+	return n, err
+}
+
+// Close closes the streamed response, if it is an io.Closer, and ends the
+// invocation if the response was not read in full.
+func (r *streamReader) Close() error {
+	var err error
+	if c, ok := r.reader.(io.Closer); ok {
+		err = c.Close()
+	}
+	r.end(nil)
+	return err
+}
+
+// end ends the invocation streaming the response, which failed with err if
+// it is not nil.
+func (r *streamReader) end(err error) {
+	r.once.Do(func() {
+		r.span.SetAttributes(StreamBytesKey.Int64(r.n))
+		r.instrumentor.tracingEnd(r.ctx, r.span, err)
+	})
+}
+
+// contentTypeStreamReader is a streamReader of a response defining its
+// content type.
+type contentTypeStreamReader struct {
+	*streamReader
+	contentType func() string
+}
+
+func (r *contentTypeStreamReader) ContentType() string {
+	return r.contentType()
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestWrapHandlerEventCapture(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []otellambda.Option
+		event string
+		want  []attribute.KeyValue
+	}{
+		{
+			name:  "Disabled",
+			event: `{"key":"value"}`,
+		},
+		{
+			name:  "APIGateway",
+			opts:  []otellambda.Option{otellambda.WithEventCapture()},
+			event: `{"headers":{"Authorization":"Bearer t0k3n","Host":"example.com"},"requestContext":{"apiId":"id"}}`,
+			want: []attribute.KeyValue{
+				otellambda.EventSourceKey.String("aws:apigateway"),
+				otellambda.EventKey.String(`{"headers":{"Authorization":"REDACTED","Host":"example.com"},"requestContext":{"apiId":"id"}}`),
+			},
+		},
+		{
+			name: "SQS",
+			opts: []otellambda.Option{
+				otellambda.WithEventCapture(),
+				otellambda.WithEventScrubJSONPaths(otellambda.EventSourceSQS, "$.Records[*].body"),
+			},
+			event: `{"Records":[{"body":"secret","eventSource":"aws:sqs"}]}`,
+			want: []attribute.KeyValue{
+				otellambda.EventSourceKey.String("aws:sqs"),
+				otellambda.EventKey.String(`{"Records":[{"body":"REDACTED","eventSource":"aws:sqs"}]}`),
+			},
+		},
+		{
+			name: "OtherSource",
+			opts: []otellambda.Option{
+				otellambda.WithEventCapture(),
+				otellambda.WithEventScrubJSONPaths(otellambda.EventSourceSQS, "$.key"),
+			},
+			event: `{"key":"value"}`,
+			want: []attribute.KeyValue{
+				otellambda.EventKey.String(`{"key":"value"}`),
+			},
+		},
+		{
+			name: "AnySource",
+			opts: []otellambda.Option{
+				otellambda.WithEventCapture(),
+				otellambda.WithEventScrubJSONPaths(otellambda.EventSourceAny, "$.key"),
+			},
+			event: `{"key":"value"}`,
+			want: []attribute.KeyValue{
+				otellambda.EventKey.String(`{"key":"REDACTED"}`),
+			},
+		},
+		{
+			name: "Truncated",
+			opts: []otellambda.Option{
+				otellambda.WithEventCapture(),
+				otellambda.WithEventSizeLimit(8),
+			},
+			event: `{"key":"value"}`,
+			want: []attribute.KeyValue{
+				otellambda.EventTruncatedKey.Bool(true),
+				otellambda.EventKey.String(`{"key":"`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnvVars(t)
+			tp, memExporter := initMockTracerProvider()
+
+			wrapped := otellambda.WrapHandler(emptyHandler{},
+				append([]otellambda.Option{otellambda.WithTracerProvider(tp)}, tt.opts...)...)
+			_, err := wrapped.Invoke(mockContext, []byte(tt.event))
+			require.NoError(t, err)
+
+			spans := memExporter.GetSpans()
+			require.Len(t, spans, 1)
+			if tt.want == nil {
+				assert.Empty(t, spans[0].Events)
+				return
+			}
+			require.Len(t, spans[0].Events, 1)
+			assert.Equal(t, "aws.lambda.event", spans[0].Events[0].Name)
+			assert.Equal(t, tt.want, spans[0].Events[0].Attributes)
+		})
+	}
+}
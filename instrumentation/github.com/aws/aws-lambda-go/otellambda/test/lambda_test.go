@@ -128,6 +128,7 @@ var (
 			attribute.String("faas.invocation_id", "123"),
 			attribute.String("aws.lambda.invoked_arn", "arn:partition:service:region:account-id:resource-type:resource-id"),
 			attribute.String("cloud.account.id", "account-id"),
+			attribute.Bool("faas.coldstart", true),
 		},
 		Events:            nil,
 		Links:             nil,
@@ -320,6 +321,7 @@ var (
 			attribute.String("faas.invocation_id", "123"),
 			attribute.String("aws.lambda.invoked_arn", "arn:partition:service:region:account-id:resource-type:resource-id"),
 			attribute.String("cloud.account.id", "account-id"),
+			attribute.Bool("faas.coldstart", true),
 		},
 		Events:            nil,
 		Links:             nil,
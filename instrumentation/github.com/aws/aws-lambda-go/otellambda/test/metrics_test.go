@@ -0,0 +1,81 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type failingHandler struct {
+	fail bool
+}
+
+func (h *failingHandler) Invoke(context.Context, []byte) ([]byte, error) {
+	if h.fail {
+		return nil, errors.New("failed")
+	}
+	return nil, nil
+}
+
+func TestWrapHandlerColdStartAndMetrics(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	handler := &failingHandler{}
+	wrapped := otellambda.WrapHandler(handler,
+		otellambda.WithTracerProvider(tp),
+		otellambda.WithMeterProvider(mp))
+
+	_, err := wrapped.Invoke(mockContext, []byte{})
+	require.NoError(t, err)
+	handler.fail = true
+	_, err = wrapped.Invoke(mockContext, []byte{})
+	require.Error(t, err)
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Contains(t, spans[0].Attributes, attribute.Bool("faas.coldstart", true))
+	for _, kv := range spans[1].Attributes {
+		assert.NotEqual(t, attribute.Key("faas.coldstart"), kv.Key, "warm invocation")
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, otellambda.ScopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	metrics := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	require.Contains(t, metrics, "faas.invoke_duration")
+	duration := metrics["faas.invoke_duration"].(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(2), duration.DataPoints[0].Count)
+
+	for name, want := range map[string]int64{
+		"faas.invocations":        2,
+		"faas.errors":             1,
+		"faas.coldstarts":         1,
+		"faas.active_invocations": 0,
+	} {
+		require.Contains(t, metrics, name)
+		sum := metrics[name].(metricdata.Sum[int64])
+		require.Len(t, sum.DataPoints, 1, name)
+		assert.Equal(t, want, sum.DataPoints[0].Value, name)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-lambda-go/otellambda"
+)
+
+type contentTypeReader struct {
+	r io.Reader
+}
+
+func (r contentTypeReader) Read(p []byte) (int, error) {
+	return r.r.Read(p)
+}
+
+func (contentTypeReader) ContentType() string {
+	return "text/plain"
+}
+
+// jsonReader is a reader the Lambda runtime serializes to JSON, not streams.
+type jsonReader struct {
+	*strings.Reader `json:"-"`
+	Message         string `json:"message"`
+}
+
+func invokeInstrumented(t *testing.T, handler interface{}, opts ...otellambda.Option) interface{} {
+	t.Helper()
+	wrapped := otellambda.InstrumentHandler(handler, opts...)
+	resp := reflect.ValueOf(wrapped).Call([]reflect.Value{reflect.ValueOf(mockContext)})
+	require.Len(t, resp, 2)
+	require.Nil(t, resp[1].Interface())
+	return resp[0].Interface()
+}
+
+func TestInstrumentHandlerStreaming(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+
+	handler := func(context.Context) (io.Reader, error) {
+		return contentTypeReader{strings.NewReader("hello world")}, nil
+	}
+	val := invokeInstrumented(t, handler, otellambda.WithTracerProvider(tp))
+
+	r, ok := val.(io.ReadCloser)
+	require.True(t, ok)
+	ct, ok := val.(interface{ ContentType() string })
+	require.True(t, ok, "ContentType not preserved")
+	assert.Equal(t, "text/plain", ct.ContentType())
+
+	assert.Empty(t, memExporter.GetSpans(), "span ended before the stream")
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+	require.NoError(t, r.Close())
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes
+	assert.Contains(t, attrs, otellambda.StreamBytesKey.Int64(11))
+	var ttfb bool
+	for _, kv := range attrs {
+		if kv.Key == otellambda.StreamTimeToFirstByteKey {
+			ttfb = true
+			assert.GreaterOrEqual(t, kv.Value.AsFloat64(), 0.0)
+		}
+	}
+	assert.True(t, ttfb, "missing time to first byte")
+}
+
+func TestInstrumentHandlerStreamingRuntime(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+
+	handler := func(context.Context) (io.Reader, error) {
+		return contentTypeReader{strings.NewReader("hello world")}, nil
+	}
+	h := lambda.NewHandler(otellambda.InstrumentHandler(handler, otellambda.WithTracerProvider(tp)))
+
+	b, err := h.Invoke(mockContext, []byte("{}"))
+	require.NoError(t, err)
+	assert.Equal(t, "hello world", string(b))
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 1, "span not ended by the runtime")
+	assert.Contains(t, spans[0].Attributes, otellambda.StreamBytesKey.Int64(11))
+}
+
+func TestInstrumentHandlerStreamingClosed(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+
+	handler := func(context.Context) (io.Reader, error) {
+		return strings.NewReader("hello world"), nil
+	}
+	val := invokeInstrumented(t, handler, otellambda.WithTracerProvider(tp))
+
+	r, ok := val.(io.ReadCloser)
+	require.True(t, ok)
+	_, ok = val.(interface{ ContentType() string })
+	assert.False(t, ok, "ContentType added")
+
+	require.NoError(t, r.Close())
+	require.NoError(t, r.Close())
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes, otellambda.StreamBytesKey.Int64(0))
+}
+
+func TestInstrumentHandlerNotStreaming(t *testing.T) {
+	setEnvVars(t)
+	tp, memExporter := initMockTracerProvider()
+
+	handler := func(context.Context) (jsonReader, error) {
+		return jsonReader{Reader: strings.NewReader("hello"), Message: "hello"}, nil
+	}
+	val := invokeInstrumented(t, handler, otellambda.WithTracerProvider(tp))
+	assert.IsType(t, jsonReader{}, val)
+
+	spans := memExporter.GetSpans()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes {
+		assert.NotEqual(t, otellambda.StreamBytesKey, kv.Key)
+	}
+}
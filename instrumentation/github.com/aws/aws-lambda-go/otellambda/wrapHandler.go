@@ -21,11 +21,11 @@ type wrappedHandler struct {
 var _ lambda.Handler = wrappedHandler{}
 
 // Invoke adds OTel span surrounding customer Handler invocation.
-func (h wrappedHandler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+func (h wrappedHandler) Invoke(ctx context.Context, payload []byte) (response []byte, err error) {
 	ctx, span := h.instrumentor.tracingBegin(ctx, payload)
-	defer h.instrumentor.tracingEnd(ctx, span)
+	defer func() { h.instrumentor.tracingEnd(ctx, span, err) }()
 
-	response, err := h.handler.Invoke(ctx, payload)
+	response, err = h.handler.Invoke(ctx, payload)
 	if err != nil {
 		return nil, err
 	}
@@ -84,6 +84,11 @@ func (whf *wrappedHandlerFunction) wrapperInternals(ctx context.Context, handler
 }
 
 // InstrumentHandler Provides a lambda handler which wraps customer lambda handler with OTel Tracing.
+//
+// If the handler returns an io.Reader streamed by the Lambda runtime, e.g.
+// for response streaming, the span of the invocation ends once the response
+// is read in full or closed, and records the number of bytes streamed and the
+// time to the first byte.
 func InstrumentHandler(handlerFunc interface{}, options ...Option) interface{} {
 	whf := wrappedHandlerFunction{instrumentor: newInstrumentor(options...)}
 
@@ -139,9 +144,15 @@ func InstrumentHandler(handlerFunc interface{}, options ...Option) interface{} {
 
 // Adds OTel span surrounding customer handler call.
 func (whf *wrappedHandlerFunction) wrapper(handlerFunc interface{}) func(ctx context.Context, eventJSON []byte, event interface{}, takesContext bool) []reflect.Value {
-	return func(ctx context.Context, eventJSON []byte, event interface{}, takesContext bool) []reflect.Value {
+	return func(ctx context.Context, eventJSON []byte, event interface{}, takesContext bool) (response []reflect.Value) {
 		ctx, span := whf.instrumentor.tracingBegin(ctx, eventJSON)
-		defer whf.instrumentor.tracingEnd(ctx, span)
+		var streaming bool
+		defer func() {
+			// The invocation of a streamed response ends with the stream.
+			if !streaming {
+				whf.instrumentor.tracingEnd(ctx, span, responseError(response))
+			}
+		}()
 
 		handler := reflect.ValueOf(handlerFunc)
 		var args []reflect.Value
@@ -152,12 +163,26 @@ func (whf *wrappedHandlerFunction) wrapper(handlerFunc interface{}) func(ctx con
 			args = append(args, reflect.ValueOf(event))
 		}
 
-		response := handler.Call(args)
+		response = handler.Call(args)
+		if r, ok := streamResponse(response); ok {
+			streaming = true
+			response[0] = reflect.ValueOf(whf.instrumentor.newStreamReader(ctx, span, r))
+		}
 
 		return response
 	}
 }
 
+// responseError returns the error returned by a handler, whose return values
+// are response, if any.
+func responseError(response []reflect.Value) error {
+	if len(response) == 0 {
+		return nil
+	}
+	err, _ := response[len(response)-1].Interface().(error)
+	return err
+}
+
 // Determine if an interface{} is nil or the
 // if the reflect.Value of the event is nil.
 func eventExists(event interface{}) bool {
@@ -8,6 +8,8 @@ import (
 
 	v2Middleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/smithy-go/middleware"
 
@@ -20,13 +22,26 @@ const (
 	RegionKey    attribute.Key = "aws.region"
 	RequestIDKey attribute.Key = "aws.request_id"
 	AWSSystemVal string        = "aws-api"
+
+	// LambdaFunctionErrorKey is the type of the error of a function invoked
+	// with Lambda Invoke, e.g. "Unhandled".
+	LambdaFunctionErrorKey attribute.Key = "aws.lambda.function_error"
 )
 
 var servicemap = map[string]AttributeSetter{
 	dynamodb.ServiceID: DynamoDBAttributeSetter,
+	lambda.ServiceID:   LambdaAttributeSetter,
+	s3.ServiceID:       S3AttributeSetter,
 	sqs.ServiceID:      SQSAttributeSetter,
 }
 
+var responseServicemap = map[string]ResponseAttributeSetter{
+	dynamodb.ServiceID: DynamoDBResponseAttributeSetter,
+	lambda.ServiceID:   LambdaResponseAttributeSetter,
+	s3.ServiceID:       S3ResponseAttributeSetter,
+	sqs.ServiceID:      SQSResponseAttributeSetter,
+}
+
 // SystemAttr return the AWS RPC system attribute.
 func SystemAttr() attribute.KeyValue {
 	return semconv.RPCSystemKey.String(AWSSystemVal)
@@ -63,3 +78,16 @@ func DefaultAttributeSetter(ctx context.Context, in middleware.InitializeInput)
 
 	return []attribute.KeyValue{}
 }
+
+// DefaultResponseAttributeSetter checks to see if there are service specific attributes available to set for the
+// output of an operation of the AWS service.
+// If there are service specific attributes available then they will be included.
+func DefaultResponseAttributeSetter(ctx context.Context, in middleware.InitializeInput, out middleware.InitializeOutput) []attribute.KeyValue {
+	serviceID := v2Middleware.GetServiceID(ctx)
+
+	if fn, ok := responseServicemap[serviceID]; ok {
+		return fn(ctx, in, out)
+	}
+
+	return []attribute.KeyValue{}
+}
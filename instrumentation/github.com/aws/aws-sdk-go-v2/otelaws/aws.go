@@ -5,6 +5,7 @@ package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.co
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	v2Middleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
@@ -14,6 +15,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
 	"go.opentelemetry.io/otel/trace"
@@ -29,10 +31,20 @@ type spanTimestampKey struct{}
 // AttributeSetter returns an array of KeyValue pairs, it can be used to set custom attributes.
 type AttributeSetter func(context.Context, middleware.InitializeInput) []attribute.KeyValue
 
+// ResponseAttributeSetter returns an array of KeyValue pairs describing the
+// output of a successful operation, e.g. the capacity it consumed, which are
+// set on its span once it completes.
+type ResponseAttributeSetter func(context.Context, middleware.InitializeInput, middleware.InitializeOutput) []attribute.KeyValue
+
 type otelMiddlewares struct {
-	tracer          trace.Tracer
-	propagator      propagation.TextMapPropagator
-	attributeSetter []AttributeSetter
+	tracer                  trace.Tracer
+	propagator              propagation.TextMapPropagator
+	attributeSetter         []AttributeSetter
+	responseAttributeSetter []ResponseAttributeSetter
+
+	messageAttributePropagation bool
+
+	metrics *clientMetrics
 }
 
 func (m otelMiddlewares) initializeMiddlewareBefore(stack *middleware.Stack) error {
@@ -55,29 +67,43 @@ func (m otelMiddlewares) initializeMiddlewareAfter(stack *middleware.Stack) erro
 		operation := v2Middleware.GetOperationName(ctx)
 		region := v2Middleware.GetRegion(ctx)
 
-		attributes := []attribute.KeyValue{
+		metricAttributes := []attribute.KeyValue{
 			SystemAttr(),
 			ServiceAttr(serviceID),
 			RegionAttr(region),
 			OperationAttr(operation),
 		}
+		attributes := slices.Clone(metricAttributes)
 		for _, setter := range m.attributeSetter {
 			attributes = append(attributes, setter(ctx, in)...)
 		}
 
+		start := ctx.Value(spanTimestampKey{}).(time.Time)
+		ctx, size := withPayloadSize(ctx)
 		ctx, span := m.tracer.Start(ctx, spanName(serviceID, operation),
-			trace.WithTimestamp(ctx.Value(spanTimestampKey{}).(time.Time)),
+			trace.WithTimestamp(start),
 			trace.WithSpanKind(trace.SpanKindClient),
 			trace.WithAttributes(attributes...),
 		)
 		defer span.End()
 
+		if m.messageAttributePropagation {
+			in = injectMessageAttributes(ctx, m.propagator, in)
+		}
+
 		out, metadata, err = next.HandleInitialize(ctx, in)
 		if err != nil {
 			span.RecordError(err)
 			span.SetStatus(codes.Error, err.Error())
+		} else {
+			for _, setter := range m.responseAttributeSetter {
+				span.SetAttributes(setter(ctx, in, out)...)
+			}
 		}
 
+		elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+		m.metrics.record(ctx, elapsed, size, metadata, metricAttributes)
+
 		return out, metadata, err
 	}),
 		middleware.After)
@@ -98,6 +124,10 @@ func (m otelMiddlewares) deserializeMiddleware(stack *middleware.Stack) error {
 		span := trace.SpanFromContext(ctx)
 		span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
 
+		if size := payloadSizeFromContext(ctx); size != nil {
+			size.response = resp.ContentLength
+		}
+
 		requestID, ok := v2Middleware.GetRequestIDMetadata(metadata)
 		if ok {
 			span.SetAttributes(RequestIDAttr(requestID))
@@ -117,6 +147,14 @@ func (m otelMiddlewares) finalizeMiddleware(stack *middleware.Stack) error {
 		switch req := in.Request.(type) {
 		case *smithyhttp.Request:
 			m.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+			if size := payloadSizeFromContext(ctx); size != nil {
+				if n, ok, err := req.StreamLength(); err == nil && ok {
+					size.request = n
+				} else if req.GetStream() == nil {
+					size.request = 0
+				}
+			}
 		default:
 		}
 
@@ -139,6 +177,7 @@ func spanName(serviceID, operation string) string {
 func AppendMiddlewares(apiOptions *[]func(*middleware.Stack) error, opts ...Option) {
 	cfg := config{
 		TracerProvider:    otel.GetTracerProvider(),
+		MeterProvider:     otel.GetMeterProvider(),
 		TextMapPropagator: otel.GetTextMapPropagator(),
 	}
 	for _, opt := range opts {
@@ -148,12 +187,21 @@ func AppendMiddlewares(apiOptions *[]func(*middleware.Stack) error, opts ...Opti
 	if cfg.AttributeSetter == nil {
 		cfg.AttributeSetter = []AttributeSetter{DefaultAttributeSetter}
 	}
+	if cfg.ResponseAttributeSetter == nil {
+		cfg.ResponseAttributeSetter = []ResponseAttributeSetter{DefaultResponseAttributeSetter}
+	}
 
 	m := otelMiddlewares{
 		tracer: cfg.TracerProvider.Tracer(ScopeName,
 			trace.WithInstrumentationVersion(Version())),
-		propagator:      cfg.TextMapPropagator,
-		attributeSetter: cfg.AttributeSetter,
+		propagator:              cfg.TextMapPropagator,
+		attributeSetter:         cfg.AttributeSetter,
+		responseAttributeSetter: cfg.ResponseAttributeSetter,
+
+		messageAttributePropagation: cfg.MessageAttributePropagation,
+
+		metrics: newClientMetrics(cfg.MeterProvider.Meter(ScopeName,
+			metric.WithInstrumentationVersion(Version()))),
 	}
 	*apiOptions = append(*apiOptions, m.initializeMiddlewareBefore, m.initializeMiddlewareAfter, m.finalizeMiddleware, m.deserializeMiddleware)
 }
@@ -4,14 +4,20 @@
 package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 
 import (
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
 type config struct {
 	TracerProvider    trace.TracerProvider
+	MeterProvider     metric.MeterProvider
 	TextMapPropagator propagation.TextMapPropagator
 	AttributeSetter   []AttributeSetter
+
+	ResponseAttributeSetter []ResponseAttributeSetter
+
+	MessageAttributePropagation bool
 }
 
 // Option applies an option value.
@@ -37,6 +43,16 @@ func WithTracerProvider(provider trace.TracerProvider) Option {
 	})
 }
 
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// If none is specified, the global MeterProvider is used.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
 // WithTextMapPropagator specifies a Text Map Propagator to use when propagating context.
 // If none is specified, the global TextMapPropagator is used.
 func WithTextMapPropagator(propagator propagation.TextMapPropagator) Option {
@@ -54,3 +70,27 @@ func WithAttributeSetter(attributesetters ...AttributeSetter) Option {
 		cfg.AttributeSetter = append(cfg.AttributeSetter, attributesetters...)
 	})
 }
+
+// WithResponseAttributeSetter specifies an attribute setter function for setting service specific attributes
+// describing the output of operations.
+// If none is specified, the service will be determined by the DefaultResponseAttributeSetter function and the corresponding attributes will be included.
+func WithResponseAttributeSetter(attributesetters ...ResponseAttributeSetter) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.ResponseAttributeSetter = append(cfg.ResponseAttributeSetter, attributesetters...)
+	})
+}
+
+// WithMessageAttributePropagation enables the propagation of the trace context
+// in the message attributes of the messages sent to SQS queues, by SendMessage
+// and SendMessageBatch, and published to SNS topics, by Publish and
+// PublishBatch, to connect the traces of their producers and consumers. The
+// TextMapPropagator injects the trace context of the span of the operation.
+// Messages already having too many message attributes are sent without it.
+//
+// ReceiveMessage requests the message attributes of the trace context, which
+// ExtractSQSMessage extracts from the received messages.
+func WithMessageAttributePropagation() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.MessageAttributePropagation = true
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/aws/smithy-go/middleware"
 
 	"go.opentelemetry.io/otel/attribute"
@@ -181,3 +182,70 @@ func DynamoDBAttributeSetter(ctx context.Context, in middleware.InitializeInput)
 
 	return dynamodbAttributes
 }
+
+// DynamoDBResponseAttributeSetter sets DynamoDB specific attributes depending on the output of the DynamoDB operation performed.
+func DynamoDBResponseAttributeSetter(ctx context.Context, in middleware.InitializeInput, out middleware.InitializeOutput) []attribute.KeyValue {
+	var dynamodbAttributes []attribute.KeyValue
+
+	var capacity []types.ConsumedCapacity
+	var metrics *types.ItemCollectionMetrics
+	switch v := out.Result.(type) {
+	case *dynamodb.BatchGetItemOutput:
+		capacity = v.ConsumedCapacity
+	case *dynamodb.BatchWriteItemOutput:
+		capacity = v.ConsumedCapacity
+
+		if v.ItemCollectionMetrics != nil {
+			m, _ := json.Marshal(v.ItemCollectionMetrics)
+			dynamodbAttributes = append(dynamodbAttributes, semconv.AWSDynamoDBItemCollectionMetrics(string(m)))
+		}
+	case *dynamodb.DeleteItemOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+		metrics = v.ItemCollectionMetrics
+	case *dynamodb.GetItemOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+	case *dynamodb.PutItemOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+		metrics = v.ItemCollectionMetrics
+	case *dynamodb.QueryOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+		dynamodbAttributes = append(dynamodbAttributes,
+			semconv.AWSDynamoDBCount(int(v.Count)),
+			semconv.AWSDynamoDBScannedCount(int(v.ScannedCount)),
+		)
+	case *dynamodb.ScanOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+		dynamodbAttributes = append(dynamodbAttributes,
+			semconv.AWSDynamoDBCount(int(v.Count)),
+			semconv.AWSDynamoDBScannedCount(int(v.ScannedCount)),
+		)
+	case *dynamodb.UpdateItemOutput:
+		capacity = consumedCapacity(v.ConsumedCapacity)
+		metrics = v.ItemCollectionMetrics
+	}
+
+	if capacity != nil {
+		var cc []string
+		for _, c := range capacity {
+			b, _ := json.Marshal(c)
+			cc = append(cc, string(b))
+		}
+		dynamodbAttributes = append(dynamodbAttributes, semconv.AWSDynamoDBConsumedCapacity(cc...))
+	}
+
+	if metrics != nil {
+		m, _ := json.Marshal(metrics)
+		dynamodbAttributes = append(dynamodbAttributes, semconv.AWSDynamoDBItemCollectionMetrics(string(m)))
+	}
+
+	return dynamodbAttributes
+}
+
+// consumedCapacity returns the consumed capacity c of an operation on a
+// single table as a slice, or nil if it was not returned.
+func consumedCapacity(c *types.ConsumedCapacity) []types.ConsumedCapacity {
+	if c == nil {
+		return nil
+	}
+	return []types.ConsumedCapacity{*c}
+}
@@ -344,3 +344,32 @@ func TestDynamodbTagsUpdateTableInput(t *testing.T) {
 	assert.Contains(t, attributes, attribute.Float64("aws.dynamodb.provisioned_read_capacity", 123))
 	assert.Contains(t, attributes, attribute.Float64("aws.dynamodb.provisioned_write_capacity", 456))
 }
+
+func TestDynamodbTagsQueryOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &dynamodb.QueryOutput{
+			Count:        2,
+			ScannedCount: 5,
+			ConsumedCapacity: &dtypes.ConsumedCapacity{
+				TableName:     aws.String("table1"),
+				CapacityUnits: aws.Float64(1),
+			},
+		},
+	}
+
+	attributes := DynamoDBResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Contains(t, attributes, attribute.Int("aws.dynamodb.count", 2))
+	assert.Contains(t, attributes, attribute.Int("aws.dynamodb.scanned_count", 5))
+	assert.Contains(t, attributes, attribute.StringSlice("aws.dynamodb.consumed_capacity", []string{`{"CapacityUnits":1,"GlobalSecondaryIndexes":null,"LocalSecondaryIndexes":null,"ReadCapacityUnits":null,"Table":null,"TableName":"table1","WriteCapacityUnits":null}`}))
+}
+
+func TestDynamodbTagsPutItemOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &dynamodb.PutItemOutput{},
+	}
+
+	attributes := DynamoDBResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Empty(t, attributes)
+}
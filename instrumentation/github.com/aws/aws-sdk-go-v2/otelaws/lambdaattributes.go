@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+
+import (
+	"context"
+	"strings"
+
+	v2Middleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/smithy-go/middleware"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// LambdaAttributeSetter sets Lambda specific attributes depending on the Lambda operation being performed.
+func LambdaAttributeSetter(ctx context.Context, in middleware.InitializeInput) []attribute.KeyValue {
+	var lambdaAttributes []attribute.KeyValue
+
+	var name *string
+	switch v := in.Parameters.(type) {
+	case *lambda.InvokeInput:
+		name = v.FunctionName
+	case *lambda.InvokeWithResponseStreamInput:
+		name = v.FunctionName
+	}
+	if name == nil {
+		return lambdaAttributes
+	}
+
+	lambdaAttributes = append(lambdaAttributes,
+		semconv.FaaSInvokedName(*name),
+		semconv.FaaSInvokedProviderAWS,
+	)
+	if region := v2Middleware.GetRegion(ctx); region != "" {
+		lambdaAttributes = append(lambdaAttributes, semconv.FaaSInvokedRegion(region))
+	}
+	if strings.HasPrefix(*name, "arn:") {
+		lambdaAttributes = append(lambdaAttributes, semconv.AWSLambdaInvokedARN(*name))
+	}
+
+	return lambdaAttributes
+}
+
+// LambdaResponseAttributeSetter sets Lambda specific attributes depending on the output of the Lambda operation performed.
+func LambdaResponseAttributeSetter(ctx context.Context, in middleware.InitializeInput, out middleware.InitializeOutput) []attribute.KeyValue {
+	var lambdaAttributes []attribute.KeyValue
+
+	if v, ok := out.Result.(*lambda.InvokeOutput); ok && v.FunctionError != nil {
+		lambdaAttributes = append(lambdaAttributes, LambdaFunctionErrorKey.String(*v.FunctionError))
+	}
+
+	return lambdaAttributes
+}
@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestLambdaInvokeInput(t *testing.T) {
+	input := middleware.InitializeInput{
+		Parameters: &lambda.InvokeInput{
+			FunctionName: aws.String("test-function"),
+		},
+	}
+
+	attributes := LambdaAttributeSetter(context.TODO(), input)
+
+	assert.Contains(t, attributes, semconv.FaaSInvokedName("test-function"))
+	assert.Contains(t, attributes, semconv.FaaSInvokedProviderAWS)
+	assert.NotContains(t, attributes, semconv.AWSLambdaInvokedARN("test-function"))
+}
+
+func TestLambdaInvokeInputARN(t *testing.T) {
+	arn := "arn:aws:lambda:us-west-2:123456789012:function:test-function"
+	input := middleware.InitializeInput{
+		Parameters: &lambda.InvokeInput{
+			FunctionName: aws.String(arn),
+		},
+	}
+
+	attributes := LambdaAttributeSetter(context.TODO(), input)
+
+	assert.Contains(t, attributes, semconv.FaaSInvokedName(arn))
+	assert.Contains(t, attributes, semconv.AWSLambdaInvokedARN(arn))
+}
+
+func TestLambdaInvokeOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &lambda.InvokeOutput{
+			FunctionError: aws.String("Unhandled"),
+		},
+	}
+
+	attributes := LambdaResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Contains(t, attributes, LambdaFunctionErrorKey.String("Unhandled"))
+}
@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/aws/smithy-go/middleware"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// maxMessageAttributes is the number of message attributes SQS and SNS
+// accept per message.
+const maxMessageAttributes = 10
+
+// stringDataType is the data type of the message attributes set by the
+// carriers.
+const stringDataType = "String"
+
+// SQSMessageAttributeCarrier adapts the message attributes of an SQS message
+// to satisfy the TextMapCarrier interface.
+type SQSMessageAttributeCarrier map[string]sqstypes.MessageAttributeValue
+
+var _ propagation.TextMapCarrier = SQSMessageAttributeCarrier{}
+
+// Get returns the string value of the message attribute key.
+func (c SQSMessageAttributeCarrier) Get(key string) string {
+	if v, ok := c[key]; ok && v.StringValue != nil {
+		return *v.StringValue
+	}
+	return ""
+}
+
+// Set sets the message attribute key to the string value.
+func (c SQSMessageAttributeCarrier) Set(key, value string) {
+	c[key] = sqstypes.MessageAttributeValue{
+		DataType:    aws.String(stringDataType),
+		StringValue: aws.String(value),
+	}
+}
+
+// Keys lists the names of the message attributes.
+func (c SQSMessageAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SNSMessageAttributeCarrier adapts the message attributes of an SNS message
+// to satisfy the TextMapCarrier interface.
+type SNSMessageAttributeCarrier map[string]snstypes.MessageAttributeValue
+
+var _ propagation.TextMapCarrier = SNSMessageAttributeCarrier{}
+
+// Get returns the string value of the message attribute key.
+func (c SNSMessageAttributeCarrier) Get(key string) string {
+	if v, ok := c[key]; ok && v.StringValue != nil {
+		return *v.StringValue
+	}
+	return ""
+}
+
+// Set sets the message attribute key to the string value.
+func (c SNSMessageAttributeCarrier) Set(key, value string) {
+	c[key] = snstypes.MessageAttributeValue{
+		DataType:    aws.String(stringDataType),
+		StringValue: aws.String(value),
+	}
+}
+
+// Keys lists the names of the message attributes.
+func (c SNSMessageAttributeCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ExtractSQSMessage returns a copy of ctx holding the trace context
+// propagated in the message attributes of msg, e.g. to start the span
+// processing a message received from a queue as a child of, or linked to,
+// the span sending it.
+//
+// The message attributes are only received if they are requested by the
+// ReceiveMessage call, which the WithMessageAttributePropagation option
+// does. Messages published to an SNS topic are delivered to an SQS queue
+// with their message attributes if the subscription enables raw message
+// delivery.
+//
+// The TextMapPropagator of opts, or the global one, extracts the trace
+// context. The other options are ignored.
+func ExtractSQSMessage(ctx context.Context, msg sqstypes.Message, opts ...Option) context.Context {
+	cfg := config{TextMapPropagator: otel.GetTextMapPropagator()}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return cfg.TextMapPropagator.Extract(ctx, SQSMessageAttributeCarrier(msg.MessageAttributes))
+}
+
+// injectMessageAttributes returns in with the trace context of ctx injected
+// by p into the message attributes of the messages sent to SQS or published
+// to SNS, and with the fields of p added to the message attributes requested
+// by SQS ReceiveMessage. The parameters of in are copied, not modified.
+//
+// Messages whose message attributes cannot hold the trace context, as
+// their number is limited, are sent without it.
+func injectMessageAttributes(ctx context.Context, p propagation.TextMapPropagator, in middleware.InitializeInput) middleware.InitializeInput {
+	switch v := in.Parameters.(type) {
+	case *sqs.SendMessageInput:
+		params := *v
+		params.MessageAttributes = injectSQS(ctx, p, params.MessageAttributes)
+		in.Parameters = &params
+	case *sqs.SendMessageBatchInput:
+		params := *v
+		params.Entries = make([]sqstypes.SendMessageBatchRequestEntry, len(v.Entries))
+		for i, e := range v.Entries {
+			e.MessageAttributes = injectSQS(ctx, p, e.MessageAttributes)
+			params.Entries[i] = e
+		}
+		in.Parameters = &params
+	case *sqs.ReceiveMessageInput:
+		params := *v
+		params.MessageAttributeNames = receiveAttributeNames(p, params.MessageAttributeNames)
+		in.Parameters = &params
+	case *sns.PublishInput:
+		params := *v
+		params.MessageAttributes = injectSNS(ctx, p, params.MessageAttributes)
+		in.Parameters = &params
+	case *sns.PublishBatchInput:
+		params := *v
+		params.PublishBatchRequestEntries = make([]snstypes.PublishBatchRequestEntry, len(v.PublishBatchRequestEntries))
+		for i, e := range v.PublishBatchRequestEntries {
+			e.MessageAttributes = injectSNS(ctx, p, e.MessageAttributes)
+			params.PublishBatchRequestEntries[i] = e
+		}
+		in.Parameters = &params
+	}
+	return in
+}
+
+// injectSQS returns a copy of the SQS message attributes attrs holding the
+// trace context of ctx, or attrs if the trace context does not fit.
+func injectSQS(ctx context.Context, p propagation.TextMapPropagator, attrs map[string]sqstypes.MessageAttributeValue) map[string]sqstypes.MessageAttributeValue {
+	c := SQSMessageAttributeCarrier(maps.Clone(attrs))
+	if c == nil {
+		c = SQSMessageAttributeCarrier{}
+	}
+	p.Inject(ctx, c)
+	if len(c) == len(attrs) || len(c) > maxMessageAttributes {
+		return attrs
+	}
+	return c
+}
+
+// injectSNS returns a copy of the SNS message attributes attrs holding the
+// trace context of ctx, or attrs if the trace context does not fit.
+func injectSNS(ctx context.Context, p propagation.TextMapPropagator, attrs map[string]snstypes.MessageAttributeValue) map[string]snstypes.MessageAttributeValue {
+	c := SNSMessageAttributeCarrier(maps.Clone(attrs))
+	if c == nil {
+		c = SNSMessageAttributeCarrier{}
+	}
+	p.Inject(ctx, c)
+	if len(c) == len(attrs) || len(c) > maxMessageAttributes {
+		return attrs
+	}
+	return c
+}
+
+// receiveAttributeNames returns the message attribute names requested by
+// SQS ReceiveMessage, names, with the fields of p added unless all message
+// attributes are requested.
+func receiveAttributeNames(p propagation.TextMapPropagator, names []string) []string {
+	if slices.Contains(names, "All") || slices.Contains(names, ".*") {
+		return names
+	}
+
+	out := slices.Clone(names)
+	for _, f := range p.Fields() {
+		if !slices.Contains(names, f) {
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQSMessageAttributeCarrier(t *testing.T) {
+	c := SQSMessageAttributeCarrier{}
+	c.Set("key", "value")
+
+	assert.Equal(t, "value", c.Get("key"))
+	assert.Equal(t, "", c.Get("missing"))
+	assert.Equal(t, []string{"key"}, c.Keys())
+	assert.Equal(t, "String", *c["key"].DataType)
+}
+
+func TestInjectMessageAttributesSNSPublishBatch(t *testing.T) {
+	propagator := mockPropagator{
+		injectKey:   "mock-key",
+		injectValue: "mock-value",
+	}
+
+	full := map[string]snstypes.MessageAttributeValue{}
+	for i := 0; i < maxMessageAttributes; i++ {
+		full[strconv.Itoa(i)] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("v")}
+	}
+	params := &sns.PublishBatchInput{
+		PublishBatchRequestEntries: []snstypes.PublishBatchRequestEntry{
+			{Id: aws.String("1")},
+			{Id: aws.String("2"), MessageAttributes: full},
+		},
+	}
+
+	in := injectMessageAttributes(context.Background(), propagator, middleware.InitializeInput{Parameters: params})
+
+	got, ok := in.Parameters.(*sns.PublishBatchInput)
+	require.True(t, ok)
+	assert.Equal(t, "mock-value", SNSMessageAttributeCarrier(got.PublishBatchRequestEntries[0].MessageAttributes).Get("mock-key"))
+	assert.NotContains(t, got.PublishBatchRequestEntries[1].MessageAttributes, "mock-key", "message attribute limit exceeded")
+
+	assert.Nil(t, params.PublishBatchRequestEntries[0].MessageAttributes, "input modified")
+}
+
+func TestReceiveAttributeNames(t *testing.T) {
+	propagator := mockPropagator{}
+	assert.Equal(t, []string{"All"}, receiveAttributeNames(propagator, []string{"All"}))
+	assert.Equal(t, []string{"key"}, receiveAttributeNames(propagator, []string{"key"}))
+}
@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// clientMetrics are the instruments of the metrics of the operations of the
+// AWS clients.
+type clientMetrics struct {
+	duration     metric.Float64Histogram
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+	retries      metric.Int64Counter
+	throttles    metric.Int64Counter
+}
+
+func newClientMetrics(meter metric.Meter) *clientMetrics {
+	m := &clientMetrics{}
+	var err error
+	m.duration, err = meter.Float64Histogram(
+		"rpc.client.duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Measures the duration of AWS operations, including their retries."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.duration = noop.Float64Histogram{}
+	}
+
+	m.requestSize, err = meter.Int64Histogram(
+		"rpc.client.request.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Measures the size of the HTTP request bodies of AWS operations."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.requestSize = noop.Int64Histogram{}
+	}
+
+	m.responseSize, err = meter.Int64Histogram(
+		"rpc.client.response.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Measures the size of the HTTP response bodies of AWS operations."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.responseSize = noop.Int64Histogram{}
+	}
+
+	m.retries, err = meter.Int64Counter(
+		"aws.client.retries",
+		metric.WithUnit("{retry}"),
+		metric.WithDescription("Counts the attempts of AWS operations retried by the SDK."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.retries = noop.Int64Counter{}
+	}
+
+	m.throttles, err = meter.Int64Counter(
+		"aws.client.throttles",
+		metric.WithUnit("{attempt}"),
+		metric.WithDescription("Counts the attempts of AWS operations throttled by the service."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.throttles = noop.Int64Counter{}
+	}
+	return m
+}
+
+type payloadSizeKey struct{}
+
+// payloadSize is the size of the HTTP request and response bodies of the
+// last attempt of an operation, or -1 if it is unknown.
+type payloadSize struct {
+	request, response int64
+}
+
+// withPayloadSize returns a copy of ctx holding a payloadSize recorded by
+// the finalize and deserialize middlewares.
+func withPayloadSize(ctx context.Context) (context.Context, *payloadSize) {
+	s := &payloadSize{request: -1, response: -1}
+	return context.WithValue(ctx, payloadSizeKey{}, s), s
+}
+
+func payloadSizeFromContext(ctx context.Context) *payloadSize {
+	s, _ := ctx.Value(payloadSizeKey{}).(*payloadSize)
+	return s
+}
+
+// record records the metrics of an operation, whose attributes are attrs,
+// taking duration milliseconds and whose middleware metadata is metadata.
+func (m *clientMetrics) record(ctx context.Context, duration float64, size *payloadSize, metadata middleware.Metadata, attrs []attribute.KeyValue) {
+	opt := metric.WithAttributeSet(attribute.NewSet(attrs...))
+
+	m.duration.Record(ctx, duration, opt)
+	if size.request >= 0 {
+		m.requestSize.Record(ctx, size.request, opt)
+	}
+	if size.response >= 0 {
+		m.responseSize.Record(ctx, size.response, opt)
+	}
+
+	results, ok := retry.GetAttemptResults(metadata)
+	if !ok || len(results.Results) == 0 {
+		return
+	}
+	if n := len(results.Results) - 1; n > 0 {
+		m.retries.Add(ctx, int64(n), opt)
+	}
+	var throttled int64
+	for _, r := range results.Results {
+		if r.Err != nil && retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(r.Err) == aws.TrueTernary {
+			throttled++
+		}
+	}
+	if throttled > 0 {
+		m.throttles.Add(ctx, throttled, opt)
+	}
+}
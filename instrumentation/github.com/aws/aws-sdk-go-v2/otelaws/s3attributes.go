@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+// S3AttributeSetter sets S3 specific attributes depending on the S3 operation being performed.
+func S3AttributeSetter(ctx context.Context, in middleware.InitializeInput) []attribute.KeyValue {
+	var s3Attributes []attribute.KeyValue
+
+	var bucket, key *string
+	switch v := in.Parameters.(type) {
+	case *s3.CopyObjectInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.CreateBucketInput:
+		bucket = v.Bucket
+	case *s3.CreateMultipartUploadInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.CompleteMultipartUploadInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.AbortMultipartUploadInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.DeleteBucketInput:
+		bucket = v.Bucket
+	case *s3.DeleteObjectInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.DeleteObjectsInput:
+		bucket = v.Bucket
+	case *s3.GetObjectInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.HeadBucketInput:
+		bucket = v.Bucket
+	case *s3.HeadObjectInput:
+		bucket, key = v.Bucket, v.Key
+	case *s3.ListObjectsInput:
+		bucket = v.Bucket
+	case *s3.ListObjectsV2Input:
+		bucket = v.Bucket
+	case *s3.PutObjectInput:
+		bucket, key = v.Bucket, v.Key
+
+		if v.ContentLength != nil {
+			s3Attributes = append(s3Attributes, semconv.HTTPRequestContentLength(int(*v.ContentLength)))
+		}
+	case *s3.UploadPartInput:
+		bucket, key = v.Bucket, v.Key
+
+		if v.ContentLength != nil {
+			s3Attributes = append(s3Attributes, semconv.HTTPRequestContentLength(int(*v.ContentLength)))
+		}
+	}
+
+	if bucket != nil {
+		s3Attributes = append(s3Attributes, semconv.AWSS3Bucket(*bucket))
+	}
+	if key != nil {
+		s3Attributes = append(s3Attributes, semconv.AWSS3Key(*key))
+	}
+
+	return s3Attributes
+}
+
+// S3ResponseAttributeSetter sets S3 specific attributes depending on the output of the S3 operation performed.
+func S3ResponseAttributeSetter(ctx context.Context, in middleware.InitializeInput, out middleware.InitializeOutput) []attribute.KeyValue {
+	var s3Attributes []attribute.KeyValue
+
+	switch v := out.Result.(type) {
+	case *s3.GetObjectOutput:
+		if v.ContentLength != nil {
+			s3Attributes = append(s3Attributes, semconv.HTTPResponseContentLength(int(*v.ContentLength)))
+		}
+	case *s3.HeadObjectOutput:
+		if v.ContentLength != nil {
+			s3Attributes = append(s3Attributes, semconv.HTTPResponseContentLength(int(*v.ContentLength)))
+		}
+	}
+
+	return s3Attributes
+}
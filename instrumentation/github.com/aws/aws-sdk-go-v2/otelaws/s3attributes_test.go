@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelaws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+)
+
+func TestS3GetObjectInput(t *testing.T) {
+	input := middleware.InitializeInput{
+		Parameters: &s3.GetObjectInput{
+			Bucket: aws.String("test-bucket"),
+			Key:    aws.String("test/key"),
+		},
+	}
+
+	attributes := S3AttributeSetter(context.TODO(), input)
+
+	assert.Contains(t, attributes, semconv.AWSS3Bucket("test-bucket"))
+	assert.Contains(t, attributes, semconv.AWSS3Key("test/key"))
+}
+
+func TestS3PutObjectInput(t *testing.T) {
+	input := middleware.InitializeInput{
+		Parameters: &s3.PutObjectInput{
+			Bucket:        aws.String("test-bucket"),
+			Key:           aws.String("test/key"),
+			ContentLength: aws.Int64(42),
+		},
+	}
+
+	attributes := S3AttributeSetter(context.TODO(), input)
+
+	assert.Contains(t, attributes, semconv.AWSS3Bucket("test-bucket"))
+	assert.Contains(t, attributes, semconv.AWSS3Key("test/key"))
+	assert.Contains(t, attributes, semconv.HTTPRequestContentLength(42))
+}
+
+func TestS3ListObjectsV2Input(t *testing.T) {
+	input := middleware.InitializeInput{
+		Parameters: &s3.ListObjectsV2Input{
+			Bucket: aws.String("test-bucket"),
+		},
+	}
+
+	attributes := S3AttributeSetter(context.TODO(), input)
+
+	assert.Equal(t, []attribute.KeyValue{semconv.AWSS3Bucket("test-bucket")}, attributes)
+}
+
+func TestS3GetObjectOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &s3.GetObjectOutput{
+			ContentLength: aws.Int64(42),
+		},
+	}
+
+	attributes := S3ResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Contains(t, attributes, semconv.HTTPResponseContentLength(42))
+}
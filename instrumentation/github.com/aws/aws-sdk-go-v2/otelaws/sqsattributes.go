@@ -5,6 +5,8 @@ package otelaws // import "go.opentelemetry.io/contrib/instrumentation/github.co
 
 import (
 	"context"
+	"net/url"
+	"path"
 
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
 	"github.com/aws/smithy-go/middleware"
@@ -34,13 +36,17 @@ func SQSAttributeSetter(ctx context.Context, in middleware.InitializeInput) []at
 	case *sqs.PurgeQueueInput:
 		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
 	case *sqs.ReceiveMessageInput:
-		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
+		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl), semconv.MessagingOperationReceive)
 	case *sqs.RemovePermissionInput:
 		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
 	case *sqs.SendMessageBatchInput:
-		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
+		sqsAttributes = append(sqsAttributes,
+			key.String(*v.QueueUrl),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingBatchMessageCount(len(v.Entries)),
+		)
 	case *sqs.SendMessageInput:
-		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
+		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl), semconv.MessagingOperationPublish)
 	case *sqs.SetQueueAttributesInput:
 		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
 	case *sqs.TagQueueInput:
@@ -49,5 +55,41 @@ func SQSAttributeSetter(ctx context.Context, in middleware.InitializeInput) []at
 		sqsAttributes = append(sqsAttributes, key.String(*v.QueueUrl))
 	}
 
+	if name := queueName(sqsAttributes); name != "" {
+		sqsAttributes = append(sqsAttributes, semconv.MessagingDestinationName(name))
+	}
+
+	return sqsAttributes
+}
+
+// SQSResponseAttributeSetter sets SQS specific attributes depending on the output of the SQS operation performed.
+func SQSResponseAttributeSetter(ctx context.Context, in middleware.InitializeInput, out middleware.InitializeOutput) []attribute.KeyValue {
+	var sqsAttributes []attribute.KeyValue
+
+	switch v := out.Result.(type) {
+	case *sqs.ReceiveMessageOutput:
+		sqsAttributes = append(sqsAttributes, semconv.MessagingBatchMessageCount(len(v.Messages)))
+	case *sqs.SendMessageOutput:
+		if v.MessageId != nil {
+			sqsAttributes = append(sqsAttributes, semconv.MessagingMessageID(*v.MessageId))
+		}
+	}
+
 	return sqsAttributes
 }
+
+// queueName returns the name of the queue, the last segment of the path of
+// its URL, of the SQS attributes attrs, or "" if they have no queue URL.
+func queueName(attrs []attribute.KeyValue) string {
+	for _, kv := range attrs {
+		if kv.Key != semconv.NetPeerNameKey {
+			continue
+		}
+		u, err := url.Parse(kv.Value.AsString())
+		if err != nil || u.Path == "" {
+			return ""
+		}
+		return path.Base(u.Path)
+	}
+	return ""
+}
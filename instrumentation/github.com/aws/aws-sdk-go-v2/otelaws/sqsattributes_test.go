@@ -9,6 +9,7 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/aws/smithy-go/middleware"
 	"github.com/stretchr/testify/assert"
 
@@ -182,3 +183,42 @@ func TestSQSUntagQueueInput(t *testing.T) {
 
 	assert.Contains(t, attributes, semconv.NetPeerName("test-queue-url"))
 }
+
+func TestSQSSendMessageBatchInputMessaging(t *testing.T) {
+	input := middleware.InitializeInput{
+		Parameters: &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+			Entries:  make([]types.SendMessageBatchRequestEntry, 2),
+		},
+	}
+
+	attributes := SQSAttributeSetter(context.TODO(), input)
+
+	assert.Contains(t, attributes, semconv.MessagingDestinationName("test-queue"))
+	assert.Contains(t, attributes, semconv.MessagingOperationPublish)
+	assert.Contains(t, attributes, semconv.MessagingBatchMessageCount(2))
+}
+
+func TestSQSReceiveMessageOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &sqs.ReceiveMessageOutput{
+			Messages: make([]types.Message, 3),
+		},
+	}
+
+	attributes := SQSResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Contains(t, attributes, semconv.MessagingBatchMessageCount(3))
+}
+
+func TestSQSSendMessageOutput(t *testing.T) {
+	output := middleware.InitializeOutput{
+		Result: &sqs.SendMessageOutput{
+			MessageId: aws.String("test-message-id"),
+		},
+	}
+
+	attributes := SQSResponseAttributeSetter(context.TODO(), middleware.InitializeInput{}, output)
+
+	assert.Contains(t, attributes, semconv.MessagingMessageID("test-message-id"))
+}
@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	smithyauth "github.com/aws/smithy-go/auth"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type sqsAuthResolver struct{}
+
+func (r *sqsAuthResolver) ResolveAuthSchemes(context.Context, *sqs.AuthResolverParameters) ([]*smithyauth.Option, error) {
+	return []*smithyauth.Option{
+		{SchemeID: smithyauth.SchemeIDAnonymous},
+	}, nil
+}
+
+type messageAttributeValue struct {
+	DataType    string
+	StringValue string
+}
+
+type sqsRequest struct {
+	MessageAttributes     map[string]messageAttributeValue
+	MessageAttributeNames []string
+}
+
+func newSQSClient(t *testing.T, respBody string) (*sqs.Client, *sqsRequest) {
+	var req sqsRequest
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			b, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			assert.NoError(t, json.Unmarshal(b, &req))
+
+			w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+			_, err = w.Write([]byte(respBody))
+			assert.NoError(t, err)
+		}))
+	t.Cleanup(srv.Close)
+
+	svc := sqs.New(sqs.Options{
+		Region:             "us-east-1",
+		BaseEndpoint:       &srv.URL,
+		AuthSchemeResolver: &sqsAuthResolver{},
+		AuthSchemes: []smithyhttp.AuthScheme{
+			smithyhttp.NewAnonymousScheme(),
+		},
+		Retryer: aws.NopRetryer{},
+	})
+	return svc, &req
+}
+
+func TestMessageAttributePropagationSend(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	svc, req := newSQSClient(t, `{"MessageId":"id"}`)
+
+	in := &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+		MessageBody: aws.String("body"),
+	}
+	_, err := svc.SendMessage(context.Background(), in, func(options *sqs.Options) {
+		otelaws.AppendMiddlewares(&options.APIOptions,
+			otelaws.WithTracerProvider(provider),
+			otelaws.WithTextMapPropagator(propagation.TraceContext{}),
+			otelaws.WithMessageAttributePropagation(),
+		)
+	})
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+
+	require.Contains(t, req.MessageAttributes, "traceparent")
+	traceparent := req.MessageAttributes["traceparent"]
+	assert.Equal(t, "String", traceparent.DataType)
+
+	carrier := propagation.MapCarrier{"traceparent": traceparent.StringValue}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	sc := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, spans[0].SpanContext().TraceID(), sc.TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), sc.SpanID())
+
+	assert.Nil(t, in.MessageAttributes, "input modified")
+}
+
+func TestMessageAttributePropagationDisabled(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	svc, req := newSQSClient(t, `{"MessageId":"id"}`)
+
+	_, err := svc.SendMessage(context.Background(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+		MessageBody: aws.String("body"),
+	}, func(options *sqs.Options) {
+		otelaws.AppendMiddlewares(&options.APIOptions,
+			otelaws.WithTracerProvider(provider),
+			otelaws.WithTextMapPropagator(propagation.TraceContext{}),
+		)
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, req.MessageAttributes)
+}
+
+func TestMessageAttributePropagationReceive(t *testing.T) {
+	const traceparent = "00-0102030405060708090a0b0c0d0e0f10-0102030405060708-01"
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	svc, req := newSQSClient(t, `{"Messages":[{"MessageId":"id","Body":"body","MessageAttributes":{"traceparent":{"DataType":"String","StringValue":"`+traceparent+`"}}}]}`)
+
+	out, err := svc.ReceiveMessage(context.Background(), &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/test-queue"),
+		MessageAttributeNames: []string{"key"},
+	}, func(options *sqs.Options) {
+		otelaws.AppendMiddlewares(&options.APIOptions,
+			otelaws.WithTracerProvider(provider),
+			otelaws.WithTextMapPropagator(propagation.TraceContext{}),
+			otelaws.WithMessageAttributePropagation(),
+		)
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"key", "traceparent", "tracestate"}, req.MessageAttributeNames)
+
+	require.Len(t, out.Messages, 1)
+	ctx := otelaws.ExtractSQSMessage(context.Background(), out.Messages[0],
+		otelaws.WithTextMapPropagator(propagation.TraceContext{}))
+	sc := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", sc.TraceID().String())
+	assert.Equal(t, "0102030405060708", sc.SpanID().String())
+	assert.True(t, sc.IsRemote())
+}
@@ -0,0 +1,129 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	const (
+		throttled = `<?xml version="1.0"?>
+		<ErrorResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+		  <Error>
+		    <Type>Sender</Type>
+		    <Code>Throttling</Code>
+		    <Message>Rate exceeded</Message>
+		  </Error>
+		  <RequestId>5fe4f2b3-0f1c-4b9c-8d2f-7f3b9f3c9e15</RequestId>
+		</ErrorResponse>`
+		changed = `<?xml version="1.0" encoding="UTF-8"?>
+		<ChangeResourceRecordSetsResponse xmlns="https://route53.amazonaws.com/doc/2013-04-01/">
+		  <ChangeInfo>
+		    <Id>/change/C2682N5HXP0BZ4</Id>
+		    <Status>PENDING</Status>
+		    <SubmittedAt>2010-09-10T01:36:41.958Z</SubmittedAt>
+		  </ChangeInfo>
+		</ChangeResourceRecordSetsResponse>`
+	)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(throttled))
+				return
+			}
+			_, _ = w.Write([]byte(changed))
+		}))
+	defer srv.Close()
+
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	svc := route53.New(route53.Options{
+		Region:             "us-east-1",
+		BaseEndpoint:       &srv.URL,
+		AuthSchemeResolver: &route53AuthResolver{},
+		AuthSchemes: []smithyhttp.AuthScheme{
+			smithyhttp.NewAnonymousScheme(),
+		},
+		Retryer: retry.NewStandard(func(o *retry.StandardOptions) {
+			o.Backoff = retry.BackoffDelayerFunc(func(int, error) (time.Duration, error) {
+				return 0, nil
+			})
+		}),
+	})
+
+	_, err := svc.ChangeResourceRecordSets(context.Background(), &route53.ChangeResourceRecordSetsInput{
+		ChangeBatch: &types.ChangeBatch{
+			Changes: []types.Change{},
+			Comment: aws.String("mock"),
+		},
+		HostedZoneId: aws.String("zone"),
+	}, func(options *route53.Options) {
+		otelaws.AppendMiddlewares(&options.APIOptions, otelaws.WithMeterProvider(provider))
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, otelaws.ScopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	want := attribute.NewSet(
+		attribute.String("rpc.system", "aws-api"),
+		attribute.String("rpc.service", "Route 53"),
+		attribute.String("aws.region", "us-east-1"),
+		attribute.String("rpc.method", "ChangeResourceRecordSets"),
+	)
+	metrics := map[string]metricdata.Aggregation{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m.Data
+	}
+
+	require.Contains(t, metrics, "rpc.client.duration")
+	duration := metrics["rpc.client.duration"].(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+	assert.Equal(t, want, duration.DataPoints[0].Attributes)
+
+	require.Contains(t, metrics, "rpc.client.request.size")
+	requestSize := metrics["rpc.client.request.size"].(metricdata.Histogram[int64])
+	require.Len(t, requestSize.DataPoints, 1)
+	assert.Positive(t, requestSize.DataPoints[0].Sum)
+
+	require.Contains(t, metrics, "rpc.client.response.size")
+	responseSize := metrics["rpc.client.response.size"].(metricdata.Histogram[int64])
+	require.Len(t, responseSize.DataPoints, 1)
+	assert.Equal(t, int64(len(changed)), responseSize.DataPoints[0].Sum)
+
+	for _, name := range []string{"aws.client.retries", "aws.client.throttles"} {
+		require.Contains(t, metrics, name)
+		sum := metrics[name].(metricdata.Sum[int64])
+		require.Len(t, sum.DataPoints, 1, name)
+		assert.Equal(t, int64(1), sum.DataPoints[0].Value, name)
+		assert.Equal(t, want, sum.DataPoints[0].Attributes, name)
+	}
+}
@@ -6,6 +6,7 @@ package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.co
 import (
 	"net/http"
 
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	oteltrace "go.opentelemetry.io/otel/trace"
 )
@@ -13,11 +14,13 @@ import (
 // config is used to configure the mux middleware.
 type config struct {
 	TracerProvider    oteltrace.TracerProvider
+	MeterProvider     metric.MeterProvider
 	Propagators       propagation.TextMapPropagator
 	spanNameFormatter func(string, *http.Request) string
 	PublicEndpoint    bool
 	PublicEndpointFn  func(*http.Request) bool
 	Filters           []Filter
+	Routes            map[string]RouteConfig
 }
 
 // Option specifies instrumentation configuration options.
@@ -76,6 +79,25 @@ func WithTracerProvider(provider oteltrace.TracerProvider) Option {
 	})
 }
 
+// WithMeterProvider specifies a meter provider to use for creating a meter.
+// If none is specified, the global provider is used.
+//
+// The middleware records the http.server.request.duration,
+// http.server.request.body.size, http.server.response.body.size and
+// http.server.active_requests metrics of the requests it traces, as defined
+// by the semantic conventions of HTTP servers, with the path template of the
+// matched route as their http.route attribute. Like the spans, they have the
+// attributes of the v1.20.0 semantic conventions, and also those of v1.26.0
+// if the OTEL_HTTP_CLIENT_COMPATIBILITY_MODE environment variable is set to
+// http/dup.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
 // WithSpanNameFormatter specifies a function to use for generating a custom span
 // name. By default, the route name (path template or regexp) is used. The route
 // name is provided so you can use it in the span name without needing to
@@ -3,6 +3,6 @@
 
 // Package otelmux instruments the github.com/gorilla/mux package.
 //
-// Currently only the routing of a received message can be instrumented. To do
-// it, use the Middleware function.
+// Currently only the routing of a received message can be instrumented, with
+// spans and metrics. To do it, use the Middleware function.
 package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
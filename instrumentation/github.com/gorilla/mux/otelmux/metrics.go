@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux/internal/semconvutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// serverMetrics are the instruments of the metrics of the requests served by
+// the middleware, as defined by the semantic conventions of HTTP servers.
+type serverMetrics struct {
+	duration       metric.Float64Histogram
+	requestSize    metric.Int64Histogram
+	responseSize   metric.Int64Histogram
+	activeRequests metric.Int64UpDownCounter
+}
+
+func newServerMetrics(meter metric.Meter) *serverMetrics {
+	m := &serverMetrics{}
+	var err error
+	m.duration, err = meter.Float64Histogram(
+		"http.server.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of HTTP server requests."),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.075, 0.1, 0.25, 0.5, 0.75, 1, 2.5, 5, 7.5, 10),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.duration = noop.Float64Histogram{}
+	}
+
+	m.requestSize, err = meter.Int64Histogram(
+		"http.server.request.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server request bodies."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.requestSize = noop.Int64Histogram{}
+	}
+
+	m.responseSize, err = meter.Int64Histogram(
+		"http.server.response.body.size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Size of HTTP server response bodies."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.responseSize = noop.Int64Histogram{}
+	}
+
+	m.activeRequests, err = meter.Int64UpDownCounter(
+		"http.server.active_requests",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Number of active HTTP server requests."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.activeRequests = noop.Int64UpDownCounter{}
+	}
+	return m
+}
+
+// semconvDuplicate reports whether the attributes of the v1.26.0 semantic
+// conventions are recorded in addition to the v1.20.0 ones, as set by the
+// OTEL_HTTP_CLIENT_COMPATIBILITY_MODE=http/dup environment variable, like
+// otelhttp does.
+func semconvDuplicate() bool {
+	return strings.ToLower(os.Getenv("OTEL_HTTP_CLIENT_COMPATIBILITY_MODE")) == "http/dup"
+}
+
+// methodAttr returns the http.request.method attribute of method. Methods
+// not defined by RFC 9110 or RFC 5789 are reported as _OTHER.
+func methodAttr(method string) attribute.KeyValue {
+	switch method {
+	case http.MethodConnect:
+		return semconvNew.HTTPRequestMethodConnect
+	case http.MethodDelete:
+		return semconvNew.HTTPRequestMethodDelete
+	case "", http.MethodGet:
+		return semconvNew.HTTPRequestMethodGet
+	case http.MethodHead:
+		return semconvNew.HTTPRequestMethodHead
+	case http.MethodOptions:
+		return semconvNew.HTTPRequestMethodOptions
+	case http.MethodPatch:
+		return semconvNew.HTTPRequestMethodPatch
+	case http.MethodPost:
+		return semconvNew.HTTPRequestMethodPost
+	case http.MethodPut:
+		return semconvNew.HTTPRequestMethodPut
+	case http.MethodTrace:
+		return semconvNew.HTTPRequestMethodTrace
+	}
+	return semconvNew.HTTPRequestMethodOther
+}
+
+// dupRequestAttrs returns the v1.26.0 attributes of the method, scheme and
+// protocol version of r, recorded on spans and metrics in the
+// OTEL_HTTP_CLIENT_COMPATIBILITY_MODE=http/dup mode.
+func dupRequestAttrs(r *http.Request) []attribute.KeyValue {
+	attrs := dupActiveRequestAttrs(r)
+	switch r.ProtoMajor {
+	case 1:
+		attrs = append(attrs, semconvNew.NetworkProtocolVersion("1."+strconv.Itoa(r.ProtoMinor)))
+	case 2, 3:
+		attrs = append(attrs, semconvNew.NetworkProtocolVersion(strconv.Itoa(r.ProtoMajor)))
+	}
+	return attrs
+}
+
+func dupActiveRequestAttrs(r *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{methodAttr(r.Method), semconvNew.URLScheme(scheme(r))}
+}
+
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// activeRequestAttrs returns the attributes of the active requests metric
+// of r: the http.method and http.scheme attributes of the v1.20.0 semantic
+// conventions, as recorded on spans, and their v1.26.0 counterparts if
+// duplicate is true.
+func activeRequestAttrs(r *http.Request, duplicate bool) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(methodAttr(r.Method).Value.AsString()),
+		semconv.HTTPSchemeKey.String(scheme(r)),
+	}
+	if duplicate {
+		attrs = append(attrs, dupActiveRequestAttrs(r)...)
+	}
+	return attrs
+}
+
+// requestMetricAttrs returns the attributes of the duration and size metrics
+// of r, received by service and matching route, whose response has the
+// status code status. They are the attributes of the v1.20.0 semantic
+// conventions, as recorded on spans, and their v1.26.0 counterparts if
+// duplicate is true.
+func requestMetricAttrs(service string, r *http.Request, route string, status int, duplicate bool) []attribute.KeyValue {
+	attrs := append(semconvutil.HTTPServerRequestMetrics(service, r), semconv.HTTPStatusCode(status))
+	if route != "" {
+		attrs = append(attrs, semconv.HTTPRoute(route))
+	}
+	if duplicate {
+		attrs = append(attrs, dupRequestAttrs(r)...)
+		attrs = append(attrs, semconvNew.HTTPResponseStatusCode(status))
+	}
+	return attrs
+}
+
+// countingReader counts the bytes read from a request body.
+type countingReader struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	return n, err
+}
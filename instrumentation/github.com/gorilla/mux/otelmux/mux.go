@@ -7,14 +7,18 @@ import (
 	"fmt"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/felixge/httpsnoop"
 	"github.com/gorilla/mux"
 
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux/internal/semconvutil"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -38,6 +42,14 @@ func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 		ScopeName,
 		trace.WithInstrumentationVersion(Version()),
 	)
+	if cfg.MeterProvider == nil {
+		cfg.MeterProvider = otel.GetMeterProvider()
+	}
+	meter := cfg.MeterProvider.Meter(
+		ScopeName,
+		metric.WithInstrumentationVersion(Version()),
+	)
+	metrics := newServerMetrics(meter)
 	if cfg.Propagators == nil {
 		cfg.Propagators = otel.GetTextMapPropagator()
 	}
@@ -49,12 +61,15 @@ func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 		return traceware{
 			service:           service,
 			tracer:            tracer,
+			metrics:           metrics,
 			propagators:       cfg.Propagators,
 			handler:           handler,
 			spanNameFormatter: cfg.spanNameFormatter,
 			publicEndpoint:    cfg.PublicEndpoint,
 			publicEndpointFn:  cfg.PublicEndpointFn,
 			filters:           cfg.Filters,
+			routes:            cfg.Routes,
+			semconvDuplicate:  semconvDuplicate(),
 		}
 	}
 }
@@ -62,18 +77,23 @@ func Middleware(service string, opts ...Option) mux.MiddlewareFunc {
 type traceware struct {
 	service           string
 	tracer            trace.Tracer
+	metrics           *serverMetrics
 	propagators       propagation.TextMapPropagator
 	handler           http.Handler
 	spanNameFormatter func(string, *http.Request) string
 	publicEndpoint    bool
 	publicEndpointFn  func(*http.Request) bool
 	filters           []Filter
+	routes            map[string]RouteConfig
+	semconvDuplicate  bool
 }
 
 type recordingResponseWriter struct {
 	writer  http.ResponseWriter
 	written bool
 	status  int
+	// size is the number of bytes of the response body written.
+	size int64
 }
 
 var rrwPool = &sync.Pool{
@@ -86,13 +106,16 @@ func getRRW(writer http.ResponseWriter) *recordingResponseWriter {
 	rrw := rrwPool.Get().(*recordingResponseWriter)
 	rrw.written = false
 	rrw.status = http.StatusOK
+	rrw.size = 0
 	rrw.writer = httpsnoop.Wrap(writer, httpsnoop.Hooks{
 		Write: func(next httpsnoop.WriteFunc) httpsnoop.WriteFunc {
 			return func(b []byte) (int, error) {
 				if !rrw.written {
 					rrw.written = true
 				}
-				return next(b)
+				n, err := next(b)
+				rrw.size += int64(n)
+				return n, err
 			}
 		},
 		WriteHeader: func(next httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
@@ -126,10 +149,20 @@ func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	route := mux.CurrentRoute(r)
+	rc := routeConfig(tw.routes, route)
+	if !rc.traced(r) {
+		tw.handler.ServeHTTP(w, r)
+		return
+	}
+
+	requestStartTime := time.Now()
+	activeAttrs := metric.WithAttributeSet(attribute.NewSet(activeRequestAttrs(r, tw.semconvDuplicate)...))
+	tw.metrics.activeRequests.Add(r.Context(), 1, activeAttrs)
+	defer tw.metrics.activeRequests.Add(r.Context(), -1, activeAttrs)
 
 	ctx := tw.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 	routeStr := ""
-	route := mux.CurrentRoute(r)
 	if route != nil {
 		var err error
 		routeStr, err = route.GetPathTemplate()
@@ -145,6 +178,12 @@ func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		trace.WithAttributes(semconvutil.HTTPServerRequest(tw.service, r)...),
 		trace.WithSpanKind(trace.SpanKindServer),
 	}
+	if tw.semconvDuplicate {
+		opts = append(opts, trace.WithAttributes(dupRequestAttrs(r)...))
+	}
+	if len(rc.Attributes) > 0 {
+		opts = append(opts, trace.WithAttributes(rc.Attributes...))
+	}
 
 	if tw.publicEndpoint || (tw.publicEndpointFn != nil && tw.publicEndpointFn(r.WithContext(ctx))) {
 		opts = append(opts, trace.WithNewRoot())
@@ -154,6 +193,7 @@ func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	metricRoute := routeStr
 	if routeStr == "" {
 		routeStr = fmt.Sprintf("HTTP %s route not found", r.Method)
 	} else {
@@ -164,11 +204,29 @@ func (tw traceware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	ctx, span := tw.tracer.Start(ctx, spanName, opts...)
 	defer span.End()
 	r2 := r.WithContext(ctx)
+	var body *countingReader
+	if r2.Body != nil && r2.Body != http.NoBody {
+		body = &countingReader{ReadCloser: r2.Body}
+		r2.Body = body
+	}
 	rrw := getRRW(w)
 	defer putRRW(rrw)
 	tw.handler.ServeHTTP(rrw.writer, r2)
 	if rrw.status > 0 {
 		span.SetAttributes(semconv.HTTPStatusCode(rrw.status))
+		if tw.semconvDuplicate {
+			span.SetAttributes(semconvNew.HTTPResponseStatusCode(rrw.status))
+		}
 	}
 	span.SetStatus(semconvutil.HTTPServerStatus(rrw.status))
+
+	metricAttrs := append(requestMetricAttrs(tw.service, r, metricRoute, rrw.status, tw.semconvDuplicate), rc.Attributes...)
+	o := metric.WithAttributeSet(attribute.NewSet(metricAttrs...))
+	var requestSize int64
+	if body != nil {
+		requestSize = body.n.Load()
+	}
+	tw.metrics.requestSize.Record(ctx, requestSize, o)
+	tw.metrics.responseSize.Record(ctx, rrw.size, o)
+	tw.metrics.duration.Record(ctx, time.Since(requestStartTime).Seconds(), o)
 }
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmux // import "go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RouteConfig configures the instrumentation of the requests matching a
+// named route, see WithRouteConfig.
+type RouteConfig struct {
+	// Disabled disables the tracing and metrics of the requests of the
+	// route, e.g. of a /metrics endpoint.
+	Disabled bool
+	// Filters are used in addition to the filters set by WithFilter. All of
+	// them must allow a request of the route for it to be traced.
+	Filters []Filter
+	// Attributes are added to the span and metrics of the requests of the
+	// route. They should have a small set of values, not to increase the
+	// cardinality of the metrics.
+	Attributes []attribute.KeyValue
+}
+
+// traced reports whether the request r of the route configured by rc is
+// traced.
+func (rc RouteConfig) traced(r *http.Request) bool {
+	if rc.Disabled {
+		return false
+	}
+	for _, f := range rc.Filters {
+		if !f(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// routeConfig returns the RouteConfig of route, which is empty if route is
+// nil, unnamed or not configured.
+func routeConfig(routes map[string]RouteConfig, route *mux.Route) RouteConfig {
+	if route == nil || route.GetName() == "" {
+		return RouteConfig{}
+	}
+	return routes[route.GetName()]
+}
+
+// WithRouteConfig configures the instrumentation of the requests matching
+// the route named name, see mux.Route.Name, with rc, e.g. to disable the
+// tracing of a /metrics route, or to add the team owning a route to the
+// attributes of its spans and metrics:
+//
+//	router.Handle("/metrics", promhttp.Handler()).Name("metrics")
+//	router.Use(otelmux.Middleware("server",
+//		otelmux.WithRouteConfig("metrics", otelmux.RouteConfig{Disabled: true}),
+//	))
+//
+// If WithRouteConfig is used several times with the same name, the last
+// configuration is used.
+func WithRouteConfig(name string, rc RouteConfig) Option {
+	return optionFunc(func(c *config) {
+		if c.Routes == nil {
+			c.Routes = make(map[string]RouteConfig)
+		}
+		c.Routes[name] = rc
+	})
+}
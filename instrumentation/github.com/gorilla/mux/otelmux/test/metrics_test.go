@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+)
+
+func TestMetrics(t *testing.T) {
+	testCases := []struct {
+		name        string
+		duplicate   bool
+		attrs       []attribute.KeyValue
+		activeAttrs []attribute.KeyValue
+	}{
+		{
+			name: "default",
+			attrs: []attribute.KeyValue{
+				attribute.String("http.method", "POST"),
+				attribute.String("http.scheme", "http"),
+				attribute.String("net.host.name", "foobar"),
+				attribute.String("net.protocol.name", "http"),
+				attribute.String("net.protocol.version", "1.1"),
+				attribute.Int("http.status_code", http.StatusCreated),
+				attribute.String("http.route", "/user/{id}"),
+			},
+			activeAttrs: []attribute.KeyValue{
+				attribute.String("http.method", "POST"),
+				attribute.String("http.scheme", "http"),
+			},
+		},
+		{
+			name:      "duplicate",
+			duplicate: true,
+			attrs: []attribute.KeyValue{
+				attribute.String("http.method", "POST"),
+				attribute.String("http.scheme", "http"),
+				attribute.String("net.host.name", "foobar"),
+				attribute.String("net.protocol.name", "http"),
+				attribute.String("net.protocol.version", "1.1"),
+				attribute.Int("http.status_code", http.StatusCreated),
+				attribute.String("http.route", "/user/{id}"),
+				attribute.String("http.request.method", "POST"),
+				attribute.String("url.scheme", "http"),
+				attribute.String("network.protocol.version", "1.1"),
+				attribute.Int("http.response.status_code", http.StatusCreated),
+			},
+			activeAttrs: []attribute.KeyValue{
+				attribute.String("http.method", "POST"),
+				attribute.String("http.scheme", "http"),
+				attribute.String("http.request.method", "POST"),
+				attribute.String("url.scheme", "http"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.duplicate {
+				t.Setenv("OTEL_HTTP_CLIENT_COMPATIBILITY_MODE", "http/dup")
+			}
+			testMetrics(t, attribute.NewSet(tc.attrs...), attribute.NewSet(tc.activeAttrs...))
+		})
+	}
+}
+
+func testMetrics(t *testing.T, attrs, activeAttrs attribute.Set) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware("foobar", otelmux.WithMeterProvider(provider)))
+	router.HandleFunc("/user/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, "created")
+	})
+
+	r := httptest.NewRequest(http.MethodPost, "/user/123", strings.NewReader("payload"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	sm := rm.ScopeMetrics[0]
+	assert.Equal(t, otelmux.ScopeName, sm.Scope.Name)
+	assert.Equal(t, otelmux.Version(), sm.Scope.Version)
+
+	require.Len(t, sm.Metrics, 4)
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range sm.Metrics {
+		metrics[m.Name] = m
+	}
+
+	duration, ok := metrics["http.server.request.duration"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, duration.DataPoints, 1)
+	assert.Equal(t, "s", metrics["http.server.request.duration"].Unit)
+	assert.Equal(t, attrs, duration.DataPoints[0].Attributes)
+	assert.Equal(t, uint64(1), duration.DataPoints[0].Count)
+
+	for _, name := range []string{"http.server.request.body.size", "http.server.response.body.size"} {
+		size, ok := metrics[name].Data.(metricdata.Histogram[int64])
+		require.True(t, ok, name)
+		require.Len(t, size.DataPoints, 1, name)
+		assert.Equal(t, "By", metrics[name].Unit)
+		assert.Equal(t, attrs, size.DataPoints[0].Attributes, name)
+		assert.Equal(t, int64(7), size.DataPoints[0].Sum, name)
+	}
+
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "http.server.active_requests",
+		Description: "Number of active HTTP server requests.",
+		Unit:        "{request}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			DataPoints:  []metricdata.DataPoint[int64]{{Attributes: activeAttrs, Value: 0}},
+		},
+	}, metrics["http.server.active_requests"], metricdatatest.IgnoreTimestamp())
+}
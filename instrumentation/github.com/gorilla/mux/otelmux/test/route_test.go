@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRouteConfig(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	team := attribute.String("team", "payments")
+
+	router := mux.NewRouter()
+	router.Use(otelmux.Middleware("foobar",
+		otelmux.WithTracerProvider(provider),
+		otelmux.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelmux.WithRouteConfig("metrics", otelmux.RouteConfig{Disabled: true}),
+		otelmux.WithRouteConfig("payments", otelmux.RouteConfig{
+			Filters:    []otelmux.Filter{func(r *http.Request) bool { return r.Method != http.MethodHead }},
+			Attributes: []attribute.KeyValue{team},
+		}),
+	))
+	handler := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/metrics", handler).Name("metrics")
+	router.HandleFunc("/payments/{id}", handler).Name("payments")
+	router.HandleFunc("/users/{id}", handler)
+
+	for _, r := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/metrics", nil),
+		httptest.NewRequest(http.MethodHead, "/payments/1", nil),
+		httptest.NewRequest(http.MethodGet, "/payments/1", nil),
+		httptest.NewRequest(http.MethodGet, "/users/1", nil),
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "/payments/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), team)
+	assert.Equal(t, "/users/{id}", spans[1].Name())
+	assert.NotContains(t, spans[1].Attributes(), team)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.request.duration" {
+			continue
+		}
+		hist, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		require.Len(t, hist.DataPoints, 2)
+		for _, dp := range hist.DataPoints {
+			route, _ := dp.Attributes.Value("http.route")
+			v, ok := dp.Attributes.Value(team.Key)
+			assert.Equal(t, route.AsString() == "/payments/{id}", ok, route.AsString())
+			if ok {
+				assert.Equal(t, team.Value, v)
+			}
+		}
+	}
+}
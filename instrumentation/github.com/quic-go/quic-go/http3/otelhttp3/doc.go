@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otelhttp3 instruments the HTTP/3 client of the
+// github.com/quic-go/quic-go/http3 package.
+//
+// Requests made with the http3.Transport wrapped by NewTransport are
+// recorded with the spans and metrics of the otelhttp Transport, and the
+// QUIC handshakes of their connections are recorded as span events.
+package otelhttp3 // import "go.opentelemetry.io/contrib/instrumentation/github.com/quic-go/quic-go/http3/otelhttp3"
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/github.com/quic-go/quic-go/http3/otelhttp3"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+// newServer starts an HTTP/3 server serving h and returns its URL and the
+// TLS configuration of clients trusting its certificate.
+func newServer(t *testing.T, h http.Handler) (string, *tls.Config) {
+	t.Helper()
+
+	// The certificate of an httptest server is reused.
+	ts := httptest.NewTLSServer(h)
+	ts.Close()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &http3.Server{Handler: h, TLSConfig: http3.ConfigureTLSConfig(ts.TLS)}
+	go func() { _ = srv.Serve(conn) }()
+	t.Cleanup(func() {
+		_ = srv.Close()
+		_ = conn.Close()
+	})
+
+	clientTLS := ts.Client().Transport.(*http.Transport).TLSClientConfig
+	return "https://" + conn.LocalAddr().String(), clientTLS
+}
+
+func TestTransport(t *testing.T) {
+	url, clientTLS := newServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "hello")
+	}))
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	base := &http3.Transport{TLSClientConfig: clientTLS}
+	defer base.Close()
+	c := http.Client{Transport: otelhttp3.NewTransport(base, otelhttp.WithTracerProvider(provider))}
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(url)
+		require.NoError(t, err)
+		b, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		assert.Equal(t, "hello", string(b))
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Contains(t, span.Attributes(), semconv.NetProtocolVersion("3"))
+		assert.Contains(t, span.Attributes(), semconv.HTTPStatusCode(http.StatusOK))
+	}
+
+	// Only the first request dials the connection.
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+	assert.Equal(t, "quic.handshake.start", events[0].Name)
+	assert.Equal(t, "quic.handshake.done", events[1].Name)
+	var duration bool
+	for _, kv := range events[1].Attributes {
+		if kv.Key == otelhttp3.HandshakeDurationKey {
+			duration = true
+			assert.Positive(t, kv.Value.AsFloat64())
+		}
+	}
+	assert.True(t, duration, "missing handshake duration")
+	assert.Contains(t, events[1].Attributes, otelhttp3.Used0RTTKey.Bool(false))
+	assert.Empty(t, spans[1].Events())
+}
+
+func TestTransportDialError(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	base := &http3.Transport{
+		Dial: func(context.Context, string, *tls.Config, *quic.Config) (quic.EarlyConnection, error) {
+			return nil, assert.AnError
+		},
+	}
+	c := http.Client{Transport: otelhttp3.NewTransport(base, otelhttp.WithTracerProvider(provider))}
+
+	_, err := c.Get("https://localhost:1")
+	require.ErrorIs(t, err, assert.AnError)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	var names []string
+	for _, e := range spans[0].Events() {
+		names = append(names, e.Name)
+	}
+	assert.Equal(t, []string{"quic.handshake.start", "quic.handshake.done", "exception"}, names)
+}
@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test // import "go.opentelemetry.io/contrib/instrumentation/github.com/quic-go/quic-go/http3/otelhttp3/test"
+
+// Version is the current release version of the quic-go/http3 instrumentation test module.
+func Version() string {
+	return "0.53.0"
+	// This string is updated by the pre_release.sh script during release
+}
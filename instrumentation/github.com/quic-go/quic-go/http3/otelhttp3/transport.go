@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp3 // import "go.opentelemetry.io/contrib/instrumentation/github.com/quic-go/quic-go/http3/otelhttp3"
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Names of the events recording the QUIC handshake of the connection of a
+// request.
+const (
+	handshakeStartEventName = "quic.handshake.start"
+	handshakeDoneEventName  = "quic.handshake.done"
+)
+
+// Attribute keys of the events recording the QUIC handshake of the
+// connection of a request.
+const (
+	// HandshakeDurationKey is the duration in seconds of the QUIC
+	// handshake, from dialing the server to the handshake completing.
+	HandshakeDurationKey = attribute.Key("quic.handshake.duration")
+	// Used0RTTKey is whether the request was sent with 0-RTT data on a
+	// resumed connection.
+	Used0RTTKey = attribute.Key("quic.handshake.used_0rtt")
+)
+
+// dialFunc is the signature of the Dial field of http3.Transport.
+type dialFunc func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error)
+
+// NewTransport returns an otelhttp.Transport making the requests of base,
+// an HTTP/3 transport, with the spans and metrics of otelhttp.NewTransport
+// configured by opts. The responses are received with HTTP/3, so
+// their spans record a protocol version of 3.
+//
+// The QUIC handshake of the connection dialed for a request is recorded by
+// the quic.handshake.start and quic.handshake.done events of its span, the
+// latter with the duration of the handshake. To do it, NewTransport sets
+// the Dial field of base to a function wrapping the previous one, or
+// quic.DialAddrEarly if it is nil. Requests sent on connections already
+// established have no handshake events.
+//
+// If base is nil, a new http3.Transport is used.
+func NewTransport(base *http3.Transport, opts ...otelhttp.Option) *otelhttp.Transport {
+	if base == nil {
+		base = &http3.Transport{}
+	}
+	dial := dialFunc(base.Dial)
+	if dial == nil {
+		dial = quic.DialAddrEarly
+	}
+	base.Dial = traceHandshake(dial)
+	return otelhttp.NewTransport(base, opts...)
+}
+
+// traceHandshake returns a dial function dialing with dial and recording
+// the QUIC handshake as events of the span of the context of the request
+// the connection is dialed for.
+func traceHandshake(dial dialFunc) dialFunc {
+	return func(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return dial(ctx, addr, tlsCfg, cfg)
+		}
+
+		start := time.Now()
+		span.AddEvent(handshakeStartEventName, trace.WithTimestamp(start), trace.WithAttributes(serverAttrs(addr)...))
+		conn, err := dial(ctx, addr, tlsCfg, cfg)
+		if err != nil {
+			// The error is recorded by otelhttp, as the error of the
+			// request.
+			span.AddEvent(handshakeDoneEventName, trace.WithAttributes(
+				HandshakeDurationKey.Float64(time.Since(start).Seconds()),
+			))
+			return conn, err
+		}
+
+		// An early connection is returned before the handshake completes if
+		// 0-RTT data can be sent.
+		select {
+		case <-conn.HandshakeComplete():
+			handshakeDone(span, conn, start)
+		default:
+			go func() {
+				select {
+				case <-conn.HandshakeComplete():
+					handshakeDone(span, conn, start)
+				case <-conn.Context().Done():
+				}
+			}()
+		}
+		return conn, nil
+	}
+}
+
+// handshakeDone records the completed handshake of conn, started at start,
+// as an event of span.
+func handshakeDone(span trace.Span, conn quic.EarlyConnection, start time.Time) {
+	now := time.Now()
+	state := conn.ConnectionState()
+	attrs := []attribute.KeyValue{
+		HandshakeDurationKey.Float64(now.Sub(start).Seconds()),
+		Used0RTTKey.Bool(state.Used0RTT),
+	}
+	if addr, ok := conn.RemoteAddr().(*net.UDPAddr); ok {
+		attrs = append(attrs, semconv.NetworkPeerAddress(addr.IP.String()), semconv.NetworkPeerPort(addr.Port))
+	}
+	span.AddEvent(handshakeDoneEventName, trace.WithTimestamp(now), trace.WithAttributes(attrs...))
+}
+
+// serverAttrs returns the attributes of the server dialed at addr.
+func serverAttrs(addr string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.ServerAddress(addr)}
+	}
+	attrs := []attribute.KeyValue{semconv.ServerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.ServerPort(p))
+	}
+	return attrs
+}
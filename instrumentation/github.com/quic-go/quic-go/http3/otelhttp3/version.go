@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp3 // import "go.opentelemetry.io/contrib/instrumentation/github.com/quic-go/quic-go/http3/otelhttp3"
+
+// Version is the current release version of the quic-go/http3 instrumentation.
+func Version() string {
+	return "0.53.0"
+	// This string is updated by the pre_release.sh script during release
+}
@@ -0,0 +1,142 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+import (
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Attribute keys of the span event recording the command document, see
+// WithCommandCapture.
+const (
+	// CommandKey is the name of the span event, and the key of the
+	// attribute holding the command document as relaxed Extended JSON.
+	CommandKey = attribute.Key("db.mongodb.command")
+	// CommandTruncatedKey is the key of the attribute set if the recorded
+	// command document was truncated, see WithCommandSizeLimit.
+	CommandTruncatedKey = attribute.Key("db.mongodb.command.truncated")
+)
+
+// DefaultCommandSizeLimit is the default maximum number of bytes of a
+// command document recorded when command capture is enabled, see
+// WithCommandCapture.
+const DefaultCommandSizeLimit = 4096
+
+// redactedValue replaces the values of redacted fields.
+const redactedValue = "REDACTED"
+
+// recordCommand adds the span event recording the command document, with the
+// values of the redacted fields replaced, to span.
+func (m *monitor) recordCommand(span trace.Span, command bson.Raw) {
+	if len(command) == 0 {
+		return
+	}
+
+	var doc bson.D
+	if err := bson.Unmarshal(command, &doc); err != nil {
+		return
+	}
+	if len(m.cfg.CommandRedactedFields) > 0 {
+		redactFields(doc, m.cfg.CommandRedactedFields)
+	}
+	b, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return
+	}
+
+	data := string(b)
+	attrs := make([]attribute.KeyValue, 0, 2)
+	if limit := m.cfg.CommandSizeLimit; limit > 0 && len(data) > limit {
+		data = trimPartialRune(data[:limit])
+		attrs = append(attrs, CommandTruncatedKey.Bool(true))
+	}
+	attrs = append(attrs, CommandKey.String(data))
+	span.AddEvent(string(CommandKey), trace.WithAttributes(attrs...))
+}
+
+// redactFields replaces the values of the fields of doc, and of its embedded
+// documents and arrays, whose name is in fields with redactedValue.
+func redactFields(doc bson.D, fields map[string]struct{}) {
+	for i, e := range doc {
+		if _, ok := fields[e.Key]; ok {
+			doc[i].Value = redactedValue
+			continue
+		}
+		redactValue(e.Value, fields)
+	}
+}
+
+func redactValue(v interface{}, fields map[string]struct{}) {
+	switch v := v.(type) {
+	case bson.D:
+		redactFields(v, fields)
+	case bson.A:
+		for _, e := range v {
+			redactValue(e, fields)
+		}
+	}
+}
+
+// trimPartialRune removes a UTF-8 encoded rune split by truncation from the
+// end of s.
+func trimPartialRune(s string) string {
+	for i := len(s) - 1; i >= 0 && i >= len(s)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(s[i]) {
+			if !utf8.FullRuneInString(s[i:]) {
+				return s[:i]
+			}
+			break
+		}
+	}
+	return s
+}
+
+// WithCommandCapture specifies that the full command document, e.g. the
+// filter of a find or the pipeline of an aggregate, is recorded as a span
+// event named after the db.mongodb.command attribute holding it as relaxed
+// Extended JSON. Unlike the db.statement attribute, see
+// WithCommandAttributeDisabled, the values of the fields named by
+// WithCommandRedactedFields are redacted, and commands exceeding the limit
+// set by WithCommandSizeLimit are truncated and the event is annotated with
+// the db.mongodb.command.truncated attribute.
+//
+// Commands may contain sensitive data, command capture is disabled by
+// default. The driver does not report the documents of security-sensitive
+// commands, such as authentication.
+func WithCommandCapture() Option {
+	return optionFunc(func(cfg *config) {
+		cfg.CommandCapture = true
+	})
+}
+
+// WithCommandSizeLimit sets the maximum number of bytes of a command
+// document recorded by WithCommandCapture. If n is less than or equal to
+// zero, commands are recorded in full. The default is
+// DefaultCommandSizeLimit.
+func WithCommandSizeLimit(n int) Option {
+	return optionFunc(func(cfg *config) {
+		cfg.CommandSizeLimit = n
+	})
+}
+
+// WithCommandRedactedFields specifies the names of the fields whose values
+// are replaced with "REDACTED" in the command documents recorded by
+// WithCommandCapture, at any depth, e.g. "ssn" redacts the ssn field of the
+// inserted documents and of the filters matching it. Passing this option
+// multiple times adds all the names.
+func WithCommandRedactedFields(names ...string) Option {
+	return optionFunc(func(cfg *config) {
+		if cfg.CommandRedactedFields == nil {
+			cfg.CommandRedactedFields = make(map[string]struct{}, len(names))
+		}
+		for _, n := range names {
+			cfg.CommandRedactedFields[n] = struct{}{}
+		}
+	})
+}
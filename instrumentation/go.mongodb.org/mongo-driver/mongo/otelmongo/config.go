@@ -5,6 +5,7 @@ package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mong
 
 import (
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -17,14 +18,24 @@ type config struct {
 
 	Tracer trace.Tracer
 
+	MeterProvider metric.MeterProvider
+
+	Meter metric.Meter
+
 	CommandAttributeDisabled bool
+
+	CommandCapture        bool
+	CommandSizeLimit      int
+	CommandRedactedFields map[string]struct{}
 }
 
 // newConfig returns a config with all Options set.
 func newConfig(opts ...Option) config {
 	cfg := config{
 		TracerProvider:           otel.GetTracerProvider(),
+		MeterProvider:            otel.GetMeterProvider(),
 		CommandAttributeDisabled: true,
+		CommandSizeLimit:         DefaultCommandSizeLimit,
 	}
 	for _, opt := range opts {
 		opt.apply(&cfg)
@@ -34,6 +45,10 @@ func newConfig(opts ...Option) config {
 		ScopeName,
 		trace.WithInstrumentationVersion(Version()),
 	)
+	cfg.Meter = cfg.MeterProvider.Meter(
+		ScopeName,
+		metric.WithInstrumentationVersion(Version()),
+	)
 	return cfg
 }
 
@@ -58,6 +73,17 @@ func WithTracerProvider(provider trace.TracerProvider) Option {
 	})
 }
 
+// WithMeterProvider specifies a meter provider to use for creating a meter
+// recording the connection pool metrics, see NewPoolMonitor. If none is
+// specified, the global provider is used.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return optionFunc(func(cfg *config) {
+		if provider != nil {
+			cfg.MeterProvider = provider
+		}
+	})
+}
+
 // WithCommandAttributeDisabled specifies if the MongoDB command is added as an attribute to Spans or not.
 // This is disabled by default and the MongoDB command will not be added as an attribute
 // to Spans if this option is not provided.
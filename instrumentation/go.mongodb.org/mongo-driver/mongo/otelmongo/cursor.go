@@ -0,0 +1,215 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+import (
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Attribute keys of the spans of the commands opening and iterating a
+// cursor, e.g. a find and its getMores.
+const (
+	// CursorIDKey is the ID of the cursor opened or iterated by the command.
+	CursorIDKey = attribute.Key("db.mongodb.cursor_id")
+	// CursorDocumentsKey is the number of documents fetched by the cursor,
+	// from the command opening it up to and including the command.
+	CursorDocumentsKey = attribute.Key("db.mongodb.cursor.documents")
+	// CursorBatchesKey is the number of batches fetched by the cursor, from
+	// the command opening it up to and including the command.
+	CursorBatchesKey = attribute.Key("db.mongodb.cursor.batches")
+)
+
+const (
+	// defaultCursorTimeout is the time after which the server closes an idle
+	// cursor by default, see the cursorTimeoutMillis server parameter.
+	// Cursors that are not used for that long, e.g. those abandoned without
+	// being closed, are forgotten.
+	defaultCursorTimeout = 10 * time.Minute
+	// defaultMaxCursors is the maximum number of open cursors tracked per
+	// monitor. The least recently used cursor is forgotten if it is
+	// exceeded.
+	defaultMaxCursors = 10000
+)
+
+// cursorKey identifies a cursor, whose IDs are unique per server.
+type cursorKey struct {
+	Address string
+	ID      int64
+}
+
+// cursorState is the state of an open cursor.
+type cursorState struct {
+	// spanContext is the span context of the command that opened the
+	// cursor.
+	spanContext trace.SpanContext
+	documents   int64
+	batches     int64
+	// lastUsed is the time the cursor was last opened or iterated.
+	lastUsed time.Time
+}
+
+// cursorStarted returns the key of the cursor iterated by the getMore
+// command evt, and the options linking its span to the span of the command
+// that opened the cursor. It returns false if evt is not a getMore of a
+// cursor opened by a command traced by m. The cursors closed by a
+// killCursors command are forgotten.
+func (m *monitor) cursorStarted(evt *event.CommandStartedEvent) (cursorKey, []trace.SpanStartOption, bool) {
+	addr := connectionAddress(evt.ConnectionID)
+	switch evt.CommandName {
+	case "getMore":
+		id, ok := evt.Command.Lookup("getMore").Int64OK()
+		if !ok {
+			return cursorKey{}, nil, false
+		}
+		key := cursorKey{Address: addr, ID: id}
+		m.Lock()
+		state, ok := m.cursors[key]
+		if ok {
+			state.lastUsed = time.Now()
+		}
+		m.Unlock()
+		if !ok {
+			return cursorKey{}, nil, false
+		}
+		return key, []trace.SpanStartOption{
+			trace.WithAttributes(CursorIDKey.Int64(id)),
+			trace.WithLinks(trace.Link{SpanContext: state.spanContext}),
+		}, true
+	case "killCursors":
+		ids, ok := evt.Command.Lookup("cursors").ArrayOK()
+		if !ok {
+			return cursorKey{}, nil, false
+		}
+		values, _ := ids.Values()
+		m.Lock()
+		for _, v := range values {
+			if id, ok := v.Int64OK(); ok {
+				delete(m.cursors, cursorKey{Address: addr, ID: id})
+			}
+		}
+		m.Unlock()
+	}
+	return cursorKey{}, nil, false
+}
+
+// cursorFinished records the documents and batches fetched by the command
+// whose span is span and whose reply is reply, if it opened or iterated a
+// cursor, and tracks the cursor until it is exhausted. getMore is the key
+// of the cursor iterated by the command, if isGetMore is true.
+func (m *monitor) cursorFinished(span trace.Span, evt *event.CommandFinishedEvent, reply bson.Raw, getMore cursorKey, isGetMore bool) {
+	if !isGetMore && evt.CommandName == "getMore" {
+		// The cursor was not opened by a command traced by m.
+		return
+	}
+	id, n, ok := replyCursor(reply)
+	if !ok {
+		if isGetMore {
+			m.closeCursor(getMore)
+		}
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 3)
+	m.Lock()
+	state, ok := m.cursors[getMore]
+	if isGetMore && !ok {
+		// Killed while the getMore was in flight.
+		m.Unlock()
+		return
+	}
+	if !isGetMore {
+		state = &cursorState{spanContext: span.SpanContext()}
+		if id != 0 {
+			m.trackCursor(cursorKey{Address: connectionAddress(evt.ConnectionID), ID: id}, state)
+			attrs = append(attrs, CursorIDKey.Int64(id))
+		}
+	}
+	state.documents += n
+	state.batches++
+	attrs = append(attrs,
+		CursorDocumentsKey.Int64(state.documents),
+		CursorBatchesKey.Int64(state.batches),
+	)
+	if isGetMore && id == 0 {
+		delete(m.cursors, getMore)
+	}
+	m.Unlock()
+
+	span.SetAttributes(attrs...)
+}
+
+// trackCursor tracks the cursor key opened with state until it is
+// exhausted or closed. Cursors unused for longer than the cursor timeout are
+// forgotten, as is the least recently used cursor if the number of tracked
+// cursors reaches its maximum. m must be locked.
+func (m *monitor) trackCursor(key cursorKey, state *cursorState) {
+	now := time.Now()
+	state.lastUsed = now
+	if len(m.cursors) >= m.maxCursors || now.Sub(m.cursorsSwept) >= m.cursorTimeout {
+		m.cursorsSwept = now
+		for k, s := range m.cursors {
+			if now.Sub(s.lastUsed) >= m.cursorTimeout {
+				delete(m.cursors, k)
+			}
+		}
+	}
+	if len(m.cursors) >= m.maxCursors {
+		var (
+			oldest   cursorKey
+			lastUsed time.Time
+		)
+		for k, s := range m.cursors {
+			if lastUsed.IsZero() || s.lastUsed.Before(lastUsed) {
+				oldest, lastUsed = k, s.lastUsed
+			}
+		}
+		delete(m.cursors, oldest)
+	}
+	m.cursors[key] = state
+}
+
+// closeCursor forgets the cursor key, e.g. if a getMore failed.
+func (m *monitor) closeCursor(key cursorKey) {
+	m.Lock()
+	delete(m.cursors, key)
+	m.Unlock()
+}
+
+// replyCursor returns the ID of the cursor of the command reply, zero if it
+// is exhausted, and the number of documents of its batch. It returns false
+// if the reply has no cursor.
+func replyCursor(reply bson.Raw) (id int64, n int64, ok bool) {
+	cursor, ok := reply.Lookup("cursor").DocumentOK()
+	if !ok {
+		return 0, 0, false
+	}
+	if id, ok = cursor.Lookup("id").Int64OK(); !ok {
+		return 0, 0, false
+	}
+	batch, ok := cursor.Lookup("firstBatch").ArrayOK()
+	if !ok {
+		batch, ok = cursor.Lookup("nextBatch").ArrayOK()
+	}
+	if ok {
+		values, _ := batch.Values()
+		n = int64(len(values))
+	}
+	return id, n, true
+}
+
+// connectionAddress returns the address of the server of the connection
+// connID, of the form "host:port[-N]".
+func connectionAddress(connID string) string {
+	if idx := strings.IndexByte(connID, '['); idx >= 0 {
+		return connID[:idx]
+	}
+	return connID
+}
@@ -0,0 +1,101 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// testFind runs a find on m opening the cursor id.
+func testFind(t *testing.T, m *monitor, id int64) {
+	t.Helper()
+	find, err := bson.Marshal(bson.D{{Key: "find", Value: "users"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := bson.Marshal(bson.D{
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: id},
+			{Key: "ns", Value: "test.users"},
+			{Key: "firstBatch", Value: bson.A{bson.D{{Key: "i", Value: 0}}}},
+		}},
+		{Key: "ok", Value: 1.0},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	m.Started(ctx, &event.CommandStartedEvent{
+		Command:      find,
+		DatabaseName: "test",
+		CommandName:  "find",
+		RequestID:    id,
+		ConnectionID: "localhost:27017[-1]",
+	})
+	m.Succeeded(ctx, &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{
+			CommandName:  "find",
+			DatabaseName: "test",
+			RequestID:    id,
+			ConnectionID: "localhost:27017[-1]",
+		},
+		Reply: reply,
+	})
+}
+
+// testCursors returns the IDs of the cursors tracked by m.
+func testCursors(m *monitor) map[int64]bool {
+	ids := make(map[int64]bool)
+	for k := range m.cursors {
+		ids[k.ID] = true
+	}
+	return ids
+}
+
+func TestCursorLimit(t *testing.T) {
+	m := newMonitor(newConfig(WithTracerProvider(noop.NewTracerProvider())))
+	m.maxCursors = 2
+
+	for id := int64(1); id <= 3; id++ {
+		testFind(t, m, id)
+		if state, ok := m.cursors[cursorKey{Address: "localhost:27017", ID: id}]; ok {
+			// Order the cursors by use regardless of the clock resolution.
+			state.lastUsed = time.Now().Add(time.Duration(id-3) * time.Second)
+		}
+	}
+
+	// The least recently used cursor is forgotten.
+	if got := testCursors(m); len(got) != 2 || !got[2] || !got[3] {
+		t.Errorf("tracked cursors = %v, want 2 and 3", got)
+	}
+}
+
+func TestCursorTimeout(t *testing.T) {
+	m := newMonitor(newConfig(WithTracerProvider(noop.NewTracerProvider())))
+
+	testFind(t, m, 1)
+	if got := testCursors(m); len(got) != 1 || !got[1] {
+		t.Fatalf("tracked cursors = %v, want 1", got)
+	}
+
+	// The cursor is abandoned without being closed.
+	expired := time.Now().Add(-m.cursorTimeout)
+	for _, state := range m.cursors {
+		state.lastUsed = expired
+	}
+	m.cursorsSwept = expired
+
+	testFind(t, m, 2)
+	if got := testCursors(m); len(got) != 1 || !got[2] {
+		t.Errorf("tracked cursors = %v, want 2", got)
+	}
+}
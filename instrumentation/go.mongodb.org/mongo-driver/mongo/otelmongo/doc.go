@@ -7,7 +7,8 @@
 // go.mongodb.org/mongo-driver/mongo.
 //
 // `NewMonitor` will return an event.CommandMonitor which is used to trace
-// requests.
+// requests, and `NewPoolMonitor` will return an event.PoolMonitor which is
+// used to record the metrics of the connection pools.
 //
 // This code was originally based on the following:
 // - https://github.com/DataDog/dd-trace-go/tree/02f0449efa3cb382d499fadc873957385dcb2192/contrib/go.mongodb.org/mongo-driver/mongo
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -26,8 +27,17 @@ type spanKey struct {
 
 type monitor struct {
 	sync.Mutex
-	spans map[spanKey]trace.Span
-	cfg   config
+	spans    map[spanKey]trace.Span
+	getMores map[spanKey]cursorKey
+	cursors  map[cursorKey]*cursorState
+	cfg      config
+
+	// cursorTimeout and maxCursors bound the cursors tracked, see
+	// trackCursor. cursorsSwept is the time expired cursors were last
+	// forgotten.
+	cursorTimeout time.Duration
+	maxCursors    int
+	cursorsSwept  time.Time
 }
 
 func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
@@ -55,25 +65,33 @@ func (m *monitor) Started(ctx context.Context, evt *event.CommandStartedEvent) {
 		trace.WithSpanKind(trace.SpanKindClient),
 		trace.WithAttributes(attrs...),
 	}
+	cursor, cursorOpts, isGetMore := m.cursorStarted(evt)
+	opts = append(opts, cursorOpts...)
 	_, span := m.cfg.Tracer.Start(ctx, spanName, opts...)
+	if m.cfg.CommandCapture {
+		m.recordCommand(span, evt.Command)
+	}
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
 		RequestID:    evt.RequestID,
 	}
 	m.Lock()
 	m.spans[key] = span
+	if isGetMore {
+		m.getMores[key] = cursor
+	}
 	m.Unlock()
 }
 
 func (m *monitor) Succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
-	m.Finished(&evt.CommandFinishedEvent, nil)
+	m.Finished(&evt.CommandFinishedEvent, evt.Reply, nil)
 }
 
 func (m *monitor) Failed(ctx context.Context, evt *event.CommandFailedEvent) {
-	m.Finished(&evt.CommandFinishedEvent, fmt.Errorf("%s", evt.Failure))
+	m.Finished(&evt.CommandFinishedEvent, nil, fmt.Errorf("%s", evt.Failure))
 }
 
-func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
+func (m *monitor) Finished(evt *event.CommandFinishedEvent, reply bson.Raw, err error) {
 	key := spanKey{
 		ConnectionID: evt.ConnectionID,
 		RequestID:    evt.RequestID,
@@ -83,6 +101,10 @@ func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
 	if ok {
 		delete(m.spans, key)
 	}
+	cursor, isGetMore := m.getMores[key]
+	if isGetMore {
+		delete(m.getMores, key)
+	}
 	m.Unlock()
 	if !ok {
 		return
@@ -90,6 +112,11 @@ func (m *monitor) Finished(evt *event.CommandFinishedEvent, err error) {
 
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
+		if isGetMore {
+			m.closeCursor(cursor)
+		}
+	} else {
+		m.cursorFinished(span, evt, reply, cursor, isGetMore)
 	}
 
 	span.End()
@@ -123,11 +150,7 @@ func extractCollection(evt *event.CommandStartedEvent) (string, error) {
 
 // NewMonitor creates a new mongodb event CommandMonitor.
 func NewMonitor(opts ...Option) *event.CommandMonitor {
-	cfg := newConfig(opts...)
-	m := &monitor{
-		spans: make(map[spanKey]trace.Span),
-		cfg:   cfg,
-	}
+	m := newMonitor(newConfig(opts...))
 	return &event.CommandMonitor{
 		Started:   m.Started,
 		Succeeded: m.Succeeded,
@@ -135,6 +158,18 @@ func NewMonitor(opts ...Option) *event.CommandMonitor {
 	}
 }
 
+func newMonitor(cfg config) *monitor {
+	return &monitor{
+		spans:         make(map[spanKey]trace.Span),
+		getMores:      make(map[spanKey]cursorKey),
+		cursors:       make(map[cursorKey]*cursorState),
+		cfg:           cfg,
+		cursorTimeout: defaultCursorTimeout,
+		maxCursors:    defaultMaxCursors,
+		cursorsSwept:  time.Now(),
+	}
+}
+
 func peerInfo(evt *event.CommandStartedEvent) (hostname string, port int) {
 	hostname = evt.ConnectionID
 	port = 27017
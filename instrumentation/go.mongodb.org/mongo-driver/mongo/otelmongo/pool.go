@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelmongo // import "go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Attribute keys of the connection pool metrics, as defined by the semantic
+// conventions of database client metrics.
+const (
+	// poolNameKey is the address of the server of the pool.
+	poolNameKey = attribute.Key("pool.name")
+	// stateKey is the state of the connections, idle or used.
+	stateKey = attribute.Key("state")
+	// reasonKey is the reason a connection was closed.
+	reasonKey = attribute.Key("reason")
+)
+
+var (
+	stateIdle = stateKey.String("idle")
+	stateUsed = stateKey.String("used")
+)
+
+// connState is the state of a connection of a pool.
+type connState int
+
+const (
+	// connPending is a connection being established.
+	connPending connState = iota
+	// connIdle is a connection available in the pool.
+	connIdle
+	// connUsed is a connection checked out of the pool.
+	connUsed
+)
+
+type connKey struct {
+	Address      string
+	ConnectionID uint64
+}
+
+type poolMonitor struct {
+	sync.Mutex
+	conns   map[connKey]connState
+	maxSize map[string]int64
+
+	usage      metric.Int64UpDownCounter
+	max        metric.Int64UpDownCounter
+	pending    metric.Int64UpDownCounter
+	timeouts   metric.Int64Counter
+	created    metric.Int64Counter
+	closed     metric.Int64Counter
+	waitTime   metric.Float64Histogram
+	createTime metric.Float64Histogram
+}
+
+func newPoolMonitor(meter metric.Meter) *poolMonitor {
+	m := &poolMonitor{
+		conns:   make(map[connKey]connState),
+		maxSize: make(map[string]int64),
+	}
+	var err error
+	m.usage, err = meter.Int64UpDownCounter(
+		"db.client.connections.usage",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The number of connections that are currently in state described by the state attribute."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.usage = noop.Int64UpDownCounter{}
+	}
+
+	m.max, err = meter.Int64UpDownCounter(
+		"db.client.connections.max",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The maximum number of open connections allowed."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.max = noop.Int64UpDownCounter{}
+	}
+
+	m.pending, err = meter.Int64UpDownCounter(
+		"db.client.connections.pending_requests",
+		metric.WithUnit("{request}"),
+		metric.WithDescription("The number of pending requests for an open connection, cumulative for the entire pool."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.pending = noop.Int64UpDownCounter{}
+	}
+
+	m.timeouts, err = meter.Int64Counter(
+		"db.client.connections.timeouts",
+		metric.WithUnit("{timeout}"),
+		metric.WithDescription("The number of connection timeouts that have occurred trying to obtain a connection from the pool."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.timeouts = noop.Int64Counter{}
+	}
+
+	m.created, err = meter.Int64Counter(
+		"db.client.connections.created",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The number of connections created by the pool."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.created = noop.Int64Counter{}
+	}
+
+	m.closed, err = meter.Int64Counter(
+		"db.client.connections.closed",
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("The number of connections closed by the pool."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.closed = noop.Int64Counter{}
+	}
+
+	m.waitTime, err = meter.Float64Histogram(
+		"db.client.connections.wait_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("The time it took to obtain an open connection from the pool."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.waitTime = noop.Float64Histogram{}
+	}
+
+	m.createTime, err = meter.Float64Histogram(
+		"db.client.connections.create_time",
+		metric.WithUnit("s"),
+		metric.WithDescription("The time it took to create a new connection."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		m.createTime = noop.Float64Histogram{}
+	}
+	return m
+}
+
+// Event records the metrics of the pool event evt.
+func (m *poolMonitor) Event(evt *event.PoolEvent) {
+	ctx := context.Background()
+	pool := poolNameKey.String(evt.Address)
+	attrs := metric.WithAttributes(semconv.DBSystemMongoDB, pool)
+	key := connKey{Address: evt.Address, ConnectionID: evt.ConnectionID}
+
+	switch evt.Type {
+	case event.PoolCreated:
+		if evt.PoolOptions == nil || evt.PoolOptions.MaxPoolSize == 0 {
+			return
+		}
+		size := int64(evt.PoolOptions.MaxPoolSize)
+		m.Lock()
+		m.maxSize[evt.Address] = size
+		m.Unlock()
+		m.max.Add(ctx, size, attrs)
+	case event.PoolClosedEvent:
+		m.Lock()
+		size, ok := m.maxSize[evt.Address]
+		delete(m.maxSize, evt.Address)
+		m.Unlock()
+		if ok {
+			m.max.Add(ctx, -size, attrs)
+		}
+	case event.ConnectionCreated:
+		m.Lock()
+		m.conns[key] = connPending
+		m.Unlock()
+		m.created.Add(ctx, 1, attrs)
+	case event.ConnectionReady:
+		m.setState(ctx, key, connIdle, pool)
+		m.createTime.Record(ctx, evt.Duration.Seconds(), attrs)
+	case event.ConnectionClosed:
+		m.Lock()
+		state, ok := m.conns[key]
+		delete(m.conns, key)
+		m.Unlock()
+		if ok {
+			m.addUsage(ctx, state, -1, pool)
+		}
+		m.closed.Add(ctx, 1, metric.WithAttributes(semconv.DBSystemMongoDB, pool, reasonKey.String(evt.Reason)))
+	case event.GetStarted:
+		m.pending.Add(ctx, 1, attrs)
+	case event.GetSucceeded:
+		m.pending.Add(ctx, -1, attrs)
+		m.waitTime.Record(ctx, evt.Duration.Seconds(), attrs)
+		m.setState(ctx, key, connUsed, pool)
+	case event.GetFailed:
+		m.pending.Add(ctx, -1, attrs)
+		m.waitTime.Record(ctx, evt.Duration.Seconds(), attrs)
+		if evt.Reason == event.ReasonTimedOut {
+			m.timeouts.Add(ctx, 1, attrs)
+		}
+	case event.ConnectionReturned:
+		m.setState(ctx, key, connIdle, pool)
+	}
+}
+
+// setState moves the connection key to state, updating the usage of the
+// pool named by pool.
+func (m *poolMonitor) setState(ctx context.Context, key connKey, state connState, pool attribute.KeyValue) {
+	m.Lock()
+	prev, ok := m.conns[key]
+	m.conns[key] = state
+	m.Unlock()
+	if ok && prev == state {
+		return
+	}
+	if ok {
+		m.addUsage(ctx, prev, -1, pool)
+	}
+	m.addUsage(ctx, state, 1, pool)
+}
+
+// addUsage adds incr to the usage of the connections in state of the pool
+// named by pool.
+func (m *poolMonitor) addUsage(ctx context.Context, state connState, incr int64, pool attribute.KeyValue) {
+	var s attribute.KeyValue
+	switch state {
+	case connIdle:
+		s = stateIdle
+	case connUsed:
+		s = stateUsed
+	default:
+		return
+	}
+	m.usage.Add(ctx, incr, metric.WithAttributes(semconv.DBSystemMongoDB, pool, s))
+}
+
+// NewPoolMonitor creates a new mongodb event PoolMonitor recording the
+// metrics of the connection pools of the client, as defined by the semantic
+// conventions of database client metrics: the connections in use and idle,
+// whose sum is the size of the pool, the maximum size of the pool, the
+// requests waiting for a connection and the time they wait, the timeouts,
+// and the connections created and closed.
+//
+// Of the options, only WithMeterProvider applies to the monitor.
+func NewPoolMonitor(opts ...Option) *event.PoolMonitor {
+	cfg := newConfig(opts...)
+	m := newPoolMonitor(cfg.Meter)
+	return &event.PoolMonitor{
+		Event: m.Event,
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// commandEvent returns the attributes of the span event recording command,
+// monitored with opts, or nil if there is no such event.
+func commandEvent(t *testing.T, command bson.Raw, opts ...otelmongo.Option) []attribute.KeyValue {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cm := otelmongo.NewMonitor(append(opts, otelmongo.WithTracerProvider(provider))...)
+
+	ctx := context.Background()
+	cm.Started(ctx, &event.CommandStartedEvent{
+		Command:      command,
+		DatabaseName: "test",
+		CommandName:  "find",
+		RequestID:    1,
+		ConnectionID: "localhost:27017[-1]",
+	})
+	cm.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: event.CommandFinishedEvent{
+		CommandName:  "find",
+		DatabaseName: "test",
+		RequestID:    1,
+		ConnectionID: "localhost:27017[-1]",
+	}})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	for _, e := range spans[0].Events() {
+		if e.Name == string(otelmongo.CommandKey) {
+			return e.Attributes
+		}
+	}
+	return nil
+}
+
+func TestCommandCapture(t *testing.T) {
+	command, err := bson.Marshal(bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{
+			{Key: "name", Value: "alice"},
+			{Key: "$or", Value: bson.A{
+				bson.D{{Key: "ssn", Value: "123-45-6789"}},
+				bson.D{{Key: "age", Value: 42}},
+			}},
+		}},
+		{Key: "$db", Value: "test"},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts []otelmongo.Option
+		want []attribute.KeyValue
+	}{
+		{
+			name: "Full",
+			want: []attribute.KeyValue{
+				otelmongo.CommandKey.String(`{"find":"users","filter":{"name":"alice","$or":[{"ssn":"123-45-6789"},{"age":42}]},"$db":"test"}`),
+			},
+		},
+		{
+			name: "Redacted",
+			opts: []otelmongo.Option{otelmongo.WithCommandRedactedFields("ssn"), otelmongo.WithCommandRedactedFields("name")},
+			want: []attribute.KeyValue{
+				otelmongo.CommandKey.String(`{"find":"users","filter":{"name":"REDACTED","$or":[{"ssn":"REDACTED"},{"age":42}]},"$db":"test"}`),
+			},
+		},
+		{
+			name: "Truncated",
+			opts: []otelmongo.Option{otelmongo.WithCommandSizeLimit(16)},
+			want: []attribute.KeyValue{
+				otelmongo.CommandTruncatedKey.Bool(true),
+				otelmongo.CommandKey.String(`{"find":"users",`),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, commandEvent(t, command, append(tt.opts, otelmongo.WithCommandCapture())...))
+		})
+	}
+}
+
+func TestCommandCaptureTruncatedRune(t *testing.T) {
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "ab€"}})
+	require.NoError(t, err)
+
+	// The limit splits the 3 bytes of the euro sign.
+	attrs := commandEvent(t, command, otelmongo.WithCommandCapture(), otelmongo.WithCommandSizeLimit(len(`{"find":"ab`)+1))
+	assert.Contains(t, attrs, otelmongo.CommandKey.String(`{"find":"ab`))
+}
+
+func TestCommandCaptureDisabled(t *testing.T) {
+	command, err := bson.Marshal(bson.D{{Key: "find", Value: "users"}})
+	require.NoError(t, err)
+	assert.Nil(t, commandEvent(t, command))
+}
+
+func TestCommandCaptureEmpty(t *testing.T) {
+	assert.Nil(t, commandEvent(t, nil, otelmongo.WithCommandCapture()))
+}
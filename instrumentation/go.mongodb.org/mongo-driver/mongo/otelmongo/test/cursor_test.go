@@ -0,0 +1,167 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+)
+
+const testConnectionID = "localhost:27017[-1]"
+
+func mustMarshal(t *testing.T, doc bson.D) bson.Raw {
+	t.Helper()
+	b, err := bson.Marshal(doc)
+	require.NoError(t, err)
+	return b
+}
+
+func cursorReply(t *testing.T, id int64, batch string, n int) bson.Raw {
+	t.Helper()
+	docs := make(bson.A, n)
+	for i := range docs {
+		docs[i] = bson.D{{Key: "i", Value: i}}
+	}
+	return mustMarshal(t, bson.D{
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: id},
+			{Key: "ns", Value: "test.users"},
+			{Key: batch, Value: docs},
+		}},
+		{Key: "ok", Value: 1.0},
+	})
+}
+
+func runCommand(t *testing.T, cm *event.CommandMonitor, requestID int64, name string, command, reply bson.Raw) {
+	t.Helper()
+	ctx := context.Background()
+	cm.Started(ctx, &event.CommandStartedEvent{
+		Command:      command,
+		DatabaseName: "test",
+		CommandName:  name,
+		RequestID:    requestID,
+		ConnectionID: testConnectionID,
+	})
+	finished := event.CommandFinishedEvent{
+		CommandName:  name,
+		DatabaseName: "test",
+		RequestID:    requestID,
+		ConnectionID: testConnectionID,
+	}
+	if reply == nil {
+		cm.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: "CursorNotFound"})
+		return
+	}
+	cm.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished, Reply: reply})
+}
+
+func getMore(t *testing.T, id int64) bson.Raw {
+	t.Helper()
+	return mustMarshal(t, bson.D{{Key: "getMore", Value: id}, {Key: "collection", Value: "users"}})
+}
+
+func TestCursorCorrelation(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cm := otelmongo.NewMonitor(otelmongo.WithTracerProvider(provider))
+
+	find := mustMarshal(t, bson.D{{Key: "find", Value: "users"}})
+	runCommand(t, cm, 1, "find", find, cursorReply(t, 42, "firstBatch", 3))
+	runCommand(t, cm, 2, "getMore", getMore(t, 42), cursorReply(t, 42, "nextBatch", 2))
+	runCommand(t, cm, 3, "getMore", getMore(t, 42), cursorReply(t, 0, "nextBatch", 1))
+	// The cursor is exhausted, further getMores are not linked.
+	runCommand(t, cm, 4, "getMore", getMore(t, 42), cursorReply(t, 0, "nextBatch", 0))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+
+	origin := spans[0]
+	assert.Empty(t, origin.Links())
+	assert.Contains(t, origin.Attributes(), otelmongo.CursorIDKey.Int64(42))
+	assert.Contains(t, origin.Attributes(), otelmongo.CursorDocumentsKey.Int64(3))
+	assert.Contains(t, origin.Attributes(), otelmongo.CursorBatchesKey.Int64(1))
+
+	for i, want := range []struct{ documents, batches int64 }{{5, 2}, {6, 3}} {
+		s := spans[i+1]
+		require.Len(t, s.Links(), 1)
+		assert.Equal(t, origin.SpanContext(), s.Links()[0].SpanContext)
+		assert.Contains(t, s.Attributes(), otelmongo.CursorIDKey.Int64(42))
+		assert.Contains(t, s.Attributes(), otelmongo.CursorDocumentsKey.Int64(want.documents))
+		assert.Contains(t, s.Attributes(), otelmongo.CursorBatchesKey.Int64(want.batches))
+	}
+
+	assert.Empty(t, spans[3].Links())
+	for _, kv := range spans[3].Attributes() {
+		assert.NotEqual(t, otelmongo.CursorBatchesKey, kv.Key)
+	}
+}
+
+func TestCursorSingleBatch(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	cm := otelmongo.NewMonitor(otelmongo.WithTracerProvider(provider))
+
+	aggregate := mustMarshal(t, bson.D{{Key: "aggregate", Value: "users"}})
+	runCommand(t, cm, 1, "aggregate", aggregate, cursorReply(t, 0, "firstBatch", 2))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	assert.Contains(t, attrs, otelmongo.CursorDocumentsKey.Int64(2))
+	assert.Contains(t, attrs, otelmongo.CursorBatchesKey.Int64(1))
+	for _, kv := range attrs {
+		assert.NotEqual(t, otelmongo.CursorIDKey, kv.Key)
+	}
+}
+
+func TestCursorClosed(t *testing.T) {
+	tests := []struct {
+		name  string
+		close func(t *testing.T, cm *event.CommandMonitor)
+	}{
+		{
+			name: "KillCursors",
+			close: func(t *testing.T, cm *event.CommandMonitor) {
+				kill := mustMarshal(t, bson.D{
+					{Key: "killCursors", Value: "users"},
+					{Key: "cursors", Value: bson.A{int64(42)}},
+				})
+				runCommand(t, cm, 2, "killCursors", kill, mustMarshal(t, bson.D{{Key: "ok", Value: 1.0}}))
+			},
+		},
+		{
+			name: "GetMoreFailed",
+			close: func(t *testing.T, cm *event.CommandMonitor) {
+				runCommand(t, cm, 2, "getMore", getMore(t, 42), nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			cm := otelmongo.NewMonitor(otelmongo.WithTracerProvider(provider))
+
+			find := mustMarshal(t, bson.D{{Key: "find", Value: "users"}})
+			runCommand(t, cm, 1, "find", find, cursorReply(t, 42, "firstBatch", 3))
+			tt.close(t, cm)
+			runCommand(t, cm, 3, "getMore", getMore(t, 42), cursorReply(t, 0, "nextBatch", 1))
+
+			spans := sr.Ended()
+			require.Len(t, spans, 3)
+			assert.Empty(t, spans[2].Links())
+		})
+	}
+}
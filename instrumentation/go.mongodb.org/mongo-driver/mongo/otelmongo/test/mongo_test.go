@@ -15,7 +15,6 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
-	"go.opentelemetry.io/contrib/internal/util"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -23,14 +22,21 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-func TestMain(m *testing.M) {
-	util.IntegrationShouldRun("test-mongo-driver")
-	os.Exit(m.Run())
+// integrationShouldRun skips the tests that need a MongoDB server unless
+// the INTEGRATION environment variable is set to "test-mongo-driver". The
+// other tests of the package always run.
+func integrationShouldRun(t *testing.T) {
+	t.Helper()
+	if os.Getenv("INTEGRATION") != "test-mongo-driver" {
+		t.Skip(`to enable integration test, set the INTEGRATION environment variable to "test-mongo-driver"`)
+	}
 }
 
 type validator func(sdktrace.ReadOnlySpan) bool
 
 func TestDBCrudOperation(t *testing.T) {
+	integrationShouldRun(t)
+
 	commonValidators := []validator{
 		func(s sdktrace.ReadOnlySpan) bool {
 			return assert.Equal(t, "test-collection.insert", s.Name(), "expected %s", s.Name())
@@ -143,6 +149,8 @@ func TestDBCrudOperation(t *testing.T) {
 }
 
 func TestDBCollectionAttribute(t *testing.T) {
+	integrationShouldRun(t)
+
 	tt := []struct {
 		title      string
 		operation  func(context.Context, *mongo.Database) (interface{}, error)
@@ -238,3 +246,53 @@ func TestDBCollectionAttribute(t *testing.T) {
 		})
 	}
 }
+
+func TestDBCommandCapture(t *testing.T) {
+	integrationShouldRun(t)
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	addr := "mongodb://localhost:27017/?connect=direct"
+	opts := options.Client()
+	opts.Monitor = otelmongo.NewMonitor(
+		otelmongo.WithTracerProvider(provider),
+		otelmongo.WithCommandCapture(),
+		otelmongo.WithCommandRedactedFields("ssn"),
+	)
+	opts.ApplyURI(addr)
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, err := client.Database("test-database").Collection("test-collection").Find(ctx, bson.D{
+		{Key: "name", Value: "alice"},
+		{Key: "ssn", Value: "123-45-6789"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = cursor.Close(ctx)
+
+	spans := sr.Ended()
+	if !assert.NotEmpty(t, spans) {
+		t.FailNow()
+	}
+	s := spans[0]
+	assert.Equal(t, "test-collection.find", s.Name())
+	if !assert.Len(t, s.Events(), 1) {
+		t.FailNow()
+	}
+	e := s.Events()[0]
+	assert.Equal(t, "db.mongodb.command", e.Name)
+	if !assert.Len(t, e.Attributes, 1) {
+		t.FailNow()
+	}
+	command := e.Attributes[0].Value.AsString()
+	assert.Contains(t, command, `"filter":{"name":"alice","ssn":"REDACTED"}`)
+	assert.NotContains(t, command, "123-45-6789")
+}
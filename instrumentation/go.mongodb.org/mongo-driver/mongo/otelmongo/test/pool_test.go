@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.mongodb.org/mongo-driver/event"
+
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
+)
+
+const testAddress = "localhost:27017"
+
+func collect(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Metrics {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.Equal(t, otelmongo.ScopeName, rm.ScopeMetrics[0].Scope.Name)
+
+	metrics := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+	return metrics
+}
+
+// sumValues returns the values of the data points of the sum m by their
+// state or reason attribute.
+func sumValues(t *testing.T, m metricdata.Metrics) map[string]int64 {
+	t.Helper()
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok, "%s is not an int64 sum", m.Name)
+
+	values := make(map[string]int64)
+	for _, dp := range sum.DataPoints {
+		pool, _ := dp.Attributes.Value("pool.name")
+		assert.Equal(t, testAddress, pool.AsString())
+		var k attribute.Value
+		if v, ok := dp.Attributes.Value("state"); ok {
+			k = v
+		} else if v, ok := dp.Attributes.Value("reason"); ok {
+			k = v
+		}
+		values[k.AsString()] = dp.Value
+	}
+	return values
+}
+
+func TestPoolMonitor(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	monitor := otelmongo.NewPoolMonitor(otelmongo.WithMeterProvider(mp))
+
+	for _, evt := range []event.PoolEvent{
+		{Type: event.PoolCreated, PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 100}},
+		{Type: event.ConnectionCreated, ConnectionID: 1},
+		{Type: event.ConnectionReady, ConnectionID: 1, Duration: 10 * time.Millisecond},
+		{Type: event.ConnectionCreated, ConnectionID: 2},
+		{Type: event.ConnectionReady, ConnectionID: 2, Duration: 20 * time.Millisecond},
+		{Type: event.ConnectionCreated, ConnectionID: 3},
+		{Type: event.ConnectionClosed, ConnectionID: 3, Reason: event.ReasonError},
+		{Type: event.GetStarted},
+		{Type: event.GetSucceeded, ConnectionID: 1, Duration: time.Millisecond},
+		{Type: event.GetStarted},
+		{Type: event.GetSucceeded, ConnectionID: 2, Duration: time.Millisecond},
+		{Type: event.GetStarted},
+		{Type: event.GetFailed, Reason: event.ReasonTimedOut, Duration: time.Second},
+		{Type: event.GetStarted},
+		{Type: event.ConnectionReturned, ConnectionID: 2},
+		{Type: event.ConnectionClosed, ConnectionID: 2, Reason: event.ReasonStale},
+	} {
+		evt := evt
+		evt.Address = testAddress
+		monitor.Event(&evt)
+	}
+
+	metrics := collect(t, reader)
+	assert.Equal(t, map[string]int64{"idle": 0, "used": 1}, sumValues(t, metrics["db.client.connections.usage"]))
+	assert.Equal(t, map[string]int64{"": 100}, sumValues(t, metrics["db.client.connections.max"]))
+	assert.Equal(t, map[string]int64{"": 1}, sumValues(t, metrics["db.client.connections.pending_requests"]))
+	assert.Equal(t, map[string]int64{"": 1}, sumValues(t, metrics["db.client.connections.timeouts"]))
+	assert.Equal(t, map[string]int64{"": 3}, sumValues(t, metrics["db.client.connections.created"]))
+	assert.Equal(t, map[string]int64{"error": 1, "stale": 1}, sumValues(t, metrics["db.client.connections.closed"]))
+
+	waitTime, ok := metrics["db.client.connections.wait_time"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, waitTime.DataPoints, 1)
+	assert.Equal(t, uint64(3), waitTime.DataPoints[0].Count)
+	assert.InDelta(t, 1.002, waitTime.DataPoints[0].Sum, 1e-9)
+
+	createTime, ok := metrics["db.client.connections.create_time"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, createTime.DataPoints, 1)
+	assert.Equal(t, uint64(2), createTime.DataPoints[0].Count)
+	assert.InDelta(t, 0.03, createTime.DataPoints[0].Sum, 1e-9)
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionClosed, Address: testAddress, ConnectionID: 1, Reason: event.ReasonPoolClosed})
+	monitor.Event(&event.PoolEvent{Type: event.PoolClosedEvent, Address: testAddress})
+
+	metrics = collect(t, reader)
+	assert.Equal(t, map[string]int64{"idle": 0, "used": 0}, sumValues(t, metrics["db.client.connections.usage"]))
+	assert.Equal(t, map[string]int64{"": 0}, sumValues(t, metrics["db.client.connections.max"]))
+}
@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// channelzLookupTimeout bounds the time spent looking up the channelz IDs of
+// a connection.
+const channelzLookupTimeout = 10 * time.Second
+
+// channelzLookup finds the channelz entities of a connection using the
+// channelz service.
+type channelzLookup struct {
+	cz            channelzpb.ChannelzClient
+	local, remote string
+}
+
+// resolveChannelz looks up the channelz IDs of the connection of cctx with
+// the local and remote addresses local and remote, and records them on its
+// connection span, if any. The RPC spans of the connection record them once
+// they are known, see channelzAttrs.
+func (c *config) resolveChannelz(cctx *connContext, local, remote net.Addr, isServer bool) { // nolint: revive  // isServer is not a control flag.
+	if local == nil || remote == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), channelzLookupTimeout)
+	defer cancel()
+
+	l := channelzLookup{cz: c.Channelz, local: local.String(), remote: remote.String()}
+	var attrs []attribute.KeyValue
+	var err error
+	if isServer {
+		attrs, err = l.server(ctx)
+	} else {
+		attrs, err = l.clientConn(ctx)
+	}
+	if err != nil {
+		otel.Handle(err)
+		return
+	}
+	if len(attrs) == 0 {
+		return
+	}
+
+	cctx.channelz.Store(&attrs)
+	if cctx.span != nil {
+		cctx.span.SetAttributes(attrs...)
+	}
+}
+
+// channelzAttrs returns the channelz IDs of the connection of cctx, if they
+// are known.
+func channelzAttrs(cctx *connContext) []attribute.KeyValue {
+	if cctx == nil {
+		return nil
+	}
+	if attrs := cctx.channelz.Load(); attrs != nil {
+		return *attrs
+	}
+	return nil
+}
+
+// server returns the attributes identifying the server and socket of the
+// connection of l.
+func (l channelzLookup) server(ctx context.Context) ([]attribute.KeyValue, error) {
+	for start := int64(0); ; {
+		resp, err := l.cz.GetServers(ctx, &channelzpb.GetServersRequest{StartServerId: start})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.GetServer() {
+			id := s.GetRef().GetServerId()
+			start = id + 1
+			socket, err := l.serverSocket(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			if socket != 0 {
+				return []attribute.KeyValue{
+					RPCGRPCChannelzServerIDKey.Int64(id),
+					RPCGRPCChannelzSocketIDKey.Int64(socket),
+				}, nil
+			}
+		}
+		if resp.GetEnd() || len(resp.GetServer()) == 0 {
+			return nil, nil
+		}
+	}
+}
+
+// serverSocket returns the ID of the socket of the connection of l among
+// the sockets of the server serverID, or zero if it is not found.
+func (l channelzLookup) serverSocket(ctx context.Context, serverID int64) (int64, error) {
+	for start := int64(0); ; {
+		resp, err := l.cz.GetServerSockets(ctx, &channelzpb.GetServerSocketsRequest{
+			ServerId:      serverID,
+			StartSocketId: start,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for _, ref := range resp.GetSocketRef() {
+			start = ref.GetSocketId() + 1
+			ok, err := l.matchSocket(ctx, ref.GetSocketId())
+			if err != nil || ok {
+				return ref.GetSocketId(), err
+			}
+		}
+		if resp.GetEnd() || len(resp.GetSocketRef()) == 0 {
+			return 0, nil
+		}
+	}
+}
+
+// clientConn returns the attributes identifying the top channel, subchannel
+// and socket of the connection of l.
+func (l channelzLookup) clientConn(ctx context.Context) ([]attribute.KeyValue, error) {
+	for start := int64(0); ; {
+		resp, err := l.cz.GetTopChannels(ctx, &channelzpb.GetTopChannelsRequest{StartChannelId: start})
+		if err != nil {
+			return nil, err
+		}
+		for _, ch := range resp.GetChannel() {
+			start = ch.GetRef().GetChannelId() + 1
+			attrs, err := l.channel(ctx, ch.GetChannelRef(), ch.GetSubchannelRef())
+			if err != nil {
+				return nil, err
+			}
+			if attrs != nil {
+				return append([]attribute.KeyValue{
+					RPCGRPCChannelzChannelIDKey.Int64(ch.GetRef().GetChannelId()),
+				}, attrs...), nil
+			}
+		}
+		if resp.GetEnd() || len(resp.GetChannel()) == 0 {
+			return nil, nil
+		}
+	}
+}
+
+// channel returns the attributes identifying the subchannel and socket of
+// the connection of l among the nested channels and subchannels of a
+// channel, or nil if it is not found.
+func (l channelzLookup) channel(ctx context.Context, channels []*channelzpb.ChannelRef, subchannels []*channelzpb.SubchannelRef) ([]attribute.KeyValue, error) {
+	for _, ref := range subchannels {
+		resp, err := l.cz.GetSubchannel(ctx, &channelzpb.GetSubchannelRequest{SubchannelId: ref.GetSubchannelId()})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.GetSubchannel().GetSocketRef() {
+			ok, err := l.matchSocket(ctx, s.GetSocketId())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				return []attribute.KeyValue{
+					RPCGRPCChannelzSubchannelIDKey.Int64(ref.GetSubchannelId()),
+					RPCGRPCChannelzSocketIDKey.Int64(s.GetSocketId()),
+				}, nil
+			}
+		}
+	}
+	for _, ref := range channels {
+		resp, err := l.cz.GetChannel(ctx, &channelzpb.GetChannelRequest{ChannelId: ref.GetChannelId()})
+		if err != nil {
+			return nil, err
+		}
+		ch := resp.GetChannel()
+		attrs, err := l.channel(ctx, ch.GetChannelRef(), ch.GetSubchannelRef())
+		if err != nil || attrs != nil {
+			return attrs, err
+		}
+	}
+	return nil, nil
+}
+
+// matchSocket reports whether the socket id is the connection of l.
+func (l channelzLookup) matchSocket(ctx context.Context, id int64) (bool, error) {
+	resp, err := l.cz.GetSocket(ctx, &channelzpb.GetSocketRequest{SocketId: id})
+	if err != nil {
+		return false, err
+	}
+	s := resp.GetSocket()
+	return channelzAddr(s.GetLocal()) == l.local && channelzAddr(s.GetRemote()) == l.remote, nil
+}
+
+// channelzAddr returns addr in the format of net.Addr.String.
+func channelzAddr(addr *channelzpb.Address) string {
+	if tcp := addr.GetTcpipAddress(); tcp != nil {
+		return net.JoinHostPort(net.IP(tcp.GetIpAddress()).String(), strconv.Itoa(int(tcp.GetPort())))
+	}
+	if uds := addr.GetUdsAddress(); uds != nil {
+		return uds.GetFilename()
+	}
+	return ""
+}
+
+type channelzOption struct{ client channelzpb.ChannelzClient }
+
+func (o channelzOption) apply(c *config) {
+	c.Channelz = o.client
+}
+
+// WithChannelz returns an Option that records the channelz IDs of
+// connections on their connection spans and on the spans of their RPCs, so
+// traces can be cross-referenced with channelz dumps. The IDs are looked up
+// with client, a client of the channelz service of the process, see
+// google.golang.org/grpc/channelz/service. Lookups happen in the background
+// when a connection is established and issue a channelz RPC per socket
+// searched, RPCs ending before the lookup completes do not record the IDs.
+// Lookup errors are reported to the global error handler.
+func WithChannelz(client channelzpb.ChannelzClient) Option {
+	return channelzOption{client: client}
+}
@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+)
+
+// fakeChannelz serves a channelz database with one server with sockets 10
+// and 11, and one top channel with a nested channel whose subchannel has
+// socket 20.
+type fakeChannelz struct {
+	channelzpb.ChannelzClient
+}
+
+func tcpAddress(port int32) *channelzpb.Address {
+	return &channelzpb.Address{Address: &channelzpb.Address_TcpipAddress{
+		TcpipAddress: &channelzpb.Address_TcpIpAddress{IpAddress: net.IPv4(127, 0, 0, 1).To4(), Port: port},
+	}}
+}
+
+func (fakeChannelz) GetServers(context.Context, *channelzpb.GetServersRequest, ...grpc.CallOption) (*channelzpb.GetServersResponse, error) {
+	return &channelzpb.GetServersResponse{
+		Server: []*channelzpb.Server{{Ref: &channelzpb.ServerRef{ServerId: 1}}},
+		End:    true,
+	}, nil
+}
+
+func (fakeChannelz) GetServerSockets(context.Context, *channelzpb.GetServerSocketsRequest, ...grpc.CallOption) (*channelzpb.GetServerSocketsResponse, error) {
+	return &channelzpb.GetServerSocketsResponse{
+		SocketRef: []*channelzpb.SocketRef{{SocketId: 10}, {SocketId: 11}},
+		End:       true,
+	}, nil
+}
+
+func (fakeChannelz) GetTopChannels(context.Context, *channelzpb.GetTopChannelsRequest, ...grpc.CallOption) (*channelzpb.GetTopChannelsResponse, error) {
+	return &channelzpb.GetTopChannelsResponse{
+		Channel: []*channelzpb.Channel{{
+			Ref:        &channelzpb.ChannelRef{ChannelId: 2},
+			ChannelRef: []*channelzpb.ChannelRef{{ChannelId: 3}},
+		}},
+		End: true,
+	}, nil
+}
+
+func (fakeChannelz) GetChannel(context.Context, *channelzpb.GetChannelRequest, ...grpc.CallOption) (*channelzpb.GetChannelResponse, error) {
+	return &channelzpb.GetChannelResponse{Channel: &channelzpb.Channel{
+		Ref:           &channelzpb.ChannelRef{ChannelId: 3},
+		SubchannelRef: []*channelzpb.SubchannelRef{{SubchannelId: 4}},
+	}}, nil
+}
+
+func (fakeChannelz) GetSubchannel(context.Context, *channelzpb.GetSubchannelRequest, ...grpc.CallOption) (*channelzpb.GetSubchannelResponse, error) {
+	return &channelzpb.GetSubchannelResponse{Subchannel: &channelzpb.Subchannel{
+		SocketRef: []*channelzpb.SocketRef{{SocketId: 20}},
+	}}, nil
+}
+
+func (fakeChannelz) GetSocket(_ context.Context, req *channelzpb.GetSocketRequest, _ ...grpc.CallOption) (*channelzpb.GetSocketResponse, error) {
+	sockets := map[int64]*channelzpb.Socket{
+		10: {Local: tcpAddress(8080), Remote: tcpAddress(50000)},
+		11: {Local: tcpAddress(8080), Remote: tcpAddress(50001)},
+		20: {Local: tcpAddress(50002), Remote: tcpAddress(9090)},
+	}
+	return &channelzpb.GetSocketResponse{Socket: sockets[req.GetSocketId()]}, nil
+}
+
+func tcpAddr(port int) net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+}
+
+func TestChannelzNotFound(t *testing.T) {
+	h := NewServerHandler(WithChannelz(fakeChannelz{}))
+	cctx := &connContext{}
+	h.(*serverHandler).resolveChannelz(cctx, tcpAddr(8080), tcpAddr(40000), true)
+	assert.Nil(t, channelzAttrs(cctx))
+}
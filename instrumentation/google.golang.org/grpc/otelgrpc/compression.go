@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// compressionMetrics are the instruments recording the compressed size of
+// messages and their compression ratio, from which the benefit of enabling
+// compression on an RPC method is evaluated.
+type compressionMetrics struct {
+	requestSize  metric.Int64Histogram
+	responseSize metric.Int64Histogram
+	ratio        metric.Float64Histogram
+}
+
+func newCompressionMetrics(meter metric.Meter, role string) *compressionMetrics {
+	var (
+		m   compressionMetrics
+		err error
+	)
+	m.requestSize, err = meter.Int64Histogram("rpc."+role+".request.compressed_size",
+		metric.WithDescription("Measures size of RPC request messages (compressed)."),
+		metric.WithUnit("By"))
+	if err != nil {
+		otel.Handle(err)
+		if m.requestSize == nil {
+			m.requestSize = noop.Int64Histogram{}
+		}
+	}
+
+	m.responseSize, err = meter.Int64Histogram("rpc."+role+".response.compressed_size",
+		metric.WithDescription("Measures size of RPC response messages (compressed)."),
+		metric.WithUnit("By"))
+	if err != nil {
+		otel.Handle(err)
+		if m.responseSize == nil {
+			m.responseSize = noop.Int64Histogram{}
+		}
+	}
+
+	m.ratio, err = meter.Float64Histogram("rpc."+role+".compression_ratio",
+		metric.WithDescription("Measures the ratio of the uncompressed to the compressed size of RPC messages, 1 for uncompressed messages."),
+		metric.WithUnit("1"))
+	if err != nil {
+		otel.Handle(err)
+		if m.ratio == nil {
+			m.ratio = noop.Float64Histogram{}
+		}
+	}
+	return &m
+}
+
+// record records the compressed size and the compression ratio of a message
+// received, if sent is false, or sent, whose size is compressed on the wire
+// and uncompressed once decoded. attrs are the metric attributes of the RPC.
+func (m *compressionMetrics) record(ctx context.Context, sent bool, compressed, uncompressed int, attrs []attribute.KeyValue) {
+	size, typ := m.requestSize, RPCMessageTypeReceived
+	if sent {
+		size, typ = m.responseSize, RPCMessageTypeSent
+	}
+	size.Record(ctx, int64(compressed), metric.WithAttributeSet(attribute.NewSet(attrs...)))
+	if compressed > 0 {
+		ratio := float64(uncompressed) / float64(compressed)
+		m.ratio.Record(ctx, ratio, metric.WithAttributeSet(attribute.NewSet(append(attrs, typ)...)))
+	}
+}
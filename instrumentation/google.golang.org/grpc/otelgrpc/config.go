@@ -4,15 +4,33 @@
 package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"net"
+	"path"
+	"regexp"
+	"sync"
+	"time"
+
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	grpc_codes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc/internal/redact"
 )
 
 const (
@@ -34,26 +52,124 @@ type InterceptorFilter func(*InterceptorInfo) bool
 // A Filter must return true if the request should be instrumented.
 type Filter func(*stats.RPCTagInfo) bool
 
+// ContextFilter is a Filter also given the context of the RPC, which holds
+// the incoming metadata and peer on servers and the outgoing metadata on
+// clients. A ContextFilter must return true if the request should be
+// instrumented.
+type ContextFilter func(context.Context, *stats.RPCTagInfo) bool
+
+// AttributeExtractor returns attributes to add to the span and metrics of an
+// RPC. It is called when the RPC starts with the context of the RPC, which
+// holds the incoming metadata on servers and the outgoing metadata on clients.
+type AttributeExtractor func(context.Context, *stats.RPCTagInfo) []attribute.KeyValue
+
 // config is a group of options for this instrumentation.
 type config struct {
-	Filter            Filter
-	InterceptorFilter InterceptorFilter
-	Propagators       propagation.TextMapPropagator
-	TracerProvider    trace.TracerProvider
-	MeterProvider     metric.MeterProvider
-	SpanStartOptions  []trace.SpanStartOption
+	Filter              Filter
+	TraceFilter         Filter
+	MetricFilter        Filter
+	ContextFilter       ContextFilter
+	ForceExemplars      bool
+	DisableTraces       bool
+	DisableMetrics      bool
+	InterceptorFilter   InterceptorFilter
+	Propagators         propagation.TextMapPropagator
+	GRPCTraceBin        bool
+	TracerProvider      trace.TracerProvider
+	MeterProvider       metric.MeterProvider
+	LoggerProvider      log.LoggerProvider
+	SpanStartOptions    []trace.SpanStartOption
+	SpanNameFormatter   func(fullMethod string) string
+	StatusCodeMapper    func(code grpc_codes.Code, isServer bool) (codes.Code, string)
+	PeerService         string
+	PeerServiceFunc     func(remote net.Addr) string
+	LoadBalancer        string
+	Channelz            channelzpb.ChannelzClient
+	StaticAttributes    []attribute.KeyValue
+	AttributeExtractors []AttributeExtractor
+	BaggageKeys         []string
 
 	ReceivedEvent bool
 	SentEvent     bool
 
+	PayloadMarshaler       PayloadMarshaler
+	PayloadCompactJSON     bool
+	PayloadRequestKey      attribute.Key
+	PayloadResponseKey     attribute.Key
+	PayloadCapture         bool
+	PayloadCaptureReceived bool
+	PayloadCaptureSent     bool
+	PayloadHash            bool
+	PayloadWireFormat      bool
+	PayloadOnError         bool
+	PayloadSizeLimit       int
+	RuntimeControl         *RuntimeControl
+	PayloadFieldMasks      map[protoreflect.FullName]payloadFieldMask
+	PayloadAllow           []string
+	PayloadDeny            []string
+	PayloadSampling        float64
+	PayloadScrubPaths      []redact.JSONPath
+	PayloadScrubRegexps    []*regexp.Regexp
+	PayloadSink            PayloadSink
+	ReplayWriter           ReplayWriter
+	ReplayFailedOnly       bool
+	ReplayCredentials      bool
+
+	MaxCapturedMessages  int
+	LastCapturedMessages int
+
+	MetadataAttributes []string
+
+	ConnectionSpans   bool
+	MessageSpans      bool
+	MessageCorrelator func(payload any) string
+
+	StreamProgressInterval time.Duration
+	StreamSpanMaxDuration  time.Duration
+	StreamSpanMaxMessages  int
+
 	tracer trace.Tracer
 	meter  metric.Meter
+	logger log.Logger
+
+	// semconv is the semantic conventions emitted by the stats handlers,
+	// see OTEL_SEMCONV_STABILITY_OPT_IN.
+	semconv semconvMode
 
+	payloadRedactor *fieldRedactor
+
+	// conns maps the connKey of open client connections to their
+	// connContext if connection spans or channelz IDs are recorded.
+	conns sync.Map
+
+	// rpcDuration and rpcCallDuration are the durations of RPCs following
+	// the v1.17.0 and the latest semantic conventions respectively. They
+	// are no-ops unless the conventions are emitted, see semconv.
 	rpcDuration        metric.Float64Histogram
+	rpcCallDuration    metric.Float64Histogram
 	rpcRequestSize     metric.Int64Histogram
 	rpcResponseSize    metric.Int64Histogram
 	rpcRequestsPerRPC  metric.Int64Histogram
 	rpcResponsesPerRPC metric.Int64Histogram
+	rpcActiveRequests  metric.Int64UpDownCounter
+	compression        *compressionMetrics
+
+	attemptStarted  metric.Int64Counter
+	attemptDuration metric.Float64Histogram
+	attemptSentSize metric.Int64Histogram
+	attemptRcvdSize metric.Int64Histogram
+
+	connActive   metric.Int64UpDownCounter
+	connDuration metric.Float64Histogram
+	connBytesIn  metric.Int64Histogram
+	connBytesOut metric.Int64Histogram
+
+	streamThroughput        metric.Float64Histogram
+	streamMessageThroughput metric.Float64Histogram
+
+	payloadsDropped       metric.Int64Counter
+	serializationErrors   metric.Int64Counter
+	serializationDuration metric.Float64Histogram
 }
 
 // Option applies an option value for a config.
@@ -64,16 +180,46 @@ type Option interface {
 // newConfig returns a config configured with all the passed Options.
 func newConfig(opts []Option, role string) *config {
 	c := &config{
-		Propagators:    otel.GetTextMapPropagator(),
-		TracerProvider: otel.GetTracerProvider(),
-		MeterProvider:  otel.GetMeterProvider(),
-		ReceivedEvent:  true,
-		SentEvent:      true,
+		Propagators:            otel.GetTextMapPropagator(),
+		TracerProvider:         otel.GetTracerProvider(),
+		MeterProvider:          otel.GetMeterProvider(),
+		ReceivedEvent:          true,
+		SentEvent:              true,
+		PayloadRequestKey:      "request",
+		PayloadResponseKey:     "response",
+		PayloadCapture:         true,
+		PayloadCaptureReceived: true,
+		PayloadCaptureSent:     true,
+		PayloadSampling:        1,
+		semconv:                semconvModeFromEnv(),
 	}
 	for _, o := range opts {
 		o.apply(c)
 	}
 
+	if c.GRPCTraceBin {
+		// The configured Propagators extract after the grpc-trace-bin
+		// header, so the span context they extract takes precedence.
+		c.Propagators = propagation.NewCompositeTextMapPropagator(grpcTraceBinPropagator{}, c.Propagators)
+	}
+	if c.DisableTraces {
+		c.TracerProvider = tracenoop.NewTracerProvider()
+	}
+	if c.DisableMetrics {
+		c.MeterProvider = noop.NewMeterProvider()
+	}
+
+	if c.PayloadMarshaler == nil {
+		// Scrubbing and the payload sink need the complete payload, and the
+		// limit of a RuntimeControl may change. The payload can only be cut
+		// short while serializing if none of them is configured.
+		limit := c.PayloadSizeLimit
+		if len(c.PayloadScrubPaths) > 0 || len(c.PayloadScrubRegexps) > 0 || c.PayloadSink != nil || c.RuntimeControl != nil {
+			limit = 0
+		}
+		c.PayloadMarshaler = newProtoJSONMarshaler(c.PayloadCompactJSON, limit)
+	}
+
 	c.tracer = c.TracerProvider.Tracer(
 		ScopeName,
 		trace.WithInstrumentationVersion(SemVersion()),
@@ -85,14 +231,36 @@ func newConfig(opts []Option, role string) *config {
 		metric.WithSchemaURL(semconv.SchemaURL),
 	)
 
+	if c.LoggerProvider != nil {
+		c.logger = c.LoggerProvider.Logger(
+			ScopeName,
+			log.WithInstrumentationVersion(Version()),
+			log.WithSchemaURL(semconv.SchemaURL),
+		)
+	}
+
 	var err error
-	c.rpcDuration, err = c.meter.Float64Histogram("rpc."+role+".duration",
-		metric.WithDescription("Measures the duration of inbound RPC."),
-		metric.WithUnit("ms"))
-	if err != nil {
-		otel.Handle(err)
-		if c.rpcDuration == nil {
-			c.rpcDuration = noop.Float64Histogram{}
+	c.rpcDuration, c.rpcCallDuration = noop.Float64Histogram{}, noop.Float64Histogram{}
+	if c.semconv.emitOld() {
+		c.rpcDuration, err = c.meter.Float64Histogram("rpc."+role+".duration",
+			metric.WithDescription("Measures the duration of inbound RPC."),
+			metric.WithUnit("ms"))
+		if err != nil {
+			otel.Handle(err)
+			if c.rpcDuration == nil {
+				c.rpcDuration = noop.Float64Histogram{}
+			}
+		}
+	}
+	if c.semconv.emitNew() {
+		c.rpcCallDuration, err = c.meter.Float64Histogram("rpc."+role+".call.duration",
+			metric.WithDescription("Measures the duration of RPCs."),
+			metric.WithUnit("s"))
+		if err != nil {
+			otel.Handle(err)
+			if c.rpcCallDuration == nil {
+				c.rpcCallDuration = noop.Float64Histogram{}
+			}
 		}
 	}
 
@@ -136,9 +304,173 @@ func newConfig(opts []Option, role string) *config {
 		}
 	}
 
+	c.rpcActiveRequests, err = c.meter.Int64UpDownCounter("rpc."+role+".active_requests",
+		metric.WithDescription("Measures the number of RPCs that are currently in flight."),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		otel.Handle(err)
+		if c.rpcActiveRequests == nil {
+			c.rpcActiveRequests = noop.Int64UpDownCounter{}
+		}
+	}
+
+	c.compression = newCompressionMetrics(c.meter, role)
+
+	c.attemptStarted, c.attemptDuration = noop.Int64Counter{}, noop.Float64Histogram{}
+	c.attemptSentSize, c.attemptRcvdSize = noop.Int64Histogram{}, noop.Int64Histogram{}
+	if role == "client" {
+		c.newAttemptInstruments()
+	}
+
+	c.connActive, err = c.meter.Int64UpDownCounter("grpc."+role+".connections.active",
+		metric.WithDescription("Measures the number of open connections."),
+		metric.WithUnit("{connection}"))
+	if err != nil {
+		otel.Handle(err)
+		if c.connActive == nil {
+			c.connActive = noop.Int64UpDownCounter{}
+		}
+	}
+
+	c.connDuration, err = c.meter.Float64Histogram("grpc."+role+".connection.duration",
+		metric.WithDescription("Measures the duration of connections."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+		if c.connDuration == nil {
+			c.connDuration = noop.Float64Histogram{}
+		}
+	}
+
+	c.connBytesIn, err = c.meter.Int64Histogram("grpc."+role+".connection.bytes_in",
+		metric.WithDescription("Measures the number of message bytes received per connection (on the wire)."),
+		metric.WithUnit("By"))
+	if err != nil {
+		otel.Handle(err)
+		if c.connBytesIn == nil {
+			c.connBytesIn = noop.Int64Histogram{}
+		}
+	}
+
+	c.connBytesOut, err = c.meter.Int64Histogram("grpc."+role+".connection.bytes_out",
+		metric.WithDescription("Measures the number of message bytes sent per connection (on the wire)."),
+		metric.WithUnit("By"))
+	if err != nil {
+		otel.Handle(err)
+		if c.connBytesOut == nil {
+			c.connBytesOut = noop.Int64Histogram{}
+		}
+	}
+
+	c.streamThroughput, err = c.meter.Float64Histogram("rpc."+role+".stream.throughput",
+		metric.WithDescription("Measures the compressed message bytes per second of streaming RPCs between progress reports."),
+		metric.WithUnit("By/s"))
+	if err != nil {
+		otel.Handle(err)
+		if c.streamThroughput == nil {
+			c.streamThroughput = noop.Float64Histogram{}
+		}
+	}
+
+	c.streamMessageThroughput, err = c.meter.Float64Histogram("rpc."+role+".stream.message_throughput",
+		metric.WithDescription("Measures the messages per second of streaming RPCs between progress reports."),
+		metric.WithUnit("{message}/s"))
+	if err != nil {
+		otel.Handle(err)
+		if c.streamMessageThroughput == nil {
+			c.streamMessageThroughput = noop.Float64Histogram{}
+		}
+	}
+
+	c.payloadsDropped, err = c.meter.Int64Counter("otelgrpc.payloads.dropped",
+		metric.WithDescription("Measures the number of captured payloads that were truncated or not recorded."),
+		metric.WithUnit("{payload}"))
+	if err != nil {
+		otel.Handle(err)
+		if c.payloadsDropped == nil {
+			c.payloadsDropped = noop.Int64Counter{}
+		}
+	}
+
+	c.serializationErrors, err = c.meter.Int64Counter("otelgrpc.serialization.errors",
+		metric.WithDescription("Measures the number of payloads that failed to serialize."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		otel.Handle(err)
+		if c.serializationErrors == nil {
+			c.serializationErrors = noop.Int64Counter{}
+		}
+	}
+
+	c.serializationDuration, err = c.meter.Float64Histogram("otelgrpc.serialization.duration",
+		metric.WithDescription("Measures the time spent serializing captured payloads."),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+		if c.serializationDuration == nil {
+			c.serializationDuration = noop.Float64Histogram{}
+		}
+	}
+
 	return c
 }
 
+// Bucket boundaries recommended by the gRPC OpenTelemetry metrics for
+// latency and message size histograms.
+var (
+	attemptLatencyBounds = []float64{0, 0.00001, 0.00005, 0.0001, 0.0003, 0.0006, 0.0008, 0.001, 0.002, 0.003, 0.004, 0.005, 0.006, 0.008, 0.01, 0.013, 0.016, 0.02, 0.025, 0.03, 0.04, 0.05, 0.065, 0.08, 0.1, 0.13, 0.16, 0.2, 0.25, 0.3, 0.4, 0.5, 0.65, 0.8, 1, 2, 5, 10, 20, 50, 100}
+	attemptSizeBounds    = []float64{0, 1024, 2048, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216, 67108864, 268435456, 1073741824, 4294967296}
+)
+
+// newAttemptInstruments creates the per-attempt client instruments of the
+// gRPC OpenTelemetry metrics. Every attempt of a retried RPC is recorded
+// separately.
+func (c *config) newAttemptInstruments() {
+	var err error
+	c.attemptStarted, err = c.meter.Int64Counter("grpc.client.attempt.started",
+		metric.WithDescription("Number of client call attempts started."),
+		metric.WithUnit("{attempt}"))
+	if err != nil {
+		otel.Handle(err)
+		if c.attemptStarted == nil {
+			c.attemptStarted = noop.Int64Counter{}
+		}
+	}
+
+	c.attemptDuration, err = c.meter.Float64Histogram("grpc.client.attempt.duration",
+		metric.WithDescription("End-to-end time taken to complete a client call attempt."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(attemptLatencyBounds...))
+	if err != nil {
+		otel.Handle(err)
+		if c.attemptDuration == nil {
+			c.attemptDuration = noop.Float64Histogram{}
+		}
+	}
+
+	c.attemptSentSize, err = c.meter.Int64Histogram("grpc.client.attempt.sent_total_compressed_message_size",
+		metric.WithDescription("Compressed message bytes sent per client call attempt."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(attemptSizeBounds...))
+	if err != nil {
+		otel.Handle(err)
+		if c.attemptSentSize == nil {
+			c.attemptSentSize = noop.Int64Histogram{}
+		}
+	}
+
+	c.attemptRcvdSize, err = c.meter.Int64Histogram("grpc.client.attempt.rcvd_total_compressed_message_size",
+		metric.WithDescription("Compressed message bytes received per call attempt."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(attemptSizeBounds...))
+	if err != nil {
+		otel.Handle(err)
+		if c.attemptRcvdSize == nil {
+			c.attemptRcvdSize = noop.Int64Histogram{}
+		}
+	}
+}
+
 type propagatorsOption struct{ p propagation.TextMapPropagator }
 
 func (o propagatorsOption) apply(c *config) {
@@ -193,6 +525,68 @@ func (o filterOption) apply(c *config) {
 	}
 }
 
+type traceFilterOption struct{ f Filter }
+
+func (o traceFilterOption) apply(c *config) {
+	if o.f != nil {
+		c.TraceFilter = o.f
+	}
+}
+
+// WithTraceFilter returns an Option to use the request filter to determine
+// whether a span is recorded for a request. Unlike WithFilter, it does not
+// affect the metrics recorded for the request.
+func WithTraceFilter(f Filter) Option {
+	return traceFilterOption{f: f}
+}
+
+type metricFilterOption struct{ f Filter }
+
+func (o metricFilterOption) apply(c *config) {
+	if o.f != nil {
+		c.MetricFilter = o.f
+	}
+}
+
+// WithMetricFilter returns an Option to use the request filter to determine
+// whether metrics are recorded for a request. Unlike WithFilter, it does not
+// affect the span recorded for the request.
+func WithMetricFilter(f Filter) Option {
+	return metricFilterOption{f: f}
+}
+
+type contextFilterOption struct{ f ContextFilter }
+
+func (o contextFilterOption) apply(c *config) {
+	if o.f != nil {
+		c.ContextFilter = o.f
+	}
+}
+
+// WithContextFilter returns an Option to use the request filter, given the
+// context of the RPC, e.g. to filter requests by their metadata or peer. A
+// request is instrumented only if it is accepted by both f and the filter
+// set by WithFilter, if any.
+func WithContextFilter(f ContextFilter) Option {
+	return contextFilterOption{f: f}
+}
+
+type exemplarsForFilteredSpansOption struct{ enabled bool }
+
+func (o exemplarsForFilteredSpansOption) apply(c *config) {
+	c.ForceExemplars = o.enabled
+}
+
+// WithExemplarsForFilteredSpans returns an Option that configures whether
+// the RPC metrics of the stats handlers keep exemplars for RPCs whose span
+// is not sampled or was excluded by WithTraceFilter. When enabled, the
+// exemplars of these RPCs reference the span context of the RPC, or of its
+// parent if no span was started, as if it was sampled. Exemplars must be
+// enabled in the MeterProvider. This is disabled by default.
+func WithExemplarsForFilteredSpans(enabled bool) Option {
+	return exemplarsForFilteredSpansOption{enabled: enabled}
+}
+
 // WithTracerProvider returns an Option to use the TracerProvider when
 // creating a Tracer.
 func WithTracerProvider(tp trace.TracerProvider) Option {
@@ -213,6 +607,23 @@ func WithMeterProvider(mp metric.MeterProvider) Option {
 	return meterProviderOption{mp: mp}
 }
 
+type loggerProviderOption struct{ lp log.LoggerProvider }
+
+func (o loggerProviderOption) apply(c *config) {
+	if o.lp != nil {
+		c.LoggerProvider = o.lp
+	}
+}
+
+// WithPayloadLoggerProvider returns an Option to emit recorded request and
+// response payloads as log records using a Logger from lp instead of adding
+// them to message events. The log records are emitted with the context of
+// the RPC so they can be correlated with its span. Message events are still
+// added to the span without the payload.
+func WithPayloadLoggerProvider(lp log.LoggerProvider) Option {
+	return loggerProviderOption{lp: lp}
+}
+
 // Event type that can be recorded, see WithMessageEvents.
 type Event int
 
@@ -259,3 +670,512 @@ func (o spanStartOption) apply(c *config) {
 func WithSpanOptions(opts ...trace.SpanStartOption) Option {
 	return spanStartOption{opts}
 }
+
+type staticAttributesOption struct{ attrs []attribute.KeyValue }
+
+func (o staticAttributesOption) apply(c *config) {
+	c.StaticAttributes = append(c.StaticAttributes, o.attrs...)
+}
+
+// WithStaticAttributes returns an Option that adds attrs to every span and
+// metric recorded by the stats handlers, e.g. the deployment environment or
+// cluster name. Passing this option multiple times adds all the attributes.
+func WithStaticAttributes(attrs ...attribute.KeyValue) Option {
+	return staticAttributesOption{attrs: attrs}
+}
+
+type baggageAttributesOption struct{ keys []string }
+
+func (o baggageAttributesOption) apply(c *config) {
+	c.BaggageKeys = append(c.BaggageKeys, o.keys...)
+}
+
+// WithBaggageAttributes returns an Option that copies the baggage members
+// with the given keys onto the span and metric attributes of RPCs, e.g. a
+// tenant or job ID. Each member present in the baggage is recorded as a
+// string attribute named after its key. Servers read the baggage extracted
+// from the incoming request, which requires the configured propagators to
+// include propagation.Baggage, and clients the baggage of the context of
+// the RPC. Passing this option multiple times adds all the keys.
+func WithBaggageAttributes(keys ...string) Option {
+	return baggageAttributesOption{keys: keys}
+}
+
+type withoutTracesOption struct{}
+
+func (withoutTracesOption) apply(c *config) {
+	c.DisableTraces = true
+}
+
+// WithoutTraces returns an Option that disables tracing. No spans are
+// started and the span of the caller is neither modified nor replaced in
+// the propagated context. Use it for deployments that only collect
+// metrics.
+func WithoutTraces() Option {
+	return withoutTracesOption{}
+}
+
+type withoutMetricsOption struct{}
+
+func (withoutMetricsOption) apply(c *config) {
+	c.DisableMetrics = true
+}
+
+// WithoutMetrics returns an Option that disables metrics. The instruments
+// are not created with the configured MeterProvider and no measurements or
+// metric attributes are computed. Use it for deployments that only collect
+// traces.
+func WithoutMetrics() Option {
+	return withoutMetricsOption{}
+}
+
+type statusCodeMapperOption struct {
+	f func(grpc_codes.Code, bool) (codes.Code, string)
+}
+
+func (o statusCodeMapperOption) apply(c *config) {
+	c.StatusCodeMapper = o.f
+}
+
+// WithStatusCodeMapper returns an Option that uses f to set the span status
+// of failed RPCs. f is passed the gRPC status code the RPC failed with and
+// whether the span is a server span, and returns the span status code and
+// description. If f returns codes.Error without a description, the gRPC
+// status message is used.
+//
+// By default, failed client RPCs are marked as errors and server RPCs only
+// if they failed with UNKNOWN, DEADLINE_EXCEEDED, UNIMPLEMENTED, INTERNAL,
+// UNAVAILABLE or DATA_LOSS.
+func WithStatusCodeMapper(f func(code grpc_codes.Code, isServer bool) (codes.Code, string)) Option {
+	return statusCodeMapperOption{f: f}
+}
+
+type spanNameFormatterOption struct{ f func(string) string }
+
+func (o spanNameFormatterOption) apply(c *config) {
+	c.SpanNameFormatter = o.f
+}
+
+// WithSpanNameFormatter returns an Option that uses f to name the spans
+// recorded by the stats handlers. f is passed the full gRPC method name,
+// e.g. "/cedana.daemon.v1.Daemon/Dump". By default, spans are named after
+// the method without the leading slash.
+func WithSpanNameFormatter(f func(fullMethod string) string) Option {
+	return spanNameFormatterOption{f: f}
+}
+
+type peerServiceOption struct{ name string }
+
+func (o peerServiceOption) apply(c *config) {
+	c.PeerService = o.name
+}
+
+// WithPeerService returns an Option that sets the peer.service attribute of
+// the spans recorded by the client stats handler to name, the logical name
+// of the service called. It has no effect on server stats handlers.
+func WithPeerService(name string) Option {
+	return peerServiceOption{name: name}
+}
+
+type peerServiceFuncOption struct{ f func(net.Addr) string }
+
+func (o peerServiceFuncOption) apply(c *config) {
+	c.PeerServiceFunc = o.f
+}
+
+// WithPeerServiceFunc returns an Option that sets the peer.service attribute
+// of the spans recorded by the client stats handler to the value returned by
+// f for the remote address of the connection an RPC is sent on. If f returns
+// an empty string, the value set with WithPeerService, if any, is kept. f
+// must be safe for concurrent use. It has no effect on server stats handlers.
+func WithPeerServiceFunc(f func(remote net.Addr) string) Option {
+	return peerServiceFuncOption{f: f}
+}
+
+type loadBalancerOption struct{ name string }
+
+func (o loadBalancerOption) apply(c *config) {
+	c.LoadBalancer = o.name
+}
+
+// WithLoadBalancer returns an Option that sets the rpc.grpc.lb.policy
+// attribute of the spans recorded by the client stats handler to name, the
+// load balancing policy of the client, e.g. "round_robin" or
+// "xds_cluster_manager_experimental". The address of the backend picked for
+// an RPC is always recorded with the rpc.grpc.lb.backend_address attribute,
+// and metadata added by the picker with the request metadata configured with
+// WithMetadataAttributes. It has no effect on server stats handlers.
+func WithLoadBalancer(name string) Option {
+	return loadBalancerOption{name: name}
+}
+
+type attributeExtractorOption struct{ f AttributeExtractor }
+
+func (o attributeExtractorOption) apply(c *config) {
+	if o.f != nil {
+		c.AttributeExtractors = append(c.AttributeExtractors, o.f)
+	}
+}
+
+// WithAttributeExtractor returns an Option that adds the attributes returned
+// by f to the span and metrics of every RPC, e.g. a tenant ID or job ID read
+// from the incoming metadata. f is called from the stats handler when the RPC
+// starts and must be safe for concurrent use. Passing this option multiple
+// times adds the attributes of all extractors.
+func WithAttributeExtractor(f AttributeExtractor) Option {
+	return attributeExtractorOption{f: f}
+}
+
+type payloadCaptureOption struct{ enabled bool }
+
+func (o payloadCaptureOption) apply(c *config) {
+	c.PayloadCapture = o.enabled
+}
+
+// WithPayloadCapture returns an Option that configures whether the serialized
+// request and response payloads are recorded on message events. When
+// disabled, message events only carry the message type, ID, and sizes.
+// Payload capture is enabled by default.
+func WithPayloadCapture(enabled bool) Option {
+	return payloadCaptureOption{enabled: enabled}
+}
+
+type payloadCaptureEventsOption struct{ events []Event }
+
+func (o payloadCaptureEventsOption) apply(c *config) {
+	c.PayloadCaptureReceived, c.PayloadCaptureSent = false, false
+	for _, e := range o.events {
+		switch e {
+		case ReceivedEvents:
+			c.PayloadCaptureReceived = true
+		case SentEvents:
+			c.PayloadCaptureSent = true
+		}
+	}
+}
+
+// WithPayloadCaptureEvents returns an Option that restricts payload capture
+// to the messages of the given directions. The message events of the other
+// direction are still recorded without their payload.
+//
+// Valid events are:
+//   - ReceivedEvents: Record the payload of received messages, i.e. the
+//     requests of a server and the responses of a client.
+//   - SentEvents: Record the payload of sent messages, i.e. the responses of
+//     a server and the requests of a client.
+//
+// By default, the payloads of both directions are recorded.
+func WithPayloadCaptureEvents(events ...Event) Option {
+	return payloadCaptureEventsOption{events: events}
+}
+
+type payloadCaptureOnErrorOption struct{ enabled bool }
+
+func (o payloadCaptureOnErrorOption) apply(c *config) {
+	c.PayloadOnError = o.enabled
+}
+
+// WithPayloadCaptureOnError returns an Option that configures whether
+// payloads are only recorded for RPCs that end in an error. When enabled, the
+// serialized payloads of an RPC are buffered until it ends. They are added to
+// the message events of the span if the RPC failed and discarded otherwise.
+// This is disabled by default.
+func WithPayloadCaptureOnError(enabled bool) Option {
+	return payloadCaptureOnErrorOption{enabled: enabled}
+}
+
+type payloadMarshalerOption struct{ m PayloadMarshaler }
+
+func (o payloadMarshalerOption) apply(c *config) {
+	if o.m != nil {
+		c.PayloadMarshaler = o.m
+	}
+}
+
+// WithPayloadMarshaler returns an Option to use m to serialize the request
+// and response payloads recorded on message events. If this option is not
+// provided, proto messages are serialized as JSON using [protojson].
+func WithPayloadMarshaler(m PayloadMarshaler) Option {
+	return payloadMarshalerOption{m: m}
+}
+
+type payloadCompactJSONOption struct{ compact bool }
+
+func (o payloadCompactJSONOption) apply(c *config) {
+	c.PayloadCompactJSON = o.compact
+}
+
+// WithPayloadCompactJSON returns an Option that configures whether the
+// default PayloadMarshaler serializes payloads as compact JSON. Compact JSON
+// is not indented and omits fields with default values. By default, payloads
+// are indented and all fields are emitted. This option has no effect if a
+// PayloadMarshaler is provided with WithPayloadMarshaler.
+func WithPayloadCompactJSON(compact bool) Option {
+	return payloadCompactJSONOption{compact: compact}
+}
+
+type payloadAttributeKeysOption struct{ request, response attribute.Key }
+
+func (o payloadAttributeKeysOption) apply(c *config) {
+	if o.request != "" {
+		c.PayloadRequestKey = o.request
+	}
+	if o.response != "" {
+		c.PayloadResponseKey = o.response
+	}
+}
+
+// WithPayloadAttributeKeys returns an Option that sets the attribute keys
+// used to record payloads on message events. The request key is used for
+// received messages and the response key for sent messages. By default, the
+// keys "request" and "response" are used. An empty key leaves the respective
+// default unchanged.
+func WithPayloadAttributeKeys(request, response attribute.Key) Option {
+	return payloadAttributeKeysOption{request: request, response: response}
+}
+
+type payloadHashOption struct{ enabled bool }
+
+func (o payloadHashOption) apply(c *config) {
+	c.PayloadHash = o.enabled
+}
+
+// WithPayloadHash returns an Option that configures whether message events
+// record a hash of the payload instead of its serialized content. When
+// enabled, the message.hash attribute holds the hex encoded SHA-256 hash of
+// the deterministic proto encoding of the message, after any configured
+// redaction, and the rpc.message.proto_type attribute holds its proto type
+// name. This allows identical or retried requests to be correlated without
+// recording their content. This is disabled by default.
+func WithPayloadHash(enabled bool) Option {
+	return payloadHashOption{enabled: enabled}
+}
+
+type payloadSizeLimitOption struct{ n int }
+
+func (o payloadSizeLimitOption) apply(c *config) {
+	c.PayloadSizeLimit = o.n
+}
+
+// WithPayloadSizeLimit returns an Option that limits the size, in bytes, of
+// the serialized request and response payloads recorded on message events.
+// Payloads exceeding n bytes are truncated and the event is annotated with
+// the message.truncated attribute. If n is less than or equal to zero, which
+// is the default, payloads are recorded in full.
+func WithPayloadSizeLimit(n int) Option {
+	return payloadSizeLimitOption{n: n}
+}
+
+type payloadMethodsOption struct{ allow, deny []string }
+
+func (o payloadMethodsOption) apply(c *config) {
+	for _, p := range append(append([]string(nil), o.allow...), o.deny...) {
+		if _, err := path.Match(p, ""); err != nil {
+			otel.Handle(fmt.Errorf("invalid payload method pattern %q: %w", p, err))
+		}
+	}
+	c.PayloadAllow = o.allow
+	c.PayloadDeny = o.deny
+}
+
+// WithPayloadMethods returns an Option that restricts payload capture to a
+// subset of RPC methods. Patterns are matched against the full method name,
+// e.g. "/package.Service/Method", using the syntax of [path.Match], so
+// "/cedana.daemon.*/Dump" matches the Dump method of every service in the
+// cedana.daemon package.
+//
+// If allow is not empty, payloads are only recorded for methods matching at
+// least one of its patterns. Payloads are never recorded for methods matching
+// a pattern in deny. Message events of methods without payload capture still
+// record the message type, ID, and sizes.
+func WithPayloadMethods(allow, deny []string) Option {
+	return payloadMethodsOption{allow: allow, deny: deny}
+}
+
+// capturePayload reports whether payloads of an RPC of the method fullMethod
+// are recorded.
+func (c *config) capturePayload(fullMethod string) bool {
+	if !c.payloadCaptureEnabled() {
+		return false
+	}
+	if matchMethod(c.PayloadDeny, fullMethod) {
+		return false
+	}
+	if len(c.PayloadAllow) > 0 && !matchMethod(c.PayloadAllow, fullMethod) {
+		return false
+	}
+	return c.PayloadSampling >= 1 || rand.Float64() < c.PayloadSampling
+}
+
+// capturePayloadDirection reports whether the payloads of messages sent, or
+// received if sent is false, are captured.
+func (c *config) capturePayloadDirection(sent bool) bool {
+	if sent {
+		return c.PayloadCaptureSent
+	}
+	return c.PayloadCaptureReceived
+}
+
+// matchMethod reports whether fullMethod matches any of patterns.
+func matchMethod(patterns []string, fullMethod string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, fullMethod); ok {
+			return true
+		}
+	}
+	return false
+}
+
+type payloadSamplingRatioOption struct{ fraction float64 }
+
+func (o payloadSamplingRatioOption) apply(c *config) {
+	c.PayloadSampling = math.Max(0, math.Min(1, o.fraction))
+}
+
+// WithPayloadSamplingRatio returns an Option that records payloads for only
+// the given fraction of RPCs. The decision is made independently of trace
+// sampling when the RPC starts, so sampled traces may contain RPCs without
+// payloads. Fractions >= 1 record payloads for all RPCs, which is the
+// default, and fractions <= 0 never record payloads.
+func WithPayloadSamplingRatio(fraction float64) Option {
+	return payloadSamplingRatioOption{fraction: fraction}
+}
+
+type maxCapturedMessagesOption struct{ n int }
+
+func (o maxCapturedMessagesOption) apply(c *config) {
+	c.MaxCapturedMessages = o.n
+}
+
+// WithMaxCapturedMessages returns an Option that limits payload capture to
+// the first n messages sent or received by an RPC. Message events of
+// subsequent messages only record the message type, ID, and sizes, unless
+// they are among the last messages captured with WithLastCapturedMessages.
+// If n is less than or equal to zero, which is the default, the payloads of
+// all messages are recorded.
+func WithMaxCapturedMessages(n int) Option {
+	return maxCapturedMessagesOption{n: n}
+}
+
+type lastCapturedMessagesOption struct{ n int }
+
+func (o lastCapturedMessagesOption) apply(c *config) {
+	c.LastCapturedMessages = o.n
+}
+
+// WithLastCapturedMessages returns an Option that additionally records the
+// payloads of the last n messages of an RPC exceeding the limit set with
+// WithMaxCapturedMessages. The serialized payloads of these messages are
+// buffered and their message events are added to the span when the RPC
+// ends. This option has no effect unless WithMaxCapturedMessages is used.
+func WithLastCapturedMessages(n int) Option {
+	return lastCapturedMessagesOption{n: n}
+}
+
+type connectionSpansOption struct{ enabled bool }
+
+func (o connectionSpansOption) apply(c *config) {
+	c.ConnectionSpans = o.enabled
+}
+
+// WithConnectionSpans returns an Option that configures whether a span is
+// recorded for each connection handled by a stats handler. The span starts
+// when the connection is established and ends when it is closed. The spans
+// of RPCs sent or received on the connection are linked to it. Connection
+// spans are disabled by default.
+func WithConnectionSpans(enabled bool) Option {
+	return connectionSpansOption{enabled: enabled}
+}
+
+type messageSpansOption struct{ enabled bool }
+
+func (o messageSpansOption) apply(c *config) {
+	c.MessageSpans = o.enabled
+}
+
+// WithMessageSpans returns an Option that configures whether the messages of
+// streaming RPCs are recorded as child spans of the RPC span instead of
+// events on it. Each message span starts when the message is received or
+// sent and ends once the stats handler processed it, and holds the
+// attributes of the message event, including any captured payload. Unary
+// RPCs keep recording message events. Message spans are disabled by
+// default.
+func WithMessageSpans(enabled bool) Option {
+	return messageSpansOption{enabled: enabled}
+}
+
+type messageCorrelationOption struct{ f func(payload any) string }
+
+func (o messageCorrelationOption) apply(c *config) {
+	c.MessageCorrelator = o.f
+}
+
+// WithMessageCorrelation returns an Option that correlates the responses of
+// streaming RPCs with the requests they answer, e.g. for bidirectional
+// streams multiplexing several operations. f is passed the payload of every
+// message of a stream and returns the correlation ID of the message, or an
+// empty string if the message is not correlated. Requests are the messages
+// received by servers and sent by clients.
+//
+// The message event of a response with the correlation ID of an unanswered
+// request has the message.correlated_id attribute set to the message ID of
+// the request. If WithMessageSpans is enabled, the message span of the
+// response is also linked to the message span of the request.
+func WithMessageCorrelation(f func(payload any) string) Option {
+	return messageCorrelationOption{f: f}
+}
+
+type streamProgressIntervalOption struct{ d time.Duration }
+
+func (o streamProgressIntervalOption) apply(c *config) {
+	c.StreamProgressInterval = o.d
+}
+
+// WithStreamProgressInterval returns an Option that configures the interval
+// at which the progress of streaming RPCs is reported while they are open.
+// Every interval, a stream.progress event with the messages and compressed
+// message bytes sent and received so far, and the throughput since the
+// previous report, is added to the RPC span and the throughput is recorded
+// by the rpc.{server|client}.stream.throughput and
+// rpc.{server|client}.stream.message_throughput metrics. This makes stalls
+// of long-lived streams observable before they end.
+//
+// Progress is not reported if d is not positive, which is the default.
+func WithStreamProgressInterval(d time.Duration) Option {
+	return streamProgressIntervalOption{d: d}
+}
+
+type streamSpanSplittingOption struct {
+	maxDuration time.Duration
+	maxMessages int
+}
+
+func (o streamSpanSplittingOption) apply(c *config) {
+	c.StreamSpanMaxDuration = o.maxDuration
+	c.StreamSpanMaxMessages = o.maxMessages
+}
+
+// WithStreamSpanSplitting returns an Option that configures the spans of
+// streaming RPCs to be split into several spans, so long-lived streams are
+// exported while they are open and are not lost if the process exits before
+// they end. Once the span of a stream lasted for maxDuration or recorded
+// maxMessages messages, it is ended when the next message is received or
+// sent and a new span is started for the rest of the stream. The new span
+// has the same parent, name and start attributes as the first one, is
+// linked to the span it follows and holds its position in the stream in the
+// rpc.grpc.stream.segment attribute. Attributes added after the first span
+// started, such as the peer and metadata attributes, are only recorded on
+// the segments that were open when they were added.
+//
+// Spans are only split when a message is received or sent: the span of a
+// stream that stays idle longer than maxDuration is not ended, nor
+// exported, until the next message or the end of the stream. The context of
+// the RPC passed to the application keeps holding the first span, so the
+// spans the application starts from it are children of the first segment,
+// even after it ended.
+//
+// A limit that is not positive is not applied. Spans are not split by
+// default.
+func WithStreamSpanSplitting(maxDuration time.Duration, maxMessages int) Option {
+	return streamSpanSplittingOption{maxDuration: maxDuration, maxMessages: maxMessages}
+}
@@ -14,15 +14,33 @@ import (
 )
 
 func TestNilInstruments(t *testing.T) {
+	t.Setenv(semconvOptInEnv, "rpc/dup")
 	mp := meterProvider{}
 	c := newConfig([]Option{WithMeterProvider(mp)}, "test")
 
 	ctx := context.Background()
 	assert.NotPanics(t, func() { c.rpcDuration.Record(ctx, 0) }, "rpcDuration")
+	assert.NotPanics(t, func() { c.rpcCallDuration.Record(ctx, 0) }, "rpcCallDuration")
 	assert.NotPanics(t, func() { c.rpcRequestSize.Record(ctx, 0) }, "rpcRequestSize")
 	assert.NotPanics(t, func() { c.rpcResponseSize.Record(ctx, 0) }, "rpcResponseSize")
 	assert.NotPanics(t, func() { c.rpcRequestsPerRPC.Record(ctx, 0) }, "rpcRequestsPerRPC")
 	assert.NotPanics(t, func() { c.rpcResponsesPerRPC.Record(ctx, 0) }, "rpcResponsesPerRPC")
+	assert.NotPanics(t, func() { c.rpcActiveRequests.Add(ctx, 0) }, "rpcActiveRequests")
+	assert.NotPanics(t, func() { c.connActive.Add(ctx, 0) }, "connActive")
+	assert.NotPanics(t, func() { c.connDuration.Record(ctx, 0) }, "connDuration")
+	assert.NotPanics(t, func() { c.connBytesIn.Record(ctx, 0) }, "connBytesIn")
+	assert.NotPanics(t, func() { c.connBytesOut.Record(ctx, 0) }, "connBytesOut")
+	assert.NotPanics(t, func() { c.streamThroughput.Record(ctx, 0) }, "streamThroughput")
+	assert.NotPanics(t, func() { c.streamMessageThroughput.Record(ctx, 0) }, "streamMessageThroughput")
+	assert.NotPanics(t, func() { c.payloadsDropped.Add(ctx, 0) }, "payloadsDropped")
+	assert.NotPanics(t, func() { c.serializationErrors.Add(ctx, 0) }, "serializationErrors")
+	assert.NotPanics(t, func() { c.serializationDuration.Record(ctx, 0) }, "serializationDuration")
+
+	c = newConfig([]Option{WithMeterProvider(mp)}, "client")
+	assert.NotPanics(t, func() { c.attemptStarted.Add(ctx, 0) }, "attemptStarted")
+	assert.NotPanics(t, func() { c.attemptDuration.Record(ctx, 0) }, "attemptDuration")
+	assert.NotPanics(t, func() { c.attemptSentSize.Record(ctx, 0) }, "attemptSentSize")
+	assert.NotPanics(t, func() { c.attemptRcvdSize.Record(ctx, 0) }, "attemptRcvdSize")
 }
 
 type meterProvider struct {
@@ -45,3 +63,75 @@ func (meter) Int64Histogram(string, ...metric.Int64HistogramOption) (metric.Int6
 func (meter) Float64Histogram(string, ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
 	return nil, assert.AnError
 }
+
+func (meter) Int64Counter(string, ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return nil, assert.AnError
+}
+
+func (meter) Int64UpDownCounter(string, ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	return nil, assert.AnError
+}
+
+func TestCapturePayload(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		method string
+		want   bool
+	}{
+		{
+			name:   "Default",
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   true,
+		},
+		{
+			name:   "Disabled",
+			opts:   []Option{WithPayloadCapture(false), WithPayloadMethods([]string{"/*/*"}, nil)},
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   false,
+		},
+		{
+			name:   "Allowed",
+			opts:   []Option{WithPayloadMethods([]string{"/cedana.daemon.*/Dump"}, nil)},
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   true,
+		},
+		{
+			name:   "NotAllowed",
+			opts:   []Option{WithPayloadMethods([]string{"/cedana.daemon.*/Dump"}, nil)},
+			method: "/cedana.daemon.Daemon/Restore",
+			want:   false,
+		},
+		{
+			name:   "Denied",
+			opts:   []Option{WithPayloadMethods([]string{"/cedana.daemon.*/*"}, []string{"/*/Restore"})},
+			method: "/cedana.daemon.Daemon/Restore",
+			want:   false,
+		},
+		{
+			name:   "NotSampled",
+			opts:   []Option{WithPayloadSamplingRatio(0)},
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   false,
+		},
+		{
+			name:   "Sampled",
+			opts:   []Option{WithPayloadSamplingRatio(1)},
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   true,
+		},
+		{
+			name:   "NotDenied",
+			opts:   []Option{WithPayloadMethods(nil, []string{"/*/Restore"})},
+			method: "/cedana.daemon.Daemon/Dump",
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig(tt.opts, "test")
+			assert.Equal(t, tt.want, c.capturePayload(tt.method))
+		})
+	}
+}
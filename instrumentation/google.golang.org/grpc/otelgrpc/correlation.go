@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// messageCorrelation tracks the unanswered request messages of a stream,
+// see WithMessageCorrelation.
+type messageCorrelation struct {
+	mu sync.Mutex
+	// requests maps the correlation ID of unanswered requests to their
+	// message ID.
+	requests map[string]int64
+	// spans maps the message ID of unanswered requests to the span context
+	// of their message span.
+	spans map[int64]trace.SpanContext
+}
+
+// correlateMessage correlates the message e with message ID id and payload
+// sent or received on the stream of gctx. If request is true, the message
+// is a request that later responses may answer, otherwise it is a response
+// and e is annotated with the message ID of the request it answers.
+func (c *config) correlateMessage(gctx *gRPCContext, e *messageEvent, id int64, request bool, payload any) {
+	if c.MessageCorrelator == nil || gctx == nil || !gctx.streaming.Load() {
+		return
+	}
+	key := c.MessageCorrelator(payload)
+	if key == "" {
+		return
+	}
+
+	mc := &gctx.correlation
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if request {
+		if mc.requests == nil {
+			mc.requests = make(map[string]int64)
+		}
+		mc.requests[key] = id
+		e.requestID = id
+		return
+	}
+
+	reqID, ok := mc.requests[key]
+	if !ok {
+		return
+	}
+	delete(mc.requests, key)
+	e.answersID = reqID
+	e.attrs = append(e.attrs, RPCMessageCorrelatedIDKey.Int64(reqID))
+}
+
+// setRequestSpan records sc as the message span of the request with message
+// ID id.
+func (gctx *gRPCContext) setRequestSpan(id int64, sc trace.SpanContext) {
+	if id == 0 || !sc.IsValid() {
+		return
+	}
+	mc := &gctx.correlation
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	if mc.spans == nil {
+		mc.spans = make(map[int64]trace.SpanContext)
+	}
+	mc.spans[id] = sc
+}
+
+// requestLink returns a link to the message span of the request with
+// message ID id, if any.
+func (gctx *gRPCContext) requestLink(id int64) []trace.Link {
+	if id == 0 {
+		return nil
+	}
+	mc := &gctx.correlation
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	sc, ok := mc.spans[id]
+	if !ok {
+		return nil
+	}
+	delete(mc.spans, id)
+	return []trace.Link{{SpanContext: sc}}
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	grpc_codes "google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// deadlineAttrs returns the attributes describing the deadline of the RPC of
+// ctx when it begins at begin.
+func deadlineAttrs(ctx context.Context, begin time.Time) []attribute.KeyValue {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return []attribute.KeyValue{RPCGRPCRequestDeadlineSetKey.Bool(false)}
+	}
+	return []attribute.KeyValue{
+		RPCGRPCRequestDeadlineSetKey.Bool(true),
+		RPCGRPCRequestDeadlineKey.String(deadline.UTC().Format(time.RFC3339Nano)),
+		RPCGRPCRequestTimeRemainingKey.Float64(deadline.Sub(begin).Seconds()),
+	}
+}
+
+// deadlineExceededSource returns the attribute telling whether the RPC of
+// ctx that failed with DEADLINE_EXCEEDED at end did so because its own
+// deadline expired.
+func deadlineExceededSource(ctx context.Context, end time.Time) attribute.KeyValue {
+	if deadline, ok := ctx.Deadline(); ok && !end.Before(deadline) {
+		return RPCGRPCDeadlineExceededLocal
+	}
+	return RPCGRPCDeadlineExceededRemote
+}
+
+// endReason returns the attribute classifying why the RPC of ctx ended with
+// the status code code. Servers see RPCs cancelled by their client as
+// CANCELLED, clients only attribute a cancellation to themselves if ctx was
+// cancelled.
+func endReason(ctx context.Context, code grpc_codes.Code, isServer bool) attribute.KeyValue { // nolint: revive  // isServer is not a control flag.
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return RPCGRPCEndReasonDeadlineExceeded
+	}
+	switch code {
+	case grpc_codes.DeadlineExceeded:
+		return RPCGRPCEndReasonDeadlineExceeded
+	case grpc_codes.Canceled:
+		if isServer || errors.Is(ctx.Err(), context.Canceled) {
+			return RPCGRPCEndReasonCancelledByClient
+		}
+		return RPCGRPCEndReasonCancelled
+	case grpc_codes.Unavailable:
+		return RPCGRPCEndReasonTransportError
+	default:
+		return RPCGRPCEndReasonApplicationError
+	}
+}
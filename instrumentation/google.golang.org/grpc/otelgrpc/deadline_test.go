@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestDeadlineAttrs(t *testing.T) {
+	begin := time.Date(2024, 7, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []attribute.KeyValue{
+		RPCGRPCRequestDeadlineSetKey.Bool(false),
+	}, deadlineAttrs(context.Background(), begin))
+
+	ctx, cancel := context.WithDeadline(context.Background(), begin.Add(1500*time.Millisecond))
+	defer cancel()
+	assert.Equal(t, []attribute.KeyValue{
+		RPCGRPCRequestDeadlineSetKey.Bool(true),
+		RPCGRPCRequestDeadlineKey.String("2024-07-01T12:00:01.5Z"),
+		RPCGRPCRequestTimeRemainingKey.Float64(1.5),
+	}, deadlineAttrs(ctx, begin))
+}
+
+func TestDeadlineExceededSource(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	assert.Equal(t, RPCGRPCDeadlineExceededLocal, deadlineExceededSource(ctx, deadline))
+	assert.Equal(t, RPCGRPCDeadlineExceededLocal, deadlineExceededSource(ctx, deadline.Add(time.Millisecond)))
+	assert.Equal(t, RPCGRPCDeadlineExceededRemote, deadlineExceededSource(ctx, deadline.Add(-time.Millisecond)))
+	assert.Equal(t, RPCGRPCDeadlineExceededRemote, deadlineExceededSource(context.Background(), deadline))
+}
+
+func TestEndReason(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		code     codes.Code
+		isServer bool
+		want     attribute.KeyValue
+	}{
+		{name: "ServerCancelled", ctx: cancelled, code: codes.Canceled, isServer: true, want: RPCGRPCEndReasonCancelledByClient},
+		{name: "ClientCancelled", ctx: cancelled, code: codes.Canceled, want: RPCGRPCEndReasonCancelledByClient},
+		{name: "ClientRemoteCancel", ctx: context.Background(), code: codes.Canceled, want: RPCGRPCEndReasonCancelled},
+		{name: "DeadlineCode", ctx: context.Background(), code: codes.DeadlineExceeded, want: RPCGRPCEndReasonDeadlineExceeded},
+		// Servers see RPCs whose deadline expired as cancelled.
+		{name: "ServerDeadline", ctx: expired, code: codes.Canceled, isServer: true, want: RPCGRPCEndReasonDeadlineExceeded},
+		{name: "Transport", ctx: context.Background(), code: codes.Unavailable, want: RPCGRPCEndReasonTransportError},
+		{name: "Application", ctx: context.Background(), code: codes.NotFound, isServer: true, want: RPCGRPCEndReasonApplicationError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, endReason(tt.ctx, tt.code, tt.isServer))
+		})
+	}
+}
@@ -7,5 +7,13 @@ Package otelgrpc is the instrumentation library for [google.golang.org/grpc].
 Use [NewClientHandler] with [grpc.WithStatsHandler] to instrument a gRPC client.
 
 Use [NewServerHandler] with [grpc.StatsHandler] to instrument a gRPC server.
+
+The stats handlers emit the v1.17.0 RPC semantic conventions by default.
+Set the OTEL_SEMCONV_STABILITY_OPT_IN environment variable to "rpc" to emit
+the v1.26.0 semantic conventions instead, or to "rpc/dup" to emit both.
+The duration of RPCs is then recorded in seconds by the
+rpc.server.call.duration and rpc.client.call.duration histograms instead of,
+or in addition to, the rpc.server.duration and rpc.client.duration
+histograms in milliseconds.
 */
 package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// FieldMaskMode determines how a field mask passed to WithPayloadFieldMask
+// is applied to a recorded payload.
+type FieldMaskMode int
+
+const (
+	// FieldMaskInclude records only the fields listed in the mask.
+	FieldMaskInclude FieldMaskMode = iota
+	// FieldMaskExclude records every field except those listed in the mask.
+	FieldMaskExclude
+)
+
+// fieldMask is a parsed field mask. Each key is a field name and its value
+// holds the sub-paths of that field. A field with no sub-paths is selected
+// as a whole.
+type fieldMask map[protoreflect.Name]fieldMask
+
+// payloadFieldMask is a field mask applied to payloads of a message type.
+type payloadFieldMask struct {
+	mode FieldMaskMode
+	mask fieldMask
+}
+
+func newFieldMask(paths []string) fieldMask {
+	root := fieldMask{}
+	for _, p := range paths {
+		node := root
+		for _, name := range strings.Split(p, ".") {
+			n := protoreflect.Name(name)
+			child, ok := node[n]
+			if !ok {
+				child = fieldMask{}
+				node[n] = child
+			}
+			node = child
+		}
+	}
+	return root
+}
+
+// apply applies m to msg.
+func (m payloadFieldMask) apply(msg protoreflect.Message) {
+	switch m.mode {
+	case FieldMaskInclude:
+		m.mask.include(msg)
+	case FieldMaskExclude:
+		m.mask.exclude(msg)
+	}
+}
+
+// include clears all populated fields of msg not selected by m.
+func (m fieldMask) include(msg protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		sub, ok := m[fd.Name()]
+		switch {
+		case !ok:
+			msg.Clear(fd)
+		case len(sub) > 0:
+			eachMessage(msg, fd, sub.include)
+		}
+	}
+}
+
+// exclude clears all fields of msg selected by m.
+func (m fieldMask) exclude(msg protoreflect.Message) {
+	fields := msg.Descriptor().Fields()
+	for name, sub := range m {
+		fd := fields.ByName(name)
+		if fd == nil || !msg.Has(fd) {
+			continue
+		}
+		if len(sub) == 0 {
+			msg.Clear(fd)
+			continue
+		}
+		eachMessage(msg, fd, sub.exclude)
+	}
+}
+
+// eachMessage calls f for every message held by the populated field fd of
+// msg. This includes the elements of repeated fields and the values of maps.
+func eachMessage(msg protoreflect.Message, fd protoreflect.FieldDescriptor, f func(protoreflect.Message)) {
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return
+		}
+		msg.Mutable(fd).Map().Range(func(_ protoreflect.MapKey, v protoreflect.Value) bool {
+			f(v.Message())
+			return true
+		})
+	case fd.IsList():
+		if fd.Message() == nil {
+			return
+		}
+		l := msg.Mutable(fd).List()
+		for i := 0; i < l.Len(); i++ {
+			f(l.Get(i).Message())
+		}
+	case fd.Message() != nil:
+		f(msg.Mutable(fd).Message())
+	}
+}
+
+type payloadFieldMaskOption struct {
+	msgType protoreflect.FullName
+	m       payloadFieldMask
+}
+
+func (o payloadFieldMaskOption) apply(c *config) {
+	if c.PayloadFieldMasks == nil {
+		c.PayloadFieldMasks = make(map[protoreflect.FullName]payloadFieldMask)
+	}
+	c.PayloadFieldMasks[o.msgType] = o.m
+}
+
+// WithPayloadFieldMask returns an Option that applies mask to the recorded
+// payloads of messages with the fully-qualified type name msgType before they
+// are serialized. With FieldMaskInclude only the fields listed in mask are
+// recorded, with FieldMaskExclude the listed fields are removed. The message
+// sent or received by the application is never modified.
+//
+// Paths in mask use proto field names and may refer to nested fields, e.g.
+// "credentials.token". Paths traversing repeated or map fields apply to every
+// element. Passing this option multiple times for the same msgType replaces
+// the previously configured mask.
+func WithPayloadFieldMask(msgType protoreflect.FullName, mode FieldMaskMode, mask *fieldmaskpb.FieldMask) Option {
+	return payloadFieldMaskOption{
+		msgType: msgType,
+		m: payloadFieldMask{
+			mode: mode,
+			mask: newFieldMask(mask.GetPaths()),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	pb "google.golang.org/grpc/interop/grpc_testing"
+)
+
+func TestPayloadFieldMask(t *testing.T) {
+	newMsg := func() *pb.StreamingOutputCallRequest {
+		return &pb.StreamingOutputCallRequest{
+			ResponseType: pb.PayloadType_COMPRESSABLE,
+			ResponseParameters: []*pb.ResponseParameters{
+				{Size: 1, IntervalUs: 10},
+				{Size: 2, IntervalUs: 20},
+			},
+			Payload: &pb.Payload{
+				Type: pb.PayloadType_COMPRESSABLE,
+				Body: []byte("secret"),
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		mode  FieldMaskMode
+		paths []string
+		want  *pb.StreamingOutputCallRequest
+	}{
+		{
+			name:  "Exclude",
+			mode:  FieldMaskExclude,
+			paths: []string{"payload.body", "response_parameters.interval_us"},
+			want: &pb.StreamingOutputCallRequest{
+				ResponseType: pb.PayloadType_COMPRESSABLE,
+				ResponseParameters: []*pb.ResponseParameters{
+					{Size: 1},
+					{Size: 2},
+				},
+				Payload: &pb.Payload{Type: pb.PayloadType_COMPRESSABLE},
+			},
+		},
+		{
+			name:  "Include",
+			mode:  FieldMaskInclude,
+			paths: []string{"response_type", "response_parameters.size"},
+			want: &pb.StreamingOutputCallRequest{
+				ResponseType: pb.PayloadType_COMPRESSABLE,
+				ResponseParameters: []*pb.ResponseParameters{
+					{Size: 1},
+					{Size: 2},
+				},
+			},
+		},
+		{
+			name:  "UnknownField",
+			mode:  FieldMaskExclude,
+			paths: []string{"unknown", "payload.unknown"},
+			want:  newMsg(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newMsg()
+			c := newConfig([]Option{WithPayloadFieldMask(
+				"grpc.testing.StreamingOutputCallRequest",
+				tt.mode,
+				&fieldmaskpb.FieldMask{Paths: tt.paths},
+			)}, "test")
+
+			got := c.redactPayload(msg)
+			assert.True(t, proto.Equal(tt.want, got.(proto.Message)), "got %v", got)
+			assert.True(t, proto.Equal(newMsg(), msg), "original message modified")
+		})
+	}
+}
+
+func TestPayloadFieldMaskOtherType(t *testing.T) {
+	c := newConfig([]Option{WithPayloadFieldMask(
+		"grpc.testing.StreamingOutputCallRequest",
+		FieldMaskInclude,
+		&fieldmaskpb.FieldMask{},
+	)}, "test")
+
+	msg := &pb.SimpleRequest{ResponseSize: 1}
+	assert.Same(t, msg, c.redactPayload(msg))
+}
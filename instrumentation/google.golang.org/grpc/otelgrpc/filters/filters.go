@@ -2,7 +2,9 @@
 // SPDX-License-Identifier: Apache-2.0
 
 // Package filters provides a set of filters useful with the
-// [otelgrpc.WithFilter] option to control which inbound requests are instrumented.
+// [otelgrpc.WithFilter], [otelgrpc.WithTraceFilter], and
+// [otelgrpc.WithMetricFilter] options to control which requests are
+// instrumented.
 package filters // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 
 import (
@@ -11,7 +13,7 @@ import (
 
 	"google.golang.org/grpc/stats"
 
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 )
 
 type gRPCPath struct {
@@ -126,3 +128,36 @@ func ServicePrefix(pre string) otelgrpc.Filter {
 func HealthCheck() otelgrpc.Filter {
 	return ServicePrefix("grpc.health.v1.Health")
 }
+
+// ServiceGlob returns a Filter that returns true if the request's
+// service name, i.e. package.service, matches the shell pattern p as
+// defined by path.Match, e.g. "cedana.*.Daemon". A malformed pattern
+// never matches.
+func ServiceGlob(p string) otelgrpc.Filter {
+	return func(i *stats.RPCTagInfo) bool {
+		ok, _ := path.Match(p, splitFullMethod(i).service)
+		return ok
+	}
+}
+
+// FullMethodGlob returns a Filter that returns true if the request's
+// full RPC method string, i.e. /package.service/method, matches the shell
+// pattern p as defined by path.Match, e.g. "/cedana.*/Get*". A malformed
+// pattern never matches.
+func FullMethodGlob(p string) otelgrpc.Filter {
+	return func(i *stats.RPCTagInfo) bool {
+		ok, _ := path.Match(p, i.FullMethodName)
+		return ok
+	}
+}
+
+// Reflection returns a Filter that returns true if the request's
+// service name is the gRPC Server Reflection service, in either its v1 or
+// v1alpha version.
+// https://github.com/grpc/grpc/blob/master/doc/server-reflection.md
+func Reflection() otelgrpc.Filter {
+	return Any(
+		ServiceName("grpc.reflection.v1.ServerReflection"),
+		ServiceName("grpc.reflection.v1alpha.ServerReflection"),
+	)
+}
@@ -8,7 +8,7 @@ import (
 
 	"google.golang.org/grpc/stats"
 
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 )
 
 type testCase struct {
@@ -296,3 +296,101 @@ func TestHealthCheck(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceGlob(t *testing.T) {
+	const dummyFullMethodName = "/cedana.daemon.v1.Daemon/Dump"
+	tcs := []testCase{
+		{
+			name: "true",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    ServiceGlob("cedana.*.Daemon"),
+			want: true,
+		},
+		{
+			name: "false",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    ServiceGlob("cedana.*.Gpu"),
+			want: false,
+		},
+		{
+			name: "malformed pattern",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    ServiceGlob("cedana.[.Daemon"),
+			want: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		out := tc.f(tc.i)
+		if tc.want != out {
+			t.Errorf("test case '%v' failed, wanted %v but obtained %v", tc.name, tc.want, out)
+		}
+	}
+}
+
+func TestFullMethodGlob(t *testing.T) {
+	const dummyFullMethodName = "/cedana.daemon.v1.Daemon/GetContainerInfo"
+	tcs := []testCase{
+		{
+			name: "true",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    FullMethodGlob("/cedana.*/Get*"),
+			want: true,
+		},
+		{
+			name: "false",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    FullMethodGlob("/cedana.*/Dump"),
+			want: false,
+		},
+		{
+			name: "malformed pattern",
+			i:    dummyRPCTagInfo(dummyFullMethodName),
+			f:    FullMethodGlob("/cedana.*/[Get*"),
+			want: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		out := tc.f(tc.i)
+		if tc.want != out {
+			t.Errorf("test case '%v' failed, wanted %v but obtained %v", tc.name, tc.want, out)
+		}
+	}
+}
+
+func TestReflection(t *testing.T) {
+	tcs := []testCase{
+		{
+			name: "v1",
+			i:    dummyRPCTagInfo("/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"),
+			f:    Reflection(),
+			want: true,
+		},
+		{
+			name: "v1alpha",
+			i:    dummyRPCTagInfo("/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"),
+			f:    Reflection(),
+			want: true,
+		},
+		{
+			name: "false",
+			i:    dummyRPCTagInfo("/grpc.health.v1.Health/Check"),
+			f:    Reflection(),
+			want: false,
+		},
+		{
+			name: "not health check or reflection",
+			i:    dummyRPCTagInfo("/grpc.health.v1.Health/Check"),
+			f:    None(HealthCheck(), Reflection()),
+			want: false,
+		},
+	}
+
+	for _, tc := range tcs {
+		out := tc.f(tc.i)
+		if tc.want != out {
+			t.Errorf("test case '%v' failed, wanted %v but obtained %v", tc.name, tc.want, out)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/matcher"
+
+	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+)
+
+// Matching returns a ContextFilter that returns true for the RPCs matched by
+// m, to share the rules of the filters of otelgrpc and otelhttp.
+//
+// An RPC is matched as a POST request whose path and route are its full
+// method name, e.g. "/grpc.health.v1.Health/Check", whose headers are its
+// metadata, and whose peer is the IP address of the client of a server.
+// The peer of a client RPC is unknown.
+func Matching(m matcher.Matcher) otelgrpc.ContextFilter {
+	return func(ctx context.Context, i *stats.RPCTagInfo) bool {
+		return m(matcherRequest(ctx, i))
+	}
+}
+
+// NotMatching returns a ContextFilter that returns true for the RPCs not
+// matched by m, e.g. health checks, see Matching.
+func NotMatching(m matcher.Matcher) otelgrpc.ContextFilter {
+	return func(ctx context.Context, i *stats.RPCTagInfo) bool {
+		return !m(matcherRequest(ctx, i))
+	}
+}
+
+// matcherRequest returns the matcher.Request describing the RPC i whose
+// context is ctx.
+func matcherRequest(ctx context.Context, i *stats.RPCTagInfo) *matcher.Request {
+	r := &matcher.Request{
+		Method: http.MethodPost,
+		Path:   i.FullMethodName,
+		Route:  i.FullMethodName,
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.Peer = p.Addr.String()
+		if host, _, err := net.SplitHostPort(r.Peer); err == nil {
+			r.Peer = host
+		}
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		md, _ = metadata.FromOutgoingContext(ctx)
+	}
+	r.Header = md.Get
+	return r
+}
@@ -0,0 +1,45 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/contrib/instrumentation/matcher"
+)
+
+func TestMatching(t *testing.T) {
+	healthChecks := matcher.Or(
+		matcher.Path("/grpc.health.v1.Health/*"),
+		matcher.Header("User-Agent", "kube-probe/*"),
+	)
+	info := dummyRPCTagInfo("/example.HelloService/Hello")
+	health := dummyRPCTagInfo("/grpc.health.v1.Health/Check")
+
+	ctx := context.Background()
+	assert.True(t, Matching(healthChecks)(ctx, health))
+	assert.False(t, Matching(healthChecks)(ctx, info))
+	assert.False(t, NotMatching(healthChecks)(ctx, health))
+	assert.True(t, NotMatching(healthChecks)(ctx, info))
+
+	incoming := metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "kube-probe/1.30"))
+	assert.True(t, Matching(healthChecks)(incoming, info))
+	outgoing := metadata.NewOutgoingContext(ctx, metadata.Pairs("user-agent", "kube-probe/1.30"))
+	assert.True(t, Matching(healthChecks)(outgoing, info))
+
+	lb := matcher.Peer(netip.MustParsePrefix("10.0.0.0/8"))
+	fromLB := peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 4242}})
+	assert.True(t, Matching(lb)(fromLB, info))
+	assert.False(t, Matching(lb)(ctx, info))
+
+	assert.True(t, Matching(matcher.Method("POST"))(ctx, info))
+	assert.True(t, Matching(matcher.Route("/example.HelloService/Hello"))(ctx, info))
+}
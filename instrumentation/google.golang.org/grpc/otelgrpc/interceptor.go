@@ -30,17 +30,34 @@ import (
 
 type messageType attribute.KeyValue
 
-// Event adds an event of the messageType to the span associated with the
-// passed context with a message id.
-func (m messageType) Event(ctx context.Context, id int, _ interface{}) {
+// interceptorContext returns the gRPCContext holding the payload capture
+// state of an RPC calling fullMethod instrumented by an interceptor. ctx must
+// hold the span of the RPC.
+func (c *config) interceptorContext(ctx context.Context, fullMethod string) *gRPCContext {
+	return &gRPCContext{
+		fullMethod:     fullMethod,
+		capturePayload: trace.SpanFromContext(ctx).IsRecording() && c.capturePayload(fullMethod),
+	}
+}
+
+// messageEvent adds an event of messageType m with a message id to the span
+// associated with ctx. The payload of the message is recorded on the event
+// the same way it is by the stats handlers.
+func (c *config) messageEvent(ctx context.Context, gctx *gRPCContext, m messageType, id int, payload interface{}) {
 	span := trace.SpanFromContext(ctx)
 	if !span.IsRecording() {
 		return
 	}
-	span.AddEvent("message", trace.WithAttributes(
-		attribute.KeyValue(m),
-		RPCMessageIDKey.Int(id),
-	))
+
+	e := messageEvent{
+		attrs: append([]attribute.KeyValue{attribute.KeyValue(m), RPCMessageIDKey.Int(id)}, messageProtoTypeAttrs(payload)...),
+		sent:  m == messageSent,
+	}
+	key := c.PayloadRequestKey
+	if e.sent {
+		key = c.PayloadResponseKey
+	}
+	c.addMessageEvent(ctx, span, gctx, e, key, payload)
 }
 
 var (
@@ -92,20 +109,22 @@ func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
 		defer span.End()
 
 		ctx = inject(ctx, cfg.Propagators)
+		gctx := cfg.interceptorContext(ctx, method)
 
 		if cfg.SentEvent {
-			messageSent.Event(ctx, 1, req)
+			cfg.messageEvent(ctx, gctx, messageSent, 1, req)
 		}
 
 		err := invoker(ctx, method, req, reply, cc, callOpts...)
 
 		if cfg.ReceivedEvent {
-			messageReceived.Event(ctx, 1, reply)
+			cfg.messageEvent(ctx, gctx, messageReceived, 1, reply)
 		}
+		cfg.flushMessageEvents(ctx, span, gctx, err != nil)
 
 		if err != nil {
 			s, _ := status.FromError(err)
-			span.SetStatus(codes.Error, s.Message())
+			span.SetStatus(cfg.spanStatus(s, false))
 			span.SetAttributes(statusCodeAttr(s.Code()))
 		} else {
 			span.SetAttributes(statusCodeAttr(grpc_codes.OK))
@@ -122,6 +141,8 @@ type clientStream struct {
 	desc *grpc.StreamDesc
 
 	span trace.Span
+	cfg  *config
+	gctx *gRPCContext
 
 	receivedEvent bool
 	sentEvent     bool
@@ -145,7 +166,7 @@ func (w *clientStream) RecvMsg(m interface{}) error {
 		w.receivedMessageID++
 
 		if w.receivedEvent {
-			messageReceived.Event(w.Context(), w.receivedMessageID, m)
+			w.cfg.messageEvent(w.Context(), w.gctx, messageReceived, w.receivedMessageID, m)
 		}
 	}
 
@@ -158,7 +179,7 @@ func (w *clientStream) SendMsg(m interface{}) error {
 	w.sentMessageID++
 
 	if w.sentEvent {
-		messageSent.Event(w.Context(), w.sentMessageID, m)
+		w.cfg.messageEvent(w.Context(), w.gctx, messageSent, w.sentMessageID, m)
 	}
 
 	if err != nil {
@@ -186,20 +207,23 @@ func (w *clientStream) CloseSend() error {
 	return err
 }
 
-func wrapClientStream(s grpc.ClientStream, desc *grpc.StreamDesc, span trace.Span, cfg *config) *clientStream {
+func wrapClientStream(s grpc.ClientStream, desc *grpc.StreamDesc, span trace.Span, cfg *config, gctx *gRPCContext) *clientStream {
 	return &clientStream{
 		ClientStream:  s,
 		span:          span,
 		desc:          desc,
+		cfg:           cfg,
+		gctx:          gctx,
 		receivedEvent: cfg.ReceivedEvent,
 		sentEvent:     cfg.SentEvent,
 	}
 }
 
 func (w *clientStream) endSpan(err error) {
+	w.cfg.flushMessageEvents(w.Context(), w.span, w.gctx, err != nil)
 	if err != nil {
 		s, _ := status.FromError(err)
-		w.span.SetStatus(codes.Error, s.Message())
+		w.span.SetStatus(w.cfg.spanStatus(s, false))
 		w.span.SetAttributes(statusCodeAttr(s.Code()))
 	} else {
 		w.span.SetAttributes(statusCodeAttr(grpc_codes.OK))
@@ -255,12 +279,12 @@ func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
 		s, err := streamer(ctx, desc, cc, method, callOpts...)
 		if err != nil {
 			grpcStatus, _ := status.FromError(err)
-			span.SetStatus(codes.Error, grpcStatus.Message())
+			span.SetStatus(cfg.spanStatus(grpcStatus, false))
 			span.SetAttributes(statusCodeAttr(grpcStatus.Code()))
 			span.End()
 			return s, err
 		}
-		stream := wrapClientStream(s, desc, span, cfg)
+		stream := wrapClientStream(s, desc, span, cfg, cfg.interceptorContext(ctx, method))
 		return stream, nil
 	}
 }
@@ -296,6 +320,7 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 		startOpts := append([]trace.SpanStartOption{
 			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(attr...),
+			trace.WithAttributes(tlsPeerAttrs(ctx)...),
 		},
 			cfg.SpanStartOptions...,
 		)
@@ -307,8 +332,9 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 		)
 		defer span.End()
 
+		gctx := cfg.interceptorContext(ctx, info.FullMethod)
 		if cfg.ReceivedEvent {
-			messageReceived.Event(ctx, 1, req)
+			cfg.messageEvent(ctx, gctx, messageReceived, 1, req)
 		}
 
 		before := time.Now()
@@ -317,24 +343,23 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 
 		s, _ := status.FromError(err)
 		if err != nil {
-			statusCode, msg := serverStatus(s)
-			span.SetStatus(statusCode, msg)
+			span.SetStatus(cfg.spanStatus(s, true))
 			if cfg.SentEvent {
-				messageSent.Event(ctx, 1, s.Proto())
+				cfg.messageEvent(ctx, gctx, messageSent, 1, s.Proto())
 			}
 		} else {
 			if cfg.SentEvent {
-				messageSent.Event(ctx, 1, resp)
+				cfg.messageEvent(ctx, gctx, messageSent, 1, resp)
 			}
 		}
+		cfg.flushMessageEvents(ctx, span, gctx, err != nil)
 		grpcStatusCodeAttr := statusCodeAttr(s.Code())
 		span.SetAttributes(grpcStatusCodeAttr)
 
-		// Use floating point division here for higher precision (instead of Millisecond method).
-		elapsedTime := float64(time.Since(before)) / float64(time.Millisecond)
+		elapsedTime := time.Since(before)
 
 		metricAttrs = append(metricAttrs, grpcStatusCodeAttr)
-		cfg.rpcDuration.Record(ctx, elapsedTime, metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
+		cfg.recordRPCDuration(ctx, elapsedTime, metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
 
 		return resp, err
 	}
@@ -344,7 +369,9 @@ func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
 // SendMsg method call.
 type serverStream struct {
 	grpc.ServerStream
-	ctx context.Context
+	ctx  context.Context
+	cfg  *config
+	gctx *gRPCContext
 
 	receivedMessageID int
 	sentMessageID     int
@@ -363,7 +390,7 @@ func (w *serverStream) RecvMsg(m interface{}) error {
 	if err == nil {
 		w.receivedMessageID++
 		if w.receivedEvent {
-			messageReceived.Event(w.Context(), w.receivedMessageID, m)
+			w.cfg.messageEvent(w.Context(), w.gctx, messageReceived, w.receivedMessageID, m)
 		}
 	}
 
@@ -375,16 +402,18 @@ func (w *serverStream) SendMsg(m interface{}) error {
 
 	w.sentMessageID++
 	if w.sentEvent {
-		messageSent.Event(w.Context(), w.sentMessageID, m)
+		w.cfg.messageEvent(w.Context(), w.gctx, messageSent, w.sentMessageID, m)
 	}
 
 	return err
 }
 
-func wrapServerStream(ctx context.Context, ss grpc.ServerStream, cfg *config) *serverStream {
+func wrapServerStream(ctx context.Context, ss grpc.ServerStream, cfg *config, gctx *gRPCContext) *serverStream {
 	return &serverStream{
 		ServerStream:  ss,
 		ctx:           ctx,
+		cfg:           cfg,
+		gctx:          gctx,
 		receivedEvent: cfg.ReceivedEvent,
 		sentEvent:     cfg.SentEvent,
 	}
@@ -413,7 +442,7 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 			Type:             StreamServer,
 		}
 		if cfg.InterceptorFilter != nil && !cfg.InterceptorFilter(i) {
-			return handler(srv, wrapServerStream(ctx, ss, cfg))
+			return handler(srv, wrapServerStream(ctx, ss, cfg, cfg.interceptorContext(ctx, info.FullMethod)))
 		}
 
 		ctx = extract(ctx, cfg.Propagators)
@@ -422,6 +451,7 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 		startOpts := append([]trace.SpanStartOption{
 			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(attr...),
+			trace.WithAttributes(tlsPeerAttrs(ctx)...),
 		},
 			cfg.SpanStartOptions...,
 		)
@@ -433,11 +463,12 @@ func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
 		)
 		defer span.End()
 
-		err := handler(srv, wrapServerStream(ctx, ss, cfg))
+		gctx := cfg.interceptorContext(ctx, info.FullMethod)
+		err := handler(srv, wrapServerStream(ctx, ss, cfg, gctx))
+		cfg.flushMessageEvents(ctx, span, gctx, err != nil)
 		if err != nil {
 			s, _ := status.FromError(err)
-			statusCode, msg := serverStatus(s)
-			span.SetStatus(statusCode, msg)
+			span.SetStatus(cfg.spanStatus(s, true))
 			span.SetAttributes(statusCodeAttr(s.Code()))
 		} else {
 			span.SetAttributes(statusCodeAttr(grpc_codes.OK))
@@ -506,6 +537,25 @@ func statusCodeAttr(c grpc_codes.Code) attribute.KeyValue {
 	return GRPCStatusCodeKey.Int64(int64(c))
 }
 
+// spanStatus returns the span status code and description of an RPC that
+// failed with grpcStatus. If a StatusCodeMapper is configured, it decides
+// the status and the message of grpcStatus is used as the description of
+// error statuses it returns without one. Otherwise, client spans are always
+// marked as errors and server spans as described by serverStatus.
+func (c *config) spanStatus(grpcStatus *status.Status, isServer bool) (codes.Code, string) { // nolint: revive  // isServer is not a control flag.
+	if c.StatusCodeMapper != nil {
+		code, desc := c.StatusCodeMapper(grpcStatus.Code(), isServer)
+		if code == codes.Error && desc == "" {
+			desc = grpcStatus.Message()
+		}
+		return code, desc
+	}
+	if isServer {
+		return serverStatus(grpcStatus)
+	}
+	return codes.Error, grpcStatus.Message()
+}
+
 // serverStatus returns a span status code and message for a given gRPC
 // status code. It maps specific gRPC status codes to a corresponding span
 // status code and message. This function is intended for use on the server
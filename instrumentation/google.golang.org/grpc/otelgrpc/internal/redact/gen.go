@@ -0,0 +1,8 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redact // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/internal/redact"
+
+// Generate redact package:
+//go:generate gotmpl --body=../../../../../../internal/shared/redact/redact_test.go.tmpl "--data={}" --out=redact_test.go
+//go:generate gotmpl --body=../../../../../../internal/shared/redact/redact.go.tmpl "--data={}" --out=redact.go
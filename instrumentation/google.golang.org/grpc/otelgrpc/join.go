@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+
+	"google.golang.org/grpc/stats"
+)
+
+// JoinHandlers returns a stats.Handler that calls all handlers, in order. It
+// allows the stats handlers of this package to be combined with other stats
+// handlers, as a gRPC client or server only accepts a single one.
+//
+// The contexts returned by TagRPC and TagConn are chained: each handler is
+// passed the context returned by the previous one, so the values added by all
+// handlers, e.g. propagated metadata, are kept in the returned context. Nil
+// handlers are ignored.
+func JoinHandlers(handlers ...stats.Handler) stats.Handler {
+	var hs joinedHandler
+	for _, h := range handlers {
+		if h != nil {
+			hs = append(hs, h)
+		}
+	}
+	if len(hs) == 1 {
+		return hs[0]
+	}
+	return hs
+}
+
+type joinedHandler []stats.Handler
+
+// TagRPC can attach some information to the given context.
+func (hs joinedHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	for _, h := range hs {
+		ctx = h.TagRPC(ctx, info)
+	}
+	return ctx
+}
+
+// HandleRPC processes the RPC stats.
+func (hs joinedHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	for _, h := range hs {
+		h.HandleRPC(ctx, rs)
+	}
+}
+
+// TagConn can attach some information to the given context.
+func (hs joinedHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	for _, h := range hs {
+		ctx = h.TagConn(ctx, info)
+	}
+	return ctx
+}
+
+// HandleConn processes the Conn stats.
+func (hs joinedHandler) HandleConn(ctx context.Context, cs stats.ConnStats) {
+	for _, h := range hs {
+		h.HandleConn(ctx, cs)
+	}
+}
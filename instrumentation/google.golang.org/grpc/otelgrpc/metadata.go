@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	requestMetadataPrefix  = "rpc.grpc.request.metadata."
+	responseMetadataPrefix = "rpc.grpc.response.metadata."
+)
+
+// sensitiveMetadataKeys are the metadata keys whose values are always
+// redacted when recorded.
+var sensitiveMetadataKeys = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+	"x-api-key":           true,
+}
+
+// metadataAttrs returns the attributes recording the values of the
+// configured MetadataAttributes keys of md. Each attribute key is the
+// metadata key prefixed with prefix.
+func (c *config) metadataAttrs(prefix string, md metadata.MD) []attribute.KeyValue {
+	if len(c.MetadataAttributes) == 0 || len(md) == 0 {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	for _, k := range c.MetadataAttributes {
+		vals := md.Get(k)
+		if len(vals) == 0 {
+			continue
+		}
+		if sensitiveMetadataKeys[k] {
+			vals = []string{redactedValue}
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+k, vals))
+	}
+	return attrs
+}
+
+type metadataAttributesOption struct{ keys []string }
+
+func (o metadataAttributesOption) apply(c *config) {
+	for _, k := range o.keys {
+		c.MetadataAttributes = append(c.MetadataAttributes, strings.ToLower(k))
+	}
+}
+
+// WithMetadataAttributes returns an Option that records the values of the
+// given request and response metadata keys as span attributes. Request
+// metadata is recorded as rpc.grpc.request.metadata.<key> and response
+// headers and trailers as rpc.grpc.response.metadata.<key>, where <key> is
+// the lowercase metadata key.
+//
+// Values of credential bearing keys, such as authorization and cookie, are
+// always recorded as "REDACTED".
+func WithMetadataAttributes(keys ...string) Option {
+	return metadataAttributesOption{keys: keys}
+}
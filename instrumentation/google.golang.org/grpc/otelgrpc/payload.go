@@ -0,0 +1,335 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PayloadMarshaler serializes the request and response messages of an RPC
+// so they can be recorded on message events.
+type PayloadMarshaler interface {
+	// Marshal returns the value recorded for msg, a message sent or received
+	// by the RPC method fullMethod. If an error is returned, the error
+	// message is recorded instead.
+	Marshal(ctx context.Context, fullMethod string, msg any) (attribute.Value, error)
+}
+
+// PayloadMarshalerFunc is an adapter to allow the use of ordinary functions
+// as a PayloadMarshaler.
+type PayloadMarshalerFunc func(ctx context.Context, fullMethod string, msg any) (attribute.Value, error)
+
+// Marshal calls f(ctx, fullMethod, msg).
+func (f PayloadMarshalerFunc) Marshal(ctx context.Context, fullMethod string, msg any) (attribute.Value, error) {
+	return f(ctx, fullMethod, msg)
+}
+
+// protoJSONMarshaler is the default PayloadMarshaler. It serializes proto
+// messages as JSON.
+type protoJSONMarshaler struct {
+	opts protojson.MarshalOptions
+	// limit is the number of bytes of the serialized payload that are
+	// recorded, see payloadToJSON.
+	limit int
+}
+
+func newProtoJSONMarshaler(compact bool, limit int) protoJSONMarshaler {
+	if compact {
+		return protoJSONMarshaler{limit: limit}
+	}
+	return protoJSONMarshaler{
+		opts: protojson.MarshalOptions{
+			EmitUnpopulated: true,
+			Indent:          "  ",
+		},
+		limit: limit,
+	}
+}
+
+func (m protoJSONMarshaler) Marshal(_ context.Context, _ string, msg any) (attribute.Value, error) {
+	data, err := payloadToJSON(msg, m.opts, m.limit)
+	if err != nil {
+		return attribute.Value{}, err
+	}
+	return attribute.StringValue(data), nil
+}
+
+// maxPooledPayloadBuffer is the capacity above which payload serialization
+// buffers are not returned to payloadBufferPool, so a few large messages do
+// not keep large buffers alive.
+const maxPooledPayloadBuffer = 4 << 20
+
+// payloadBufferPool holds the buffers proto messages are serialized into by
+// payloadToJSON.
+var payloadBufferPool = sync.Pool{
+	New: func() any {
+		b := make([]byte, 0, 4096)
+		return &b
+	},
+}
+
+// payloadAttrs returns the attributes recording payload under key on a
+// message event. Any configured field mask and redaction is applied before
+// serialization. A serialized string payload is truncated to the configured
+// PayloadSizeLimit, in which case RPCMessageTruncatedKey is also returned,
+// or replaced by a reference if it is stored in the configured PayloadSink. No
+// attributes are returned if payload capture is disabled for the RPC or its
+// span is not recording.
+func (c *config) payloadAttrs(ctx context.Context, gctx *gRPCContext, key attribute.Key, payload any) []attribute.KeyValue {
+	capture := c.payloadCaptureEnabled() && trace.SpanFromContext(ctx).IsRecording()
+	var fullMethod string
+	if gctx != nil {
+		capture = gctx.capturePayload
+		fullMethod = gctx.fullMethod
+	}
+	if !capture {
+		return nil
+	}
+
+	start := time.Now()
+	if c.PayloadHash {
+		attrs, err := payloadHashAttrs(c.redactPayload(payload))
+		c.recordSerialization(ctx, gctx, start, err)
+		return attrs
+	}
+
+	var attrs []attribute.KeyValue
+	var v attribute.Value
+	var err error
+	if c.PayloadWireFormat {
+		v, err = wireFormat(payload)
+		attrs = append(attrs, wireEncodingBase64)
+	} else {
+		v, err = c.PayloadMarshaler.Marshal(ctx, fullMethod, c.redactPayload(payload))
+	}
+	c.recordSerialization(ctx, gctx, start, err)
+	if err != nil {
+		v = attribute.StringValue(fmt.Sprintf("Error marshaling payload: %v", err))
+		attrs = nil
+	}
+	if v.Type() != attribute.STRING {
+		return []attribute.KeyValue{{Key: key, Value: v}}
+	}
+
+	full := v.AsString()
+	if !c.PayloadWireFormat {
+		full = c.scrubPayload(full)
+	}
+	data, truncated := truncatePayload(full, c.payloadSizeLimit())
+	if !truncated {
+		return append(attrs, key.String(data))
+	}
+	if sinkAttrs, ok := c.sinkPayload(ctx, fullMethod, full); ok {
+		return append(attrs, sinkAttrs...)
+	}
+	c.dropPayloads(ctx, gctx, 1, payloadDropTruncated)
+	return append(attrs,
+		key.String(data),
+		RPCMessageTruncatedKey.Bool(true),
+	)
+}
+
+// payloadHashAttrs returns the attributes recording a SHA-256 hash of payload
+// and its message type. Proto messages are hashed using their deterministic
+// wire encoding so equal messages produce the same hash.
+func payloadHashAttrs(payload any) ([]attribute.KeyValue, error) {
+	var data []byte
+	msgType := fmt.Sprintf("%T", payload)
+	if msg, ok := payload.(proto.Message); ok {
+		msgType = string(msg.ProtoReflect().Descriptor().FullName())
+		var err error
+		data, err = proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return []attribute.KeyValue{RPCMessageProtoTypeKey.String(msgType)}, err
+		}
+	} else {
+		data = []byte(fmt.Sprintf("%+v", payload))
+	}
+
+	sum := sha256.Sum256(data)
+	return []attribute.KeyValue{
+		RPCMessageHashKey.String(hex.EncodeToString(sum[:])),
+		RPCMessageProtoTypeKey.String(msgType),
+	}, nil
+}
+
+// messageProtoTypeAttrs returns the attribute recording the fully-qualified
+// name of the proto message type of payload, if it is a proto message. It
+// is recorded on every message event, even if the payload is not captured.
+func messageProtoTypeAttrs(payload any) []attribute.KeyValue {
+	msg, ok := payload.(proto.Message)
+	if !ok || msg == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		RPCMessageProtoTypeKey.String(string(msg.ProtoReflect().Descriptor().FullName())),
+	}
+}
+
+// emitPayload emits the payload of e as a log record. The record is emitted
+// with ctx so it can be correlated with the span of the RPC. The payload is
+// the body of the record, the other payload attributes, e.g. its encoding,
+// hash or reference, are recorded as its attributes.
+func (c *config) emitPayload(ctx context.Context, gctx *gRPCContext, e messageEvent) {
+	var r log.Record
+	r.SetTimestamp(e.time)
+	r.SetSeverity(log.SeverityInfo)
+
+	attrs := make([]log.KeyValue, 0, len(e.attrs)+len(e.payload))
+	if gctx != nil {
+		for _, kv := range gctx.metricAttrs {
+			attrs = append(attrs, logKeyValue(kv))
+		}
+	}
+	for _, kv := range e.attrs {
+		attrs = append(attrs, logKeyValue(kv))
+	}
+	for _, kv := range e.payload {
+		if kv.Key == e.payloadKey {
+			r.SetBody(logValue(kv.Value))
+			continue
+		}
+		attrs = append(attrs, logKeyValue(kv))
+	}
+	r.AddAttributes(attrs...)
+
+	c.logger.Emit(ctx, r)
+}
+
+func logKeyValue(kv attribute.KeyValue) log.KeyValue {
+	return log.KeyValue{Key: string(kv.Key), Value: logValue(kv.Value)}
+}
+
+func logValue(v attribute.Value) log.Value {
+	switch v.Type() {
+	case attribute.BOOL:
+		return log.BoolValue(v.AsBool())
+	case attribute.INT64:
+		return log.Int64Value(v.AsInt64())
+	case attribute.FLOAT64:
+		return log.Float64Value(v.AsFloat64())
+	case attribute.STRING:
+		return log.StringValue(v.AsString())
+	case attribute.BOOLSLICE:
+		s := v.AsBoolSlice()
+		vals := make([]log.Value, len(s))
+		for i, b := range s {
+			vals[i] = log.BoolValue(b)
+		}
+		return log.SliceValue(vals...)
+	case attribute.INT64SLICE:
+		s := v.AsInt64Slice()
+		vals := make([]log.Value, len(s))
+		for i, n := range s {
+			vals[i] = log.Int64Value(n)
+		}
+		return log.SliceValue(vals...)
+	case attribute.FLOAT64SLICE:
+		s := v.AsFloat64Slice()
+		vals := make([]log.Value, len(s))
+		for i, f := range s {
+			vals[i] = log.Float64Value(f)
+		}
+		return log.SliceValue(vals...)
+	case attribute.STRINGSLICE:
+		s := v.AsStringSlice()
+		vals := make([]log.Value, len(s))
+		for i, str := range s {
+			vals[i] = log.StringValue(str)
+		}
+		return log.SliceValue(vals...)
+	}
+	return log.Value{}
+}
+
+// truncatePayload truncates data to at most limit bytes without splitting a
+// UTF-8 encoded rune. It reports whether data was truncated. A limit less
+// than or equal to zero means no limit.
+func truncatePayload(data string, limit int) (string, bool) {
+	if limit <= 0 || len(data) <= limit {
+		return data, false
+	}
+	for limit > 0 && !utf8.RuneStart(data[limit]) {
+		limit--
+	}
+	return data[:limit], true
+}
+
+// payloadToJSON serializes payload as JSON. Proto messages are serialized
+// into a pooled buffer and only the string actually recorded is allocated:
+// if limit is positive, at most limit+1 bytes are returned, which is enough
+// for truncatePayload to truncate the payload to limit bytes and report it
+// as truncated.
+func payloadToJSON(payload any, marshaler protojson.MarshalOptions, limit int) (string, error) {
+	if payload == nil {
+		return "null", nil
+	}
+
+	protoMsg, ok := payload.(proto.Message)
+	if !ok {
+		return fmt.Sprintf("%+v", payload), nil
+	}
+
+	bp := payloadBufferPool.Get().(*[]byte)
+	defer func() {
+		if cap(*bp) <= maxPooledPayloadBuffer {
+			payloadBufferPool.Put(bp)
+		}
+	}()
+
+	jsonData, err := marshaler.MarshalAppend((*bp)[:0], protoMsg)
+	*bp = jsonData[:0]
+	if err != nil {
+		return "", err
+	}
+
+	if limit > 0 && len(jsonData) > limit {
+		jsonData = jsonData[:limit+1]
+	}
+	return string(jsonData), nil
+}
+
+// Reasons payloads are not recorded, or only partially, reported by the
+// otelgrpc.payloads.dropped metric.
+var (
+	payloadDropTruncated    = attribute.NewSet(attribute.String("reason", "truncated"))
+	payloadDropMaxMessages  = attribute.NewSet(attribute.String("reason", "max_messages"))
+	payloadDropRPCSucceeded = attribute.NewSet(attribute.String("reason", "rpc_succeeded"))
+)
+
+// recordSerialization records the duration of a payload serialization that
+// started at start and failed with err, if not nil, unless metrics are not
+// recorded for the RPC of gctx.
+func (c *config) recordSerialization(ctx context.Context, gctx *gRPCContext, start time.Time, err error) {
+	if gctx != nil && !gctx.recordMetrics {
+		return
+	}
+	c.serializationDuration.Record(ctx, time.Since(start).Seconds())
+	if err != nil {
+		c.serializationErrors.Add(ctx, 1)
+	}
+}
+
+// dropPayloads records n payloads of the RPC of gctx as dropped for reason,
+// unless metrics are not recorded for it.
+func (c *config) dropPayloads(ctx context.Context, gctx *gRPCContext, n int64, reason attribute.Set) {
+	if gctx != nil && !gctx.recordMetrics {
+		return
+	}
+	c.payloadsDropped.Add(ctx, n, metric.WithAttributeSet(reason))
+}
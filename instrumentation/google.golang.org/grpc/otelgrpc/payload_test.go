@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	pb "google.golang.org/grpc/interop/grpc_testing"
+)
+
+func TestPayloadToJSONLimit(t *testing.T) {
+	msg := wrapperspb.String(strings.Repeat("é", 10))
+	full, err := payloadToJSON(msg, protojson.MarshalOptions{}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, `"`+strings.Repeat("é", 10)+`"`, full)
+
+	// One byte more than the limit is kept so truncatePayload reports the
+	// payload as truncated.
+	got, err := payloadToJSON(msg, protojson.MarshalOptions{}, 6)
+	require.NoError(t, err)
+	assert.Equal(t, full[:7], got)
+	data, truncated := truncatePayload(got, 6)
+	assert.True(t, truncated)
+	assert.Equal(t, `"`+strings.Repeat("é", 2), data)
+
+	got, err = payloadToJSON(msg, protojson.MarshalOptions{}, len(full))
+	require.NoError(t, err)
+	assert.Equal(t, full, got)
+}
+
+func TestTruncatePayload(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		limit     int
+		want      string
+		truncated bool
+	}{
+		{name: "NoLimit", data: "abcdef", limit: 0, want: "abcdef"},
+		{name: "UnderLimit", data: "abc", limit: 4, want: "abc"},
+		{name: "AtLimit", data: "abcd", limit: 4, want: "abcd"},
+		{name: "OverLimit", data: "abcdef", limit: 4, want: "abcd", truncated: true},
+		{name: "RuneBoundary", data: "aé", limit: 2, want: "a", truncated: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncatePayload(tt.data, tt.limit)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.truncated, truncated)
+		})
+	}
+}
+
+func BenchmarkPayloadToJSON(b *testing.B) {
+	payload := &pb.SimpleRequest{
+		Payload: &pb.Payload{Body: make([]byte, 1<<20)},
+	}
+
+	for name, limit := range map[string]int{
+		"Unlimited": 0,
+		"Limit4KiB": 4 << 10,
+	} {
+		b.Run(name, func(b *testing.B) {
+			m := newProtoJSONMarshaler(false, limit)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				_, _ = m.Marshal(context.Background(), "/test.Service/Method", payload)
+			}
+		})
+	}
+}
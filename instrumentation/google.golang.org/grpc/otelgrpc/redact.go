@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// redactedValue replaces the value of redacted string fields.
+const redactedValue = "REDACTED"
+
+// fieldRedactor redacts the fields of a message that are annotated with a
+// field option extension.
+type fieldRedactor struct {
+	xt     protoreflect.ExtensionType
+	values []protoreflect.Value
+
+	// redacted caches whether a message type, identified by its full name,
+	// contains fields to redact, either directly or in nested messages.
+	redacted sync.Map
+}
+
+// sensitive reports whether the field fd is redacted.
+func (r *fieldRedactor) sensitive(fd protoreflect.FieldDescriptor) bool {
+	opts := fd.Options()
+	if opts == nil || !proto.HasExtension(opts, r.xt) {
+		return false
+	}
+	v := proto.GetExtension(opts, r.xt)
+	if len(r.values) == 0 {
+		b, ok := v.(bool)
+		return !ok || b
+	}
+
+	ev := r.xt.ValueOf(v)
+	if r.xt.TypeDescriptor().IsList() {
+		l := ev.List()
+		for i := 0; i < l.Len(); i++ {
+			if r.match(l.Get(i)) {
+				return true
+			}
+		}
+		return false
+	}
+	return r.match(ev)
+}
+
+func (r *fieldRedactor) match(v protoreflect.Value) bool {
+	for _, want := range r.values {
+		if v.Equal(want) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsRedaction reports whether messages of type md can contain fields to
+// redact.
+func (r *fieldRedactor) needsRedaction(md protoreflect.MessageDescriptor) bool {
+	return r.scan(md, map[protoreflect.FullName]bool{})
+}
+
+func (r *fieldRedactor) scan(md protoreflect.MessageDescriptor, visiting map[protoreflect.FullName]bool) bool {
+	name := md.FullName()
+	if v, ok := r.redacted.Load(name); ok {
+		return v.(bool)
+	}
+	if visiting[name] {
+		// Recursive message, the outermost scan determines the result.
+		return false
+	}
+	visiting[name] = true
+
+	var found bool
+	fields := md.Fields()
+	for i := 0; i < fields.Len() && !found; i++ {
+		fd := fields.Get(i)
+		switch {
+		case r.sensitive(fd):
+			found = true
+		case fd.IsMap():
+			if vd := fd.MapValue().Message(); vd != nil {
+				found = r.scan(vd, visiting)
+			}
+		case fd.Message() != nil:
+			found = r.scan(fd.Message(), visiting)
+		}
+	}
+
+	delete(visiting, name)
+	if len(visiting) == 0 || found {
+		r.redacted.Store(name, found)
+	}
+	return found
+}
+
+// redact redacts the sensitive fields of msg in place. Sensitive singular
+// string fields are replaced by redactedValue, all other sensitive fields are
+// cleared.
+func (r *fieldRedactor) redact(msg protoreflect.Message) {
+	var fields []protoreflect.FieldDescriptor
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		fields = append(fields, fd)
+		return true
+	})
+	for _, fd := range fields {
+		switch {
+		case r.sensitive(fd):
+			if fd.Kind() == protoreflect.StringKind && fd.Cardinality() != protoreflect.Repeated {
+				msg.Set(fd, protoreflect.ValueOfString(redactedValue))
+			} else {
+				msg.Clear(fd)
+			}
+		case fd.IsMap():
+			if vd := fd.MapValue().Message(); vd != nil && r.needsRedaction(vd) {
+				eachMessage(msg, fd, r.redact)
+			}
+		case fd.Message() != nil && r.needsRedaction(fd.Message()):
+			eachMessage(msg, fd, r.redact)
+		}
+	}
+}
+
+// redactPayload returns payload with the configured field mask and field
+// redaction applied. The passed payload is not modified, a copy is returned
+// if it needs to be changed.
+func (c *config) redactPayload(payload any) any {
+	msg, ok := payload.(proto.Message)
+	if !ok {
+		return payload
+	}
+
+	md := msg.ProtoReflect().Descriptor()
+	m, masked := c.PayloadFieldMasks[md.FullName()]
+	redact := c.payloadRedactor != nil && c.payloadRedactor.needsRedaction(md)
+	if !masked && !redact {
+		return payload
+	}
+
+	msg = proto.Clone(msg)
+	if masked {
+		m.apply(msg.ProtoReflect())
+	}
+	if redact {
+		c.payloadRedactor.redact(msg.ProtoReflect())
+	}
+	return msg
+}
+
+type payloadSensitiveFieldsOption struct {
+	xt     protoreflect.ExtensionType
+	values []protoreflect.Value
+}
+
+func (o payloadSensitiveFieldsOption) apply(c *config) {
+	if o.xt != nil {
+		c.payloadRedactor = &fieldRedactor{xt: o.xt, values: o.values}
+	}
+}
+
+// WithPayloadSensitiveFields returns an Option that redacts fields of
+// recorded payloads based on the field option extension xt, so redaction can
+// be declared in the proto schema. Sensitive string fields are replaced by
+// "REDACTED", other sensitive fields are removed. The message sent or
+// received by the application is never modified.
+//
+// If no values are passed, a field is sensitive if xt is set on its options
+// and, for a bool extension such as a custom (sensitive) = true, is true.
+// Otherwise, a field is sensitive if the value of xt, or one of its elements
+// for repeated extensions, equals one of values. For example, fields with
+// the google.api.field_behavior INPUT_ONLY annotation are redacted with:
+//
+//	WithPayloadSensitiveFields(
+//		annotations.E_FieldBehavior,
+//		protoreflect.ValueOfEnum(annotations.FieldBehavior_INPUT_ONLY.Number()),
+//	)
+func WithPayloadSensitiveFields(xt protoreflect.ExtensionType, values ...protoreflect.Value) Option {
+	return payloadSensitiveFieldsOption{xt: xt, values: values}
+}
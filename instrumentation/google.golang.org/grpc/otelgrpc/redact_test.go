@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// sensitiveTestTypes returns a bool field option extension named sensitive,
+// an enum field option extension named behavior, and a message type
+// annotated with them:
+//
+//	message Credentials {
+//	  string user = 1;
+//	  string token = 2 [(sensitive) = true];
+//	  string note = 3 [(behavior) = INPUT_ONLY];
+//	  repeated Credentials children = 4;
+//	}
+func sensitiveTestTypes(t *testing.T) (sensitive, behavior protoreflect.ExtensionType, md protoreflect.MessageDescriptor) {
+	t.Helper()
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(descriptorpb.File_google_protobuf_descriptor_proto))
+
+	optsFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("options.proto"),
+		Package:    proto.String("test"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Behavior"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("INPUT_ONLY"), Number: proto.Int32(1)},
+			},
+		}},
+		Extension: []*descriptorpb.FieldDescriptorProto{
+			{
+				Name:     proto.String("sensitive"),
+				Number:   proto.Int32(50000),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_BOOL.Enum(),
+				Extendee: proto.String(".google.protobuf.FieldOptions"),
+			},
+			{
+				Name:     proto.String("behavior"),
+				Number:   proto.Int32(50001),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_ENUM.Enum(),
+				TypeName: proto.String(".test.Behavior"),
+				Extendee: proto.String(".google.protobuf.FieldOptions"),
+			},
+		},
+	}, files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(optsFile))
+
+	sensitive = dynamicpb.NewExtensionType(optsFile.Extensions().ByName("sensitive"))
+	behavior = dynamicpb.NewExtensionType(optsFile.Extensions().ByName("behavior"))
+
+	tokenOpts := &descriptorpb.FieldOptions{}
+	proto.SetExtension(tokenOpts, sensitive, true)
+	noteOpts := &descriptorpb.FieldOptions{}
+	behaviors := behavior.New().List()
+	behaviors.Append(protoreflect.ValueOfEnum(1))
+	noteOpts.ProtoReflect().Set(behavior.TypeDescriptor(), protoreflect.ValueOfList(behaviors))
+
+	optional := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	msgFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("credentials.proto"),
+		Package:    proto.String("test"),
+		Dependency: []string{"options.proto"},
+		Syntax:     proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Credentials"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user"), Number: proto.Int32(1), Label: optional, Type: str, JsonName: proto.String("user")},
+				{Name: proto.String("token"), Number: proto.Int32(2), Label: optional, Type: str, JsonName: proto.String("token"), Options: tokenOpts},
+				{Name: proto.String("note"), Number: proto.Int32(3), Label: optional, Type: str, JsonName: proto.String("note"), Options: noteOpts},
+				{
+					Name:     proto.String("children"),
+					Number:   proto.Int32(4),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_MESSAGE.Enum(),
+					TypeName: proto.String(".test.Credentials"),
+					JsonName: proto.String("children"),
+				},
+			},
+		}},
+	}, files)
+	require.NoError(t, err)
+
+	return sensitive, behavior, msgFile.Messages().ByName("Credentials")
+}
+
+func TestPayloadSensitiveFields(t *testing.T) {
+	sensitive, behavior, md := sensitiveTestTypes(t)
+
+	newMsg := func(user, token, note string, children ...*dynamicpb.Message) *dynamicpb.Message {
+		msg := dynamicpb.NewMessage(md)
+		fields := md.Fields()
+		for name, v := range map[protoreflect.Name]string{"user": user, "token": token, "note": note} {
+			if v != "" {
+				msg.Set(fields.ByName(name), protoreflect.ValueOfString(v))
+			}
+		}
+		if len(children) > 0 {
+			l := msg.Mutable(fields.ByName("children")).List()
+			for _, c := range children {
+				l.Append(protoreflect.ValueOfMessage(c))
+			}
+		}
+		return msg
+	}
+
+	tests := []struct {
+		name string
+		opt  Option
+		want *dynamicpb.Message
+	}{
+		{
+			name: "Bool",
+			opt:  WithPayloadSensitiveFields(sensitive),
+			want: newMsg("alice", redactedValue, "n", newMsg("bob", redactedValue, "")),
+		},
+		{
+			name: "RepeatedEnum",
+			opt:  WithPayloadSensitiveFields(behavior, protoreflect.ValueOfEnum(1)),
+			want: newMsg("alice", "t0", redactedValue, newMsg("bob", "t1", "")),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newMsg("alice", "t0", "n", newMsg("bob", "t1", ""))
+			c := newConfig([]Option{tt.opt}, "test")
+
+			got := c.redactPayload(msg)
+			assert.True(t, proto.Equal(tt.want, got.(proto.Message)), "got %v", got)
+			assert.True(t, proto.Equal(newMsg("alice", "t0", "n", newMsg("bob", "t1", "")), msg), "original message modified")
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ReplayRecord is a record of an RPC holding everything needed to issue it
+// again, see WithReplayWriter.
+type ReplayRecord struct {
+	// FullMethod is the full RPC method string, i.e.,
+	// /package.service/method.
+	FullMethod string `json:"method"`
+	// Metadata is the request metadata of the RPC. Credential bearing keys,
+	// such as authorization and cookie, are omitted unless
+	// WithReplayCredentialMetadata is enabled.
+	Metadata metadata.MD `json:"metadata,omitempty"`
+	// Requests and Responses are the messages of the RPC in the proto wire
+	// format, in the order they were sent or received.
+	Requests  [][]byte `json:"requests"`
+	Responses [][]byte `json:"responses"`
+	// Code and Message are the status of the RPC.
+	Code    grpc_codes.Code `json:"code"`
+	Message string          `json:"message,omitempty"`
+	// StartTime and EndTime are the times the RPC began and ended.
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	// TraceID and SpanID are the hex encoded IDs of the span of the RPC.
+	TraceID string `json:"trace_id"`
+	SpanID  string `json:"span_id"`
+}
+
+// ReplayWriter writes the ReplayRecords of RPCs, see WithReplayWriter.
+type ReplayWriter interface {
+	// WriteReplay writes r, the record of an RPC that ended. It must be safe
+	// for concurrent use and should not block, it is called by the RPC.
+	WriteReplay(ctx context.Context, r *ReplayRecord) error
+}
+
+// ReplayWriterFunc is an adapter to allow the use of ordinary functions as a
+// ReplayWriter.
+type ReplayWriterFunc func(ctx context.Context, r *ReplayRecord) error
+
+// WriteReplay calls f(ctx, r).
+func (f ReplayWriterFunc) WriteReplay(ctx context.Context, r *ReplayRecord) error {
+	return f(ctx, r)
+}
+
+// jsonReplayWriter is a ReplayWriter writing records as JSON lines.
+type jsonReplayWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONReplayWriter returns a ReplayWriter that writes records to w as
+// JSON, one record per line. Messages are base64 encoded.
+func NewJSONReplayWriter(w io.Writer) ReplayWriter {
+	return &jsonReplayWriter{enc: json.NewEncoder(w)}
+}
+
+func (w *jsonReplayWriter) WriteReplay(_ context.Context, r *ReplayRecord) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.enc.Encode(r)
+}
+
+// replayRecorder collects the ReplayRecord of an RPC while it is running.
+type replayRecorder struct {
+	mu     sync.Mutex
+	record ReplayRecord
+}
+
+func newReplayRecorder(fullMethod string, sc trace.SpanContext) *replayRecorder {
+	return &replayRecorder{record: ReplayRecord{
+		FullMethod: fullMethod,
+		TraceID:    sc.TraceID().String(),
+		SpanID:     sc.SpanID().String(),
+	}}
+}
+
+// setMetadata records md as the request metadata of the RPC. Credential
+// bearing keys are omitted unless credentials is true.
+func (r *replayRecorder) setMetadata(md metadata.MD, credentials bool) {
+	if r == nil {
+		return
+	}
+	md = md.Copy()
+	if !credentials {
+		for k := range md {
+			if sensitiveMetadataKeys[k] {
+				delete(md, k)
+			}
+		}
+	}
+	r.mu.Lock()
+	r.record.Metadata = md
+	r.mu.Unlock()
+}
+
+// addMessage records a request or response message msg, whose wire format
+// is data if it is known.
+func (r *replayRecorder) addMessage(request bool, msg any, data []byte) {
+	if r == nil {
+		return
+	}
+	if data == nil {
+		m, ok := msg.(proto.Message)
+		if !ok {
+			return
+		}
+		var err error
+		if data, err = proto.Marshal(m); err != nil {
+			otel.Handle(err)
+			return
+		}
+	} else {
+		// Data may be reused by gRPC once the stats handler returns.
+		data = append([]byte(nil), data...)
+	}
+
+	r.mu.Lock()
+	if request {
+		r.record.Requests = append(r.record.Requests, data)
+	} else {
+		r.record.Responses = append(r.record.Responses, data)
+	}
+	r.mu.Unlock()
+}
+
+// writeReplay writes the ReplayRecord of the RPC of gctx that ended as
+// described by end to the configured ReplayWriter, if the RPC is recorded.
+func (c *config) writeReplay(ctx context.Context, gctx *gRPCContext, end *stats.End) {
+	if gctx == nil || gctx.replay == nil {
+		return
+	}
+	if c.ReplayFailedOnly && end.Error == nil {
+		return
+	}
+
+	s, _ := status.FromError(end.Error)
+	r := gctx.replay
+	r.mu.Lock()
+	record := r.record
+	r.mu.Unlock()
+	record.Code, record.Message = s.Code(), s.Message()
+	record.StartTime, record.EndTime = end.BeginTime, end.EndTime
+	if err := c.ReplayWriter.WriteReplay(ctx, &record); err != nil {
+		otel.Handle(err)
+	}
+}
+
+type replayWriterOption struct {
+	w          ReplayWriter
+	failedOnly bool
+}
+
+func (o replayWriterOption) apply(c *config) {
+	c.ReplayWriter = o.w
+	c.ReplayFailedOnly = o.failedOnly
+}
+
+// WithReplayWriter returns an Option that writes a ReplayRecord of the RPCs
+// handled by a stats handler to w when they end, so they can be issued again
+// elsewhere, e.g. to reproduce a failure on another host. If failedOnly is
+// true, only the records of failed RPCs are written. Errors returned by w
+// are reported to the global error handler.
+//
+// Records are only collected for RPCs whose payloads are captured, see
+// WithPayloadCapture, WithPayloadMethods and WithPayloadSamplingRatio.
+// Messages are recorded in the proto wire format, they are not subject to
+// field masks, redaction or scrubbing. The request metadata is recorded
+// without its credential bearing keys, such as authorization, cookie and
+// x-api-key, see WithReplayCredentialMetadata.
+func WithReplayWriter(w ReplayWriter, failedOnly bool) Option {
+	return replayWriterOption{w: w, failedOnly: failedOnly}
+}
+
+type replayCredentialMetadataOption struct{ enabled bool }
+
+func (o replayCredentialMetadataOption) apply(c *config) {
+	c.ReplayCredentials = o.enabled
+}
+
+// WithReplayCredentialMetadata returns an Option that records the values of
+// credential bearing request metadata keys, such as authorization, cookie
+// and x-api-key, in the ReplayRecords written by WithReplayWriter, for RPCs
+// to be issued again with the same credentials. They are omitted by default,
+// this must only be enabled if the records are written to a trusted
+// destination.
+func WithReplayCredentialMetadata(enabled bool) Option {
+	return replayCredentialMetadataOption{enabled: enabled}
+}
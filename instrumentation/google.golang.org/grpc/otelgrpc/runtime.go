@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import "sync/atomic"
+
+// RuntimeSettings are the settings of stats handlers and interceptors that
+// can be changed while they are in use, see RuntimeControl.
+type RuntimeSettings struct {
+	// PayloadCapture replaces the value set by WithPayloadCapture.
+	PayloadCapture bool
+	// PayloadSizeLimit replaces the value set by WithPayloadSizeLimit.
+	PayloadSizeLimit int
+	// Filter replaces the Filter set by WithFilter. If nil, no RPCs are
+	// filtered out.
+	Filter Filter
+}
+
+// RuntimeControl holds RuntimeSettings that can be updated while a process
+// is running, e.g. from an admin endpoint, without recreating the stats
+// handlers and interceptors using them. It is safe for concurrent use.
+type RuntimeControl struct {
+	settings atomic.Pointer[RuntimeSettings]
+}
+
+// NewRuntimeControl returns a RuntimeControl holding s.
+func NewRuntimeControl(s RuntimeSettings) *RuntimeControl {
+	r := &RuntimeControl{}
+	r.Update(s)
+	return r
+}
+
+// Settings returns the current settings of r.
+func (r *RuntimeControl) Settings() RuntimeSettings {
+	return *r.settings.Load()
+}
+
+// Update replaces the settings of r with s. RPCs that already started keep
+// using the filter and payload capture decision made when they started.
+func (r *RuntimeControl) Update(s RuntimeSettings) {
+	r.settings.Store(&s)
+}
+
+type runtimeControlOption struct{ r *RuntimeControl }
+
+func (o runtimeControlOption) apply(c *config) {
+	c.RuntimeControl = o.r
+}
+
+// WithRuntimeControl returns an Option that reads the settings described by
+// RuntimeSettings from r each time they are used, instead of the values set
+// by the corresponding options, so they can be changed at runtime.
+func WithRuntimeControl(r *RuntimeControl) Option {
+	return runtimeControlOption{r: r}
+}
+
+// payloadCaptureEnabled returns whether payload capture is enabled.
+func (c *config) payloadCaptureEnabled() bool {
+	if c.RuntimeControl != nil {
+		return c.RuntimeControl.Settings().PayloadCapture
+	}
+	return c.PayloadCapture
+}
+
+// payloadSizeLimit returns the size limit of serialized payloads.
+func (c *config) payloadSizeLimit() int {
+	if c.RuntimeControl != nil {
+		return c.RuntimeControl.Settings().PayloadSizeLimit
+	}
+	return c.PayloadSizeLimit
+}
+
+// rpcFilter returns the Filter of the RPCs recorded by stats handlers.
+func (c *config) rpcFilter() Filter {
+	if c.RuntimeControl != nil {
+		return c.RuntimeControl.Settings().Filter
+	}
+	return c.Filter
+}
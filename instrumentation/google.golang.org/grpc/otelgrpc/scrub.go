@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc/internal/redact"
+)
+
+// scrubPayload applies the configured JSON path and regular expression
+// scrubbing to the serialized payload data. If JSON paths are configured and
+// data is not valid JSON, "REDACTED" is returned instead of data.
+func (c *config) scrubPayload(data string) string {
+	data = redact.JSON(data, c.PayloadScrubPaths)
+	for _, re := range c.PayloadScrubRegexps {
+		data = scrubRegexp(re, data)
+	}
+	return data
+}
+
+// scrubRegexp replaces the matches of re in data with redactedValue. If re
+// has subexpressions, only the text matched by them is replaced.
+func scrubRegexp(re *regexp.Regexp, data string) string {
+	if re.NumSubexp() == 0 {
+		return re.ReplaceAllLiteralString(data, redactedValue)
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range re.FindAllStringSubmatchIndex(data, -1) {
+		for i := 2; i < len(m); i += 2 {
+			start, end := m[i], m[i+1]
+			if start < last || start < 0 {
+				continue
+			}
+			b.WriteString(data[last:start])
+			b.WriteString(redactedValue)
+			last = end
+		}
+	}
+	if last == 0 {
+		return data
+	}
+	b.WriteString(data[last:])
+	return b.String()
+}
+
+type payloadScrubJSONPathsOption struct{ paths []string }
+
+func (o payloadScrubJSONPathsOption) apply(c *config) {
+	for _, p := range o.paths {
+		path, err := redact.ParseJSONPath(p)
+		if err != nil {
+			otel.Handle(err)
+			continue
+		}
+		c.PayloadScrubPaths = append(c.PayloadScrubPaths, path)
+	}
+}
+
+// WithPayloadScrubJSONPaths returns an Option that replaces the values
+// selected by paths in serialized payloads with "REDACTED" before they are
+// recorded. Unlike WithPayloadFieldMask and WithPayloadSensitiveFields, it
+// does not depend on the proto schema of the messages and applies to the
+// JSON produced by any PayloadMarshaler.
+//
+// Paths use a subset of JSONPath: they start with "$" followed by member
+// names (".credentials" or "['credentials']"), array indices ("[0]") and
+// wildcards (".*" or "[*]"), e.g. "$.credentials.*" or "$.items[*].token".
+// Invalid paths are reported to the global error handler and ignored.
+//
+// Scrubbed payloads are re-encoded, which sorts object members by name.
+// Payloads that are not valid JSON are replaced with "REDACTED" entirely.
+// Passing this option multiple times adds all the paths.
+func WithPayloadScrubJSONPaths(paths ...string) Option {
+	return payloadScrubJSONPathsOption{paths: paths}
+}
+
+type payloadScrubRegexpsOption struct{ res []*regexp.Regexp }
+
+func (o payloadScrubRegexpsOption) apply(c *config) {
+	for _, re := range o.res {
+		if re != nil {
+			c.PayloadScrubRegexps = append(c.PayloadScrubRegexps, re)
+		}
+	}
+}
+
+// WithPayloadScrubRegexps returns an Option that replaces the matches of res
+// in serialized payloads with "REDACTED" before they are recorded. If an
+// expression has subexpressions, only the text they match is replaced, e.g.
+// `"token":\s*"([^"]*)"` only replaces the token value. Regular expressions
+// are applied after WithPayloadScrubJSONPaths, in order. Passing this option
+// multiple times adds all the expressions.
+func WithPayloadScrubRegexps(res ...*regexp.Regexp) Option {
+	return payloadScrubRegexpsOption{res: res}
+}
@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScrubPayload(t *testing.T) {
+	const payload = `{"user":"alice","credentials":{"token":"t0k3n","key":"k"},"items":[{"token":"a","id":1},{"token":"b","id":2}]}`
+
+	tests := []struct {
+		name string
+		opts []Option
+		data string
+		want string
+	}{
+		{
+			name: "Member",
+			opts: []Option{WithPayloadScrubJSONPaths("$.user")},
+			data: `{"user":"alice","id":1}`,
+			want: `{"id":1,"user":"REDACTED"}`,
+		},
+		{
+			name: "Wildcard",
+			opts: []Option{WithPayloadScrubJSONPaths("$.credentials.*", "$.items[*].token")},
+			data: payload,
+			want: `{"credentials":{"key":"REDACTED","token":"REDACTED"},"items":[{"id":1,"token":"REDACTED"},{"id":2,"token":"REDACTED"}],"user":"alice"}`,
+		},
+		{
+			name: "Index",
+			opts: []Option{WithPayloadScrubJSONPaths("$.items[1]")},
+			data: `{"items":[1,2,3]}`,
+			want: `{"items":[1,"REDACTED",3]}`,
+		},
+		{
+			name: "NoMatch",
+			opts: []Option{WithPayloadScrubJSONPaths("$.password")},
+			data: "{\n  \"user\": \"alice\"\n}",
+			want: "{\n  \"user\": \"alice\"\n}",
+		},
+		{
+			name: "Indented",
+			opts: []Option{WithPayloadScrubJSONPaths("$.user")},
+			data: "{\n  \"user\": \"alice\"\n}",
+			want: "{\n  \"user\": \"REDACTED\"\n}",
+		},
+		{
+			name: "InvalidJSON",
+			opts: []Option{WithPayloadScrubJSONPaths("$.user")},
+			data: "Error marshaling payload",
+			want: "REDACTED",
+		},
+		{
+			name: "InvalidPath",
+			opts: []Option{WithPayloadScrubJSONPaths("user")},
+			data: "not JSON",
+			want: "not JSON",
+		},
+		{
+			name: "Regexp",
+			opts: []Option{WithPayloadScrubRegexps(regexp.MustCompile(`sk-[a-z0-9]+`))},
+			data: `{"key":"sk-abc123","other":"sk-def"}`,
+			want: `{"key":"REDACTED","other":"REDACTED"}`,
+		},
+		{
+			name: "RegexpSubexp",
+			opts: []Option{WithPayloadScrubRegexps(regexp.MustCompile(`"token":\s*"([^"]*)"`))},
+			data: `{"token": "a", "user": "alice", "token":"b"}`,
+			want: `{"token": "REDACTED", "user": "alice", "token":"REDACTED"}`,
+		},
+		{
+			name: "PathsThenRegexps",
+			opts: []Option{
+				WithPayloadScrubJSONPaths("$.user"),
+				WithPayloadScrubRegexps(nil, regexp.MustCompile(`REDACTED`)),
+			},
+			data: `{"user":"alice"}`,
+			want: `{"user":"REDACTED"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newConfig(tt.opts, "server")
+			assert.Equal(t, tt.want, c.scrubPayload(tt.data))
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 
 import (
+	grpc_codes "google.golang.org/grpc/codes"
+
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
@@ -25,8 +27,152 @@ const (
 	// The uncompressed size of the message transmitted or received in
 	// bytes.
 	RPCMessageUncompressedSizeKey = attribute.Key("message.uncompressed_size")
+
+	// Whether the payload recorded for the message transmitted or received
+	// was truncated to the configured payload size limit.
+	RPCMessageTruncatedKey = attribute.Key("message.truncated")
+
+	// Hex encoded SHA-256 hash of the message transmitted or received.
+	RPCMessageHashKey = attribute.Key("message.hash")
+
+	// Fully-qualified proto type name of the message transmitted or
+	// received. It is namespaced under rpc.message, but not named
+	// rpc.message.type, which the latest semantic conventions define as the
+	// direction of the message, SENT or RECEIVED, see
+	// OTEL_SEMCONV_STABILITY_OPT_IN.
+	RPCMessageProtoTypeKey = attribute.Key("rpc.message.proto_type")
+
+	// Message ID of the request message answered by the response message
+	// transmitted or received, see WithMessageCorrelation.
+	RPCMessageCorrelatedIDKey = attribute.Key("message.correlated_id")
+
+	// URI of the complete payload of the message transmitted or received,
+	// stored by the PayloadSink configured with WithPayloadSink.
+	RPCMessagePayloadURIKey = attribute.Key("message.payload_uri")
+
+	// Encoding of the payload recorded for the message transmitted or
+	// received, if it is not JSON, see WithPayloadWireFormat.
+	RPCMessagePayloadEncodingKey = attribute.Key("message.payload_encoding")
+
+	// JSON encoded details of the status of a failed RPC, see
+	// google.golang.org/genproto/googleapis/rpc/errdetails.
+	RPCGRPCStatusDetailsKey = attribute.Key("rpc.grpc.status_details")
+
+	// Whether the RPC has a deadline.
+	RPCGRPCRequestDeadlineSetKey = attribute.Key("rpc.grpc.request.deadline_set")
+
+	// Deadline of the RPC as an RFC 3339 timestamp.
+	RPCGRPCRequestDeadlineKey = attribute.Key("rpc.grpc.request.deadline")
+
+	// Time remaining until the deadline of the RPC when it began, in
+	// seconds.
+	RPCGRPCRequestTimeRemainingKey = attribute.Key("rpc.grpc.request.time_remaining")
+
+	// Whether an RPC failing with DEADLINE_EXCEEDED exceeded its own
+	// deadline ("local") or received the error from elsewhere ("remote").
+	RPCGRPCDeadlineExceededSourceKey = attribute.Key("rpc.grpc.deadline_exceeded.source")
+
+	// Why a failed RPC ended, e.g. "cancelled_by_client" or
+	// "deadline_exceeded". Unlike the status code, it tells a client
+	// cancelling the RPC apart from its deadline expiring.
+	RPCGRPCEndReasonKey = attribute.Key("rpc.grpc.end_reason")
+
+	// Zero-based position of a span of a streaming RPC whose span was
+	// split, see WithStreamSpanSplitting.
+	RPCGRPCStreamSegmentKey = attribute.Key("rpc.grpc.stream.segment")
+
+	// Address of the backend the load balancer of the client picked for the
+	// RPC.
+	RPCGRPCLBBackendAddressKey = attribute.Key("rpc.grpc.lb.backend_address")
+
+	// Load balancing policy of the client, see WithLoadBalancer.
+	RPCGRPCLBPolicyKey = attribute.Key("rpc.grpc.lb.policy")
+
+	// Channelz IDs of the connection of an RPC, see WithChannelz.
+	RPCGRPCChannelzSocketIDKey     = attribute.Key("rpc.grpc.channelz.socket_id")
+	RPCGRPCChannelzServerIDKey     = attribute.Key("rpc.grpc.channelz.server_id")
+	RPCGRPCChannelzChannelIDKey    = attribute.Key("rpc.grpc.channelz.channel_id")
+	RPCGRPCChannelzSubchannelIDKey = attribute.Key("rpc.grpc.channelz.subchannel_id")
+)
+
+// Attribute keys of the TLS connection of server RPCs, see
+// https://opentelemetry.io/docs/specs/semconv/attributes-registry/tls/.
+const (
+	// Name of the protocol of the connection, always "tls".
+	TLSProtocolNameKey = attribute.Key("tls.protocol.name")
+
+	// Version of the TLS protocol of the connection, e.g. "1.3".
+	TLSProtocolVersionKey = attribute.Key("tls.protocol.version")
+
+	// Name of the cipher suite of the connection, e.g.
+	// "TLS_AES_128_GCM_SHA256".
+	TLSCipherKey = attribute.Key("tls.cipher")
+
+	// Distinguished name of the subject of the client certificate.
+	TLSClientSubjectKey = attribute.Key("tls.client.subject")
+
+	// Distinguished name of the issuer of the client certificate.
+	TLSClientIssuerKey = attribute.Key("tls.client.issuer")
+
+	// SPIFFE ID of the client, from the URI SAN of its certificate.
+	TLSClientSPIFFEIDKey = attribute.Key("tls.client.spiffe_id")
 )
 
+// Attribute keys of stream.progress events, see WithStreamProgressInterval.
+const (
+	// Number of messages received or sent by the stream so far.
+	StreamMessagesReceivedKey = attribute.Key("stream.messages_received")
+	StreamMessagesSentKey     = attribute.Key("stream.messages_sent")
+
+	// Compressed message bytes received or sent by the stream so far.
+	StreamBytesReceivedKey = attribute.Key("stream.bytes_received")
+	StreamBytesSentKey     = attribute.Key("stream.bytes_sent")
+
+	// Compressed message bytes per second received or sent by the stream
+	// since the previous progress event.
+	StreamReceiveThroughputKey = attribute.Key("stream.receive_throughput")
+	StreamSendThroughputKey    = attribute.Key("stream.send_throughput")
+)
+
+// Attribute keys of the gRPC OpenTelemetry metrics, see
+// https://github.com/grpc/proposal/blob/master/A66-otel-stats.md.
+const (
+	// Full gRPC method name, without the leading slash.
+	GRPCMethodKey = attribute.Key("grpc.method")
+
+	// gRPC status code received by the client, e.g. "DEADLINE_EXCEEDED".
+	GRPCStatusKey = attribute.Key("grpc.status")
+)
+
+// grpcStatusNames are the canonical names of the gRPC status codes.
+var grpcStatusNames = [...]string{
+	grpc_codes.OK:                 "OK",
+	grpc_codes.Canceled:           "CANCELLED",
+	grpc_codes.Unknown:            "UNKNOWN",
+	grpc_codes.InvalidArgument:    "INVALID_ARGUMENT",
+	grpc_codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	grpc_codes.NotFound:           "NOT_FOUND",
+	grpc_codes.AlreadyExists:      "ALREADY_EXISTS",
+	grpc_codes.PermissionDenied:   "PERMISSION_DENIED",
+	grpc_codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	grpc_codes.FailedPrecondition: "FAILED_PRECONDITION",
+	grpc_codes.Aborted:            "ABORTED",
+	grpc_codes.OutOfRange:         "OUT_OF_RANGE",
+	grpc_codes.Unimplemented:      "UNIMPLEMENTED",
+	grpc_codes.Internal:           "INTERNAL",
+	grpc_codes.Unavailable:        "UNAVAILABLE",
+	grpc_codes.DataLoss:           "DATA_LOSS",
+	grpc_codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// grpcStatus returns the grpc.status attribute for code.
+func grpcStatus(code grpc_codes.Code) attribute.KeyValue {
+	if int(code) < len(grpcStatusNames) {
+		return GRPCStatusKey.String(grpcStatusNames[code])
+	}
+	return GRPCStatusKey.String(code.String())
+}
+
 // Semantic conventions for common RPC attributes.
 var (
 	// Semantic convention for gRPC as the remoting system.
@@ -38,4 +184,15 @@ var (
 	// Semantic conventions for RPC message types.
 	RPCMessageTypeSent     = RPCMessageTypeKey.String("SENT")
 	RPCMessageTypeReceived = RPCMessageTypeKey.String("RECEIVED")
+
+	// Semantic conventions for the sources of DEADLINE_EXCEEDED errors.
+	RPCGRPCDeadlineExceededLocal  = RPCGRPCDeadlineExceededSourceKey.String("local")
+	RPCGRPCDeadlineExceededRemote = RPCGRPCDeadlineExceededSourceKey.String("remote")
+
+	// Semantic conventions for the reasons failed RPCs ended.
+	RPCGRPCEndReasonCancelledByClient = RPCGRPCEndReasonKey.String("cancelled_by_client")
+	RPCGRPCEndReasonCancelled         = RPCGRPCEndReasonKey.String("cancelled")
+	RPCGRPCEndReasonDeadlineExceeded  = RPCGRPCEndReasonKey.String("deadline_exceeded")
+	RPCGRPCEndReasonTransportError    = RPCGRPCEndReasonKey.String("transport_error")
+	RPCGRPCEndReasonApplicationError  = RPCGRPCEndReasonKey.String("application_error")
 )
@@ -0,0 +1,280 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// semconvOptInEnv is the environment variable used to opt in to the latest
+// RPC semantic conventions.
+const semconvOptInEnv = "OTEL_SEMCONV_STABILITY_OPT_IN"
+
+// semconvMode determines which semantic conventions the stats handlers
+// emit.
+type semconvMode uint8
+
+const (
+	// semconvModeOld emits the v1.17.0 semantic conventions only. This is the
+	// default.
+	semconvModeOld semconvMode = iota
+	// semconvModeNew emits the latest semantic conventions only. It is
+	// selected with "rpc".
+	semconvModeNew
+	// semconvModeDup emits both the v1.17.0 and the latest semantic
+	// conventions. It is selected with "rpc/dup" and takes precedence over
+	// "rpc".
+	semconvModeDup
+)
+
+// semconvModeFromEnv returns the semconvMode selected by the comma-separated
+// OTEL_SEMCONV_STABILITY_OPT_IN environment variable.
+func semconvModeFromEnv() semconvMode {
+	mode := semconvModeOld
+	for _, v := range strings.Split(os.Getenv(semconvOptInEnv), ",") {
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "rpc/dup":
+			return semconvModeDup
+		case "rpc":
+			mode = semconvModeNew
+		}
+	}
+	return mode
+}
+
+func (m semconvMode) emitOld() bool { return m != semconvModeNew }
+
+func (m semconvMode) emitNew() bool { return m != semconvModeOld }
+
+// recordRPCDuration records the duration d of an RPC in milliseconds
+// following the v1.17.0 semantic conventions, and in seconds following the
+// latest ones. The instruments of the conventions not emitted are no-ops.
+func (c *config) recordRPCDuration(ctx context.Context, d time.Duration, opts ...metric.RecordOption) {
+	// Use floating point division here for higher precision (instead of Millisecond method).
+	c.rpcDuration.Record(ctx, float64(d)/float64(time.Millisecond), opts...)
+	c.rpcCallDuration.Record(ctx, d.Seconds(), opts...)
+}
+
+// messageEventName returns the name of message events. Both conventions
+// are emitted on a single "message" event in semconvModeDup mode.
+func (m semconvMode) messageEventName() string {
+	if m == semconvModeNew {
+		return "rpc.message"
+	}
+	return "message"
+}
+
+// messageAttrs returns the attributes of a message event.
+func (m semconvMode) messageAttrs(sent bool, id int64, compressed, uncompressed int) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, 8)
+	if m.emitOld() {
+		typ := semconv.MessageTypeReceived
+		if sent {
+			typ = semconv.MessageTypeSent
+		}
+		attrs = append(attrs,
+			typ,
+			semconv.MessageIDKey.Int64(id),
+			semconv.MessageCompressedSizeKey.Int(compressed),
+			semconv.MessageUncompressedSizeKey.Int(uncompressed),
+		)
+	}
+	if m.emitNew() {
+		typ := semconvNew.RPCMessageTypeReceived
+		if sent {
+			typ = semconvNew.RPCMessageTypeSent
+		}
+		attrs = append(attrs,
+			typ,
+			semconvNew.RPCMessageIDKey.Int64(id),
+			semconvNew.RPCMessageCompressedSizeKey.Int(compressed),
+			semconvNew.RPCMessageUncompressedSizeKey.Int(uncompressed),
+		)
+	}
+	return attrs
+}
+
+// remoteAddrAttrs returns the attributes describing addr, the remote
+// address of an RPC or connection. For clients, the remote address is also
+// described as the server address following the latest semantic
+// conventions.
+func (m semconvMode) remoteAddrAttrs(addr net.Addr, isServer bool) []attribute.KeyValue { // nolint: revive  // isServer is not a control flag.
+	if addr == nil {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if m.emitOld() {
+		attrs = append(attrs, peerAttr(addr.String())...)
+	}
+	if m.emitNew() {
+		attrs = append(attrs, newRemoteAddrAttr(addr, isServer)...)
+	}
+	return attrs
+}
+
+// serverAddrAttrs returns the attributes describing addr, the local address
+// of a server RPC, as the server address. They are only defined by the
+// latest semantic conventions.
+func (m semconvMode) serverAddrAttrs(addr net.Addr) []attribute.KeyValue {
+	if !m.emitNew() {
+		return nil
+	}
+	host, port, ok := splitAddr(addr)
+	if !ok {
+		return nil
+	}
+	attrs := []attribute.KeyValue{semconvNew.ServerAddress(host)}
+	if port > 0 {
+		attrs = append(attrs, semconvNew.ServerPort(port))
+	}
+	return attrs
+}
+
+// localAddrAttrs returns the attributes describing the local address of a
+// connection.
+func (m semconvMode) localAddrAttrs(addr net.Addr) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if m.emitOld() {
+		attrs = append(attrs, localAddrAttr(addr)...)
+	}
+	if m.emitNew() {
+		attrs = append(attrs, newLocalAddrAttr(addr)...)
+	}
+	return attrs
+}
+
+// newPeerAttr returns attributes about the peer address following the
+// latest semantic conventions.
+func newPeerAttr(addr string) []attribute.KeyValue {
+	host, p, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil
+	}
+
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return nil
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return []attribute.KeyValue{
+			semconvNew.NetworkPeerAddress(host),
+			semconvNew.NetworkPeerPort(port),
+		}
+	}
+	return []attribute.KeyValue{
+		semconvNew.ServerAddress(host),
+		semconvNew.ServerPort(port),
+	}
+}
+
+// newRemoteAddrAttr returns the attributes describing the remote address
+// addr following the latest semantic conventions. Addresses of unknown
+// networks are parsed as host and port, see newPeerAttr.
+func newRemoteAddrAttr(addr net.Addr, isServer bool) []attribute.KeyValue { // nolint: revive  // isServer is not a control flag.
+	host, port, ok := splitAddr(addr)
+	if !ok {
+		return newPeerAttr(addr.String())
+	}
+
+	var attrs []attribute.KeyValue
+	if isUnixAddr(addr) {
+		attrs = append(attrs, semconvNew.NetworkTransportUnix)
+	} else {
+		attrs = append(attrs, semconvNew.NetworkTransportTCP)
+	}
+	// The clients of unix domain sockets are usually unnamed.
+	if host != "" && host != "@" {
+		attrs = append(attrs, semconvNew.NetworkPeerAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconvNew.NetworkPeerPort(port))
+		}
+	}
+	if !isServer && host != "" {
+		attrs = append(attrs, semconvNew.ServerAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconvNew.ServerPort(port))
+		}
+	}
+	return attrs
+}
+
+// splitAddr returns the host and port of addr, a TCP or unix domain socket
+// address. The host of a unix domain socket address is its path and its
+// port is zero. It returns false for nil addresses and other networks.
+func splitAddr(addr net.Addr) (host string, port int, ok bool) {
+	if addr == nil {
+		return "", 0, false
+	}
+	if isUnixAddr(addr) {
+		return addr.String(), 0, true
+	}
+	switch addr.Network() {
+	case "tcp", "tcp4", "tcp6":
+	default:
+		return "", 0, false
+	}
+
+	host, p, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return "", 0, false
+	}
+	port, err = strconv.Atoi(p)
+	if err != nil {
+		return "", 0, false
+	}
+	return host, port, true
+}
+
+// isUnixAddr returns whether addr is a unix domain socket address.
+func isUnixAddr(addr net.Addr) bool {
+	switch addr.Network() {
+	case "unix", "unixgram", "unixpacket":
+		return true
+	}
+	return false
+}
+
+// newLocalAddrAttr returns the attributes describing the local address of a
+// connection following the latest semantic conventions.
+func newLocalAddrAttr(addr net.Addr) []attribute.KeyValue {
+	if addr == nil {
+		return nil
+	}
+
+	switch addr.Network() {
+	case "unix", "unixpacket":
+		return []attribute.KeyValue{
+			semconvNew.NetworkTransportUnix,
+			semconvNew.NetworkLocalAddress(addr.String()),
+		}
+	case "tcp", "tcp4", "tcp6":
+		attrs := []attribute.KeyValue{semconvNew.NetworkTransportTCP}
+		host, p, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return attrs
+		}
+		attrs = append(attrs, semconvNew.NetworkLocalAddress(host))
+		if port, err := strconv.Atoi(p); err == nil {
+			attrs = append(attrs, semconvNew.NetworkLocalPort(port))
+		}
+		return attrs
+	default:
+		return nil
+	}
+}
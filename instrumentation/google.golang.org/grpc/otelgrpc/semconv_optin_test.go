@@ -0,0 +1,121 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestSemconvModeFromEnv(t *testing.T) {
+	tests := []struct {
+		env  string
+		want semconvMode
+	}{
+		{env: "", want: semconvModeOld},
+		{env: "http", want: semconvModeOld},
+		{env: "rpc", want: semconvModeNew},
+		{env: "http/dup, RPC", want: semconvModeNew},
+		{env: "rpc/dup", want: semconvModeDup},
+		{env: "rpc/dup,rpc", want: semconvModeDup},
+		{env: "rpc,rpc/dup", want: semconvModeDup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(semconvOptInEnv, tt.env)
+			assert.Equal(t, tt.want, semconvModeFromEnv())
+		})
+	}
+}
+
+func TestNewLocalAddrAttr(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		semconvNew.NetworkTransportTCP,
+		semconvNew.NetworkLocalAddress("::1"),
+		semconvNew.NetworkLocalPort(443),
+	}, newLocalAddrAttr(&net.TCPAddr{IP: net.ParseIP("::1"), Port: 443}))
+
+	assert.Equal(t, []attribute.KeyValue{
+		semconvNew.NetworkTransportUnix,
+		semconvNew.NetworkLocalAddress("/run/cedana.sock"),
+	}, newLocalAddrAttr(&net.UnixAddr{Name: "/run/cedana.sock", Net: "unix"}))
+
+	assert.Nil(t, newLocalAddrAttr(nil))
+}
+
+func TestNewPeerAttr(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		semconvNew.ServerAddress("cedana.local"),
+		semconvNew.ServerPort(8080),
+	}, newPeerAttr("cedana.local:8080"))
+	assert.Nil(t, newPeerAttr("invalid"))
+}
+
+func TestNewRemoteAddrAttr(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     net.Addr
+		isServer bool
+		want     []attribute.KeyValue
+	}{
+		{
+			name:     "ServerTCP",
+			addr:     &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 52000},
+			isServer: true,
+			want: []attribute.KeyValue{
+				semconvNew.NetworkTransportTCP,
+				semconvNew.NetworkPeerAddress("10.0.0.1"),
+				semconvNew.NetworkPeerPort(52000),
+			},
+		},
+		{
+			name: "ClientTCP",
+			addr: &net.TCPAddr{IP: net.ParseIP("::1"), Port: 8080},
+			want: []attribute.KeyValue{
+				semconvNew.NetworkTransportTCP,
+				semconvNew.NetworkPeerAddress("::1"),
+				semconvNew.NetworkPeerPort(8080),
+				semconvNew.ServerAddress("::1"),
+				semconvNew.ServerPort(8080),
+			},
+		},
+		{
+			name:     "ServerUnnamedUnix",
+			addr:     &net.UnixAddr{Name: "@", Net: "unix"},
+			isServer: true,
+			want:     []attribute.KeyValue{semconvNew.NetworkTransportUnix},
+		},
+		{
+			name: "ClientUnix",
+			addr: &net.UnixAddr{Name: "/run/cedana.sock", Net: "unix"},
+			want: []attribute.KeyValue{
+				semconvNew.NetworkTransportUnix,
+				semconvNew.NetworkPeerAddress("/run/cedana.sock"),
+				semconvNew.ServerAddress("/run/cedana.sock"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newRemoteAddrAttr(tt.addr, tt.isServer))
+		})
+	}
+}
+
+func TestServerAddrAttrs(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		semconvNew.ServerAddress("/run/cedana.sock"),
+	}, semconvModeNew.serverAddrAttrs(&net.UnixAddr{Name: "/run/cedana.sock", Net: "unix"}))
+	assert.Equal(t, []attribute.KeyValue{
+		semconvNew.ServerAddress("127.0.0.1"),
+		semconvNew.ServerPort(8080),
+	}, semconvModeDup.serverAddrAttrs(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}))
+	assert.Nil(t, semconvModeOld.serverAddrAttrs(&net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}))
+	assert.Nil(t, semconvModeNew.serverAddrAttrs(nil))
+}
@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PayloadSink stores serialized payloads exceeding the payload size limit
+// outside of the trace, see WithPayloadSink.
+type PayloadSink interface {
+	// Store stores data, the serialized payload of a message sent or
+	// received by the RPC method fullMethod, and returns a URI referencing
+	// it. hash is the hex encoded SHA-256 hash of data.
+	Store(ctx context.Context, fullMethod, hash string, data []byte) (string, error)
+}
+
+// PayloadSinkFunc is an adapter to allow the use of ordinary functions as a
+// PayloadSink.
+type PayloadSinkFunc func(ctx context.Context, fullMethod, hash string, data []byte) (string, error)
+
+// Store calls f(ctx, fullMethod, hash, data).
+func (f PayloadSinkFunc) Store(ctx context.Context, fullMethod, hash string, data []byte) (string, error) {
+	return f(ctx, fullMethod, hash, data)
+}
+
+// filePayloadSink is a PayloadSink writing payloads to files in a directory.
+type filePayloadSink struct {
+	dir string
+}
+
+// NewFilePayloadSink returns a PayloadSink that writes payloads to files in
+// dir, which must exist, named by their hash. The files have no extension,
+// as payloads are not necessarily JSON, e.g. if they are recorded in wire
+// format, see the message.payload_encoding attribute. The returned URIs use
+// the file scheme.
+func NewFilePayloadSink(dir string) PayloadSink {
+	return filePayloadSink{dir: dir}
+}
+
+func (s filePayloadSink) Store(_ context.Context, _, hash string, data []byte) (string, error) {
+	name, err := filepath.Abs(filepath.Join(s.dir, hash))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		return "", err
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(name)}).String(), nil
+}
+
+// sinkPayload stores data, the complete serialized payload of a message of
+// the RPC method fullMethod, in the configured PayloadSink and returns the
+// attributes referencing it. It returns false if no PayloadSink is
+// configured or data could not be stored.
+func (c *config) sinkPayload(ctx context.Context, fullMethod, data string) ([]attribute.KeyValue, bool) {
+	if c.PayloadSink == nil {
+		return nil, false
+	}
+
+	sum := sha256.Sum256([]byte(data))
+	hash := hex.EncodeToString(sum[:])
+	uri, err := c.PayloadSink.Store(ctx, fullMethod, hash, []byte(data))
+	if err != nil {
+		otel.Handle(err)
+		return nil, false
+	}
+	return []attribute.KeyValue{
+		RPCMessagePayloadURIKey.String(uri),
+		RPCMessageHashKey.String(hash),
+		RPCMessageTruncatedKey.Bool(true),
+	}, true
+}
+
+type payloadSinkOption struct{ s PayloadSink }
+
+func (o payloadSinkOption) apply(c *config) {
+	c.PayloadSink = o.s
+}
+
+// WithPayloadSink returns an Option that stores serialized payloads
+// exceeding the limit set by WithPayloadSizeLimit in full in s. Instead of
+// the truncated payload, the message event is annotated with the
+// message.payload_uri attribute referencing the stored payload, and its
+// SHA-256 hash and the message.truncated attribute. If s fails to store a
+// payload, the error is reported to the global error handler and the
+// truncated payload is recorded.
+func WithPayloadSink(s PayloadSink) Option {
+	return payloadSinkOption{s: s}
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilePayloadSink(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte(`{"name":"test"}`)
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	uri, err := NewFilePayloadSink(dir).Store(context.Background(), "/test.Service/Method", hash, data)
+	require.NoError(t, err)
+
+	u, err := url.Parse(uri)
+	require.NoError(t, err)
+	assert.Equal(t, "file", u.Scheme)
+	assert.Equal(t, hash, filepath.Base(u.Path))
+	got, err := os.ReadFile(u.Path)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	_, err = NewFilePayloadSink(dir+"/missing").Store(context.Background(), "/test.Service/Method", hash, data)
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanSegment tracks the current span of a streaming RPC whose span is
+// split, see WithStreamSpanSplitting.
+type spanSegment struct {
+	// parent, name and opts are used to start the next span.
+	parent context.Context
+	name   string
+	opts   []trace.SpanStartOption
+
+	mu       sync.Mutex
+	span     trace.Span
+	start    time.Time
+	messages int
+	index    int
+}
+
+// newSpanSegment returns the spanSegment of span, started with parent, name
+// and opts, or nil if spans are not split.
+func (c *config) newSpanSegment(parent context.Context, name string, opts []trace.SpanStartOption, span trace.Span) *spanSegment {
+	if c.StreamSpanMaxDuration <= 0 && c.StreamSpanMaxMessages <= 0 {
+		return nil
+	}
+	if !span.IsRecording() {
+		return nil
+	}
+	return &spanSegment{
+		parent: parent,
+		name:   name,
+		opts:   opts,
+		span:   span,
+		start:  time.Now(),
+	}
+}
+
+// current returns the current span of s.
+func (s *spanSegment) current() trace.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.span
+}
+
+// splitSpan counts a message sent or received on the stream of s and
+// returns the span to record it on. If the current span reached the
+// configured limits, it is ended and a new span linked to it is started
+// first. The limits are only checked here, idle streams are not split, and
+// the context of the RPC is not updated with the new span.
+func (c *config) splitSpan(s *spanSegment) trace.Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	full := c.StreamSpanMaxMessages > 0 && s.messages >= c.StreamSpanMaxMessages
+	expired := c.StreamSpanMaxDuration > 0 && now.Sub(s.start) >= c.StreamSpanMaxDuration
+	if full || expired {
+		prev := s.span
+		prev.SetAttributes(RPCGRPCStreamSegmentKey.Int(s.index))
+		prev.End(trace.WithTimestamp(now))
+
+		s.index++
+		opts := append(s.opts[:len(s.opts):len(s.opts)],
+			trace.WithTimestamp(now),
+			trace.WithLinks(trace.Link{SpanContext: prev.SpanContext()}),
+			trace.WithAttributes(RPCGRPCStreamSegmentKey.Int(s.index)),
+		)
+		_, s.span = c.tracer.Start(s.parent, s.name, opts...)
+		s.start, s.messages = now, 0
+	}
+	s.messages++
+	return s.span
+}
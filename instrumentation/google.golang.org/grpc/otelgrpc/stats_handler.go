@@ -5,7 +5,10 @@ package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.g
 
 import (
 	"context"
-	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -16,21 +19,110 @@ import (
 
 	"github.com/cedana/opentelemetry-go-contrib/instrumentation/google.golang.org/grpc/otelgrpc/internal"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
-	"google.golang.org/protobuf/encoding/protojson"
-	"google.golang.org/protobuf/proto"
 )
 
 type gRPCContextKey struct{}
 
+type connContextKey struct{}
+
+// connContext holds the state of a connection tracked by a stats handler.
+type connContext struct {
+	attrs     []attribute.KeyValue
+	beginTime time.Time
+	bytesIn   int64
+	bytesOut  int64
+
+	// span is the connection span, see WithConnectionSpans. key identifies
+	// the connection in config.conns for client connections.
+	span trace.Span
+	key  connKey
+	// channelz holds the channelz IDs of the connection once they are
+	// known, see WithChannelz.
+	channelz atomic.Pointer[[]attribute.KeyValue]
+}
+
+// connKey identifies a connection by its local and remote address.
+type connKey struct {
+	local, remote string
+}
+
+func newConnKey(local, remote net.Addr) connKey {
+	var k connKey
+	if local != nil {
+		k.local = local.Network() + ":" + local.String()
+	}
+	if remote != nil {
+		k.remote = remote.Network() + ":" + remote.String()
+	}
+	return k
+}
+
 type gRPCContext struct {
 	messagesReceived int64
 	messagesSent     int64
+	bytesReceived    int64
+	bytesSent        int64
+	fullMethod       string
 	metricAttrs      []attribute.KeyValue
-	record           bool
+	customAttrs      []attribute.KeyValue
+	recordTrace      bool
+	recordMetrics    bool
+	capturePayload   bool
+	messagesCaptured int64
+
+	// conn is the connection of a client RPC once it is known.
+	conn atomic.Pointer[connContext]
+
+	// active is set while the RPC is counted as in flight by the
+	// active_requests metric.
+	active atomic.Bool
+	// streaming is set when the RPC begins if it is a client or server
+	// streaming RPC, see WithMessageSpans.
+	streaming atomic.Bool
+	// progressStop is closed when the RPC ends to stop recording its
+	// progress, see WithStreamProgressInterval.
+	progressStop chan struct{}
+	// segment is the current span of the RPC if its span is split, see
+	// WithStreamSpanSplitting.
+	segment *spanSegment
+	// correlation tracks the request messages of the stream that have not
+	// been answered yet, see WithMessageCorrelation.
+	correlation messageCorrelation
+
+	// replay collects the ReplayRecord of the RPC, see WithReplayWriter.
+	replay *replayRecorder
+
+	// pendingEvents holds message events whose payload is only recorded if
+	// the RPC fails, see WithPayloadCaptureOnError. lastEvents holds the
+	// most recent messages exceeding MaxCapturedMessages, see
+	// WithLastCapturedMessages.
+	pendingMu     sync.Mutex
+	pendingEvents []messageEvent
+	lastEvents    []messageEvent
+}
+
+// messageEvent is a message event held back until the end of an RPC.
+type messageEvent struct {
+	attrs   []attribute.KeyValue
+	payload []attribute.KeyValue
+	// payloadKey is the key of the payload attribute among payload, if the
+	// payload is recorded in full rather than by hash or reference.
+	payloadKey attribute.Key
+	time       time.Time
+	// start is the time the message was received or sent. It is the start
+	// time of the message span, see WithMessageSpans.
+	start time.Time
+	// requestID is the message ID of a request message, whose message span
+	// is recorded for responses to be linked to it. answersID is the message
+	// ID of the request a response answers. See WithMessageCorrelation.
+	requestID int64
+	answersID int64
+	// sent is true if the message was sent and false if it was received.
+	sent bool
 }
 
 type serverHandler struct {
@@ -47,11 +139,29 @@ func NewServerHandler(opts ...Option) stats.Handler {
 
 // TagConn can attach some information to the given context.
 func (h *serverHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
-	return ctx
+	return h.tagConn(ctx, info, trace.SpanKindServer)
 }
 
 // HandleConn processes the Conn stats.
 func (h *serverHandler) HandleConn(ctx context.Context, info stats.ConnStats) {
+	cctx := h.handleConn(ctx, info)
+	if cctx == nil {
+		return
+	}
+
+	attrs := metric.WithAttributeSet(attribute.NewSet(cctx.attrs...))
+	switch info.(type) {
+	case *stats.ConnBegin:
+		h.connActive.Add(ctx, 1, attrs)
+	case *stats.ConnEnd:
+		h.connActive.Add(ctx, -1, attrs)
+
+		// Use floating point division here for higher precision (instead of Seconds method).
+		elapsedTime := float64(time.Since(cctx.beginTime)) / float64(time.Second)
+		h.connDuration.Record(ctx, elapsedTime, attrs)
+		h.connBytesIn.Record(ctx, atomic.LoadInt64(&cctx.bytesIn), attrs)
+		h.connBytesOut.Record(ctx, atomic.LoadInt64(&cctx.bytesOut), attrs)
+	}
 }
 
 // TagRPC can attach some information to the given context.
@@ -59,20 +169,36 @@ func (h *serverHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) cont
 	ctx = extract(ctx, h.config.Propagators)
 
 	name, attrs := internal.ParseFullMethod(info.FullMethodName)
+	name = h.spanName(info.FullMethodName, name)
 	attrs = append(attrs, RPCSystemGRPC)
-	ctx, _ = h.tracer.Start(
-		trace.ContextWithRemoteSpanContext(ctx, trace.SpanContextFromContext(ctx)),
-		name,
-		trace.WithSpanKind(trace.SpanKindServer),
-		trace.WithAttributes(attrs...),
-	)
+	customAttrs := h.customAttrs(ctx, info)
+	attrs = append(attrs, customAttrs...)
 
 	gctx := gRPCContext{
+		fullMethod:  info.FullMethodName,
 		metricAttrs: attrs,
-		record:      true,
+		customAttrs: customAttrs,
 	}
-	if h.config.Filter != nil {
-		gctx.record = h.config.Filter(info)
+	gctx.recordTrace, gctx.recordMetrics = h.filter(ctx, info)
+	if h.StreamProgressInterval > 0 {
+		gctx.progressStop = make(chan struct{})
+	}
+	if gctx.recordTrace {
+		parent := trace.ContextWithRemoteSpanContext(ctx, trace.SpanContextFromContext(ctx))
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(tlsPeerAttrs(ctx)...),
+			trace.WithLinks(connLink(ctx)...),
+		}
+		var span trace.Span
+		ctx, span = h.tracer.Start(parent, name, opts...)
+		gctx.segment = h.newSpanSegment(parent, name, opts, span)
+		// Avoid serializing payloads that would never be exported.
+		gctx.capturePayload = span.IsRecording() && h.config.capturePayload(info.FullMethodName)
+		if gctx.capturePayload && h.ReplayWriter != nil {
+			gctx.replay = newReplayRecorder(info.FullMethodName, span.SpanContext())
+		}
 	}
 	return context.WithValue(ctx, gRPCContextKey{}, &gctx)
 }
@@ -99,20 +225,38 @@ func NewClientHandler(opts ...Option) stats.Handler {
 // TagRPC can attach some information to the given context.
 func (h *clientHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
 	name, attrs := internal.ParseFullMethod(info.FullMethodName)
+	name = h.spanName(info.FullMethodName, name)
 	attrs = append(attrs, RPCSystemGRPC)
-	ctx, _ = h.tracer.Start(
-		ctx,
-		name,
-		trace.WithSpanKind(trace.SpanKindClient),
-		trace.WithAttributes(attrs...),
-	)
+	customAttrs := h.customAttrs(ctx, info)
+	attrs = append(attrs, customAttrs...)
 
 	gctx := gRPCContext{
+		fullMethod:  info.FullMethodName,
 		metricAttrs: attrs,
-		record:      true,
+		customAttrs: customAttrs,
+	}
+	gctx.recordTrace, gctx.recordMetrics = h.filter(ctx, info)
+	if h.StreamProgressInterval > 0 {
+		gctx.progressStop = make(chan struct{})
+	}
+	if isSuppressed(ctx) {
+		gctx.recordTrace = false
 	}
-	if h.config.Filter != nil {
-		gctx.record = h.config.Filter(info)
+	if gctx.recordTrace {
+		parent := ctx
+		opts := []trace.SpanStartOption{
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+			trace.WithAttributes(h.peerServiceAttr()...),
+		}
+		var span trace.Span
+		ctx, span = h.tracer.Start(parent, name, opts...)
+		gctx.segment = h.newSpanSegment(parent, name, opts, span)
+		// Avoid serializing payloads that would never be exported.
+		gctx.capturePayload = span.IsRecording() && h.config.capturePayload(info.FullMethodName)
+		if gctx.capturePayload && h.ReplayWriter != nil {
+			gctx.replay = newReplayRecorder(info.FullMethodName, span.SpanContext())
+		}
 	}
 
 	return inject(context.WithValue(ctx, gRPCContextKey{}, &gctx), h.config.Propagators)
@@ -126,12 +270,74 @@ func (h *clientHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
 
 // TagConn can attach some information to the given context.
 func (h *clientHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
-	return ctx
+	if !h.ConnectionSpans && h.Channelz == nil {
+		return ctx
+	}
+	return h.tagConn(ctx, info, trace.SpanKindClient)
 }
 
 // HandleConn processes the Conn stats.
-func (h *clientHandler) HandleConn(context.Context, stats.ConnStats) {
-	// no-op
+func (h *clientHandler) HandleConn(ctx context.Context, info stats.ConnStats) {
+	h.handleConn(ctx, info)
+}
+
+// tagConn returns ctx with a connContext for the connection described by
+// info. If connection spans are enabled, a connection span of kind is started.
+func (c *config) tagConn(ctx context.Context, info *stats.ConnTagInfo, kind trace.SpanKind) context.Context {
+	cctx := connContext{
+		attrs:     append(c.semconv.localAddrAttrs(info.LocalAddr), c.StaticAttributes...),
+		beginTime: time.Now(),
+	}
+	if c.ConnectionSpans {
+		attrs := []attribute.KeyValue{RPCSystemGRPC}
+		attrs = append(attrs, cctx.attrs...)
+		attrs = append(attrs, c.semconv.remoteAddrAttrs(info.RemoteAddr, kind == trace.SpanKindServer)...)
+		// The connection span is not stored in ctx so it does not become
+		// the parent of the RPC spans of the connection.
+		_, cctx.span = c.tracer.Start(ctx, "grpc.connection",
+			trace.WithNewRoot(),
+			trace.WithSpanKind(kind),
+			trace.WithAttributes(attrs...),
+		)
+	}
+	if kind == trace.SpanKindClient && (c.ConnectionSpans || c.Channelz != nil) {
+		// Client RPC contexts are not derived from the connection context,
+		// RPC spans are linked once their connection is known.
+		cctx.key = newConnKey(info.LocalAddr, info.RemoteAddr)
+		c.conns.Store(cctx.key, &cctx)
+	}
+	if c.Channelz != nil {
+		go c.resolveChannelz(&cctx, info.LocalAddr, info.RemoteAddr, kind == trace.SpanKindServer)
+	}
+	return context.WithValue(ctx, connContextKey{}, &cctx)
+}
+
+// handleConn ends the connection span of the connection of ctx when the
+// connection ends. It returns the connContext of the connection, if any.
+func (c *config) handleConn(ctx context.Context, info stats.ConnStats) *connContext {
+	cctx, _ := ctx.Value(connContextKey{}).(*connContext)
+	if cctx == nil {
+		return nil
+	}
+	if _, ok := info.(*stats.ConnEnd); ok {
+		if cctx.key != (connKey{}) {
+			c.conns.Delete(cctx.key)
+		}
+		if cctx.span != nil {
+			cctx.span.End()
+		}
+	}
+	return cctx
+}
+
+// connLink returns a link to the connection span of the connection of ctx,
+// if any.
+func connLink(ctx context.Context) []trace.Link {
+	cctx, _ := ctx.Value(connContextKey{}).(*connContext)
+	if cctx == nil || cctx.span == nil {
+		return nil
+	}
+	return []trace.Link{{SpanContext: cctx.span.SpanContext()}}
 }
 
 func (c *config) handleRPC(ctx context.Context, rs stats.RPCStats, isServer bool) { // nolint: revive  // isServer is not a control flag.
@@ -139,107 +345,449 @@ func (c *config) handleRPC(ctx context.Context, rs stats.RPCStats, isServer bool
 	var metricAttrs []attribute.KeyValue
 	var messageId int64
 
+	recordMetrics := true
 	gctx, _ := ctx.Value(gRPCContextKey{}).(*gRPCContext)
 	if gctx != nil {
-		if !gctx.record {
+		if !gctx.recordTrace && !gctx.recordMetrics {
 			return
 		}
+		if !gctx.recordTrace {
+			// No span was started for the RPC, do not modify the span of
+			// the caller found in ctx.
+			span = trace.SpanFromContext(context.Background())
+		} else if gctx.segment != nil {
+			span = gctx.segment.current()
+		}
+		recordMetrics = gctx.recordMetrics
 		metricAttrs = make([]attribute.KeyValue, 0, len(gctx.metricAttrs)+1)
 		metricAttrs = append(metricAttrs, gctx.metricAttrs...)
 	}
 
+	// mctx is the context measurements are recorded with, it determines
+	// the span context of their exemplars.
+	mctx := c.exemplarContext(ctx)
+
 	switch rs := rs.(type) {
 	case *stats.Begin:
+		if span.IsRecording() {
+			span.SetAttributes(deadlineAttrs(ctx, rs.BeginTime)...)
+		}
+		if gctx != nil {
+			gctx.streaming.Store(rs.IsClientStream || rs.IsServerStream)
+			if gctx.progressStop != nil && gctx.streaming.Load() {
+				recvAttrs, sentAttrs := streamProgressAttrs(metricAttrs)
+				go c.recordStreamProgress(mctx, span, gctx, recvAttrs, sentAttrs, recordMetrics)
+			}
+		}
+		if gctx != nil && recordMetrics {
+			gctx.active.Store(true)
+			c.rpcActiveRequests.Add(mctx, 1, metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
+		}
+		if !isServer && gctx != nil && recordMetrics {
+			c.attemptStarted.Add(mctx, 1, metric.WithAttributeSet(c.attemptAttrs(gctx)))
+		}
 	case *stats.InPayload:
+		if cctx, ok := ctx.Value(connContextKey{}).(*connContext); ok {
+			atomic.AddInt64(&cctx.bytesIn, int64(rs.WireLength))
+		}
 		if gctx != nil {
 			messageId = atomic.AddInt64(&gctx.messagesReceived, 1)
-			c.rpcRequestSize.Record(ctx, int64(rs.Length), metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
-		}
-		reqJSON := payloadToJSON(rs.Payload)
-		span.AddEvent("message",
-			trace.WithAttributes(
-				semconv.MessageTypeReceived,
-				semconv.MessageIDKey.Int64(messageId),
-				semconv.MessageCompressedSizeKey.Int(rs.CompressedLength),
-				semconv.MessageUncompressedSizeKey.Int(rs.Length),
-				attribute.String("request", reqJSON),
-			),
-		)
+			atomic.AddInt64(&gctx.bytesReceived, int64(rs.CompressedLength))
+		}
+		if gctx != nil && recordMetrics {
+			c.rpcRequestSize.Record(mctx, int64(rs.Length), metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
+			c.compression.record(mctx, false, rs.CompressedLength, rs.Length, metricAttrs)
+		}
+		if gctx != nil && gctx.segment != nil && gctx.streaming.Load() {
+			span = c.splitSpan(gctx.segment)
+		}
+		e := messageEvent{
+			attrs: append(c.semconv.messageAttrs(false, messageId, rs.CompressedLength, rs.Length), messageProtoTypeAttrs(rs.Payload)...),
+			start: rs.RecvTime,
+		}
+		c.correlateMessage(gctx, &e, messageId, isServer, rs.Payload)
+		c.addMessageEvent(ctx, span, gctx, e, c.PayloadRequestKey, c.capturedPayload(rs.Payload, rs.Data)) // nolint:staticcheck  // Data is the only source of the wire bytes.
+		if gctx != nil {
+			gctx.replay.addMessage(isServer, rs.Payload, rs.Data) // nolint:staticcheck  // Data is the only source of the wire bytes.
+		}
 	case *stats.OutPayload:
+		if cctx, ok := ctx.Value(connContextKey{}).(*connContext); ok {
+			atomic.AddInt64(&cctx.bytesOut, int64(rs.WireLength))
+		}
 		if gctx != nil {
 			messageId = atomic.AddInt64(&gctx.messagesSent, 1)
-			c.rpcResponseSize.Record(ctx, int64(rs.Length), metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
-		}
-
-		respJSON := payloadToJSON(rs.Payload)
-		span.AddEvent("message",
-			trace.WithAttributes(
-				semconv.MessageTypeSent,
-				semconv.MessageIDKey.Int64(messageId),
-				semconv.MessageCompressedSizeKey.Int(rs.CompressedLength),
-				semconv.MessageUncompressedSizeKey.Int(rs.Length),
-				attribute.String("response", respJSON),
-			),
-		)
+			atomic.AddInt64(&gctx.bytesSent, int64(rs.CompressedLength))
+		}
+		if gctx != nil && recordMetrics {
+			c.rpcResponseSize.Record(mctx, int64(rs.Length), metric.WithAttributeSet(attribute.NewSet(metricAttrs...)))
+			c.compression.record(mctx, true, rs.CompressedLength, rs.Length, metricAttrs)
+		}
+
+		if gctx != nil && gctx.segment != nil && gctx.streaming.Load() {
+			span = c.splitSpan(gctx.segment)
+		}
+		e := messageEvent{
+			attrs: append(c.semconv.messageAttrs(true, messageId, rs.CompressedLength, rs.Length), messageProtoTypeAttrs(rs.Payload)...),
+			start: rs.SentTime,
+			sent:  true,
+		}
+		c.correlateMessage(gctx, &e, messageId, !isServer, rs.Payload)
+		c.addMessageEvent(ctx, span, gctx, e, c.PayloadResponseKey, c.capturedPayload(rs.Payload, rs.Data)) // nolint:staticcheck  // Data is the only source of the wire bytes.
+		if gctx != nil {
+			gctx.replay.addMessage(!isServer, rs.Payload, rs.Data) // nolint:staticcheck  // Data is the only source of the wire bytes.
+		}
+	case *stats.InHeader:
+		if isServer {
+			span.SetAttributes(c.semconv.serverAddrAttrs(rs.LocalAddr)...)
+			span.SetAttributes(c.metadataAttrs(requestMetadataPrefix, rs.Header)...)
+			if gctx != nil {
+				gctx.replay.setMetadata(rs.Header, c.ReplayCredentials)
+			}
+		} else {
+			span.SetAttributes(c.metadataAttrs(responseMetadataPrefix, rs.Header)...)
+		}
+	case *stats.InTrailer:
+		span.SetAttributes(c.metadataAttrs(responseMetadataPrefix, rs.Trailer)...)
 	case *stats.OutTrailer:
+		span.SetAttributes(c.metadataAttrs(responseMetadataPrefix, rs.Trailer)...)
 	case *stats.OutHeader:
 		if p, ok := peer.FromContext(ctx); ok {
-			span.SetAttributes(peerAttr(p.Addr.String())...)
+			span.SetAttributes(c.semconv.remoteAddrAttrs(p.Addr, isServer)...)
+		}
+		if isServer {
+			span.SetAttributes(c.metadataAttrs(responseMetadataPrefix, rs.Header)...)
+		} else {
+			span.SetAttributes(c.metadataAttrs(requestMetadataPrefix, rs.Header)...)
+			if gctx != nil {
+				gctx.replay.setMetadata(rs.Header, c.ReplayCredentials)
+			}
+			span.SetAttributes(c.lbAttrs(rs.RemoteAddr)...)
+			if v, ok := c.conns.Load(newConnKey(rs.LocalAddr, rs.RemoteAddr)); ok {
+				cctx := v.(*connContext)
+				if cctx.span != nil {
+					span.AddLink(trace.Link{SpanContext: cctx.span.SpanContext()})
+				}
+				if gctx != nil {
+					gctx.conn.Store(cctx)
+				}
+			}
+			if c.PeerServiceFunc != nil && rs.RemoteAddr != nil {
+				if name := c.PeerServiceFunc(rs.RemoteAddr); name != "" {
+					span.SetAttributes(semconv.PeerService(name))
+				}
+			}
 		}
 	case *stats.End:
 		var rpcStatusAttr attribute.KeyValue
 
+		s, _ := status.FromError(rs.Error)
 		if rs.Error != nil {
-			s, _ := status.FromError(rs.Error)
-			if isServer {
-				statusCode, msg := serverStatus(s)
-				span.SetStatus(statusCode, msg)
-			} else {
-				span.SetStatus(codes.Error, s.Message())
+			span.SetStatus(c.spanStatus(s, isServer))
+			if s.Code() == grpc_codes.DeadlineExceeded {
+				span.SetAttributes(deadlineExceededSource(ctx, rs.EndTime))
 			}
+			reason := endReason(ctx, s.Code(), isServer)
+			span.SetAttributes(reason)
+			metricAttrs = append(metricAttrs, reason)
+			c.addStatusDetailsEvent(span, s)
 			rpcStatusAttr = semconv.RPCGRPCStatusCodeKey.Int(int(s.Code()))
 		} else {
 			rpcStatusAttr = semconv.RPCGRPCStatusCodeKey.Int(int(grpc_codes.OK))
 		}
 		span.SetAttributes(rpcStatusAttr)
+		if c.Channelz != nil {
+			var cctx *connContext
+			if gctx != nil {
+				cctx = gctx.conn.Load()
+			}
+			if cctx == nil {
+				cctx, _ = ctx.Value(connContextKey{}).(*connContext)
+			}
+			span.SetAttributes(channelzAttrs(cctx)...)
+		}
+		if gctx != nil && gctx.progressStop != nil {
+			close(gctx.progressStop)
+		}
+		c.flushMessageEvents(ctx, span, gctx, rs.Error != nil)
+		c.writeReplay(ctx, gctx, rs)
 		span.End()
 
+		if !recordMetrics {
+			return
+		}
+
+		if gctx != nil && gctx.active.CompareAndSwap(true, false) {
+			c.rpcActiveRequests.Add(mctx, -1, metric.WithAttributeSet(attribute.NewSet(gctx.metricAttrs...)))
+		}
 		metricAttrs = append(metricAttrs, rpcStatusAttr)
 		// Allocate vararg slice once.
 		recordOpts := []metric.RecordOption{metric.WithAttributeSet(attribute.NewSet(metricAttrs...))}
 
-		// Use floating point division here for higher precision (instead of Millisecond method).
-		// Measure right before calling Record() to capture as much elapsed time as possible.
-		elapsedTime := float64(rs.EndTime.Sub(rs.BeginTime)) / float64(time.Millisecond)
-
-		c.rpcDuration.Record(ctx, elapsedTime, recordOpts...)
+		c.recordRPCDuration(mctx, rs.EndTime.Sub(rs.BeginTime), recordOpts...)
 		if gctx != nil {
-			c.rpcRequestsPerRPC.Record(ctx, atomic.LoadInt64(&gctx.messagesReceived), recordOpts...)
-			c.rpcResponsesPerRPC.Record(ctx, atomic.LoadInt64(&gctx.messagesSent), recordOpts...)
+			c.rpcRequestsPerRPC.Record(mctx, atomic.LoadInt64(&gctx.messagesReceived), recordOpts...)
+			c.rpcResponsesPerRPC.Record(mctx, atomic.LoadInt64(&gctx.messagesSent), recordOpts...)
+		}
+		if !isServer && gctx != nil {
+			attemptOpts := []metric.RecordOption{metric.WithAttributeSet(c.attemptAttrs(gctx, grpcStatus(s.Code())))}
+			c.attemptDuration.Record(mctx, rs.EndTime.Sub(rs.BeginTime).Seconds(), attemptOpts...)
+			c.attemptSentSize.Record(mctx, atomic.LoadInt64(&gctx.bytesSent), attemptOpts...)
+			c.attemptRcvdSize.Record(mctx, atomic.LoadInt64(&gctx.bytesReceived), attemptOpts...)
 		}
 	default:
 		return
 	}
 }
 
-func payloadToJSON(payload any) string {
-	if payload == nil {
-		return "null"
+// exemplarContext returns the context to record the measurements of an RPC
+// with. If exemplars are forced, see WithExemplarsForFilteredSpans, the span
+// context of ctx is marked as sampled so exemplars referencing it are kept by
+// the trace based exemplar filter.
+func (c *config) exemplarContext(ctx context.Context) context.Context {
+	if !c.ForceExemplars {
+		return ctx
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() || sc.IsSampled() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, sc.WithTraceFlags(sc.TraceFlags().WithSampled(true)))
+}
+
+// filter reports whether a span and metrics are recorded for the RPC
+// described by info.
+func (c *config) filter(ctx context.Context, info *stats.RPCTagInfo) (recordTrace, recordMetrics bool) {
+	if f := c.rpcFilter(); f != nil && !f(info) {
+		return false, false
+	}
+	if c.ContextFilter != nil && !c.ContextFilter(ctx, info) {
+		return false, false
+	}
+
+	recordTrace, recordMetrics = !c.DisableTraces, !c.DisableMetrics
+	if recordTrace && c.TraceFilter != nil {
+		recordTrace = c.TraceFilter(info)
+	}
+	if recordMetrics && c.MetricFilter != nil {
+		recordMetrics = c.MetricFilter(info)
+	}
+	return recordTrace, recordMetrics
+}
+
+// lbAttrs returns the attributes describing the load balancing pick of a
+// client RPC sent to the backend at addr.
+func (c *config) lbAttrs(addr net.Addr) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if addr != nil {
+		attrs = append(attrs, RPCGRPCLBBackendAddressKey.String(addr.String()))
+	}
+	if c.LoadBalancer != "" {
+		attrs = append(attrs, RPCGRPCLBPolicyKey.String(c.LoadBalancer))
+	}
+	return attrs
+}
+
+// peerServiceAttr returns the peer.service attribute of client spans
+// configured with WithPeerService, if any.
+func (c *config) peerServiceAttr() []attribute.KeyValue {
+	if c.PeerService == "" {
+		return nil
 	}
+	return []attribute.KeyValue{semconv.PeerService(c.PeerService)}
+}
+
+// spanName returns the name of the span of an RPC calling fullMethod. name is
+// the default span name.
+func (c *config) spanName(fullMethod, name string) string {
+	if c.SpanNameFormatter == nil {
+		return name
+	}
+	return c.SpanNameFormatter(fullMethod)
+}
 
-	protoMsg, ok := payload.(proto.Message)
-	if !ok {
-		return fmt.Sprintf("%+v", payload)
+// customAttrs returns the static attributes, the attributes returned by
+// the attribute extractors and the baggage attributes for the RPC described
+// by info.
+func (c *config) customAttrs(ctx context.Context, info *stats.RPCTagInfo) []attribute.KeyValue {
+	if len(c.AttributeExtractors) == 0 && len(c.BaggageKeys) == 0 {
+		return c.StaticAttributes
 	}
 
-	marshaler := protojson.MarshalOptions{
-		EmitUnpopulated: true,
-		Indent:          "  ",
+	attrs := make([]attribute.KeyValue, 0, len(c.StaticAttributes)+len(c.BaggageKeys))
+	attrs = append(attrs, c.StaticAttributes...)
+	for _, f := range c.AttributeExtractors {
+		attrs = append(attrs, f(ctx, info)...)
 	}
-	jsonData, err := marshaler.Marshal(protoMsg)
-	if err != nil {
-		return fmt.Sprintf("Error marshaling to JSON: %v", err)
+	if len(c.BaggageKeys) > 0 {
+		bag := baggage.FromContext(ctx)
+		for _, key := range c.BaggageKeys {
+			if m := bag.Member(key); m.Key() != "" {
+				attrs = append(attrs, attribute.String(key, m.Value()))
+			}
+		}
 	}
+	return attrs
+}
+
+// attemptAttrs returns the attributes of the per-attempt client metrics of
+// the RPC of gctx.
+func (c *config) attemptAttrs(gctx *gRPCContext, attrs ...attribute.KeyValue) attribute.Set {
+	kvs := make([]attribute.KeyValue, 0, 1+len(attrs)+len(gctx.customAttrs))
+	kvs = append(kvs, GRPCMethodKey.String(strings.TrimPrefix(gctx.fullMethod, "/")))
+	kvs = append(kvs, attrs...)
+	kvs = append(kvs, gctx.customAttrs...)
+	return attribute.NewSet(kvs...)
+}
 
-	return string(jsonData)
+// addMessageEvent adds the message event e to span and records payload
+// under key on it if payload capture is enabled for the message.
+//
+// Events are held back until the RPC ends if their payload is only recorded
+// for failed RPCs or if they might be among the last captured messages.
+func (c *config) addMessageEvent(ctx context.Context, span trace.Span, gctx *gRPCContext, e messageEvent, key attribute.Key, payload any) {
+	e.time = time.Now()
+	e.payloadKey = key
+	if e.start.IsZero() || e.start.After(e.time) {
+		e.start = e.time
+	}
+	if gctx == nil {
+		if c.capturePayloadDirection(e.sent) {
+			e.payload = c.payloadAttrs(ctx, gctx, key, payload)
+		}
+		c.recordMessageEvent(ctx, span, gctx, e, true)
+		return
+	}
+	if !gctx.capturePayload || !c.capturePayloadDirection(e.sent) {
+		c.recordMessageEvent(ctx, span, gctx, e, false)
+		return
+	}
+
+	n := atomic.AddInt64(&gctx.messagesCaptured, 1)
+	if c.MaxCapturedMessages > 0 && n > int64(c.MaxCapturedMessages) {
+		if c.LastCapturedMessages <= 0 {
+			c.dropPayloads(ctx, gctx, 1, payloadDropMaxMessages)
+			c.recordMessageEvent(ctx, span, gctx, e, false)
+			return
+		}
+
+		e.payload = c.payloadAttrs(ctx, gctx, key, payload)
+		gctx.pendingMu.Lock()
+		var evicted messageEvent
+		full := len(gctx.lastEvents) == c.LastCapturedMessages
+		if full {
+			evicted = gctx.lastEvents[0]
+			gctx.lastEvents = gctx.lastEvents[1:]
+		}
+		gctx.lastEvents = append(gctx.lastEvents, e)
+		gctx.pendingMu.Unlock()
+
+		// The evicted message is no longer among the last captured ones.
+		if full {
+			c.dropPayloads(ctx, gctx, 1, payloadDropMaxMessages)
+			c.recordMessageEvent(ctx, span, gctx, evicted, false)
+		}
+		return
+	}
+
+	e.payload = c.payloadAttrs(ctx, gctx, key, payload)
+	if !c.PayloadOnError {
+		c.recordMessageEvent(ctx, span, gctx, e, true)
+		return
+	}
+
+	gctx.pendingMu.Lock()
+	gctx.pendingEvents = append(gctx.pendingEvents, e)
+	gctx.pendingMu.Unlock()
+}
+
+// flushMessageEvents adds the message events held back by addMessageEvent to
+// span. If payloads are only captured for failed RPCs, their payload
+// attributes are only included if failed is true.
+func (c *config) flushMessageEvents(ctx context.Context, span trace.Span, gctx *gRPCContext, failed bool) {
+	if gctx == nil {
+		return
+	}
+
+	gctx.pendingMu.Lock()
+	events := append(gctx.pendingEvents, gctx.lastEvents...)
+	gctx.pendingEvents, gctx.lastEvents = nil, nil
+	gctx.pendingMu.Unlock()
+
+	withPayload := failed || !c.PayloadOnError
+	if !withPayload {
+		var dropped int64
+		for _, e := range events {
+			if e.payload != nil {
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			c.dropPayloads(ctx, gctx, dropped, payloadDropRPCSucceeded)
+		}
+	}
+	for _, e := range events {
+		c.recordMessageEvent(ctx, span, gctx, e, withPayload)
+	}
+}
+
+// recordMessageEvent adds e to span, or records it as a child span of span
+// for streaming RPCs if MessageSpans is set. If withPayload is true, the
+// payload attributes of e are recorded on the event or, if a LoggerProvider
+// is configured, emitted as a log record instead.
+func (c *config) recordMessageEvent(ctx context.Context, span trace.Span, gctx *gRPCContext, e messageEvent, withPayload bool) {
+	attrs := e.attrs
+	emit := withPayload && len(e.payload) > 0 && c.logger != nil
+	if withPayload && len(e.payload) > 0 && !emit {
+		attrs = append(attrs, e.payload...)
+	}
+
+	if c.MessageSpans && gctx != nil && gctx.streaming.Load() && span.IsRecording() {
+		var ms trace.Span
+		ctx, ms = c.tracer.Start(
+			trace.ContextWithSpan(ctx, span),
+			c.semconv.messageEventName(),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithTimestamp(e.start),
+			trace.WithAttributes(attrs...),
+			trace.WithLinks(gctx.requestLink(e.answersID)...),
+		)
+		ms.End(trace.WithTimestamp(e.time))
+		gctx.setRequestSpan(e.requestID, ms.SpanContext())
+	} else {
+		span.AddEvent(c.semconv.messageEventName(), trace.WithAttributes(attrs...), trace.WithTimestamp(e.time))
+	}
+
+	if emit {
+		c.emitPayload(ctx, gctx, e)
+	}
+}
+
+// localAddrAttr returns the attributes describing the local address of a
+// connection.
+func localAddrAttr(addr net.Addr) []attribute.KeyValue {
+	if addr == nil {
+		return nil
+	}
+
+	switch addr.Network() {
+	case "unix", "unixpacket":
+		return []attribute.KeyValue{
+			semconv.NetTransportOther,
+			semconv.NetSockFamilyUnix,
+			semconv.NetSockHostAddr(addr.String()),
+		}
+	case "tcp", "tcp4", "tcp6":
+		attrs := []attribute.KeyValue{semconv.NetTransportTCP}
+		host, p, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return attrs
+		}
+		attrs = append(attrs, semconv.NetSockHostAddr(host))
+		if port, err := strconv.Atoi(p); err == nil {
+			attrs = append(attrs, semconv.NetSockHostPort(port))
+		}
+		return attrs
+	default:
+		return []attribute.KeyValue{semconv.NetTransportOther}
+	}
 }
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestGRPCStatus(t *testing.T) {
+	assert.Equal(t, GRPCStatusKey.String("OK"), grpcStatus(codes.OK))
+	assert.Equal(t, GRPCStatusKey.String("DEADLINE_EXCEEDED"), grpcStatus(codes.DeadlineExceeded))
+	assert.Equal(t, GRPCStatusKey.String("UNAUTHENTICATED"), grpcStatus(codes.Unauthenticated))
+	assert.Equal(t, GRPCStatusKey.String("Code(42)"), grpcStatus(codes.Code(42)))
+}
+
+func TestLocalAddrAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		addr net.Addr
+		want []attribute.KeyValue
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name: "TCP",
+			addr: &net.TCPAddr{IP: net.ParseIP("::1"), Port: 443},
+			want: []attribute.KeyValue{
+				semconv.NetTransportTCP,
+				semconv.NetSockHostAddr("::1"),
+				semconv.NetSockHostPort(443),
+			},
+		},
+		{
+			name: "Unix",
+			addr: &net.UnixAddr{Name: "/run/cedana.sock", Net: "unix"},
+			want: []attribute.KeyValue{
+				semconv.NetTransportOther,
+				semconv.NetSockFamilyUnix,
+				semconv.NetSockHostAddr("/run/cedana.sock"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, localAddrAttr(tt.addr))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"strconv"
+
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// statusDetailsEventName is the name of the span event recording the details
+// of the status of a failed RPC.
+const statusDetailsEventName = "rpc.grpc.status_details"
+
+// addStatusDetailsEvent adds an event holding the details of s, e.g.
+// errdetails.RetryInfo or errdetails.BadRequest messages, to span. No event
+// is added if s has no details.
+func (c *config) addStatusDetailsEvent(span trace.Span, s *status.Status) {
+	details := s.Proto().GetDetails()
+	if len(details) == 0 || !span.IsRecording() {
+		return
+	}
+
+	values := make([]string, 0, len(details))
+	for _, d := range details {
+		values = append(values, c.statusDetailJSON(d))
+	}
+	span.AddEvent(statusDetailsEventName, trace.WithAttributes(
+		GRPCStatusCodeKey.Int(int(s.Code())),
+		RPCGRPCStatusDetailsKey.StringSlice(values),
+	))
+}
+
+// statusDetailJSON returns the JSON encoding of the status detail d. Field
+// masks and sensitive fields configured for payloads are applied to it. If
+// the type of d is unknown, only its type URL is encoded.
+func (c *config) statusDetailJSON(d *anypb.Any) string {
+	typeOnly := `{"@type":` + strconv.Quote(d.GetTypeUrl()) + `}`
+
+	msg, err := d.UnmarshalNew()
+	if err != nil {
+		return typeOnly
+	}
+	if redacted, ok := c.redactPayload(msg).(proto.Message); ok {
+		msg = redacted
+	}
+	a, err := anypb.New(msg)
+	if err != nil {
+		return typeOnly
+	}
+	b, err := protojson.Marshal(a)
+	if err != nil {
+		return typeOnly
+	}
+	return string(b)
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// streamProgressEventName is the name of the events reporting the progress
+// of streaming RPCs, see WithStreamProgressInterval.
+const streamProgressEventName = "stream.progress"
+
+// streamProgressAttrs returns the attribute sets of the throughput of the
+// received and sent messages of a streaming RPC with metricAttrs.
+//
+// They must be built before recordStreamProgress is started, as creating a
+// set sorts the attributes in place, and metricAttrs are used by the stats
+// handler concurrently.
+func streamProgressAttrs(metricAttrs []attribute.KeyValue) (recv, sent attribute.Set) {
+	n := len(metricAttrs)
+	recv = attribute.NewSet(append(metricAttrs[:n:n], RPCMessageTypeReceived)...)
+	sent = attribute.NewSet(append(metricAttrs[:n:n], RPCMessageTypeSent)...)
+	return recv, sent
+}
+
+// recordStreamProgress reports the progress of the streaming RPC of gctx
+// every StreamProgressInterval until gctx.progressStop is closed, recording
+// the throughput metrics with the attributes recvAttrs and sentAttrs, see
+// streamProgressAttrs. It must be run in its own goroutine.
+func (c *config) recordStreamProgress(ctx context.Context, span trace.Span, gctx *gRPCContext, recvAttrs, sentAttrs attribute.Set, recordMetrics bool) {
+	ticker := time.NewTicker(c.StreamProgressInterval)
+	defer ticker.Stop()
+
+	recvOpts := []metric.RecordOption{metric.WithAttributeSet(recvAttrs)}
+	sentOpts := []metric.RecordOption{metric.WithAttributeSet(sentAttrs)}
+
+	last := time.Now()
+	var lastMsgsIn, lastMsgsOut, lastBytesIn, lastBytesOut int64
+	for {
+		select {
+		case <-gctx.progressStop:
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(last).Seconds()
+			if elapsed <= 0 {
+				continue
+			}
+			msgsIn := atomic.LoadInt64(&gctx.messagesReceived)
+			msgsOut := atomic.LoadInt64(&gctx.messagesSent)
+			bytesIn := atomic.LoadInt64(&gctx.bytesReceived)
+			bytesOut := atomic.LoadInt64(&gctx.bytesSent)
+
+			recvRate := float64(bytesIn-lastBytesIn) / elapsed
+			sentRate := float64(bytesOut-lastBytesOut) / elapsed
+			if gctx.segment != nil {
+				span = gctx.segment.current()
+			}
+			if span.IsRecording() {
+				span.AddEvent(streamProgressEventName, trace.WithTimestamp(now), trace.WithAttributes(
+					StreamMessagesReceivedKey.Int64(msgsIn),
+					StreamMessagesSentKey.Int64(msgsOut),
+					StreamBytesReceivedKey.Int64(bytesIn),
+					StreamBytesSentKey.Int64(bytesOut),
+					StreamReceiveThroughputKey.Float64(recvRate),
+					StreamSendThroughputKey.Float64(sentRate),
+				))
+			}
+			if recordMetrics {
+				c.streamThroughput.Record(ctx, recvRate, recvOpts...)
+				c.streamThroughput.Record(ctx, sentRate, sentOpts...)
+				c.streamMessageThroughput.Record(ctx, float64(msgsIn-lastMsgsIn)/elapsed, recvOpts...)
+				c.streamMessageThroughput.Record(ctx, float64(msgsOut-lastMsgsOut)/elapsed, sentOpts...)
+			}
+
+			last = now
+			lastMsgsIn, lastMsgsOut, lastBytesIn, lastBytesOut = msgsIn, msgsOut, bytesIn, bytesOut
+		}
+	}
+}
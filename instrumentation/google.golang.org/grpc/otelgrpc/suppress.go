@@ -0,0 +1,27 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import "context"
+
+type suppressedContextKey struct{}
+
+// WithSuppressedContext returns a copy of parent in which the spans of
+// client RPCs are suppressed. The client stats handler does not start a span
+// for RPCs made with the returned context, or any context derived from it.
+// Metrics are still recorded and the span context of parent is still
+// propagated.
+//
+// This is useful for RPCs made from code paths that are already
+// instrumented, e.g. a telemetry exporter or a wrapper recording its own
+// client spans, to avoid duplicate spans.
+func WithSuppressedContext(parent context.Context) context.Context {
+	return context.WithValue(parent, suppressedContextKey{}, true)
+}
+
+// isSuppressed reports whether client spans are suppressed in ctx.
+func isSuppressed(ctx context.Context) bool {
+	suppressed, _ := ctx.Value(suppressedContextKey{}).(bool)
+	return suppressed
+}
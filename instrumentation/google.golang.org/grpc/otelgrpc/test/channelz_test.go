@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	channelzpb "google.golang.org/grpc/channelz/grpc_channelz_v1"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// fakeChannelz serves a channelz database with one server with sockets 10
+// and 11, and one top channel with a nested channel whose subchannel has
+// socket 20.
+type fakeChannelz struct {
+	channelzpb.ChannelzClient
+}
+
+func tcpAddress(port int32) *channelzpb.Address {
+	return &channelzpb.Address{Address: &channelzpb.Address_TcpipAddress{
+		TcpipAddress: &channelzpb.Address_TcpIpAddress{IpAddress: net.IPv4(127, 0, 0, 1).To4(), Port: port},
+	}}
+}
+
+func (fakeChannelz) GetServers(context.Context, *channelzpb.GetServersRequest, ...grpc.CallOption) (*channelzpb.GetServersResponse, error) {
+	return &channelzpb.GetServersResponse{
+		Server: []*channelzpb.Server{{Ref: &channelzpb.ServerRef{ServerId: 1}}},
+		End:    true,
+	}, nil
+}
+
+func (fakeChannelz) GetServerSockets(context.Context, *channelzpb.GetServerSocketsRequest, ...grpc.CallOption) (*channelzpb.GetServerSocketsResponse, error) {
+	return &channelzpb.GetServerSocketsResponse{
+		SocketRef: []*channelzpb.SocketRef{{SocketId: 10}, {SocketId: 11}},
+		End:       true,
+	}, nil
+}
+
+func (fakeChannelz) GetTopChannels(context.Context, *channelzpb.GetTopChannelsRequest, ...grpc.CallOption) (*channelzpb.GetTopChannelsResponse, error) {
+	return &channelzpb.GetTopChannelsResponse{
+		Channel: []*channelzpb.Channel{{
+			Ref:        &channelzpb.ChannelRef{ChannelId: 2},
+			ChannelRef: []*channelzpb.ChannelRef{{ChannelId: 3}},
+		}},
+		End: true,
+	}, nil
+}
+
+func (fakeChannelz) GetChannel(context.Context, *channelzpb.GetChannelRequest, ...grpc.CallOption) (*channelzpb.GetChannelResponse, error) {
+	return &channelzpb.GetChannelResponse{Channel: &channelzpb.Channel{
+		Ref:           &channelzpb.ChannelRef{ChannelId: 3},
+		SubchannelRef: []*channelzpb.SubchannelRef{{SubchannelId: 4}},
+	}}, nil
+}
+
+func (fakeChannelz) GetSubchannel(context.Context, *channelzpb.GetSubchannelRequest, ...grpc.CallOption) (*channelzpb.GetSubchannelResponse, error) {
+	return &channelzpb.GetSubchannelResponse{Subchannel: &channelzpb.Subchannel{
+		SocketRef: []*channelzpb.SocketRef{{SocketId: 20}},
+	}}, nil
+}
+
+func (fakeChannelz) GetSocket(_ context.Context, req *channelzpb.GetSocketRequest, _ ...grpc.CallOption) (*channelzpb.GetSocketResponse, error) {
+	sockets := map[int64]*channelzpb.Socket{
+		10: {Local: tcpAddress(8080), Remote: tcpAddress(50000)},
+		11: {Local: tcpAddress(8080), Remote: tcpAddress(50001)},
+		20: {Local: tcpAddress(50002), Remote: tcpAddress(9090)},
+	}
+	return &channelzpb.GetSocketResponse{Socket: sockets[req.GetSocketId()]}, nil
+}
+
+func tcpAddr(port int) net.Addr {
+	return &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: port}
+}
+
+// hasAttr reports whether the last span ended in sr has the attribute kv.
+func hasAttr(sr *tracetest.SpanRecorder, kv attribute.KeyValue) bool {
+	spans := sr.Ended()
+	if len(spans) == 0 {
+		return false
+	}
+	for _, got := range spans[len(spans)-1].Attributes() {
+		if got == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestChannelzServer(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithConnectionSpans(true), otelgrpc.WithChannelz(fakeChannelz{}))
+
+	want := []attribute.KeyValue{
+		otelgrpc.RPCGRPCChannelzServerIDKey.Int64(1),
+		otelgrpc.RPCGRPCChannelzSocketIDKey.Int64(11),
+	}
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{LocalAddr: tcpAddr(8080), RemoteAddr: tcpAddr(50001)})
+	// The channelz IDs are resolved asynchronously.
+	require.Eventually(t, func() bool {
+		rctx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+		h.HandleRPC(rctx, &stats.End{})
+		return hasAttr(sr, want[0])
+	}, time.Second, time.Millisecond)
+	h.HandleConn(ctx, &stats.ConnEnd{})
+
+	spans := sr.Ended()
+	require.GreaterOrEqual(t, len(spans), 2)
+	for _, s := range spans[len(spans)-2:] {
+		for _, kv := range want {
+			assert.Contains(t, s.Attributes(), kv, s.Name())
+		}
+	}
+}
+
+func TestChannelzClient(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithChannelz(fakeChannelz{}))
+
+	want := []attribute.KeyValue{
+		otelgrpc.RPCGRPCChannelzChannelIDKey.Int64(2),
+		otelgrpc.RPCGRPCChannelzSubchannelIDKey.Int64(4),
+		otelgrpc.RPCGRPCChannelzSocketIDKey.Int64(20),
+	}
+	local, remote := tcpAddr(50002), tcpAddr(9090)
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{LocalAddr: local, RemoteAddr: remote})
+	// The channelz IDs are resolved asynchronously.
+	require.Eventually(t, func() bool {
+		rctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+		h.HandleRPC(rctx, &stats.OutHeader{Client: true, LocalAddr: local, RemoteAddr: remote})
+		h.HandleRPC(rctx, &stats.End{Client: true})
+		return hasAttr(sr, want[0])
+	}, time.Second, time.Millisecond)
+	h.HandleConn(ctx, &stats.ConnEnd{Client: true})
+
+	spans := sr.Ended()
+	for _, kv := range want {
+		assert.Contains(t, spans[len(spans)-1].Attributes(), kv)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func TestCompressionMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 100, CompressedLength: 25})
+	h.HandleRPC(ctx, &stats.OutPayload{Length: 10, CompressedLength: 10})
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	got := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+
+	for name, want := range map[string]int64{
+		"rpc.server.request.compressed_size":  25,
+		"rpc.server.response.compressed_size": 10,
+	} {
+		m, ok := got[name]
+		require.True(t, ok, "missing %s", name)
+		assert.Equal(t, "By", m.Unit)
+		dps := m.Data.(metricdata.Histogram[int64]).DataPoints
+		require.Len(t, dps, 1, name)
+		assert.Equal(t, want, dps[0].Sum, name)
+		method, _ := dps[0].Attributes.Value(semconv.RPCMethodKey)
+		assert.Equal(t, "Dump", method.AsString(), name)
+	}
+
+	ratio, ok := got["rpc.server.compression_ratio"]
+	require.True(t, ok, "missing rpc.server.compression_ratio")
+	assert.Equal(t, "1", ratio.Unit)
+	dps := ratio.Data.(metricdata.Histogram[float64]).DataPoints
+	require.Len(t, dps, 2)
+	want := map[attribute.Value]float64{
+		otelgrpc.RPCMessageTypeReceived.Value: 4,
+		otelgrpc.RPCMessageTypeSent.Value:     1,
+	}
+	for _, dp := range dps {
+		typ, ok := dp.Attributes.Value(otelgrpc.RPCMessageTypeKey)
+		require.True(t, ok)
+		assert.Equal(t, want[typ], dp.Sum, typ.Emit())
+		method, _ := dp.Attributes.Value(semconv.RPCMethodKey)
+		assert.Equal(t, "Dump", method.AsString())
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// operation is a message of a stream multiplexing several operations.
+type operation struct{ id string }
+
+func operationID(payload any) string {
+	if op, ok := payload.(*operation); ok {
+		return op.id
+	}
+	return ""
+}
+
+func TestMessageCorrelationEvents(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithMessageCorrelation(operationID))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Attach"})
+	h.HandleRPC(ctx, &stats.Begin{IsClientStream: true, IsServerStream: true})
+	h.HandleRPC(ctx, &stats.InPayload{Payload: &operation{id: "a"}})
+	h.HandleRPC(ctx, &stats.InPayload{Payload: &operation{id: "b"}})
+	h.HandleRPC(ctx, &stats.OutPayload{Payload: &operation{id: "b"}})
+	h.HandleRPC(ctx, &stats.OutPayload{Payload: &operation{id: "c"}})
+	h.HandleRPC(ctx, &stats.OutPayload{Payload: &operation{id: "a"}})
+	h.HandleRPC(ctx, &stats.OutPayload{Payload: &operation{id: "a"}})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 6)
+
+	correlated := func(e sdktrace.Event) (int64, bool) {
+		attrs := attribute.NewSet(e.Attributes...)
+		v, ok := attrs.Value(otelgrpc.RPCMessageCorrelatedIDKey)
+		return v.AsInt64(), ok
+	}
+	for _, e := range events[:2] {
+		_, ok := correlated(e)
+		assert.False(t, ok, "request")
+	}
+	id, ok := correlated(events[2])
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), id)
+	_, ok = correlated(events[3])
+	assert.False(t, ok, "unknown request")
+	id, ok = correlated(events[4])
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), id)
+	_, ok = correlated(events[5])
+	assert.False(t, ok, "answered request")
+}
+
+func TestMessageCorrelationSpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewClientHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMessageSpans(true),
+		otelgrpc.WithMessageCorrelation(operationID),
+	)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Attach"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true, IsClientStream: true, IsServerStream: true})
+	h.HandleRPC(ctx, &stats.OutPayload{Client: true, Payload: &operation{id: "a"}})
+	h.HandleRPC(ctx, &stats.InPayload{Client: true, Payload: &operation{id: "a"}})
+	h.HandleRPC(ctx, &stats.End{Client: true})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	request, response := spans[0], spans[1]
+	assert.Empty(t, request.Links())
+	require.Len(t, response.Links(), 1)
+	assert.Equal(t, request.SpanContext(), response.Links()[0].SpanContext)
+	assert.Contains(t, response.Attributes(), otelgrpc.RPCMessageCorrelatedIDKey.Int64(1))
+}
+
+func TestMessageCorrelationUnary(t *testing.T) {
+	called := false
+	h := otelgrpc.NewServerHandler(otelgrpc.WithMessageCorrelation(func(any) string {
+		called = true
+		return "a"
+	}))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.InPayload{})
+	h.HandleRPC(ctx, &stats.OutPayload{})
+	h.HandleRPC(ctx, &stats.End{})
+	assert.False(t, called)
+}
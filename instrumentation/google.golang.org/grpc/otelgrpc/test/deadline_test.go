@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStatsHandlerDeadline(t *testing.T) {
+	begin := time.Now()
+	deadline := begin.Add(time.Minute)
+
+	tests := []struct {
+		name    string
+		handler func(...otelgrpc.Option) stats.Handler
+		end     time.Time
+		want    attribute.KeyValue
+	}{
+		{name: "ServerLocal", handler: otelgrpc.NewServerHandler, end: deadline, want: otelgrpc.RPCGRPCDeadlineExceededLocal},
+		{name: "ServerRemote", handler: otelgrpc.NewServerHandler, end: begin.Add(time.Second), want: otelgrpc.RPCGRPCDeadlineExceededRemote},
+		{name: "ClientLocal", handler: otelgrpc.NewClientHandler, end: deadline, want: otelgrpc.RPCGRPCDeadlineExceededLocal},
+		{name: "ClientRemote", handler: otelgrpc.NewClientHandler, end: begin.Add(time.Second), want: otelgrpc.RPCGRPCDeadlineExceededRemote},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			h := tt.handler(otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))))
+
+			ctx, cancel := context.WithDeadline(context.Background(), deadline)
+			defer cancel()
+			ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.Begin{BeginTime: begin})
+			h.HandleRPC(ctx, &stats.End{
+				BeginTime: begin,
+				EndTime:   tt.end,
+				Error:     status.Error(codes.DeadlineExceeded, "context deadline exceeded"),
+			})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			attrs := spans[0].Attributes()
+			assert.Contains(t, attrs, otelgrpc.RPCGRPCRequestDeadlineSetKey.Bool(true))
+			assert.Contains(t, attrs, otelgrpc.RPCGRPCRequestTimeRemainingKey.Float64(time.Minute.Seconds()))
+			assert.Contains(t, attrs, tt.want)
+		})
+	}
+}
+
+func TestStatsHandlerEndReason(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	cancel()
+	h.HandleRPC(ctx, &stats.End{Error: status.Error(codes.Canceled, "context canceled")})
+
+	ctx = h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Contains(t, spans[0].Attributes(), otelgrpc.RPCGRPCEndReasonCancelledByClient)
+	for _, kv := range spans[1].Attributes() {
+		assert.NotEqual(t, otelgrpc.RPCGRPCEndReasonKey, kv.Key, "successful RPC has an end reason")
+	}
+}
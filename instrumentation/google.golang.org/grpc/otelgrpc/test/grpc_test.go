@@ -101,11 +101,13 @@ func TestInterceptors(t *testing.T) {
 			grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(
 				otelgrpc.WithTracerProvider(clientUnaryTP),
 				otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+				otelgrpc.WithPayloadCapture(false),
 			)),
 			//nolint:staticcheck // Interceptors are deprecated and will be removed in the next release.
 			grpc.WithStreamInterceptor(otelgrpc.StreamClientInterceptor(
 				otelgrpc.WithTracerProvider(clientStreamTP),
 				otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+				otelgrpc.WithPayloadCapture(false),
 			)),
 		},
 		[]grpc.ServerOption{
@@ -114,11 +116,13 @@ func TestInterceptors(t *testing.T) {
 				otelgrpc.WithTracerProvider(serverUnaryTP),
 				otelgrpc.WithMeterProvider(serverUnaryMP),
 				otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+				otelgrpc.WithPayloadCapture(false),
 			)),
 			//nolint:staticcheck // Interceptors are deprecated and will be removed in the next release.
 			grpc.StreamInterceptor(otelgrpc.StreamServerInterceptor(
 				otelgrpc.WithTracerProvider(serverStreamTP),
 				otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents),
+				otelgrpc.WithPayloadCapture(false),
 			)),
 		},
 	)
@@ -161,6 +165,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr string
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.Empty"),
 			},
 		},
 		{
@@ -168,6 +173,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr string
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.Empty"),
 			},
 		},
 	}, emptySpan.Events())
@@ -189,6 +195,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr string
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.SimpleRequest"),
 				// largeReqSize from "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/test" + 12 (overhead).
 			},
 		},
@@ -197,6 +204,7 @@ func checkUnaryClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr string
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.SimpleResponse"),
 				// largeRespSize from "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/test" + 8 (overhead).
 			},
 		},
@@ -229,6 +237,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -236,6 +245,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -243,6 +253,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -250,6 +261,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		// client does not record an event for the server response.
@@ -273,6 +285,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -280,6 +293,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -287,6 +301,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -294,6 +309,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -301,6 +317,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 	}, streamOutput.Events())
@@ -322,6 +339,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -329,6 +347,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -336,6 +355,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -343,6 +363,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -350,6 +371,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -357,6 +379,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -364,6 +387,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -371,6 +395,7 @@ func checkStreamClientSpans(t *testing.T, spans []trace.ReadOnlySpan, addr strin
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 	}, pingPong.Events())
@@ -397,6 +422,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -404,6 +430,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -411,6 +438,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -418,6 +446,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallRequest"),
 			},
 		},
 		{
@@ -425,6 +454,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingInputCallResponse"),
 			},
 		},
 	}, streamInput.Events())
@@ -450,6 +480,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -457,6 +488,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -464,6 +496,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -471,6 +504,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -478,6 +512,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 	}, streamOutput.Events())
@@ -503,6 +538,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -510,6 +546,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -517,6 +554,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -524,6 +562,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(2),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -531,6 +570,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -538,6 +578,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(3),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 		{
@@ -545,6 +586,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallRequest"),
 			},
 		},
 		{
@@ -552,6 +594,7 @@ func checkStreamServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(4),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.StreamingOutputCallResponse"),
 			},
 		},
 	}, pingPong.Events())
@@ -579,6 +622,7 @@ func checkUnaryServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.Empty"),
 			},
 		},
 		{
@@ -586,6 +630,7 @@ func checkUnaryServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.Empty"),
 			},
 		},
 	}, emptySpan.Events())
@@ -610,6 +655,7 @@ func checkUnaryServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("RECEIVED"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.SimpleRequest"),
 				// largeReqSize from "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/test" + 12 (overhead).
 			},
 		},
@@ -618,6 +664,7 @@ func checkUnaryServerSpans(t *testing.T, spans []trace.ReadOnlySpan) {
 			Attributes: []attribute.KeyValue{
 				otelgrpc.RPCMessageIDKey.Int(1),
 				otelgrpc.RPCMessageTypeKey.String("SENT"),
+				otelgrpc.RPCMessageProtoTypeKey.String("grpc.testing.SimpleResponse"),
 				// largeRespSize from "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/test" + 8 (overhead).
 			},
 		},
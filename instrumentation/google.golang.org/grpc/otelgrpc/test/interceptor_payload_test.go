@@ -0,0 +1,149 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestUnaryServerInterceptorPayload(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	interceptor := otelgrpc.UnaryServerInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPayloadSizeLimit(32))
+
+	req := wrapperspb.String(strings.Repeat("a", 100))
+	_, err := interceptor(context.Background(), req, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return wrapperspb.String("ok"), nil
+		})
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Len(t, got.AsString(), 32)
+	truncated, ok := eventAttr(t, events[0], otelgrpc.RPCMessageTruncatedKey)
+	require.True(t, ok, "missing truncated attribute")
+	assert.True(t, truncated.AsBool())
+
+	got, ok = eventAttr(t, events[1], "response")
+	require.True(t, ok, "missing response attribute")
+	assert.Equal(t, `"ok"`, got.AsString())
+}
+
+func TestUnaryClientInterceptorPayload(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	interceptor := otelgrpc.UnaryClientInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPayloadCaptureEvents(otelgrpc.SentEvents))
+
+	cc, err := grpc.NewClient("passthrough:///test", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = cc.Close() })
+
+	reply := wrapperspb.String("")
+	err = interceptor(context.Background(), "/test.Service/Method", wrapperspb.String("req"), reply, cc,
+		func(_ context.Context, _ string, _, reply interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+			proto.Merge(reply.(proto.Message), wrapperspb.String("resp"))
+			return nil
+		})
+	require.NoError(t, err)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "response")
+	require.True(t, ok, "missing sent payload")
+	assert.Equal(t, `"req"`, got.AsString())
+	_, ok = eventAttr(t, events[1], "request")
+	assert.False(t, ok, "unexpected received payload")
+}
+
+// testServerStream is a grpc.ServerStream receiving and sending the
+// messages of a client streaming RPC.
+type testServerStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	received []proto.Message
+}
+
+func (s *testServerStream) Context() context.Context { return s.ctx }
+
+func (s *testServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.received[0])
+	s.received = s.received[1:]
+	return nil
+}
+
+func (s *testServerStream) SendMsg(interface{}) error { return nil }
+
+func TestStreamServerInterceptorPayloadOnError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		payload bool
+	}{
+		{name: "OK", err: nil, payload: false},
+		{name: "Error", err: status.Error(grpc_codes.Internal, "failed"), payload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			interceptor := otelgrpc.StreamServerInterceptor(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPayloadCaptureOnError(true))
+
+			ss := &testServerStream{
+				ctx:      context.Background(),
+				received: []proto.Message{wrapperspb.String("a"), wrapperspb.String("b")},
+			}
+			err := interceptor(nil, ss, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"},
+				func(_ interface{}, stream grpc.ServerStream) error {
+					for i := 0; i < 2; i++ {
+						if err := stream.RecvMsg(wrapperspb.String("")); err != nil {
+							return err
+						}
+					}
+					if err := stream.SendMsg(wrapperspb.String("done")); err != nil {
+						return err
+					}
+					return tt.err
+				})
+			assert.Equal(t, tt.err, err)
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			events := spans[0].Events()
+			require.Len(t, events, 3)
+
+			got, ok := eventAttr(t, events[1], "request")
+			assert.Equal(t, tt.payload, ok, "request attribute")
+			if ok {
+				assert.Equal(t, `"b"`, got.AsString())
+			}
+			_, ok = eventAttr(t, events[2], "response")
+			assert.Equal(t, tt.payload, ok, "response attribute")
+		})
+	}
+}
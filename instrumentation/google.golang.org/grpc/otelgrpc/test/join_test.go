@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type recordingHandlerKey struct{}
+
+// recordingHandler records the stats it handles and whether the context
+// passed to it holds the values added by its TagRPC and TagConn methods.
+type recordingHandler struct {
+	name   string
+	events []string
+}
+
+func (h *recordingHandler) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	h.events = append(h.events, "TagRPC")
+	return context.WithValue(ctx, recordingHandlerKey{}, h.name)
+}
+
+func (h *recordingHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	h.events = append(h.events, "HandleRPC")
+	if _, ok := ctx.Value(recordingHandlerKey{}).(string); !ok {
+		h.events = append(h.events, "missing value")
+	}
+}
+
+func (h *recordingHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	h.events = append(h.events, "TagConn")
+	return ctx
+}
+
+func (h *recordingHandler) HandleConn(context.Context, stats.ConnStats) {
+	h.events = append(h.events, "HandleConn")
+}
+
+func TestJoinHandlers(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	custom := &recordingHandler{name: "custom"}
+	h := otelgrpc.JoinHandlers(
+		otelgrpc.NewClientHandler(
+			otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))),
+			otelgrpc.WithPropagators(propagation.TraceContext{}),
+		),
+		nil,
+		custom,
+	)
+
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+	h.HandleConn(ctx, &stats.ConnBegin{Client: true})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true})
+	h.HandleRPC(ctx, &stats.End{Client: true})
+	h.HandleConn(ctx, &stats.ConnEnd{Client: true})
+
+	assert.Equal(t, []string{"TagConn", "HandleConn", "TagRPC", "HandleRPC", "HandleRPC", "HandleConn"}, custom.events)
+	require.Len(t, sr.Ended(), 1)
+
+	// The metadata injected by the otelgrpc handler is kept.
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	assert.NotEmpty(t, md.Get("traceparent"))
+	assert.Equal(t, "custom", ctx.Value(recordingHandlerKey{}))
+}
+
+func TestJoinHandlersSingle(t *testing.T) {
+	h := otelgrpc.NewServerHandler()
+	assert.Same(t, h, otelgrpc.JoinHandlers(nil, h))
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMetadataAttributes(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMetadataAttributes("X-Job-ID", "authorization", "x-status", "missing"),
+	)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{
+		FullMethodName: "/test.Service/Method",
+	})
+	h.HandleRPC(ctx, &stats.InHeader{Header: metadata.Pairs(
+		"x-job-id", "42",
+		"authorization", "Bearer secret",
+		"x-other", "ignored",
+	)})
+	h.HandleRPC(ctx, &stats.OutHeader{Header: metadata.Pairs("x-job-id", "43")})
+	h.HandleRPC(ctx, &stats.OutTrailer{Trailer: metadata.Pairs("x-status", "done")})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+
+	assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.request.metadata.x-job-id", []string{"42"}))
+	assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.request.metadata.authorization", []string{"REDACTED"}))
+	assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.response.metadata.x-job-id", []string{"43"}))
+	assert.Contains(t, attrs, attribute.StringSlice("rpc.grpc.response.metadata.x-status", []string{"done"}))
+	for _, kv := range attrs {
+		assert.NotContains(t, kv.Key, "x-other")
+		assert.NotContains(t, kv.Key, "missing")
+	}
+}
@@ -0,0 +1,570 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/logtest"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	pb "google.golang.org/grpc/interop/grpc_testing"
+)
+
+// recordRPC runs a unary RPC carrying req and resp through h and returns
+// the span recorded for it.
+func recordRPC(t *testing.T, h func(...otelgrpc.Option) stats.Handler, req, resp any, opts ...otelgrpc.Option) sdktrace.ReadOnlySpan {
+	t.Helper()
+	return recordRPCWithError(t, h, req, resp, nil, opts...)
+}
+
+// recordRPCWithError runs a unary RPC carrying req and resp that ends with
+// err through h and returns the span recorded for it.
+func recordRPCWithError(t *testing.T, h func(...otelgrpc.Option) stats.Handler, req, resp any, err error, opts ...otelgrpc.Option) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	handler := h(append([]otelgrpc.Option{otelgrpc.WithTracerProvider(tp)}, opts...)...)
+
+	ctx := handler.TagRPC(context.Background(), &stats.RPCTagInfo{
+		FullMethodName: "/test.Service/Method",
+	})
+	handler.HandleRPC(ctx, &stats.Begin{})
+	handler.HandleRPC(ctx, &stats.InPayload{Payload: req})
+	handler.HandleRPC(ctx, &stats.OutPayload{Payload: resp})
+	handler.HandleRPC(ctx, &stats.End{Error: err})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	return spans[0]
+}
+
+func eventAttr(t *testing.T, event sdktrace.Event, key attribute.Key) (attribute.Value, bool) {
+	t.Helper()
+	for _, kv := range event.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestPayloadSizeLimit(t *testing.T) {
+	req := wrapperspb.String(strings.Repeat("a", 100))
+	resp := wrapperspb.String("ok")
+
+	span := recordRPC(t, otelgrpc.NewServerHandler, req, resp, otelgrpc.WithPayloadSizeLimit(32))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Len(t, got.AsString(), 32)
+	truncated, ok := eventAttr(t, events[0], otelgrpc.RPCMessageTruncatedKey)
+	require.True(t, ok, "missing truncated attribute")
+	assert.True(t, truncated.AsBool())
+
+	got, ok = eventAttr(t, events[1], "response")
+	require.True(t, ok, "missing response attribute")
+	assert.Equal(t, `"ok"`, got.AsString())
+	_, ok = eventAttr(t, events[1], otelgrpc.RPCMessageTruncatedKey)
+	assert.False(t, ok, "unexpected truncated attribute")
+}
+
+func TestPayloadCaptureDisabled(t *testing.T) {
+	for name, h := range map[string]func(...otelgrpc.Option) stats.Handler{
+		"Server": otelgrpc.NewServerHandler,
+		"Client": otelgrpc.NewClientHandler,
+	} {
+		t.Run(name, func(t *testing.T) {
+			span := recordRPC(t, h, wrapperspb.String("req"), wrapperspb.String("resp"), otelgrpc.WithPayloadCapture(false))
+			events := span.Events()
+			require.Len(t, events, 2)
+			for _, e := range events {
+				_, ok := eventAttr(t, e, "request")
+				assert.False(t, ok, "unexpected request attribute")
+				_, ok = eventAttr(t, e, "response")
+				assert.False(t, ok, "unexpected response attribute")
+				_, ok = eventAttr(t, e, "message.uncompressed_size")
+				assert.True(t, ok, "missing message size attribute")
+			}
+		})
+	}
+}
+
+func TestPayloadCaptureEvents(t *testing.T) {
+	tests := []struct {
+		name           string
+		events         []otelgrpc.Event
+		request, reply bool
+	}{
+		{name: "Received", events: []otelgrpc.Event{otelgrpc.ReceivedEvents}, request: true},
+		{name: "Sent", events: []otelgrpc.Event{otelgrpc.SentEvents}, reply: true},
+		{name: "Both", events: []otelgrpc.Event{otelgrpc.ReceivedEvents, otelgrpc.SentEvents}, request: true, reply: true},
+		{name: "None", events: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := recordRPC(t, otelgrpc.NewServerHandler, wrapperspb.String("req"), wrapperspb.String("resp"), otelgrpc.WithPayloadCaptureEvents(tt.events...))
+			events := span.Events()
+			require.Len(t, events, 2)
+
+			_, ok := eventAttr(t, events[0], "request")
+			assert.Equal(t, tt.request, ok, "request attribute")
+			_, ok = eventAttr(t, events[1], "response")
+			assert.Equal(t, tt.reply, ok, "response attribute")
+		})
+	}
+}
+
+func TestMessageProtoType(t *testing.T) {
+	for name, h := range map[string]func(...otelgrpc.Option) stats.Handler{
+		"Server": otelgrpc.NewServerHandler,
+		"Client": otelgrpc.NewClientHandler,
+	} {
+		t.Run(name, func(t *testing.T) {
+			span := recordRPC(t, h, &pb.SimpleRequest{}, &pb.SimpleResponse{}, otelgrpc.WithPayloadCapture(false))
+			events := span.Events()
+			require.Len(t, events, 2)
+
+			msgType, ok := eventAttr(t, events[0], otelgrpc.RPCMessageProtoTypeKey)
+			require.True(t, ok, "missing request message type")
+			assert.Equal(t, "grpc.testing.SimpleRequest", msgType.AsString())
+			msgType, ok = eventAttr(t, events[1], otelgrpc.RPCMessageProtoTypeKey)
+			require.True(t, ok, "missing response message type")
+			assert.Equal(t, "grpc.testing.SimpleResponse", msgType.AsString())
+		})
+	}
+
+	span := recordRPC(t, otelgrpc.NewServerHandler, "req", nil)
+	for _, e := range span.Events() {
+		_, ok := eventAttr(t, e, otelgrpc.RPCMessageProtoTypeKey)
+		assert.False(t, ok, "unexpected message type of non-proto payload")
+	}
+}
+
+func TestPayloadCaptureOnError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		payload bool
+	}{
+		{name: "OK", err: nil, payload: false},
+		{name: "Error", err: status.Error(grpc_codes.Internal, "failed"), payload: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			span := recordRPCWithError(t, otelgrpc.NewServerHandler, wrapperspb.String("req"), wrapperspb.String("resp"), tt.err, otelgrpc.WithPayloadCaptureOnError(true))
+			events := span.Events()
+			require.Len(t, events, 2)
+
+			_, ok := eventAttr(t, events[0], "request")
+			assert.Equal(t, tt.payload, ok, "request attribute")
+			_, ok = eventAttr(t, events[1], "response")
+			assert.Equal(t, tt.payload, ok, "response attribute")
+			for _, e := range events {
+				_, ok = eventAttr(t, e, "message.id")
+				assert.True(t, ok, "missing message ID attribute")
+			}
+			assert.False(t, events[1].Time.Before(events[0].Time), "events out of order")
+		})
+	}
+}
+
+func TestPayloadMarshaler(t *testing.T) {
+	var methods []string
+	m := otelgrpc.PayloadMarshalerFunc(func(_ context.Context, fullMethod string, msg any) (attribute.Value, error) {
+		methods = append(methods, fullMethod)
+		if msg.(*wrapperspb.StringValue).GetValue() == "resp" {
+			return attribute.Value{}, assert.AnError
+		}
+		return attribute.Int64Value(42), nil
+	})
+
+	span := recordRPC(t, otelgrpc.NewClientHandler, wrapperspb.String("req"), wrapperspb.String("resp"), otelgrpc.WithPayloadMarshaler(m))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Equal(t, attribute.Int64Value(42), got)
+
+	got, ok = eventAttr(t, events[1], "response")
+	require.True(t, ok, "missing response attribute")
+	assert.Contains(t, got.AsString(), assert.AnError.Error())
+
+	assert.Equal(t, []string{"/test.Service/Method", "/test.Service/Method"}, methods)
+}
+
+func TestPayloadMetrics(t *testing.T) {
+	// Failing to marshal the response must be reported.
+	m := otelgrpc.PayloadMarshalerFunc(func(_ context.Context, _ string, msg any) (attribute.Value, error) {
+		if msg.(*wrapperspb.StringValue).GetValue() == "resp" {
+			return attribute.Value{}, assert.AnError
+		}
+		return attribute.StringValue(strings.Repeat("x", 256)), nil
+	})
+
+	tests := []struct {
+		name    string
+		opts    []otelgrpc.Option
+		err     error
+		dropped map[string]int64
+		errors  int64
+		// serialized is the number of payloads serialized.
+		serialized uint64
+	}{
+		{
+			name:       "Truncated",
+			opts:       []otelgrpc.Option{otelgrpc.WithPayloadMarshaler(m), otelgrpc.WithPayloadSizeLimit(128)},
+			dropped:    map[string]int64{"truncated": 1},
+			errors:     1,
+			serialized: 2,
+		},
+		{
+			name:       "RPCSucceeded",
+			opts:       []otelgrpc.Option{otelgrpc.WithPayloadCaptureOnError(true)},
+			dropped:    map[string]int64{"rpc_succeeded": 2},
+			serialized: 2,
+		},
+		{
+			name:       "RPCFailed",
+			opts:       []otelgrpc.Option{otelgrpc.WithPayloadCaptureOnError(true)},
+			err:        status.Error(grpc_codes.Internal, "failed"),
+			dropped:    map[string]int64{},
+			serialized: 2,
+		},
+		{
+			name:       "MaxMessages",
+			opts:       []otelgrpc.Option{otelgrpc.WithMaxCapturedMessages(1)},
+			dropped:    map[string]int64{"max_messages": 1},
+			serialized: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			opts := append([]otelgrpc.Option{otelgrpc.WithMeterProvider(mp)}, tt.opts...)
+			recordRPCWithError(t, otelgrpc.NewServerHandler, wrapperspb.String("req"), wrapperspb.String("resp"), tt.err, opts...)
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+
+			dropped := map[string]int64{}
+			var errors, durations uint64
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					switch m.Name {
+					case "otelgrpc.payloads.dropped":
+						for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+							reason, _ := dp.Attributes.Value("reason")
+							dropped[reason.AsString()] += dp.Value
+						}
+					case "otelgrpc.serialization.errors":
+						for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+							errors += uint64(dp.Value)
+						}
+					case "otelgrpc.serialization.duration":
+						for _, dp := range m.Data.(metricdata.Histogram[float64]).DataPoints {
+							durations += dp.Count
+						}
+					}
+				}
+			}
+			assert.Equal(t, tt.dropped, dropped, "dropped payloads")
+			assert.Equal(t, uint64(tt.errors), errors, "serialization errors")
+			assert.Equal(t, tt.serialized, durations, "serialization durations")
+		})
+	}
+}
+
+func TestPayloadWireFormat(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPayloadWireFormat(true))
+
+	req := wrapperspb.String("req")
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	// Unknown field 15 of type varint is not known to the message, it must
+	// be recorded anyway.
+	data = append(data, 0x78, 0x01)
+	resp := wrapperspb.String("resp")
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+	h.HandleRPC(ctx, &stats.InPayload{Payload: req, Data: data})
+	h.HandleRPC(ctx, &stats.OutPayload{Payload: resp})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Equal(t, base64.StdEncoding.EncodeToString(data), got.AsString())
+	enc, ok := eventAttr(t, events[0], otelgrpc.RPCMessagePayloadEncodingKey)
+	require.True(t, ok, "missing payload encoding attribute")
+	assert.Equal(t, "base64", enc.AsString())
+
+	// Without the wire bytes, the message is serialized.
+	got, ok = eventAttr(t, events[1], "response")
+	require.True(t, ok, "missing response attribute")
+	decoded, err := base64.StdEncoding.DecodeString(got.AsString())
+	require.NoError(t, err)
+	var gotResp wrapperspb.StringValue
+	require.NoError(t, proto.Unmarshal(decoded, &gotResp))
+	assert.Equal(t, "resp", gotResp.GetValue())
+}
+
+func TestPayloadWireFormatNotProto(t *testing.T) {
+	span := recordRPC(t, otelgrpc.NewServerHandler, "req", "resp", otelgrpc.WithPayloadWireFormat(true))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Contains(t, got.AsString(), "not a proto message")
+	_, ok = eventAttr(t, events[0], otelgrpc.RPCMessagePayloadEncodingKey)
+	assert.False(t, ok, "unexpected payload encoding attribute")
+}
+
+func TestMaxCapturedMessages(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []otelgrpc.Option
+		want []bool
+	}{
+		{
+			name: "First",
+			opts: []otelgrpc.Option{otelgrpc.WithMaxCapturedMessages(2)},
+			want: []bool{true, true, false, false, false},
+		},
+		{
+			name: "FirstAndLast",
+			opts: []otelgrpc.Option{otelgrpc.WithMaxCapturedMessages(1), otelgrpc.WithLastCapturedMessages(2)},
+			want: []bool{true, false, false, true, true},
+		},
+		{
+			name: "LastWithoutMax",
+			opts: []otelgrpc.Option{otelgrpc.WithLastCapturedMessages(2)},
+			want: []bool{true, true, true, true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := otelgrpc.NewClientHandler(append([]otelgrpc.Option{otelgrpc.WithTracerProvider(tp)}, tt.opts...)...)
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{
+				FullMethodName: "/test.Service/Stream",
+			})
+			for range tt.want {
+				h.HandleRPC(ctx, &stats.OutPayload{Payload: wrapperspb.String("msg")})
+			}
+			h.HandleRPC(ctx, &stats.End{})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			events := spans[0].Events()
+			require.Len(t, events, len(tt.want))
+
+			got := make([]bool, len(events))
+			for _, e := range events {
+				id, ok := eventAttr(t, e, "message.id")
+				require.True(t, ok, "missing message ID attribute")
+				_, got[id.AsInt64()-1] = eventAttr(t, e, "response")
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPayloadLoggerProvider(t *testing.T) {
+	rec := logtest.NewRecorder()
+	span := recordRPC(t, otelgrpc.NewServerHandler, wrapperspb.String("req"), wrapperspb.String("resp"), otelgrpc.WithPayloadLoggerProvider(rec))
+
+	events := span.Events()
+	require.Len(t, events, 2)
+	for _, e := range events {
+		_, ok := eventAttr(t, e, "request")
+		assert.False(t, ok, "unexpected request attribute")
+		_, ok = eventAttr(t, e, "response")
+		assert.False(t, ok, "unexpected response attribute")
+	}
+
+	result := rec.Result()
+	require.Len(t, result, 1)
+	assert.Equal(t, otelgrpc.ScopeName, result[0].Name)
+	records := result[0].Records
+	require.Len(t, records, 2)
+
+	for i, want := range []string{`"req"`, `"resp"`} {
+		r := records[i]
+		assert.Equal(t, want, r.Body().AsString())
+		assert.Equal(t, events[i].Time, r.Timestamp())
+		assert.Equal(t, span.SpanContext(), oteltrace.SpanContextFromContext(r.Context()))
+
+		var method string
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == "rpc.method" {
+				method = kv.Value.AsString()
+			}
+			return true
+		})
+		assert.Equal(t, "Method", method)
+	}
+}
+
+func TestPayloadLoggerProviderWireFormat(t *testing.T) {
+	rec := logtest.NewRecorder()
+	req, resp := wrapperspb.String("req"), wrapperspb.String("resp")
+	recordRPC(t, otelgrpc.NewServerHandler, req, resp, otelgrpc.WithPayloadWireFormat(true), otelgrpc.WithPayloadLoggerProvider(rec))
+
+	result := rec.Result()
+	require.Len(t, result, 1)
+	records := result[0].Records
+	require.Len(t, records, 2)
+
+	for i, msg := range []proto.Message{req, resp} {
+		data, err := proto.Marshal(msg)
+		require.NoError(t, err)
+		r := records[i]
+		assert.Equal(t, base64.StdEncoding.EncodeToString(data), r.Body().AsString())
+
+		var encoding string
+		r.WalkAttributes(func(kv log.KeyValue) bool {
+			if kv.Key == string(otelgrpc.RPCMessagePayloadEncodingKey) {
+				encoding = kv.Value.AsString()
+			}
+			return true
+		})
+		assert.Equal(t, "base64", encoding)
+	}
+}
+
+func TestPayloadCompactJSON(t *testing.T) {
+	req := &pb.SimpleRequest{ResponseSize: 1}
+	span := recordRPC(t, otelgrpc.NewServerHandler, req, wrapperspb.String("resp"),
+		otelgrpc.WithPayloadCompactJSON(true),
+		otelgrpc.WithPayloadAttributeKeys("rpc.request.body", ""),
+	)
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "rpc.request.body")
+	require.True(t, ok, "missing request attribute")
+	assert.Equal(t, `{"responseSize":1}`, got.AsString())
+
+	_, ok = eventAttr(t, events[1], "response")
+	assert.True(t, ok, "missing response attribute")
+}
+
+func TestPayloadHash(t *testing.T) {
+	req := &pb.SimpleRequest{ResponseSize: 1, FillUsername: true}
+	span := recordRPC(t, otelgrpc.NewServerHandler, req, proto.Clone(req), otelgrpc.WithPayloadHash(true))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	reqHash, ok := eventAttr(t, events[0], otelgrpc.RPCMessageHashKey)
+	require.True(t, ok, "missing request hash")
+	assert.Len(t, reqHash.AsString(), 64)
+	respHash, ok := eventAttr(t, events[1], otelgrpc.RPCMessageHashKey)
+	require.True(t, ok, "missing response hash")
+	assert.Equal(t, reqHash, respHash)
+
+	msgType, ok := eventAttr(t, events[0], otelgrpc.RPCMessageProtoTypeKey)
+	require.True(t, ok, "missing message type")
+	assert.Equal(t, "grpc.testing.SimpleRequest", msgType.AsString())
+
+	_, ok = eventAttr(t, events[0], "request")
+	assert.False(t, ok, "unexpected request attribute")
+}
+
+func TestPayloadNotRecording(t *testing.T) {
+	var calls int
+	m := otelgrpc.PayloadMarshalerFunc(func(context.Context, string, any) (attribute.Value, error) {
+		calls++
+		return attribute.StringValue(""), nil
+	})
+
+	tests := []struct {
+		name    string
+		sampler sdktrace.Sampler
+		want    int
+	}{
+		{name: "Sampled", sampler: sdktrace.AlwaysSample(), want: 2},
+		{name: "Unsampled", sampler: sdktrace.NeverSample(), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(tt.sampler))
+			h := otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithPayloadMarshaler(m))
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{
+				FullMethodName: "/test.Service/Method",
+			})
+			h.HandleRPC(ctx, &stats.OutPayload{Payload: wrapperspb.String("req")})
+			h.HandleRPC(ctx, &stats.InPayload{Payload: wrapperspb.String("resp")})
+			h.HandleRPC(ctx, &stats.End{})
+
+			assert.Equal(t, tt.want, calls, "payload serializations")
+		})
+	}
+}
+
+func BenchmarkHandleRPCPayload(b *testing.B) {
+	payload := &pb.SimpleRequest{
+		Payload: &pb.Payload{Body: make([]byte, 1<<20)},
+	}
+
+	for name, sampler := range map[string]sdktrace.Sampler{
+		"Sampled":   sdktrace.AlwaysSample(),
+		"Unsampled": sdktrace.NeverSample(),
+	} {
+		b.Run(name, func(b *testing.B) {
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+			h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))
+			in := &stats.InPayload{Payload: payload, Length: proto.Size(payload)}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{
+					FullMethodName: "/test.Service/Method",
+				})
+				h.HandleRPC(ctx, in)
+				h.HandleRPC(ctx, &stats.End{})
+			}
+		})
+	}
+}
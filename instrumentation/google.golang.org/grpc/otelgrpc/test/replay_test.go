@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpc_codes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// replayRPC runs a unary RPC through a stats handler created by h that
+// writes replay records with opts and returns the records written.
+func replayRPC(t *testing.T, h func(...otelgrpc.Option) stats.Handler, err error, failedOnly bool, opts ...otelgrpc.Option) []*otelgrpc.ReplayRecord {
+	t.Helper()
+
+	var records []*otelgrpc.ReplayRecord
+	w := otelgrpc.ReplayWriterFunc(func(_ context.Context, r *otelgrpc.ReplayRecord) error {
+		records = append(records, r)
+		return nil
+	})
+	tp := sdktrace.NewTracerProvider()
+	handler := h(append([]otelgrpc.Option{otelgrpc.WithTracerProvider(tp), otelgrpc.WithReplayWriter(w, failedOnly)}, opts...)...)
+
+	isServer := reflect.ValueOf(h).Pointer() == reflect.ValueOf(otelgrpc.NewServerHandler).Pointer()
+	ctx := handler.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+	begin := time.Unix(1700000000, 0)
+	handler.HandleRPC(ctx, &stats.Begin{BeginTime: begin})
+	md := metadata.Pairs("tenant", "a", "authorization", "Bearer token")
+	if isServer {
+		handler.HandleRPC(ctx, &stats.InHeader{Header: md})
+		handler.HandleRPC(ctx, &stats.InPayload{Payload: wrapperspb.String("req")})
+		handler.HandleRPC(ctx, &stats.OutPayload{Payload: wrapperspb.String("resp")})
+	} else {
+		handler.HandleRPC(ctx, &stats.OutHeader{Client: true, Header: md})
+		handler.HandleRPC(ctx, &stats.OutPayload{Client: true, Payload: wrapperspb.String("req")})
+		handler.HandleRPC(ctx, &stats.InPayload{Client: true, Payload: wrapperspb.String("resp")})
+	}
+	handler.HandleRPC(ctx, &stats.End{BeginTime: begin, EndTime: begin.Add(time.Second), Error: err})
+	return records
+}
+
+func TestReplayWriter(t *testing.T) {
+	for name, h := range map[string]func(...otelgrpc.Option) stats.Handler{
+		"Server": otelgrpc.NewServerHandler,
+		"Client": otelgrpc.NewClientHandler,
+	} {
+		t.Run(name, func(t *testing.T) {
+			records := replayRPC(t, h, status.Error(grpc_codes.Internal, "failed"), false)
+			require.Len(t, records, 1)
+			r := records[0]
+
+			assert.Equal(t, "/test.Service/Method", r.FullMethod)
+			assert.Equal(t, []string{"a"}, r.Metadata.Get("tenant"))
+			assert.Empty(t, r.Metadata.Get("authorization"), "credentials recorded")
+			assert.Equal(t, grpc_codes.Internal, r.Code)
+			assert.Equal(t, "failed", r.Message)
+			assert.Equal(t, time.Second, r.EndTime.Sub(r.StartTime))
+			_, err := trace.TraceIDFromHex(r.TraceID)
+			assert.NoError(t, err, "invalid trace ID")
+
+			require.Len(t, r.Requests, 1)
+			var req wrapperspb.StringValue
+			require.NoError(t, proto.Unmarshal(r.Requests[0], &req))
+			assert.Equal(t, "req", req.GetValue())
+			require.Len(t, r.Responses, 1)
+			var resp wrapperspb.StringValue
+			require.NoError(t, proto.Unmarshal(r.Responses[0], &resp))
+			assert.Equal(t, "resp", resp.GetValue())
+		})
+	}
+}
+
+func TestReplayCredentialMetadata(t *testing.T) {
+	records := replayRPC(t, otelgrpc.NewServerHandler, nil, false, otelgrpc.WithReplayCredentialMetadata(true))
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{"a"}, records[0].Metadata.Get("tenant"))
+	assert.Equal(t, []string{"Bearer token"}, records[0].Metadata.Get("authorization"))
+}
+
+func TestReplayWriterFailedOnly(t *testing.T) {
+	assert.Empty(t, replayRPC(t, otelgrpc.NewServerHandler, nil, true))
+	assert.Len(t, replayRPC(t, otelgrpc.NewServerHandler, nil, false), 1)
+	assert.Len(t, replayRPC(t, otelgrpc.NewServerHandler, status.Error(grpc_codes.Unavailable, "down"), true), 1)
+}
+
+func TestReplayWriterPayloadCaptureDisabled(t *testing.T) {
+	assert.Empty(t, replayRPC(t, otelgrpc.NewServerHandler, nil, false, otelgrpc.WithPayloadCapture(false)))
+}
+
+func TestJSONReplayWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := otelgrpc.NewJSONReplayWriter(&buf)
+	r := &otelgrpc.ReplayRecord{
+		FullMethod: "/test.Service/Method",
+		Requests:   [][]byte{{0x0a, 0x01, 'a'}},
+		Code:       grpc_codes.NotFound,
+	}
+	require.NoError(t, w.WriteReplay(context.Background(), r))
+	require.NoError(t, w.WriteReplay(context.Background(), r))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	var got otelgrpc.ReplayRecord
+	require.NoError(t, json.Unmarshal(lines[0], &got))
+	assert.Equal(t, r.FullMethod, got.FullMethod)
+	assert.Equal(t, r.Requests, got.Requests)
+	assert.Equal(t, r.Code, got.Code)
+}
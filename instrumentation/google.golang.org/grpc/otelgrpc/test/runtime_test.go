@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRuntimeControl(t *testing.T) {
+	r := otelgrpc.NewRuntimeControl(otelgrpc.RuntimeSettings{})
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPayloadCompactJSON(true),
+		otelgrpc.WithPayloadSizeLimit(4),
+		otelgrpc.WithRuntimeControl(r),
+	)
+
+	run := func(method string) {
+		ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: method})
+		h.HandleRPC(ctx, &stats.InPayload{Payload: wrapperspb.String(strings.Repeat("x", 10))})
+		h.HandleRPC(ctx, &stats.End{})
+	}
+
+	// The settings of r replace the options.
+	run("/test.Service/Method")
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	_, ok := eventAttr(t, spans[0].Events()[0], "request")
+	assert.False(t, ok, "payload captured while disabled")
+
+	r.Update(otelgrpc.RuntimeSettings{PayloadCapture: true, PayloadSizeLimit: 8})
+	run("/test.Service/Method")
+	spans = sr.Ended()
+	require.Len(t, spans, 2)
+	got, ok := eventAttr(t, spans[1].Events()[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Equal(t, `"xxxxxxx`, got.AsString())
+
+	r.Update(otelgrpc.RuntimeSettings{PayloadCapture: true, Filter: func(info *stats.RPCTagInfo) bool {
+		return info.FullMethodName != "/grpc.health.v1.Health/Check"
+	}})
+	run("/grpc.health.v1.Health/Check")
+	run("/test.Service/Method")
+	spans = sr.Ended()
+	require.Len(t, spans, 3)
+	got, ok = eventAttr(t, spans[2].Events()[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Equal(t, `"xxxxxxxxxx"`, got.AsString())
+
+	assert.Equal(t, 0, r.Settings().PayloadSizeLimit)
+}
@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "google.golang.org/grpc/interop/grpc_testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+)
+
+func TestPayloadScrubbing(t *testing.T) {
+	req := &pb.SimpleRequest{FillUsername: true, ResponseSize: 7}
+	span := recordRPC(t, otelgrpc.NewServerHandler, req, &pb.SimpleResponse{Username: "alice"},
+		otelgrpc.WithPayloadCompactJSON(true),
+		otelgrpc.WithPayloadScrubJSONPaths("$.username"),
+		otelgrpc.WithPayloadScrubRegexps(regexp.MustCompile(`"responseSize":(\d+)`)),
+	)
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok)
+	// protojson randomizes whitespace, only check the scrubbed value.
+	assert.Contains(t, got.AsString(), `"responseSize":REDACTED`)
+	assert.NotContains(t, got.AsString(), "7")
+	got, ok = eventAttr(t, events[1], "response")
+	require.True(t, ok)
+	assert.Equal(t, `{"username":"REDACTED"}`, got.AsString())
+}
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestServerHandlerSemconvOptIn(t *testing.T) {
+	oldAttrs := []attribute.KeyValue{
+		semconv.MessageTypeReceived,
+		semconv.MessageIDKey.Int(1),
+		semconv.NetSockPeerAddr("10.0.0.1"),
+		semconv.NetSockPeerPort(52000),
+	}
+	newAttrs := []attribute.KeyValue{
+		semconvNew.RPCMessageTypeReceived,
+		semconvNew.RPCMessageIDKey.Int(1),
+		semconvNew.NetworkPeerAddress("10.0.0.1"),
+		semconvNew.NetworkPeerPort(52000),
+	}
+
+	tests := []struct {
+		env       string
+		eventName string
+		want      []attribute.KeyValue
+		notWant   []attribute.KeyValue
+	}{
+		{env: "", eventName: "message", want: oldAttrs, notWant: newAttrs},
+		{env: "rpc", eventName: "rpc.message", want: newAttrs, notWant: oldAttrs},
+		{env: "rpc/dup", eventName: "message", want: append(oldAttrs, newAttrs...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("OTEL_SEMCONV_STABILITY_OPT_IN", tt.env)
+
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp))
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 52000},
+			})
+			ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.InPayload{Length: 10})
+			h.HandleRPC(ctx, &stats.OutHeader{})
+			h.HandleRPC(ctx, &stats.End{})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			events := spans[0].Events()
+			require.Len(t, events, 1)
+			assert.Equal(t, tt.eventName, events[0].Name)
+
+			got := append(spans[0].Attributes(), events[0].Attributes...)
+			for _, kv := range tt.want {
+				assert.Contains(t, got, kv)
+			}
+			for _, kv := range tt.notWant {
+				assert.NotContains(t, got, kv)
+			}
+		})
+	}
+}
+
+func TestServerHandlerSemconvOptInMetrics(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    map[string]string
+		notWant []string
+	}{
+		{
+			env:     "",
+			want:    map[string]string{"rpc.server.duration": "ms"},
+			notWant: []string{"rpc.server.call.duration"},
+		},
+		{
+			env:     "rpc",
+			want:    map[string]string{"rpc.server.call.duration": "s"},
+			notWant: []string{"rpc.server.duration"},
+		},
+		{
+			env:  "rpc/dup",
+			want: map[string]string{"rpc.server.duration": "ms", "rpc.server.call.duration": "s"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv("OTEL_SEMCONV_STABILITY_OPT_IN", tt.env)
+
+			reader := sdkmetric.NewManualReader()
+			mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			h := otelgrpc.NewServerHandler(otelgrpc.WithMeterProvider(mp))
+
+			begin := time.Now()
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.Begin{BeginTime: begin})
+			h.HandleRPC(ctx, &stats.End{BeginTime: begin, EndTime: begin.Add(1500 * time.Millisecond)})
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+			got := make(map[string]metricdata.Metrics)
+			for _, m := range rm.ScopeMetrics[0].Metrics {
+				got[m.Name] = m
+			}
+
+			for name, unit := range tt.want {
+				m, ok := got[name]
+				require.True(t, ok, "missing %s", name)
+				assert.Equal(t, unit, m.Unit, name)
+				hist, ok := m.Data.(metricdata.Histogram[float64])
+				require.True(t, ok, name)
+				require.Len(t, hist.DataPoints, 1, name)
+				want := 1.5
+				if unit == "ms" {
+					want = 1500
+				}
+				assert.InDelta(t, want, hist.DataPoints[0].Sum, 1e-9, name)
+			}
+			for _, name := range tt.notWant {
+				assert.NotContains(t, got, name)
+			}
+		})
+	}
+}
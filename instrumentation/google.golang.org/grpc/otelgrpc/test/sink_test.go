@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+)
+
+func TestPayloadSink(t *testing.T) {
+	var stored []string
+	sink := otelgrpc.PayloadSinkFunc(func(_ context.Context, fullMethod, hash string, data []byte) (string, error) {
+		assert.Equal(t, "/test.Service/Method", fullMethod)
+		sum := sha256.Sum256(data)
+		assert.Equal(t, hex.EncodeToString(sum[:]), hash)
+		stored = append(stored, string(data))
+		return "mem://" + hash, nil
+	})
+
+	big := strings.Repeat("x", 64)
+	span := recordRPC(t, otelgrpc.NewServerHandler, wrapperspb.String(big), wrapperspb.String("ok"),
+		otelgrpc.WithPayloadCompactJSON(true), otelgrpc.WithPayloadSizeLimit(16), otelgrpc.WithPayloadSink(sink))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	require.Len(t, stored, 1)
+	assert.Equal(t, `"`+big+`"`, stored[0], "payload must be stored in full")
+
+	_, ok := eventAttr(t, events[0], "request")
+	assert.False(t, ok, "unexpected request attribute")
+	uri, ok := eventAttr(t, events[0], otelgrpc.RPCMessagePayloadURIKey)
+	require.True(t, ok, "missing payload URI attribute")
+	hash, ok := eventAttr(t, events[0], otelgrpc.RPCMessageHashKey)
+	require.True(t, ok, "missing hash attribute")
+	assert.Equal(t, "mem://"+hash.AsString(), uri.AsString())
+	truncated, ok := eventAttr(t, events[0], otelgrpc.RPCMessageTruncatedKey)
+	require.True(t, ok, "missing truncated attribute")
+	assert.True(t, truncated.AsBool())
+
+	got, ok := eventAttr(t, events[1], "response")
+	require.True(t, ok, "missing response attribute")
+	assert.Equal(t, `"ok"`, got.AsString())
+	_, ok = eventAttr(t, events[1], otelgrpc.RPCMessagePayloadURIKey)
+	assert.False(t, ok, "unexpected payload URI attribute")
+}
+
+func TestPayloadSinkError(t *testing.T) {
+	sink := otelgrpc.PayloadSinkFunc(func(context.Context, string, string, []byte) (string, error) {
+		return "", assert.AnError
+	})
+
+	span := recordRPC(t, otelgrpc.NewServerHandler, wrapperspb.String(strings.Repeat("x", 64)), wrapperspb.String("ok"),
+		otelgrpc.WithPayloadCompactJSON(true), otelgrpc.WithPayloadSizeLimit(16), otelgrpc.WithPayloadSink(sink))
+	events := span.Events()
+	require.Len(t, events, 2)
+
+	got, ok := eventAttr(t, events[0], "request")
+	require.True(t, ok, "missing request attribute")
+	assert.Len(t, got.AsString(), 16)
+	_, ok = eventAttr(t, events[0], otelgrpc.RPCMessagePayloadURIKey)
+	assert.False(t, ok, "unexpected payload URI attribute")
+}
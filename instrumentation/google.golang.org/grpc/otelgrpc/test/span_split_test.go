@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStreamSpanSplittingMessages(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithStreamSpanSplitting(0, 2))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "restore")
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Restore"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true, IsServerStream: true})
+	for i := 0; i < 5; i++ {
+		h.HandleRPC(ctx, &stats.InPayload{Client: true, Length: 1})
+	}
+	h.HandleRPC(ctx, &stats.End{Client: true})
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+	segments := spans[:3]
+	for i, s := range segments {
+		assert.Equal(t, "cedana.daemon.Daemon/Restore", s.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), s.Parent().SpanID())
+		assert.Contains(t, s.Attributes(), otelgrpc.RPCSystemGRPC)
+		assert.Contains(t, s.Attributes(), otelgrpc.RPCGRPCStreamSegmentKey.Int(i))
+		if i == 0 {
+			assert.Empty(t, s.Links())
+		} else {
+			require.Len(t, s.Links(), 1)
+			assert.Equal(t, segments[i-1].SpanContext(), s.Links()[0].SpanContext)
+		}
+	}
+	assert.Len(t, segments[0].Events(), 2)
+	assert.Len(t, segments[1].Events(), 2)
+	assert.Len(t, segments[2].Events(), 1)
+	assert.Contains(t, segments[2].Events()[0].Attributes, otelgrpc.RPCMessageIDKey.Int(5))
+}
+
+func TestStreamSpanSplittingDuration(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithStreamSpanSplitting(10*time.Millisecond, 0))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Attach"})
+	h.HandleRPC(ctx, &stats.Begin{IsClientStream: true})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1})
+	require.Empty(t, sr.Ended())
+
+	time.Sleep(20 * time.Millisecond)
+	h.HandleRPC(ctx, &stats.OutPayload{Length: 1})
+	require.Len(t, sr.Ended(), 1)
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Len(t, spans[0].Events(), 2)
+	assert.Len(t, spans[1].Events(), 1)
+	assert.Equal(t, spans[0].EndTime(), spans[1].StartTime())
+}
+
+func TestStreamSpanSplittingUnary(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithStreamSpanSplitting(0, 1))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1})
+	h.HandleRPC(ctx, &stats.OutPayload{Length: 1})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.NotContains(t, spans[0].Attributes(), otelgrpc.RPCGRPCStreamSegmentKey.Int(0))
+}
@@ -0,0 +1,938 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestServerHandlerConnMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{
+		LocalAddr:  &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080},
+		RemoteAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 52000},
+	})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+
+	attrs := attribute.NewSet(
+		semconv.NetTransportTCP,
+		semconv.NetSockHostAddr("127.0.0.1"),
+		semconv.NetSockHostPort(8080),
+	)
+	active := func(v int64) metricdata.Metrics {
+		return metricdata.Metrics{
+			Name:        "grpc.server.connections.active",
+			Description: "Measures the number of open connections.",
+			Unit:        "{connection}",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attrs, Value: v}},
+			},
+		}
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+	metricdatatest.AssertEqual(t, active(1), rm.ScopeMetrics[0].Metrics[0], metricdatatest.IgnoreTimestamp())
+
+	rctx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(rctx, &stats.InPayload{Length: 10, WireLength: 15})
+	h.HandleRPC(rctx, &stats.OutPayload{Length: 20, WireLength: 25})
+	h.HandleRPC(rctx, &stats.OutPayload{Length: 20, WireLength: 25})
+	h.HandleConn(ctx, &stats.ConnEnd{})
+
+	rm = metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	got := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+	metricdatatest.AssertEqual(t, active(0), got["grpc.server.connections.active"], metricdatatest.IgnoreTimestamp())
+
+	for name, want := range map[string]int64{
+		"grpc.server.connection.bytes_in":  15,
+		"grpc.server.connection.bytes_out": 50,
+	} {
+		m, ok := got[name]
+		require.True(t, ok, name)
+		assert.Equal(t, "By", m.Unit, name)
+		dp := m.Data.(metricdata.Histogram[int64]).DataPoints
+		require.Len(t, dp, 1, name)
+		assert.Equal(t, attrs, dp[0].Attributes, name)
+		assert.Equal(t, want, dp[0].Sum, name)
+	}
+
+	duration, ok := got["grpc.server.connection.duration"]
+	require.True(t, ok)
+	assert.Equal(t, "s", duration.Unit)
+	assert.Equal(t, uint64(1), duration.Data.(metricdata.Histogram[float64]).DataPoints[0].Count)
+}
+
+func TestClientHandlerAttemptMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewClientHandler(otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	// The first attempt fails and is retried.
+	attempt := func(err error) {
+		ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+		begin := time.Now()
+		h.HandleRPC(ctx, &stats.Begin{Client: true, BeginTime: begin})
+		h.HandleRPC(ctx, &stats.OutPayload{Client: true, Length: 20, CompressedLength: 12})
+		if err == nil {
+			h.HandleRPC(ctx, &stats.InPayload{Client: true, Length: 40, CompressedLength: 30})
+		}
+		h.HandleRPC(ctx, &stats.End{Client: true, BeginTime: begin, EndTime: begin.Add(time.Second), Error: err})
+	}
+	attempt(status.Error(codes.Unavailable, "connection reset"))
+	attempt(nil)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	got := make(map[string]metricdata.Metrics)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		got[m.Name] = m
+	}
+
+	method := otelgrpc.GRPCMethodKey.String("cedana.daemon.Daemon/Dump")
+	metricdatatest.AssertEqual(t, metricdata.Metrics{
+		Name:        "grpc.client.attempt.started",
+		Description: "Number of client call attempts started.",
+		Unit:        "{attempt}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(method), Value: 2},
+			},
+		},
+	}, got["grpc.client.attempt.started"], metricdatatest.IgnoreTimestamp())
+
+	unavailable := attribute.NewSet(method, otelgrpc.GRPCStatusKey.String("UNAVAILABLE"))
+	ok := attribute.NewSet(method, otelgrpc.GRPCStatusKey.String("OK"))
+
+	duration := got["grpc.client.attempt.duration"].Data.(metricdata.Histogram[float64])
+	require.Len(t, duration.DataPoints, 2)
+	for _, dp := range duration.DataPoints {
+		assert.Contains(t, []attribute.Set{unavailable, ok}, dp.Attributes)
+		assert.Equal(t, uint64(1), dp.Count)
+		assert.Equal(t, 1.0, dp.Sum)
+	}
+
+	for name, want := range map[string]map[attribute.Set]int64{
+		"grpc.client.attempt.sent_total_compressed_message_size": {unavailable: 12, ok: 12},
+		"grpc.client.attempt.rcvd_total_compressed_message_size": {unavailable: 0, ok: 30},
+	} {
+		dps := got[name].Data.(metricdata.Histogram[int64]).DataPoints
+		require.Len(t, dps, 2, name)
+		for _, dp := range dps {
+			assert.Equal(t, want[dp.Attributes], dp.Sum, name)
+		}
+	}
+}
+
+func TestServerHandlerNoAttemptMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		assert.NotContains(t, m.Name, "grpc.client.attempt")
+	}
+}
+
+func TestStaticAttributes(t *testing.T) {
+	env := attribute.String("deployment.environment", "staging")
+	cluster := attribute.String("cluster.name", "cedana-1")
+
+	for _, tt := range []struct {
+		name    string
+		handler func(...otelgrpc.Option) stats.Handler
+	}{
+		{name: "Server", handler: otelgrpc.NewServerHandler},
+		{name: "Client", handler: otelgrpc.NewClientHandler},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			reader := sdkmetric.NewManualReader()
+			h := tt.handler(
+				otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))),
+				otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+				otelgrpc.WithConnectionSpans(true),
+				otelgrpc.WithStaticAttributes(env),
+				otelgrpc.WithStaticAttributes(cluster),
+			)
+
+			ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+			h.HandleConn(ctx, &stats.ConnBegin{})
+			rctx := h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(rctx, &stats.Begin{})
+			h.HandleRPC(rctx, &stats.End{})
+			h.HandleConn(ctx, &stats.ConnEnd{})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 2)
+			for _, span := range spans {
+				assert.Contains(t, span.Attributes(), env, span.Name())
+				assert.Contains(t, span.Attributes(), cluster, span.Name())
+			}
+
+			var rm metricdata.ResourceMetrics
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+			require.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+			for _, m := range rm.ScopeMetrics[0].Metrics {
+				var sets []attribute.Set
+				switch data := m.Data.(type) {
+				case metricdata.Sum[int64]:
+					for _, dp := range data.DataPoints {
+						sets = append(sets, dp.Attributes)
+					}
+				case metricdata.Histogram[int64]:
+					for _, dp := range data.DataPoints {
+						sets = append(sets, dp.Attributes)
+					}
+				case metricdata.Histogram[float64]:
+					for _, dp := range data.DataPoints {
+						sets = append(sets, dp.Attributes)
+					}
+				}
+				require.NotEmpty(t, sets, m.Name)
+				for _, set := range sets {
+					assert.True(t, set.HasValue(env.Key), m.Name)
+					assert.True(t, set.HasValue(cluster.Key), m.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestAttributeExtractor(t *testing.T) {
+	tenant := func(ctx context.Context, _ *stats.RPCTagInfo) []attribute.KeyValue {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if v := md.Get("x-tenant-id"); len(v) > 0 {
+			return []attribute.KeyValue{attribute.String("tenant.id", v[0])}
+		}
+		return nil
+	}
+	method := func(_ context.Context, info *stats.RPCTagInfo) []attribute.KeyValue {
+		return []attribute.KeyValue{attribute.String("full_method", info.FullMethodName)}
+	}
+
+	sr := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithAttributeExtractor(tenant),
+		otelgrpc.WithAttributeExtractor(method),
+		otelgrpc.WithAttributeExtractor(nil),
+	)
+
+	for _, id := range []string{"a", "b"} {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-tenant-id", id))
+		ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+		h.HandleRPC(ctx, &stats.End{})
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	for i, id := range []string{"a", "b"} {
+		assert.Contains(t, spans[i].Attributes(), attribute.String("tenant.id", id))
+		assert.Contains(t, spans[i].Attributes(), attribute.String("full_method", "/cedana.daemon.Daemon/Dump"))
+	}
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	var duration metricdata.Metrics
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "rpc.server.duration" {
+			duration = m
+		}
+	}
+	dps := duration.Data.(metricdata.Histogram[float64]).DataPoints
+	require.Len(t, dps, 2)
+	var tenants []string
+	for _, dp := range dps {
+		v, ok := dp.Attributes.Value("tenant.id")
+		require.True(t, ok)
+		tenants = append(tenants, v.AsString())
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, tenants)
+}
+
+func TestBaggageAttributes(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithPropagators(propagation.Baggage{}),
+		otelgrpc.WithBaggageAttributes("tenant.id"),
+		otelgrpc.WithBaggageAttributes("job.id", "missing"),
+	)
+
+	md := metadata.Pairs("baggage", "tenant.id=acme,job.id=42,other=x")
+	ctx := h.TagRPC(metadata.NewIncomingContext(context.Background(), md), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := attribute.NewSet(spans[0].Attributes()...)
+	v, _ := attrs.Value("tenant.id")
+	assert.Equal(t, "acme", v.AsString())
+	v, _ = attrs.Value("job.id")
+	assert.Equal(t, "42", v.AsString())
+	assert.False(t, attrs.HasValue("other"))
+	assert.False(t, attrs.HasValue("missing"))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "rpc.server.duration" {
+			continue
+		}
+		dps := m.Data.(metricdata.Histogram[float64]).DataPoints
+		require.Len(t, dps, 1)
+		assert.True(t, dps[0].Attributes.HasValue("tenant.id"))
+		assert.True(t, dps[0].Attributes.HasValue("job.id"))
+	}
+}
+
+func TestWithoutTraces(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithConnectionSpans(true),
+		otelgrpc.WithoutTraces(),
+	)
+
+	ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+	h.HandleConn(ctx, &stats.ConnBegin{})
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1})
+	h.HandleRPC(ctx, &stats.End{})
+	h.HandleConn(ctx, &stats.ConnEnd{})
+	assert.Empty(t, sr.Started())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	assert.NotEmpty(t, rm.ScopeMetrics[0].Metrics)
+}
+
+func TestWithoutMetrics(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewClientHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithoutMetrics(),
+	)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true})
+	h.HandleRPC(ctx, &stats.OutPayload{Client: true, Length: 1})
+	h.HandleRPC(ctx, &stats.End{Client: true})
+	assert.Len(t, sr.Ended(), 1)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	assert.Empty(t, rm.ScopeMetrics)
+}
+
+func TestSpanNameFormatter(t *testing.T) {
+	collapse := func(fullMethod string) string {
+		return "daemon" + strings.Replace(fullMethod, ".v1.", ".", 1)
+	}
+
+	tests := []struct {
+		name string
+		opts []otelgrpc.Option
+		want string
+	}{
+		{name: "Default", want: "cedana.daemon.v1.Daemon/Dump"},
+		{name: "Formatter", opts: []otelgrpc.Option{otelgrpc.WithSpanNameFormatter(collapse)}, want: "daemon/cedana.daemon.Daemon/Dump"},
+		{name: "Nil", opts: []otelgrpc.Option{otelgrpc.WithSpanNameFormatter(nil)}, want: "cedana.daemon.v1.Daemon/Dump"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, handler := range []func(...otelgrpc.Option) stats.Handler{otelgrpc.NewServerHandler, otelgrpc.NewClientHandler} {
+				sr := tracetest.NewSpanRecorder()
+				opts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))}, tt.opts...)
+				h := handler(opts...)
+
+				ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.v1.Daemon/Dump"})
+				h.HandleRPC(ctx, &stats.End{})
+
+				spans := sr.Ended()
+				require.Len(t, spans, 1)
+				assert.Equal(t, tt.want, spans[0].Name())
+			}
+		})
+	}
+}
+
+func TestTraceMetricFilters(t *testing.T) {
+	notHealth := func(info *stats.RPCTagInfo) bool {
+		return info.FullMethodName != "/grpc.health.v1.Health/Check"
+	}
+
+	tests := []struct {
+		name        string
+		opts        []otelgrpc.Option
+		wantSpans   int
+		wantMetrics bool
+	}{
+		{name: "NoFilter", wantSpans: 1, wantMetrics: true},
+		{name: "Filter", opts: []otelgrpc.Option{otelgrpc.WithFilter(notHealth)}, wantSpans: 0, wantMetrics: false},
+		{name: "TraceFilter", opts: []otelgrpc.Option{otelgrpc.WithTraceFilter(notHealth)}, wantSpans: 0, wantMetrics: true},
+		{name: "MetricFilter", opts: []otelgrpc.Option{otelgrpc.WithMetricFilter(notHealth)}, wantSpans: 1, wantMetrics: false},
+		{
+			name:        "ContextFilter",
+			opts:        []otelgrpc.Option{otelgrpc.WithContextFilter(func(_ context.Context, info *stats.RPCTagInfo) bool { return notHealth(info) })},
+			wantSpans:   0,
+			wantMetrics: false,
+		},
+		{
+			name:        "FilterOverrides",
+			opts:        []otelgrpc.Option{otelgrpc.WithFilter(notHealth), otelgrpc.WithTraceFilter(func(*stats.RPCTagInfo) bool { return true })},
+			wantSpans:   0,
+			wantMetrics: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, handler := range []func(...otelgrpc.Option) stats.Handler{otelgrpc.NewServerHandler, otelgrpc.NewClientHandler} {
+				sr := tracetest.NewSpanRecorder()
+				tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+				reader := sdkmetric.NewManualReader()
+				opts := append([]otelgrpc.Option{
+					otelgrpc.WithTracerProvider(tp),
+					otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+				}, tt.opts...)
+				h := handler(opts...)
+
+				// The span of the caller must not be modified by filtered RPCs.
+				ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+				ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Check"})
+				h.HandleRPC(ctx, &stats.Begin{})
+				h.HandleRPC(ctx, &stats.InPayload{Length: 10})
+				h.HandleRPC(ctx, &stats.End{})
+				assert.Len(t, sr.Ended(), tt.wantSpans)
+				parent.End()
+				assert.Empty(t, sr.Ended()[len(sr.Ended())-1].Events())
+
+				var rm metricdata.ResourceMetrics
+				require.NoError(t, reader.Collect(context.Background(), &rm))
+				assert.Equal(t, tt.wantMetrics, len(rm.ScopeMetrics) > 0)
+			}
+		})
+	}
+}
+
+func durationExemplars(t *testing.T, reader sdkmetric.Reader, name string) []metricdata.Exemplar[float64] {
+	t.Helper()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == name {
+			dps := m.Data.(metricdata.Histogram[float64]).DataPoints
+			require.Len(t, dps, 1)
+			return dps[0].Exemplars
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return nil
+}
+
+func TestDurationExemplars(t *testing.T) {
+	t.Setenv("OTEL_GO_X_EXEMPLAR", "true")
+
+	for _, tt := range []struct {
+		handler func(...otelgrpc.Option) stats.Handler
+		metric  string
+	}{
+		{handler: otelgrpc.NewServerHandler, metric: "rpc.server.duration"},
+		{handler: otelgrpc.NewClientHandler, metric: "rpc.client.duration"},
+	} {
+		t.Run(tt.metric, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			reader := sdkmetric.NewManualReader()
+			h := tt.handler(
+				otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))),
+				otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+			)
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.End{})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			exemplars := durationExemplars(t, reader, tt.metric)
+			require.Len(t, exemplars, 1)
+			traceID := spans[0].SpanContext().TraceID()
+			spanID := spans[0].SpanContext().SpanID()
+			assert.Equal(t, traceID[:], exemplars[0].TraceID)
+			assert.Equal(t, spanID[:], exemplars[0].SpanID)
+		})
+	}
+}
+
+func TestExemplarsForFilteredSpans(t *testing.T) {
+	t.Setenv("OTEL_GO_X_EXEMPLAR", "true")
+
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x02},
+	})
+	noSpans := otelgrpc.WithTraceFilter(func(*stats.RPCTagInfo) bool { return false })
+
+	for _, tt := range []struct {
+		name    string
+		opts    []otelgrpc.Option
+		wantLen int
+	}{
+		{name: "Default", opts: []otelgrpc.Option{noSpans}, wantLen: 0},
+		{name: "Forced", opts: []otelgrpc.Option{noSpans, otelgrpc.WithExemplarsForFilteredSpans(true)}, wantLen: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			opts := append([]otelgrpc.Option{otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))}, tt.opts...)
+			h := otelgrpc.NewClientHandler(opts...)
+
+			ctx := trace.ContextWithSpanContext(context.Background(), parent)
+			ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/grpc.health.v1.Health/Check"})
+			h.HandleRPC(ctx, &stats.End{})
+
+			exemplars := durationExemplars(t, reader, "rpc.client.duration")
+			require.Len(t, exemplars, tt.wantLen)
+			if tt.wantLen > 0 {
+				traceID := parent.TraceID()
+				assert.Equal(t, traceID[:], exemplars[0].TraceID)
+			}
+		})
+	}
+}
+
+func TestPeerService(t *testing.T) {
+	daemon := &net.UnixAddr{Name: "/run/cedana.sock", Net: "unix"}
+	other := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 8080}
+	byAddr := func(remote net.Addr) string {
+		if remote.String() == daemon.String() {
+			return "cedana-daemon"
+		}
+		return ""
+	}
+
+	tests := []struct {
+		name    string
+		handler func(...otelgrpc.Option) stats.Handler
+		opts    []otelgrpc.Option
+		remote  net.Addr
+		want    string
+	}{
+		{name: "None", handler: otelgrpc.NewClientHandler, remote: daemon},
+		{name: "Static", handler: otelgrpc.NewClientHandler, opts: []otelgrpc.Option{otelgrpc.WithPeerService("cedana")}, remote: daemon, want: "cedana"},
+		{name: "Func", handler: otelgrpc.NewClientHandler, opts: []otelgrpc.Option{otelgrpc.WithPeerServiceFunc(byAddr)}, remote: daemon, want: "cedana-daemon"},
+		{
+			name:    "FuncOverridesStatic",
+			handler: otelgrpc.NewClientHandler,
+			opts:    []otelgrpc.Option{otelgrpc.WithPeerService("cedana"), otelgrpc.WithPeerServiceFunc(byAddr)},
+			remote:  daemon,
+			want:    "cedana-daemon",
+		},
+		{
+			name:    "FuncEmpty",
+			handler: otelgrpc.NewClientHandler,
+			opts:    []otelgrpc.Option{otelgrpc.WithPeerService("cedana"), otelgrpc.WithPeerServiceFunc(byAddr)},
+			remote:  other,
+			want:    "cedana",
+		},
+		{name: "Server", handler: otelgrpc.NewServerHandler, opts: []otelgrpc.Option{otelgrpc.WithPeerService("cedana")}, remote: daemon},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			opts := append([]otelgrpc.Option{otelgrpc.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))}, tt.opts...)
+			h := tt.handler(opts...)
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.OutHeader{Client: true, RemoteAddr: tt.remote})
+			h.HandleRPC(ctx, &stats.End{})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			set := attribute.NewSet(spans[0].Attributes()...)
+			got, ok := set.Value(semconv.PeerServiceKey)
+			if tt.want == "" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got.AsString())
+		})
+	}
+}
+
+func TestSuppressedContext(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewClientHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithPropagators(propagation.TraceContext{}),
+	)
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "export")
+	ctx = otelgrpc.WithSuppressedContext(ctx)
+	ctx = h.TagRPC(ctx, &stats.RPCTagInfo{FullMethodName: "/opentelemetry.proto.collector.trace.v1.TraceService/Export"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.End{})
+	parent.End()
+
+	// Only the span of the caller is recorded and propagated.
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "export", spans[0].Name())
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.True(t, ok)
+	require.Len(t, md.Get("traceparent"), 1)
+	propagated := propagation.TraceContext{}.Extract(context.Background(), propagation.MapCarrier{"traceparent": md.Get("traceparent")[0]})
+	assert.Equal(t, parent.SpanContext().SpanID(), trace.SpanContextFromContext(propagated).SpanID())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	assert.NotEmpty(t, rm.ScopeMetrics)
+
+	// Contexts not derived from a suppressed context are not affected.
+	ctx = h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.End{})
+	assert.Len(t, sr.Ended(), 2)
+}
+
+func TestStatusCodeMapper(t *testing.T) {
+	mapper := func(code codes.Code, isServer bool) (otelcodes.Code, string) {
+		switch {
+		case code == codes.NotFound:
+			return otelcodes.Unset, ""
+		case code == codes.ResourceExhausted && !isServer:
+			return otelcodes.Error, ""
+		case code == codes.ResourceExhausted:
+			return otelcodes.Unset, ""
+		}
+		return otelcodes.Error, "mapped"
+	}
+
+	tests := []struct {
+		name    string
+		handler func(...otelgrpc.Option) stats.Handler
+		code    codes.Code
+		want    otelcodes.Code
+		desc    string
+	}{
+		{name: "ServerNotFound", handler: otelgrpc.NewServerHandler, code: codes.NotFound, want: otelcodes.Unset},
+		{name: "ClientNotFound", handler: otelgrpc.NewClientHandler, code: codes.NotFound, want: otelcodes.Unset},
+		{name: "ServerResourceExhausted", handler: otelgrpc.NewServerHandler, code: codes.ResourceExhausted, want: otelcodes.Unset},
+		{name: "ClientResourceExhausted", handler: otelgrpc.NewClientHandler, code: codes.ResourceExhausted, want: otelcodes.Error, desc: "quota"},
+		{name: "ServerInternal", handler: otelgrpc.NewServerHandler, code: codes.Internal, want: otelcodes.Error, desc: "mapped"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := tt.handler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithStatusCodeMapper(mapper))
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(ctx, &stats.End{Error: status.Error(tt.code, "quota")})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			assert.Equal(t, sdktrace.Status{Code: tt.want, Description: tt.desc}, spans[0].Status())
+		})
+	}
+}
+
+func TestConnectionSpans(t *testing.T) {
+	serverAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 8080}
+	clientAddr := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 52000}
+
+	tests := []struct {
+		name          string
+		handler       func(...otelgrpc.Option) stats.Handler
+		local, remote net.Addr
+		kind          trace.SpanKind
+		header        stats.RPCStats
+	}{
+		{
+			name:    "Server",
+			handler: otelgrpc.NewServerHandler,
+			local:   serverAddr,
+			remote:  clientAddr,
+			kind:    trace.SpanKindServer,
+			header:  &stats.OutHeader{},
+		},
+		{
+			name:    "Client",
+			handler: otelgrpc.NewClientHandler,
+			local:   clientAddr,
+			remote:  serverAddr,
+			kind:    trace.SpanKindClient,
+			header: &stats.OutHeader{
+				Client:     true,
+				LocalAddr:  clientAddr,
+				RemoteAddr: serverAddr,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := tt.handler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithConnectionSpans(true))
+
+			ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{
+				LocalAddr:  tt.local,
+				RemoteAddr: tt.remote,
+			})
+			h.HandleConn(ctx, &stats.ConnBegin{})
+
+			// Client RPC contexts are not derived from the connection context.
+			rpcCtx := ctx
+			if tt.kind == trace.SpanKindClient {
+				rpcCtx = context.Background()
+			}
+			rpcCtx = h.TagRPC(rpcCtx, &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+			h.HandleRPC(rpcCtx, tt.header)
+			h.HandleRPC(rpcCtx, &stats.End{})
+			require.Len(t, sr.Ended(), 1)
+			assert.Len(t, sr.Started(), 2)
+
+			h.HandleConn(ctx, &stats.ConnEnd{})
+			spans := sr.Ended()
+			require.Len(t, spans, 2)
+
+			rpc, conn := spans[0], spans[1]
+			assert.Equal(t, "grpc.connection", conn.Name())
+			assert.Equal(t, tt.kind, conn.SpanKind())
+			assert.False(t, conn.Parent().IsValid())
+			assert.Contains(t, conn.Attributes(), semconv.NetSockHostPort(tt.local.(*net.TCPAddr).Port))
+			assert.Contains(t, conn.Attributes(), semconv.NetSockPeerPort(tt.remote.(*net.TCPAddr).Port))
+
+			assert.NotEqual(t, conn.SpanContext().TraceID(), rpc.SpanContext().TraceID())
+			require.Len(t, rpc.Links(), 1)
+			assert.Equal(t, conn.SpanContext(), rpc.Links()[0].SpanContext)
+		})
+	}
+}
+
+func TestConnectionSpansDisabled(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	for _, h := range []stats.Handler{
+		otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp)),
+		otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp)),
+	} {
+		ctx := h.TagConn(context.Background(), &stats.ConnTagInfo{})
+		h.HandleConn(ctx, &stats.ConnBegin{})
+		h.HandleConn(ctx, &stats.ConnEnd{})
+	}
+	assert.Empty(t, sr.Started())
+}
+
+func TestMessageSpans(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithMessageSpans(true))
+
+	recv := time.Now().Add(-time.Second)
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Attach"})
+	h.HandleRPC(ctx, &stats.Begin{IsClientStream: true, IsServerStream: true})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 4, CompressedLength: 4, RecvTime: recv})
+	h.HandleRPC(ctx, &stats.OutPayload{Length: 8, CompressedLength: 8})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	received, sent, rpc := spans[0], spans[1], spans[2]
+	assert.Equal(t, "cedana.daemon.Daemon/Attach", rpc.Name())
+	assert.Empty(t, rpc.Events())
+
+	for _, s := range []sdktrace.ReadOnlySpan{received, sent} {
+		assert.Equal(t, "message", s.Name())
+		assert.Equal(t, trace.SpanKindInternal, s.SpanKind())
+		assert.Equal(t, rpc.SpanContext().SpanID(), s.Parent().SpanID())
+	}
+	assert.Equal(t, recv, received.StartTime())
+	assert.Contains(t, received.Attributes(), otelgrpc.RPCMessageTypeReceived)
+	assert.Contains(t, received.Attributes(), otelgrpc.RPCMessageIDKey.Int(1))
+	assert.Contains(t, received.Attributes(), otelgrpc.RPCMessageUncompressedSizeKey.Int(4))
+	assert.Contains(t, sent.Attributes(), otelgrpc.RPCMessageTypeSent)
+	assert.Contains(t, sent.Attributes(), otelgrpc.RPCMessageUncompressedSizeKey.Int(8))
+}
+
+func TestMessageSpansUnary(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithMessageSpans(true))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 4})
+	h.HandleRPC(ctx, &stats.OutPayload{Length: 8})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Len(t, spans[0].Events(), 2)
+}
+
+func TestClientHandlerLBAttrs(t *testing.T) {
+	backend := &net.TCPAddr{IP: net.IPv4(10, 0, 1, 7), Port: 50051}
+	tests := []struct {
+		name string
+		opts []otelgrpc.Option
+		want []attribute.KeyValue
+	}{
+		{
+			name: "Default",
+			want: []attribute.KeyValue{otelgrpc.RPCGRPCLBBackendAddressKey.String("10.0.1.7:50051")},
+		},
+		{
+			name: "LoadBalancer",
+			opts: []otelgrpc.Option{otelgrpc.WithLoadBalancer("round_robin")},
+			want: []attribute.KeyValue{
+				otelgrpc.RPCGRPCLBBackendAddressKey.String("10.0.1.7:50051"),
+				otelgrpc.RPCGRPCLBPolicyKey.String("round_robin"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := otelgrpc.NewClientHandler(append([]otelgrpc.Option{otelgrpc.WithTracerProvider(tp)}, tt.opts...)...)
+
+			ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+			h.HandleRPC(ctx, &stats.OutHeader{Client: true, RemoteAddr: backend})
+			h.HandleRPC(ctx, &stats.End{Client: true})
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			for _, kv := range tt.want {
+				assert.Contains(t, spans[0].Attributes(), kv)
+			}
+		})
+	}
+}
+
+func TestServerHandlerNoLBAttrs(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewServerHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithLoadBalancer("round_robin"))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+	h.HandleRPC(ctx, &stats.OutHeader{RemoteAddr: &net.TCPAddr{IP: net.IPv4(10, 0, 1, 7), Port: 50051}})
+	h.HandleRPC(ctx, &stats.End{})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, otelgrpc.RPCGRPCLBPolicyKey, kv.Key)
+		assert.NotEqual(t, otelgrpc.RPCGRPCLBBackendAddressKey, kv.Key)
+	}
+}
+
+func TestActiveRequests(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		handler func(...otelgrpc.Option) stats.Handler
+		metric  string
+	}{
+		{name: "Server", handler: otelgrpc.NewServerHandler, metric: "rpc.server.active_requests"},
+		{name: "Client", handler: otelgrpc.NewClientHandler, metric: "rpc.client.active_requests"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			h := tt.handler(otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+
+			active := func() int64 {
+				t.Helper()
+				var rm metricdata.ResourceMetrics
+				require.NoError(t, reader.Collect(context.Background(), &rm))
+				for _, sm := range rm.ScopeMetrics {
+					for _, m := range sm.Metrics {
+						if m.Name != tt.metric {
+							continue
+						}
+						sum := m.Data.(metricdata.Sum[int64])
+						assert.False(t, sum.IsMonotonic)
+						require.Len(t, sum.DataPoints, 1)
+						assert.Contains(t, sum.DataPoints[0].Attributes.ToSlice(), semconv.RPCMethod("Method"))
+						return sum.DataPoints[0].Value
+					}
+				}
+				t.Fatalf("missing %s metric", tt.metric)
+				return 0
+			}
+
+			ctx1 := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+			ctx2 := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/test.Service/Method"})
+			h.HandleRPC(ctx1, &stats.Begin{})
+			h.HandleRPC(ctx2, &stats.Begin{})
+			assert.Equal(t, int64(2), active())
+
+			h.HandleRPC(ctx1, &stats.End{Error: status.Error(codes.Internal, "failed")})
+			assert.Equal(t, int64(1), active())
+			h.HandleRPC(ctx2, &stats.End{})
+			assert.Equal(t, int64(0), active())
+		})
+	}
+}
@@ -63,6 +63,16 @@ func TestStatsHandlerHandleRPCServerErrors(t *testing.T) {
 }
 
 func assertStatsHandlerServerMetrics(t *testing.T, reader metric.Reader, serviceName, name string, code grpc_codes.Code) {
+	kvs := []attribute.KeyValue{
+		semconv.RPCMethod(name),
+		semconv.RPCService(serviceName),
+		otelgrpc.RPCSystemGRPC,
+		otelgrpc.GRPCStatusCodeKey.Int64(int64(code)),
+	}
+	if code != grpc_codes.OK {
+		kvs = append(kvs, serverEndReason(code))
+	}
+	attrs := attribute.NewSet(kvs...)
 	want := metricdata.ScopeMetrics{
 		Scope: wantInstrumentationScope,
 		Metrics: []metricdata.Metrics{
@@ -74,12 +84,7 @@ func assertStatsHandlerServerMetrics(t *testing.T, reader metric.Reader, service
 					Temporality: metricdata.CumulativeTemporality,
 					DataPoints: []metricdata.HistogramDataPoint[float64]{
 						{
-							Attributes: attribute.NewSet(
-								semconv.RPCMethod(name),
-								semconv.RPCService(serviceName),
-								otelgrpc.RPCSystemGRPC,
-								otelgrpc.GRPCStatusCodeKey.Int64(int64(code)),
-							),
+							Attributes: attrs,
 						},
 					},
 				},
@@ -92,12 +97,7 @@ func assertStatsHandlerServerMetrics(t *testing.T, reader metric.Reader, service
 					Temporality: metricdata.CumulativeTemporality,
 					DataPoints: []metricdata.HistogramDataPoint[int64]{
 						{
-							Attributes: attribute.NewSet(
-								semconv.RPCMethod(name),
-								semconv.RPCService(serviceName),
-								otelgrpc.RPCSystemGRPC,
-								otelgrpc.GRPCStatusCodeKey.Int64(int64(code)),
-							),
+							Attributes: attrs,
 						},
 					},
 				},
@@ -110,12 +110,7 @@ func assertStatsHandlerServerMetrics(t *testing.T, reader metric.Reader, service
 					Temporality: metricdata.CumulativeTemporality,
 					DataPoints: []metricdata.HistogramDataPoint[int64]{
 						{
-							Attributes: attribute.NewSet(
-								semconv.RPCMethod(name),
-								semconv.RPCService(serviceName),
-								otelgrpc.RPCSystemGRPC,
-								otelgrpc.GRPCStatusCodeKey.Int64(int64(code)),
-							),
+							Attributes: attrs,
 						},
 					},
 				},
@@ -128,3 +123,18 @@ func assertStatsHandlerServerMetrics(t *testing.T, reader metric.Reader, service
 	require.Len(t, rm.ScopeMetrics, 1)
 	metricdatatest.AssertEqual(t, want, rm.ScopeMetrics[0], metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreValue())
 }
+
+// serverEndReason returns the rpc.grpc.end_reason attribute of a server RPC
+// that failed with code.
+func serverEndReason(code grpc_codes.Code) attribute.KeyValue {
+	switch code {
+	case grpc_codes.Canceled:
+		return otelgrpc.RPCGRPCEndReasonCancelledByClient
+	case grpc_codes.DeadlineExceeded:
+		return otelgrpc.RPCGRPCEndReasonDeadlineExceeded
+	case grpc_codes.Unavailable:
+		return otelgrpc.RPCGRPCEndReasonTransportError
+	default:
+		return otelgrpc.RPCGRPCEndReasonApplicationError
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func endRPC(t *testing.T, err error, opts ...otelgrpc.Option) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewClientHandler(append([]otelgrpc.Option{otelgrpc.WithTracerProvider(tp)}, opts...)...)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.End{Error: err})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	return spans[0]
+}
+
+func statusDetails(t *testing.T, event sdktrace.Event) []string {
+	t.Helper()
+
+	set := attribute.NewSet(event.Attributes...)
+	v, ok := set.Value(otelgrpc.RPCGRPCStatusDetailsKey)
+	require.True(t, ok)
+	return v.AsStringSlice()
+}
+
+func TestStatusDetailsEvent(t *testing.T) {
+	s, err := status.New(codes.Unavailable, "checkpoint storage unavailable").WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(2 * time.Second)},
+		&errdetails.ErrorInfo{Reason: "STORAGE_DOWN", Domain: "cedana.ai"},
+	)
+	require.NoError(t, err)
+
+	span := endRPC(t, s.Err())
+	require.Len(t, span.Events(), 1)
+	event := span.Events()[0]
+	assert.Equal(t, "rpc.grpc.status_details", event.Name)
+	assert.Contains(t, event.Attributes, otelgrpc.GRPCStatusCodeKey.Int(int(codes.Unavailable)))
+
+	details := statusDetails(t, event)
+	require.Len(t, details, 2)
+
+	var retry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(details[0]), &retry))
+	assert.Equal(t, "type.googleapis.com/google.rpc.RetryInfo", retry["@type"])
+	assert.Equal(t, "2s", retry["retryDelay"])
+
+	var info map[string]any
+	require.NoError(t, json.Unmarshal([]byte(details[1]), &info))
+	assert.Equal(t, "type.googleapis.com/google.rpc.ErrorInfo", info["@type"])
+	assert.Equal(t, "STORAGE_DOWN", info["reason"])
+}
+
+func TestStatusDetailsEventUnknownType(t *testing.T) {
+	detail := &anypb.Any{TypeUrl: "type.googleapis.com/cedana.Unknown", Value: []byte{0x08, 0x01}}
+	sp := status.New(codes.Internal, "internal").Proto()
+	sp.Details = append(sp.Details, detail)
+
+	span := endRPC(t, status.FromProto(sp).Err())
+	require.Len(t, span.Events(), 1)
+	assert.Equal(t, []string{`{"@type":"type.googleapis.com/cedana.Unknown"}`}, statusDetails(t, span.Events()[0]))
+}
+
+func TestStatusDetailsEventFieldMask(t *testing.T) {
+	s, err := status.New(codes.InvalidArgument, "invalid").WithDetails(wrapperspb.String("secret"))
+	require.NoError(t, err)
+
+	span := endRPC(t, s.Err(), otelgrpc.WithPayloadFieldMask("google.protobuf.StringValue", otelgrpc.FieldMaskExclude, &fieldmaskpb.FieldMask{Paths: []string{"value"}}))
+	require.Len(t, span.Events(), 1)
+	details := statusDetails(t, span.Events()[0])
+	require.Len(t, details, 1)
+	assert.NotContains(t, details[0], "secret")
+}
+
+func TestStatusDetailsEventNoDetails(t *testing.T) {
+	span := endRPC(t, status.Error(codes.NotFound, "not found"))
+	assert.Empty(t, span.Events())
+}
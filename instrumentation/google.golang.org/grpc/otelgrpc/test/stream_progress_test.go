@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/stats"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStreamProgress(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	h := otelgrpc.NewServerHandler(
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		otelgrpc.WithStreamProgressInterval(10*time.Millisecond),
+	)
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Restore"})
+	h.HandleRPC(ctx, &stats.Begin{IsClientStream: true})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1024, CompressedLength: 512})
+	h.HandleRPC(ctx, &stats.InPayload{Length: 1024, CompressedLength: 512})
+
+	require.Len(t, sr.Started(), 1)
+	span := sr.Started()[0]
+	require.Eventually(t, func() bool {
+		for _, e := range span.Events() {
+			if e.Name == "stream.progress" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	h.HandleRPC(ctx, &stats.End{})
+
+	var progress sdktrace.Event
+	for _, e := range sr.Ended()[0].Events() {
+		if e.Name == "stream.progress" {
+			progress = e
+			break
+		}
+	}
+	assert.Contains(t, progress.Attributes, otelgrpc.StreamMessagesReceivedKey.Int64(2))
+	assert.Contains(t, progress.Attributes, otelgrpc.StreamBytesReceivedKey.Int64(1024))
+	assert.Contains(t, progress.Attributes, otelgrpc.StreamMessagesSentKey.Int64(0))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	got := map[string]bool{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if !strings.HasPrefix(m.Name, "rpc.server.stream.") {
+			continue
+		}
+		if h, ok := m.Data.(metricdata.Histogram[float64]); ok && len(h.DataPoints) > 0 {
+			got[m.Name] = true
+			for _, dp := range h.DataPoints {
+				_, ok := dp.Attributes.Value(otelgrpc.RPCMessageTypeKey)
+				assert.True(t, ok, m.Name)
+			}
+		}
+	}
+	assert.True(t, got["rpc.server.stream.throughput"])
+	assert.True(t, got["rpc.server.stream.message_throughput"])
+
+	// No progress is reported after the RPC ended.
+	n := len(sr.Ended()[0].Events())
+	time.Sleep(30 * time.Millisecond)
+	assert.Len(t, span.Events(), n)
+}
+
+func TestStreamProgressUnary(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelgrpc.NewClientHandler(otelgrpc.WithTracerProvider(tp), otelgrpc.WithStreamProgressInterval(time.Millisecond))
+
+	ctx := h.TagRPC(context.Background(), &stats.RPCTagInfo{FullMethodName: "/cedana.daemon.Daemon/Dump"})
+	h.HandleRPC(ctx, &stats.Begin{Client: true})
+	time.Sleep(10 * time.Millisecond)
+	h.HandleRPC(ctx, &stats.End{Client: true})
+
+	require.Len(t, sr.Ended(), 1)
+	for _, e := range sr.Ended()[0].Events() {
+		assert.NotEqual(t, "stream.progress", e.Name)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tlsPeerAttrs returns the attributes describing the TLS connection of the
+// peer of ctx and the identity of the client certificate, if the client
+// presented one. No attributes are returned if the connection does not use
+// TLS.
+func tlsPeerAttrs(ctx context.Context) []attribute.KeyValue {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+
+	state := info.State
+	attrs := []attribute.KeyValue{TLSProtocolNameKey.String("tls")}
+	if v := tlsVersion(state.Version); v != "" {
+		attrs = append(attrs, TLSProtocolVersionKey.String(v))
+	}
+	if state.CipherSuite != 0 {
+		attrs = append(attrs, TLSCipherKey.String(tls.CipherSuiteName(state.CipherSuite)))
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		attrs = append(attrs,
+			TLSClientSubjectKey.String(cert.Subject.String()),
+			TLSClientIssuerKey.String(cert.Issuer.String()),
+		)
+	}
+	if info.SPIFFEID != nil {
+		attrs = append(attrs, TLSClientSPIFFEIDKey.String(info.SPIFFEID.String()))
+	}
+	return attrs
+}
+
+// tlsVersion returns the TLS protocol version v in the format of the
+// tls.protocol.version attribute, e.g. "1.3", or "" if v is unknown.
+func tlsVersion(v uint16) string {
+	switch v {
+	case tls.VersionTLS10, tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13:
+		return strings.TrimPrefix(tls.VersionName(v), "TLS ")
+	}
+	return ""
+}
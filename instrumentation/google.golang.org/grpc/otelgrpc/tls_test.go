@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func tlsPeerContext(authInfo credentials.AuthInfo) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr:     &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 52000},
+		AuthInfo: authInfo,
+	})
+}
+
+func TestTLSPeerAttrs(t *testing.T) {
+	spiffeID, err := url.Parse("spiffe://example.org/ns/default/sa/daemon")
+	require.NoError(t, err)
+	mTLS := credentials.TLSInfo{
+		State: tls.ConnectionState{
+			Version:     tls.VersionTLS13,
+			CipherSuite: tls.TLS_AES_128_GCM_SHA256,
+			PeerCertificates: []*x509.Certificate{{
+				Subject: pkix.Name{CommonName: "daemon", Organization: []string{"Example"}},
+				Issuer:  pkix.Name{CommonName: "Example CA"},
+			}},
+		},
+		SPIFFEID: spiffeID,
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []attribute.KeyValue
+	}{
+		{name: "NoPeer", ctx: context.Background()},
+		{name: "Insecure", ctx: tlsPeerContext(nil)},
+		{
+			name: "TLS",
+			ctx: tlsPeerContext(credentials.TLSInfo{State: tls.ConnectionState{
+				Version:     tls.VersionTLS12,
+				CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			}}),
+			want: []attribute.KeyValue{
+				TLSProtocolNameKey.String("tls"),
+				TLSProtocolVersionKey.String("1.2"),
+				TLSCipherKey.String("TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"),
+			},
+		},
+		{
+			name: "MutualTLS",
+			ctx:  tlsPeerContext(mTLS),
+			want: []attribute.KeyValue{
+				TLSProtocolNameKey.String("tls"),
+				TLSProtocolVersionKey.String("1.3"),
+				TLSCipherKey.String("TLS_AES_128_GCM_SHA256"),
+				TLSClientSubjectKey.String("CN=daemon,O=Example"),
+				TLSClientIssuerKey.String("CN=Example CA"),
+				TLSClientSPIFFEIDKey.String("spiffe://example.org/ns/default/sa/daemon"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tlsPeerAttrs(tt.ctx))
+		})
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// grpcTraceBinHeader is the metadata key of the binary trace context
+// propagated by gRPC libraries using the OpenCensus binary format.
+const grpcTraceBinHeader = "grpc-trace-bin"
+
+// Layout of the OpenCensus binary format: a version byte followed by the
+// trace ID, span ID and trace options fields, each prefixed with its ID.
+const (
+	grpcTraceBinVersion      = 0
+	grpcTraceBinTraceIDField = 0
+	grpcTraceBinSpanIDField  = 1
+	grpcTraceBinOptionsField = 2
+	grpcTraceBinLen          = 29
+)
+
+// grpcTraceBinPropagator propagates span contexts in the grpc-trace-bin
+// binary metadata header. The carrier must be a metadataSupplier, whose
+// "-bin" values are transmitted base64 encoded by gRPC.
+type grpcTraceBinPropagator struct{}
+
+var _ propagation.TextMapPropagator = grpcTraceBinPropagator{}
+
+// Inject sets the span context of ctx in the grpc-trace-bin header of
+// carrier.
+func (grpcTraceBinPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	carrier.Set(grpcTraceBinHeader, string(marshalGRPCTraceBin(sc)))
+}
+
+// Extract returns ctx with the remote span context of the grpc-trace-bin
+// header of carrier, if it is valid.
+func (grpcTraceBinPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, ok := unmarshalGRPCTraceBin([]byte(carrier.Get(grpcTraceBinHeader)))
+	if !ok {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the grpc-trace-bin header.
+func (grpcTraceBinPropagator) Fields() []string {
+	return []string{grpcTraceBinHeader}
+}
+
+// marshalGRPCTraceBin returns sc in the OpenCensus binary format.
+func marshalGRPCTraceBin(sc trace.SpanContext) []byte {
+	b := make([]byte, 0, grpcTraceBinLen)
+	b = append(b, grpcTraceBinVersion)
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	b = append(b, grpcTraceBinTraceIDField)
+	b = append(b, traceID[:]...)
+	b = append(b, grpcTraceBinSpanIDField)
+	b = append(b, spanID[:]...)
+	b = append(b, grpcTraceBinOptionsField, byte(sc.TraceFlags()&trace.FlagsSampled))
+	return b
+}
+
+// unmarshalGRPCTraceBin parses a span context in the OpenCensus binary
+// format. It returns false if b is malformed or the span context is not
+// valid. The trace options field is optional.
+func unmarshalGRPCTraceBin(b []byte) (trace.SpanContext, bool) {
+	if len(b) < grpcTraceBinLen-2 || b[0] != grpcTraceBinVersion {
+		return trace.SpanContext{}, false
+	}
+
+	var cfg trace.SpanContextConfig
+	if b[1] != grpcTraceBinTraceIDField {
+		return trace.SpanContext{}, false
+	}
+	copy(cfg.TraceID[:], b[2:18])
+	if b[18] != grpcTraceBinSpanIDField {
+		return trace.SpanContext{}, false
+	}
+	copy(cfg.SpanID[:], b[19:27])
+	if len(b) >= grpcTraceBinLen && b[27] == grpcTraceBinOptionsField {
+		cfg.TraceFlags = trace.TraceFlags(b[28]) & trace.FlagsSampled
+	}
+	cfg.Remote = true
+
+	sc := trace.NewSpanContext(cfg)
+	return sc, sc.IsValid()
+}
+
+type grpcTraceBinOption struct{ enabled bool }
+
+func (o grpcTraceBinOption) apply(c *config) {
+	c.GRPCTraceBin = o.enabled
+}
+
+// WithGRPCTraceBinPropagation returns an Option that also injects and
+// extracts the span context in the grpc-trace-bin binary metadata header
+// used by gRPC libraries in other languages, in addition to the configured
+// Propagators. If both carry a span context, the one extracted by the
+// Propagators is used. It is disabled by default.
+func WithGRPCTraceBinPropagation(enabled bool) Option {
+	return grpcTraceBinOption{enabled: enabled}
+}
@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var grpcTraceBinSpanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: trace.FlagsSampled,
+	Remote:     true,
+})
+
+func TestGRPCTraceBinRoundTrip(t *testing.T) {
+	b := marshalGRPCTraceBin(grpcTraceBinSpanContext)
+	assert.Equal(t, []byte{
+		0,
+		0, 0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36,
+		1, 0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7,
+		2, 1,
+	}, b)
+
+	sc, ok := unmarshalGRPCTraceBin(b)
+	require.True(t, ok)
+	assert.Equal(t, grpcTraceBinSpanContext, sc)
+
+	// The trace options field is optional.
+	sc, ok = unmarshalGRPCTraceBin(b[:27])
+	require.True(t, ok)
+	assert.False(t, sc.IsSampled())
+}
+
+func TestGRPCTraceBinInvalid(t *testing.T) {
+	valid := marshalGRPCTraceBin(grpcTraceBinSpanContext)
+	for name, b := range map[string][]byte{
+		"Empty":       nil,
+		"Short":       valid[:20],
+		"Version":     append([]byte{1}, valid[1:]...),
+		"TraceField":  append([]byte{0, 5}, valid[2:]...),
+		"ZeroTraceID": marshalGRPCTraceBin(trace.NewSpanContext(trace.SpanContextConfig{SpanID: trace.SpanID{1}})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, ok := unmarshalGRPCTraceBin(b)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestGRPCTraceBinPropagation(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), grpcTraceBinSpanContext)
+	md, _ := metadata.FromOutgoingContext(inject(ctx, newConfig([]Option{
+		WithPropagators(propagation.TraceContext{}),
+		WithGRPCTraceBinPropagation(true),
+	}, "").Propagators))
+	assert.Len(t, md.Get(grpcTraceBinHeader), 1)
+	assert.Len(t, md.Get("traceparent"), 1)
+}
+
+func TestGRPCTraceBinDisabled(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), grpcTraceBinSpanContext)
+	md, _ := metadata.FromOutgoingContext(inject(ctx, newConfig([]Option{
+		WithPropagators(propagation.TraceContext{}),
+	}, "").Propagators))
+	assert.Empty(t, md.Get(grpcTraceBinHeader))
+}
@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelgrpc // import "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// wireEncodingBase64 is the RPCMessagePayloadEncodingKey value of payloads
+// captured in wire format.
+var wireEncodingBase64 = RPCMessagePayloadEncodingKey.String("base64")
+
+// wirePayload is the serialized message of an RPC as sent on the wire,
+// before compression.
+type wirePayload []byte
+
+// capturedPayload returns the payload captured for a message msg whose
+// serialized form is data, which may be nil if it is not known, e.g. once
+// gRPC stops populating the deprecated Data field of payload stats.
+func (c *config) capturedPayload(msg any, data []byte) any {
+	if c.PayloadWireFormat && data != nil {
+		return wirePayload(data)
+	}
+	return msg
+}
+
+// wireFormat returns payload, a wirePayload or a proto message, base64
+// encoded in the proto wire format.
+func wireFormat(payload any) (attribute.Value, error) {
+	switch p := payload.(type) {
+	case wirePayload:
+		return attribute.StringValue(base64.StdEncoding.EncodeToString(p)), nil
+	case proto.Message:
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return attribute.Value{}, err
+		}
+		return attribute.StringValue(base64.StdEncoding.EncodeToString(data)), nil
+	default:
+		return attribute.Value{}, fmt.Errorf("%T is not a proto message", payload)
+	}
+}
+
+type payloadWireFormatOption struct{ enabled bool }
+
+func (o payloadWireFormatOption) apply(c *config) {
+	c.PayloadWireFormat = o.enabled
+}
+
+// WithPayloadWireFormat returns an Option that configures whether payloads
+// are recorded in the proto wire format, base64 encoded, instead of being
+// serialized by the PayloadMarshaler. Stats handlers record the exact bytes
+// received or sent, including unknown fields, so captured RPCs can be
+// replayed bit for bit. Interceptors, which only see the messages, record
+// them serialized with proto.Marshal. Message events are annotated with the
+// message.payload_encoding attribute.
+//
+// Payloads recorded in wire format are not subject to field masks,
+// redaction or scrubbing, so this must not be enabled for RPCs carrying
+// sensitive data. It is disabled by default and WithPayloadHash takes
+// precedence over it.
+func WithPayloadWireFormat(enabled bool) Option {
+	return payloadWireFormatOption{enabled: enabled}
+}
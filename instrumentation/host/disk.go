@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"slices"
+
+	"github.com/shirou/gopsutil/v4/disk"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Attribute keys of the disk, filesystem and per-interface network
+// measurements.
+var (
+	deviceKey     = attribute.Key("device")
+	directionKey  = attribute.Key("direction")
+	mountpointKey = attribute.Key("mountpoint")
+	typeKey       = attribute.Key("type")
+	modeKey       = attribute.Key("mode")
+	stateKey      = attribute.Key("state")
+)
+
+func (h *host) registerDisk() error {
+	var (
+		err error
+
+		diskIO         metric.Int64ObservableCounter
+		diskOperations metric.Int64ObservableCounter
+	)
+
+	if diskIO, err = h.meter.Int64ObservableCounter(
+		"system.disk.io",
+		metric.WithUnit("By"),
+		metric.WithDescription(
+			"Bytes transferred by disk devices attributed by device and direction (Read, Write)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if diskOperations, err = h.meter.Int64ObservableCounter(
+		"system.disk.operations",
+		metric.WithUnit("{operation}"),
+		metric.WithDescription(
+			"Disk operations attributed by device and direction (Read, Write)",
+		),
+	); err != nil {
+		return err
+	}
+
+	err = h.registerCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			ioStats, err := disk.IOCountersWithContext(ctx)
+			if err != nil {
+				return err
+			}
+
+			for name, stats := range ioStats {
+				device := deviceKey.String(name)
+				read := metric.WithAttributeSet(attribute.NewSet(device, directionKey.String("read")))
+				write := metric.WithAttributeSet(attribute.NewSet(device, directionKey.String("write")))
+
+				o.ObserveInt64(diskIO, int64(stats.ReadBytes), read)
+				o.ObserveInt64(diskIO, int64(stats.WriteBytes), write)
+				o.ObserveInt64(diskOperations, int64(stats.ReadCount), read)
+				o.ObserveInt64(diskOperations, int64(stats.WriteCount), write)
+			}
+			return nil
+		},
+		diskIO,
+		diskOperations,
+	)
+	return err
+}
+
+func (h *host) registerFilesystem() error {
+	var (
+		err error
+
+		filesystemUsage       metric.Int64ObservableUpDownCounter
+		filesystemUtilization metric.Float64ObservableGauge
+		filesystemInodesUsage metric.Int64ObservableUpDownCounter
+	)
+
+	if filesystemUsage, err = h.meter.Int64ObservableUpDownCounter(
+		"system.filesystem.usage",
+		metric.WithUnit("By"),
+		metric.WithDescription(
+			"Filesystem space usage attributed by mountpoint and state (Used, Free, Reserved)",
+		),
+	); err != nil {
+		return err
+	}
+
+	if filesystemUtilization, err = h.meter.Float64ObservableGauge(
+		"system.filesystem.utilization",
+		metric.WithUnit("1"),
+		metric.WithDescription(
+			"Fraction of the filesystem space used attributed by mountpoint",
+		),
+	); err != nil {
+		return err
+	}
+
+	if filesystemInodesUsage, err = h.meter.Int64ObservableUpDownCounter(
+		"system.filesystem.inodes.usage",
+		metric.WithUnit("{inode}"),
+		metric.WithDescription(
+			"Filesystem inode usage attributed by mountpoint and state (Used, Free)",
+		),
+	); err != nil {
+		return err
+	}
+
+	err = h.registerCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			// Only the partitions of physical devices, not the
+			// pseudo filesystems such as proc or tmpfs.
+			partitions, err := disk.PartitionsWithContext(ctx, false)
+			if err != nil {
+				return err
+			}
+
+			for _, p := range partitions {
+				if !h.config.FilesystemFilter.match(p.Mountpoint) {
+					continue
+				}
+				usage, err := disk.UsageWithContext(ctx, p.Mountpoint)
+				if err != nil {
+					// Report the other filesystems, e.g. if
+					// this one is not accessible.
+					otel.Handle(err)
+					continue
+				}
+
+				mode := "rw"
+				if slices.Contains(p.Opts, "ro") {
+					mode = "ro"
+				}
+				attrs := []attribute.KeyValue{
+					deviceKey.String(p.Device),
+					mountpointKey.String(p.Mountpoint),
+					typeKey.String(p.Fstype),
+					modeKey.String(mode),
+				}
+				withState := func(state string) metric.ObserveOption {
+					return metric.WithAttributeSet(attribute.NewSet(append(attrs, stateKey.String(state))...))
+				}
+
+				var reserved uint64
+				if usage.Total > usage.Used+usage.Free {
+					reserved = usage.Total - usage.Used - usage.Free
+				}
+				o.ObserveInt64(filesystemUsage, int64(usage.Used), withState("used"))
+				o.ObserveInt64(filesystemUsage, int64(usage.Free), withState("free"))
+				o.ObserveInt64(filesystemUsage, int64(reserved), withState("reserved"))
+
+				if usage.Total > 0 {
+					o.ObserveFloat64(filesystemUtilization, float64(usage.Used)/float64(usage.Total),
+						metric.WithAttributeSet(attribute.NewSet(attrs...)))
+				}
+
+				o.ObserveInt64(filesystemInodesUsage, int64(usage.InodesUsed), withState("used"))
+				o.ObserveInt64(filesystemInodesUsage, int64(usage.InodesFree), withState("free"))
+			}
+			return nil
+		},
+		filesystemUsage,
+		filesystemUtilization,
+		filesystemInodesUsage,
+	)
+	return err
+}
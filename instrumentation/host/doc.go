@@ -18,7 +18,19 @@
 //	system.cpu.time            state=user|system|other|idle
 //	system.memory.usage        state=used|available
 //	system.memory.utilization  state=used|available
-//	system.network.io          direction=transmit|receive
+//	system.network.io          direction=transmit|receive, device (optional)
+//	system.disk.io             device, direction=read|write
+//	system.disk.operations     device, direction=read|write
+//	system.filesystem.usage    device, mountpoint, type, mode, state=used|free|reserved
+//	system.filesystem.utilization    device, mountpoint, type, mode
+//	system.filesystem.inodes.usage   device, mountpoint, type, mode, state=used|free
+//
+// The filesystem metrics are reported for the filesystems of physical
+// devices, whose mountpoints are selected by the
+// WithFilesystemIncludeMountpoints and WithFilesystemExcludeMountpoints
+// options. The network metrics are reported for the interfaces selected by
+// the WithNetworkIncludeInterfaces and WithNetworkExcludeInterfaces options,
+// in total or, with the WithNetworkPerInterface option, per interface.
 //
 // See https://github.com/open-telemetry/oteps/blob/main/text/0119-standard-system-metrics.md
 // for the definition of these metric instruments.
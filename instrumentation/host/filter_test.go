@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package host
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFilter(t *testing.T) {
+	mustCompile := func(patterns ...string) []*regexp.Regexp {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	tests := []struct {
+		name   string
+		filter filter
+		match  map[string]bool
+	}{
+		{
+			name:  "Empty",
+			match: map[string]bool{"/": true, "/boot": true},
+		},
+		{
+			name:   "Include",
+			filter: filter{include: mustCompile("/", "/data/.*")},
+			match:  map[string]bool{"/": true, "/data/db": true, "/boot": false, "/data": false},
+		},
+		{
+			name:   "Exclude",
+			filter: filter{exclude: mustCompile("/snap/.*")},
+			match:  map[string]bool{"/": true, "/snap/core/1": false},
+		},
+		{
+			name: "IncludeExclude",
+			filter: filter{
+				include: mustCompile("/data/.*"),
+				exclude: mustCompile("/data/tmp"),
+			},
+			match: map[string]bool{"/data/db": true, "/data/tmp": false, "/": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, want := range tt.match {
+				if got := tt.filter.match(name); got != want {
+					t.Errorf("match(%q) = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompilePatternsInvalid(t *testing.T) {
+	if _, err := compilePatterns([]string{"("}); err == nil {
+		t.Error("expected an error")
+	}
+}
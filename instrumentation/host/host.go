@@ -5,13 +5,13 @@ package host // import "go.opentelemetry.io/contrib/instrumentation/host"
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
 
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/mem"
-	"github.com/shirou/gopsutil/v4/net"
 	"github.com/shirou/gopsutil/v4/process"
 
 	"go.opentelemetry.io/otel"
@@ -26,6 +26,10 @@ const ScopeName = "go.opentelemetry.io/contrib/instrumentation/host"
 type host struct {
 	config config
 	meter  metric.Meter
+
+	// registrations are the callbacks registered so far, unregistered if
+	// Start fails.
+	registrations []metric.Registration
 }
 
 // config contains optional settings for reporting host metrics.
@@ -33,6 +37,21 @@ type config struct {
 	// MeterProvider sets the metric.MeterProvider.  If nil, the global
 	// Provider will be used.
 	MeterProvider metric.MeterProvider
+
+	// FilesystemFilter selects the mountpoints of the filesystems whose
+	// usage is reported.
+	FilesystemFilter filter
+
+	// NetworkFilter selects the network interfaces whose traffic is
+	// reported.
+	NetworkFilter filter
+
+	// NetworkPerInterface reports the traffic of each network interface
+	// instead of the total.
+	NetworkPerInterface bool
+
+	// err is the error of an invalid option, returned by Start.
+	err error
 }
 
 // Option supports configuring optional settings for host metrics.
@@ -55,6 +74,85 @@ func (o metricProviderOption) apply(c *config) {
 	}
 }
 
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// WithFilesystemIncludeMountpoints restricts the filesystems whose usage is
+// reported to the ones mounted on a mountpoint fully matching one of the
+// regular expressions patterns, e.g. "/" or "/data/.*". By default, the
+// filesystems of all the physical devices are reported. Passing this option
+// multiple times adds all the patterns.
+func WithFilesystemIncludeMountpoints(patterns ...string) Option {
+	return optionFunc(func(c *config) {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			c.err = errors.Join(c.err, err)
+			return
+		}
+		c.FilesystemFilter.include = append(c.FilesystemFilter.include, res...)
+	})
+}
+
+// WithFilesystemExcludeMountpoints excludes the filesystems mounted on a
+// mountpoint fully matching one of the regular expressions patterns, e.g.
+// "/snap/.*", from the ones whose usage is reported. Exclusions take
+// precedence over WithFilesystemIncludeMountpoints. Passing this option
+// multiple times adds all the patterns.
+func WithFilesystemExcludeMountpoints(patterns ...string) Option {
+	return optionFunc(func(c *config) {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			c.err = errors.Join(c.err, err)
+			return
+		}
+		c.FilesystemFilter.exclude = append(c.FilesystemFilter.exclude, res...)
+	})
+}
+
+// WithNetworkIncludeInterfaces restricts the network interfaces whose
+// traffic is reported to the ones whose name fully matches one of the
+// regular expressions patterns, e.g. "eth0" or "en.*". By default, all the
+// interfaces are reported. Passing this option multiple times adds all the
+// patterns.
+func WithNetworkIncludeInterfaces(patterns ...string) Option {
+	return optionFunc(func(c *config) {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			c.err = errors.Join(c.err, err)
+			return
+		}
+		c.NetworkFilter.include = append(c.NetworkFilter.include, res...)
+	})
+}
+
+// WithNetworkExcludeInterfaces excludes the network interfaces whose name
+// fully matches one of the regular expressions patterns, e.g. "lo",
+// "veth.*" or "br-.*", from the ones whose traffic is reported. Exclusions
+// take precedence over WithNetworkIncludeInterfaces. Passing this option
+// multiple times adds all the patterns.
+func WithNetworkExcludeInterfaces(patterns ...string) Option {
+	return optionFunc(func(c *config) {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			c.err = errors.Join(c.err, err)
+			return
+		}
+		c.NetworkFilter.exclude = append(c.NetworkFilter.exclude, res...)
+	})
+}
+
+// WithNetworkPerInterface reports the traffic of each network interface
+// selected by WithNetworkIncludeInterfaces and WithNetworkExcludeInterfaces,
+// with the device attribute holding its name, instead of their total.
+func WithNetworkPerInterface() Option {
+	return optionFunc(func(c *config) {
+		c.NetworkPerInterface = true
+	})
+}
+
 // Attribute sets.
 var (
 	// Attribute sets for CPU time measurements.
@@ -89,6 +187,9 @@ func newConfig(opts ...Option) config {
 // Start initializes reporting of host metrics using the supplied config.
 func Start(opts ...Option) error {
 	c := newConfig(opts...)
+	if c.err != nil {
+		return c.err
+	}
 	if c.MeterProvider == nil {
 		c.MeterProvider = otel.GetMeterProvider()
 	}
@@ -99,7 +200,32 @@ func Start(opts ...Option) error {
 		),
 		config: c,
 	}
-	return h.register()
+	for _, register := range []func() error{h.register, h.registerDisk, h.registerFilesystem} {
+		if err := register(); err != nil {
+			return errors.Join(err, h.unregister())
+		}
+	}
+	return nil
+}
+
+// registerCallback registers f as the callback of instruments.
+func (h *host) registerCallback(f metric.Callback, instruments ...metric.Observable) error {
+	reg, err := h.meter.RegisterCallback(f, instruments...)
+	if err != nil {
+		return err
+	}
+	h.registrations = append(h.registrations, reg)
+	return nil
+}
+
+// unregister unregisters the callbacks registered so far.
+func (h *host) unregister() error {
+	var err error
+	for _, reg := range h.registrations {
+		err = errors.Join(err, reg.Unregister())
+	}
+	h.registrations = nil
+	return err
 }
 
 func (h *host) register() error {
@@ -179,7 +305,7 @@ func (h *host) register() error {
 		return err
 	}
 
-	_, err = h.meter.RegisterCallback(
+	err = h.registerCallback(
 		func(ctx context.Context, o metric.Observer) error {
 			lock.Lock()
 			defer lock.Unlock()
@@ -207,13 +333,10 @@ func (h *host) register() error {
 				return err
 			}
 
-			ioStats, err := net.IOCountersWithContext(ctx, false)
+			ioStats, err := h.networkIOCounters(ctx)
 			if err != nil {
 				return err
 			}
-			if len(ioStats) != 1 {
-				return fmt.Errorf("host network usage: incorrect summary count")
-			}
 
 			hostTime := hostTimeSlice[0]
 			opt := metric.WithAttributeSet(AttributeCPUTimeUser)
@@ -256,15 +379,20 @@ func (h *host) register() error {
 			opt = metric.WithAttributeSet(AttributeMemoryAvailable)
 			o.ObserveFloat64(hostMemoryUtilization, float64(vmStats.Available)/float64(vmStats.Total), opt)
 
-			// Host network usage
-			//
-			// TODO: These can be broken down by network
-			// interface, with similar questions to those posed
-			// about per-CPU measurements above.
-			opt = metric.WithAttributeSet(AttributeNetworkTransmit)
-			o.ObserveInt64(networkIOUsage, int64(ioStats[0].BytesSent), opt)
-			opt = metric.WithAttributeSet(AttributeNetworkReceive)
-			o.ObserveInt64(networkIOUsage, int64(ioStats[0].BytesRecv), opt)
+			// Host network usage, in total or per interface, see
+			// WithNetworkPerInterface.
+			for _, stats := range ioStats {
+				transmit, receive := AttributeNetworkTransmit, AttributeNetworkReceive
+				if h.config.NetworkPerInterface {
+					device := deviceKey.String(stats.Name)
+					transmit = attribute.NewSet(device, directionKey.String("transmit"))
+					receive = attribute.NewSet(device, directionKey.String("receive"))
+				}
+				opt = metric.WithAttributeSet(transmit)
+				o.ObserveInt64(networkIOUsage, int64(stats.BytesSent), opt)
+				opt = metric.WithAttributeSet(receive)
+				o.ObserveInt64(networkIOUsage, int64(stats.BytesRecv), opt)
+			}
 
 			return nil
 		},
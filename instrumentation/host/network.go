@@ -0,0 +1,56 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package host // import "go.opentelemetry.io/contrib/instrumentation/host"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+// networkIOCounters returns the counters of the network interfaces selected
+// by the config of h, one per interface if NetworkPerInterface is set, or
+// their total otherwise.
+func (h *host) networkIOCounters(ctx context.Context) ([]net.IOCountersStat, error) {
+	f := h.config.NetworkFilter
+	filtered := len(f.include) > 0 || len(f.exclude) > 0
+	if !filtered && !h.config.NetworkPerInterface {
+		ioStats, err := net.IOCountersWithContext(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(ioStats) != 1 {
+			return nil, fmt.Errorf("host network usage: incorrect summary count")
+		}
+		return ioStats, nil
+	}
+
+	ioStats, err := net.IOCountersWithContext(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return selectNetworkIOCounters(ioStats, f, h.config.NetworkPerInterface), nil
+}
+
+// selectNetworkIOCounters returns the counters of the network interfaces of
+// ioStats matched by f, or their total named "all" if perInterface is false.
+func selectNetworkIOCounters(ioStats []net.IOCountersStat, f filter, perInterface bool) []net.IOCountersStat {
+	selected := make([]net.IOCountersStat, 0, len(ioStats))
+	for _, stats := range ioStats {
+		if f.match(stats.Name) {
+			selected = append(selected, stats)
+		}
+	}
+	if perInterface {
+		return selected
+	}
+
+	total := net.IOCountersStat{Name: "all"}
+	for _, stats := range selected {
+		total.BytesSent += stats.BytesSent
+		total.BytesRecv += stats.BytesRecv
+	}
+	return []net.IOCountersStat{total}
+}
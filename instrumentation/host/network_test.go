@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package host
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func TestSelectNetworkIOCounters(t *testing.T) {
+	mustCompile := func(patterns ...string) []*regexp.Regexp {
+		res, err := compilePatterns(patterns)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	ioStats := []net.IOCountersStat{
+		{Name: "lo", BytesSent: 1, BytesRecv: 2},
+		{Name: "eth0", BytesSent: 10, BytesRecv: 20},
+		{Name: "eth1", BytesSent: 100, BytesRecv: 200},
+		{Name: "docker0", BytesSent: 1000, BytesRecv: 2000},
+	}
+
+	tests := []struct {
+		name         string
+		filter       filter
+		perInterface bool
+		want         []net.IOCountersStat
+	}{
+		{
+			name: "Total",
+			want: []net.IOCountersStat{{Name: "all", BytesSent: 1111, BytesRecv: 2222}},
+		},
+		{
+			name:   "IncludeTotal",
+			filter: filter{include: mustCompile("eth.*")},
+			want:   []net.IOCountersStat{{Name: "all", BytesSent: 110, BytesRecv: 220}},
+		},
+		{
+			name:   "ExcludeTotal",
+			filter: filter{exclude: mustCompile("lo", "docker.*")},
+			want:   []net.IOCountersStat{{Name: "all", BytesSent: 110, BytesRecv: 220}},
+		},
+		{
+			name:   "NoneSelectedTotal",
+			filter: filter{include: mustCompile("wlan.*")},
+			want:   []net.IOCountersStat{{Name: "all"}},
+		},
+		{
+			name:         "PerInterface",
+			perInterface: true,
+			want:         ioStats,
+		},
+		{
+			name: "IncludeExcludePerInterface",
+			filter: filter{
+				include: mustCompile("eth.*", "lo"),
+				exclude: mustCompile("eth1"),
+			},
+			perInterface: true,
+			want:         []net.IOCountersStat{ioStats[0], ioStats[1]},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectNetworkIOCounters(ioStats, tt.filter, tt.perInterface)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("selectNetworkIOCounters() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/host"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// collect starts the host instrumentation with opts and returns the metrics
+// it reports by name.
+func collect(t *testing.T, opts ...host.Option) map[string]metricdata.Metrics {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	require.NoError(t, host.Start(append(opts, host.WithMeterProvider(mp))...))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	metrics := make(map[string]metricdata.Metrics)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m
+		}
+	}
+	return metrics
+}
+
+func TestDiskMetrics(t *testing.T) {
+	metrics := collect(t)
+
+	for _, name := range []string{"system.disk.io", "system.disk.operations"} {
+		m, ok := metrics[name]
+		if !ok {
+			// The host has no disk devices.
+			continue
+		}
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		require.True(t, ok, "%s is not an int64 sum", name)
+		assert.True(t, sum.IsMonotonic, name)
+		for _, dp := range sum.DataPoints {
+			_, ok := dp.Attributes.Value("device")
+			assert.True(t, ok, "%s without device", name)
+			direction, _ := dp.Attributes.Value("direction")
+			assert.Contains(t, []string{"read", "write"}, direction.AsString(), name)
+		}
+	}
+}
+
+func TestFilesystemMetricsIncludeMountpoints(t *testing.T) {
+	metrics := collect(t, host.WithFilesystemIncludeMountpoints("/"))
+
+	m, ok := metrics["system.filesystem.usage"]
+	if !ok {
+		t.Skip("/ is not the mountpoint of a physical device")
+	}
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	require.True(t, ok)
+	require.Len(t, sum.DataPoints, 3)
+	for _, dp := range sum.DataPoints {
+		mountpoint, _ := dp.Attributes.Value("mountpoint")
+		assert.Equal(t, "/", mountpoint.AsString())
+		state, _ := dp.Attributes.Value("state")
+		assert.Contains(t, []string{"used", "free", "reserved"}, state.AsString())
+	}
+}
+
+func TestFilesystemMetricsExcludeMountpoints(t *testing.T) {
+	metrics := collect(t, host.WithFilesystemExcludeMountpoints(".*"))
+
+	for _, name := range []string{"system.filesystem.usage", "system.filesystem.utilization", "system.filesystem.inodes.usage"} {
+		if m, ok := metrics[name]; ok {
+			assert.Empty(t, dataPoints(m), name)
+		}
+	}
+}
+
+func TestStartInvalidPattern(t *testing.T) {
+	assert.Error(t, host.Start(host.WithFilesystemIncludeMountpoints("(")))
+	assert.Error(t, host.Start(host.WithNetworkExcludeInterfaces("[")))
+}
+
+func TestStartUnregistersOnError(t *testing.T) {
+	// The host instrumentation registers 3 callbacks.
+	for failAt := 1; failAt <= 3; failAt++ {
+		meter := &failingMeter{failAt: failAt}
+		err := host.Start(host.WithMeterProvider(failingMeterProvider{meter: meter}))
+		assert.ErrorIs(t, err, errRegistration)
+		assert.Equal(t, failAt-1, meter.unregistered, "failing registration %d", failAt)
+	}
+}
+
+func dataPoints(m metricdata.Metrics) []attribute.Set {
+	var sets []attribute.Set
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	case metricdata.Gauge[float64]:
+		for _, dp := range data.DataPoints {
+			sets = append(sets, dp.Attributes)
+		}
+	}
+	return sets
+}
+
+var errRegistration = errors.New("registration failed")
+
+// failingMeterProvider provides a meter failing to register a callback.
+type failingMeterProvider struct {
+	noop.MeterProvider
+	meter *failingMeter
+}
+
+func (p failingMeterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return p.meter
+}
+
+// failingMeter fails the failAt-th registration of a callback and counts the
+// registrations unregistered.
+type failingMeter struct {
+	noop.Meter
+	failAt       int
+	registered   int
+	unregistered int
+}
+
+func (m *failingMeter) RegisterCallback(metric.Callback, ...metric.Observable) (metric.Registration, error) {
+	m.registered++
+	if m.registered == m.failAt {
+		return nil, errRegistration
+	}
+	return registration{meter: m}, nil
+}
+
+type registration struct {
+	embedded.Registration
+	meter *failingMeter
+}
+
+func (r registration) Unregister() error {
+	r.meter.unregistered++
+	return nil
+}
@@ -0,0 +1,244 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package matcher provides composable matchers of requests, usable both as
+// filters of the HTTP requests instrumented by
+// go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp and of the
+// RPCs instrumented by
+// go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc,
+// so that the same rules, e.g. the requests that are not traced, can be
+// maintained for both:
+//
+//	// Health checks and the requests of load balancers.
+//	healthChecks := matcher.Or(
+//		matcher.Path("/healthz", "/grpc.health.v1.Health/*"),
+//		matcher.Header("User-Agent", "kube-probe/*"),
+//	)
+//
+//	handler := otelhttp.NewHandler(mux, "server",
+//		otelhttp.WithFilter(otelhttpfilters.NotMatching(healthChecks)))
+//	server := grpc.NewServer(grpc.StatsHandler(otelgrpc.NewServerHandler(
+//		otelgrpc.WithContextFilter(otelgrpcfilters.NotMatching(healthChecks)))))
+package matcher // import "go.opentelemetry.io/contrib/instrumentation/matcher"
+
+import (
+	"net/netip"
+	"path"
+	"strings"
+)
+
+// Request describes a request to match: an HTTP request, or a gRPC call.
+type Request struct {
+	// Method is the method of an HTTP request. It is POST for gRPC calls.
+	Method string
+	// Path is the path of the URL of an HTTP request, or the full method
+	// name of a gRPC call, e.g. "/grpc.health.v1.Health/Check".
+	Path string
+	// Route is the route of an HTTP request, e.g. "/users/{id}", or the
+	// full method name of a gRPC call. It is empty if it is unknown.
+	Route string
+	// Peer is the address of the peer of the request, the client of a
+	// server or the server of a client, either an IP address or a host
+	// name. It is empty if it is unknown.
+	Peer string
+	// Header returns the values of the header, or gRPC metadata, name of
+	// the request. It is nil if they are unknown.
+	Header func(name string) []string
+}
+
+// Matcher reports whether a request matches.
+type Matcher func(*Request) bool
+
+// And returns a Matcher matching the requests matched by all of ms.
+func And(ms ...Matcher) Matcher {
+	return func(r *Request) bool {
+		for _, m := range ms {
+			if !m(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a Matcher matching the requests matched by any of ms.
+func Or(ms ...Matcher) Matcher {
+	return func(r *Request) bool {
+		for _, m := range ms {
+			if m(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a Matcher matching the requests not matched by m.
+func Not(m Matcher) Matcher {
+	return func(r *Request) bool {
+		return !m(r)
+	}
+}
+
+// Method returns a Matcher matching the requests with one of methods,
+// ignoring case.
+func Method(methods ...string) Matcher {
+	return func(r *Request) bool {
+		for _, method := range methods {
+			if strings.EqualFold(method, r.Method) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Path returns a Matcher matching the requests whose path matches one of
+// patterns. The path is matched with path.Match, e.g. "/users/*/orders"
+// matches "/users/42/orders", except that a pattern ending with "/*" also
+// matches all the paths below it, e.g. "/admin/*" matches
+// "/admin/users/42", and "/grpc.health.v1.Health/*" all the methods of the
+// gRPC health service.
+//
+// Path panics if a pattern is malformed.
+func Path(patterns ...string) Matcher {
+	ms := make([]func(string) bool, len(patterns))
+	for i, p := range patterns {
+		ms[i] = pathMatcher(p)
+	}
+	return func(r *Request) bool {
+		for _, m := range ms {
+			if m(r.Path) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// pathMatcher returns a function reporting whether a path matches pattern,
+// see Path.
+func pathMatcher(pattern string) func(string) bool {
+	if _, err := path.Match(pattern, ""); err != nil {
+		panic("matcher: malformed path pattern " + pattern)
+	}
+	prefix, subtree := strings.CutSuffix(pattern, "/*")
+	n := strings.Count(prefix, "/") + 1
+	return func(p string) bool {
+		if ok, _ := path.Match(pattern, p); ok {
+			return true
+		}
+		if !subtree {
+			return false
+		}
+		// Match the segments of the prefix against the first segments of
+		// the path.
+		segments := strings.SplitN(p, "/", n+1)
+		if len(segments) <= n {
+			return false
+		}
+		ok, _ := path.Match(prefix, strings.Join(segments[:n], "/"))
+		return ok
+	}
+}
+
+// PathPrefix returns a Matcher matching the requests whose path starts
+// with prefix.
+func PathPrefix(prefix string) Matcher {
+	return func(r *Request) bool {
+		return strings.HasPrefix(r.Path, prefix)
+	}
+}
+
+// Route returns a Matcher matching the requests whose route is one of
+// routes. Requests whose route is unknown do not match.
+func Route(routes ...string) Matcher {
+	return func(r *Request) bool {
+		if r.Route == "" {
+			return false
+		}
+		for _, route := range routes {
+			if route == r.Route {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Peer returns a Matcher matching the requests whose peer has an IP address
+// in one of prefixes, e.g. the network of the load balancers. Requests whose
+// peer is unknown or a host name do not match.
+func Peer(prefixes ...netip.Prefix) Matcher {
+	return func(r *Request) bool {
+		addr, err := netip.ParseAddr(r.Peer)
+		if err != nil {
+			return false
+		}
+		addr = addr.Unmap()
+		for _, p := range prefixes {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PeerName returns a Matcher matching the requests whose peer is one of
+// names, ignoring case, e.g. the host name of the server of a client
+// request.
+func PeerName(names ...string) Matcher {
+	return func(r *Request) bool {
+		for _, name := range names {
+			if strings.EqualFold(name, r.Peer) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Header returns a Matcher matching the requests with the header, or gRPC
+// metadata, name. If patterns are given, one of its values must match one of
+// them, where "*" matches any sequence of characters, e.g. "kube-probe/*".
+func Header(name string, patterns ...string) Matcher {
+	return func(r *Request) bool {
+		if r.Header == nil {
+			return false
+		}
+		values := r.Header(name)
+		if len(patterns) == 0 {
+			return len(values) > 0
+		}
+		for _, v := range values {
+			for _, p := range patterns {
+				if wildcardMatch(p, v) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
+// wildcardMatch reports whether s matches pattern, where "*" matches any
+// sequence of characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package matcher
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func header(h map[string][]string) func(string) []string {
+	return func(name string) []string { return h[name] }
+}
+
+func TestMatchers(t *testing.T) {
+	get := &Request{
+		Method: "GET",
+		Path:   "/admin/users/42",
+		Route:  "/admin/users/{id}",
+		Peer:   "10.1.2.3",
+		Header: header(map[string][]string{"User-Agent": {"kube-probe/1.30"}}),
+	}
+	rpc := &Request{
+		Method: "POST",
+		Path:   "/grpc.health.v1.Health/Check",
+		Route:  "/grpc.health.v1.Health/Check",
+		Peer:   "backend.internal",
+	}
+
+	tests := []struct {
+		name string
+		m    Matcher
+		r    *Request
+		want bool
+	}{
+		{"method", Method("post", "GET"), get, true},
+		{"method mismatch", Method("POST"), get, false},
+		{"path", Path("/health", "/admin/users/*"), get, true},
+		{"path mismatch", Path("/admin/*/orders"), get, false},
+		{"path subtree", Path("/admin/*"), get, true},
+		{"path gRPC service", Path("/grpc.health.v1.Health/*"), rpc, true},
+		{"path prefix", PathPrefix("/admin/"), get, true},
+		{"route", Route("/admin/users/{id}"), get, true},
+		{"route unknown", Route(""), &Request{}, false},
+		{"peer", Peer(netip.MustParsePrefix("10.0.0.0/8")), get, true},
+		{"peer host name", Peer(netip.MustParsePrefix("10.0.0.0/8")), rpc, false},
+		{"peer name", PeerName("Backend.Internal"), rpc, true},
+		{"header present", Header("User-Agent"), get, true},
+		{"header pattern", Header("User-Agent", "curl/*", "kube-probe/*"), get, true},
+		{"header pattern mismatch", Header("User-Agent", "*-probe/2.*"), get, false},
+		{"header unknown", Header("User-Agent"), rpc, false},
+		{"and", And(Method("GET"), Path("/admin/*")), get, true},
+		{"and mismatch", And(Method("GET"), Path("/admin/*")), rpc, false},
+		{"or", Or(Method("PUT"), Path("/grpc.health.v1.Health/*")), rpc, true},
+		{"not", Not(Method("GET")), rpc, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.m(tt.r))
+		})
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	for _, tt := range []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "abcd", false},
+		{"*", "", true},
+		{"a*c", "abbc", true},
+		{"a*b*c", "axbxc", true},
+		{"a*b*c", "acb", false},
+		{"*-probe/*", "kube-probe/1.30", true},
+		{"ab*ba", "aba", false},
+	} {
+		assert.Equal(t, tt.want, wildcardMatch(tt.pattern, tt.s), "%q %q", tt.pattern, tt.s)
+	}
+}
+
+func TestPathPanics(t *testing.T) {
+	assert.Panics(t, func() { Path("/[") })
+}
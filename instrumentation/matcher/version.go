@@ -0,0 +1,10 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package matcher // import "go.opentelemetry.io/contrib/instrumentation/matcher"
+
+// Version is the current release version of the matcher package.
+func Version() string {
+	return "0.53.0"
+	// This string is updated by the pre_release.sh script during release
+}
@@ -10,11 +10,14 @@ import (
 	"net/textproto"
 	"strings"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -34,6 +37,7 @@ var (
 	HTTPConnectionDoneNetwork  = attribute.Key("http.conn.done.network")
 	HTTPConnectionDoneAddr     = attribute.Key("http.conn.done.addr")
 	HTTPDNSAddrs               = attribute.Key("http.dns.addrs")
+	HTTPPhaseDuration          = attribute.Key("http.phase.duration")
 )
 
 var hookMap = map[string]string{
@@ -64,6 +68,12 @@ func (fn clientTraceOptionFunc) apply(c *clientTracer) {
 // WithoutSubSpans will modify the httptrace.ClientTrace to only collect data
 // as Events and Attributes on a span found in the context.  By default
 // sub-spans will be generated.
+//
+// Each phase of the request (dns, connection, tls, etc) is recorded as a pair
+// of timestamped "<phase>.start" and "<phase>.done" events, the latter having
+// the http.phase.duration attribute holding the duration of the phase in
+// seconds, so the timing breakdown of the request is preserved without the
+// cost of the sub-spans.
 func WithoutSubSpans() ClientTraceOption {
 	return clientTraceOptionFunc(func(ct *clientTracer) {
 		ct.useSpans = false
@@ -116,10 +126,13 @@ type clientTracer struct {
 	context.Context
 
 	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
 
-	tr trace.Tracer
+	tr     trace.Tracer
+	phases *phaseRecorder
 
 	activeHooks     map[string]context.Context
+	hookStarts      map[string]time.Time
 	root            trace.Span
 	mtx             sync.Mutex
 	redactedHeaders map[string]struct{}
@@ -138,6 +151,7 @@ func NewClientTrace(ctx context.Context, opts ...ClientTraceOption) *httptrace.C
 	ct := &clientTracer{
 		Context:     ctx,
 		activeHooks: make(map[string]context.Context),
+		hookStarts:  make(map[string]time.Time),
 		redactedHeaders: map[string]struct{}{
 			"authorization":       {},
 			"www-authenticate":    {},
@@ -155,15 +169,20 @@ func NewClientTrace(ctx context.Context, opts ...ClientTraceOption) *httptrace.C
 	} else {
 		ct.tracerProvider = otel.GetTracerProvider()
 	}
+	ct.meterProvider = otel.GetMeterProvider()
 
 	for _, opt := range opts {
 		opt.apply(ct)
 	}
+	if !ct.useSpans {
+		ct.root = trace.SpanFromContext(ctx)
+	}
 
 	ct.tr = ct.tracerProvider.Tracer(
 		ScopeName,
 		trace.WithInstrumentationVersion(Version()),
 	)
+	ct.phases = newPhaseRecorder(ctx, newPhaseMetrics(ct.meterProvider))
 
 	return &httptrace.ClientTrace{
 		GetConn:              ct.getConn,
@@ -187,10 +206,11 @@ func NewClientTrace(ctx context.Context, opts ...ClientTraceOption) *httptrace.C
 
 func (ct *clientTracer) start(hook, spanName string, attrs ...attribute.KeyValue) {
 	if !ct.useSpans {
-		if ct.root == nil {
-			ct.root = trace.SpanFromContext(ct.Context)
-		}
-		ct.root.AddEvent(hook+".start", trace.WithAttributes(attrs...))
+		now := time.Now()
+		ct.mtx.Lock()
+		ct.hookStarts[hook] = now
+		ct.mtx.Unlock()
+		ct.root.AddEvent(hook+".start", trace.WithTimestamp(now), trace.WithAttributes(attrs...))
 		return
 	}
 
@@ -215,10 +235,18 @@ func (ct *clientTracer) start(hook, spanName string, attrs ...attribute.KeyValue
 
 func (ct *clientTracer) end(hook string, err error, attrs ...attribute.KeyValue) {
 	if !ct.useSpans {
+		now := time.Now()
+		ct.mtx.Lock()
+		start, ok := ct.hookStarts[hook]
+		delete(ct.hookStarts, hook)
+		ct.mtx.Unlock()
+		if ok {
+			attrs = append(attrs, HTTPPhaseDuration.Float64(now.Sub(start).Seconds()))
+		}
 		if err != nil {
 			attrs = append(attrs, attribute.String(hook+".error", err.Error()))
 		}
-		ct.root.AddEvent(hook+".done", trace.WithAttributes(attrs...))
+		ct.root.AddEvent(hook+".done", trace.WithTimestamp(now), trace.WithAttributes(attrs...))
 		return
 	}
 
@@ -262,6 +290,7 @@ func (ct *clientTracer) span(hook string) trace.Span {
 }
 
 func (ct *clientTracer) getConn(host string) {
+	ct.phases.getConn(host)
 	ct.start("http.getconn", "http.getconn", semconv.NetHostName(host))
 }
 
@@ -283,10 +312,12 @@ func (ct *clientTracer) putIdleConn(err error) {
 }
 
 func (ct *clientTracer) gotFirstResponseByte() {
+	ct.phases.firstByte()
 	ct.start("http.receive", "http.receive")
 }
 
 func (ct *clientTracer) dnsStart(info httptrace.DNSStartInfo) {
+	ct.phases.start("http.dns", semconvNew.DNSQuestionName(info.Host))
 	ct.start("http.dns", "http.dns", semconv.NetHostName(info.Host))
 }
 
@@ -296,9 +327,11 @@ func (ct *clientTracer) dnsDone(info httptrace.DNSDoneInfo) {
 		addrs = append(addrs, netAddr.String())
 	}
 	ct.end("http.dns", info.Err, HTTPDNSAddrs.String(sliceToString(addrs)))
+	ct.phases.end(ct.phases.metrics.dns, "http.dns", info.Err)
 }
 
 func (ct *clientTracer) connectStart(network, addr string) {
+	ct.phases.start("http.connect."+addr, peerAttrs(addr)...)
 	ct.start("http.connect."+addr, "http.connect",
 		HTTPRemoteAddr.String(addr),
 		HTTPConnectionStartNetwork.String(network),
@@ -310,14 +343,17 @@ func (ct *clientTracer) connectDone(network, addr string, err error) {
 		HTTPConnectionDoneAddr.String(addr),
 		HTTPConnectionDoneNetwork.String(network),
 	)
+	ct.phases.end(ct.phases.metrics.connect, "http.connect."+addr, err)
 }
 
 func (ct *clientTracer) tlsHandshakeStart() {
+	ct.phases.start("http.tls")
 	ct.start("http.tls", "http.tls")
 }
 
 func (ct *clientTracer) tlsHandshakeDone(_ tls.ConnectionState, err error) {
 	ct.end("http.tls", err)
+	ct.phases.end(ct.phases.metrics.tls, "http.tls", err)
 }
 
 func (ct *clientTracer) wroteHeaderField(k string, v []string) {
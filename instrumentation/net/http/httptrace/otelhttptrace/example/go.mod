@@ -24,3 +24,5 @@ require (
 	go.opentelemetry.io/otel/metric v1.28.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 )
+
+replace go.opentelemetry.io/contrib/instrumentation/matcher => ../../../../../matcher
@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttptrace // import "go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Connection phase metrics, see WithMeterProvider.
+const (
+	dnsLookupDuration     = semconv.DNSLookupDurationName        // DNS lookups, seconds
+	connectDuration       = "http.client.connect.duration"       // TCP connects, seconds
+	tlsHandshakeDuration  = "http.client.tls.handshake.duration" // TLS handshakes, seconds
+	timeToFirstByteMetric = "http.client.time_to_first_byte"     // Getting a connection to the first response byte, seconds
+)
+
+// phaseMetrics are the instruments recording the duration of the phases of
+// requests.
+type phaseMetrics struct {
+	dns             metric.Float64Histogram
+	connect         metric.Float64Histogram
+	tls             metric.Float64Histogram
+	timeToFirstByte metric.Float64Histogram
+}
+
+// newPhaseMetrics creates the instruments with provider when a client trace
+// is configured. Providers return the same instruments for the same names, so
+// the requests of all client traces are recorded together.
+func newPhaseMetrics(provider metric.MeterProvider) *phaseMetrics {
+	meter := provider.Meter(
+		ScopeName,
+		metric.WithInstrumentationVersion(Version()),
+	)
+	var (
+		pm  phaseMetrics
+		err error
+	)
+	pm.dns, err = meter.Float64Histogram(
+		dnsLookupDuration,
+		metric.WithUnit(semconv.DNSLookupDurationUnit),
+		metric.WithDescription(semconv.DNSLookupDurationDescription),
+	)
+	if err != nil {
+		otel.Handle(err)
+		pm.dns = noop.Float64Histogram{}
+	}
+
+	pm.connect, err = meter.Float64Histogram(
+		connectDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Measures the time taken to establish the connections of outbound HTTP requests."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		pm.connect = noop.Float64Histogram{}
+	}
+
+	pm.tls, err = meter.Float64Histogram(
+		tlsHandshakeDuration,
+		metric.WithUnit("s"),
+		metric.WithDescription("Measures the time taken by the TLS handshakes of the connections of outbound HTTP requests."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		pm.tls = noop.Float64Histogram{}
+	}
+
+	pm.timeToFirstByte, err = meter.Float64Histogram(
+		timeToFirstByteMetric,
+		metric.WithUnit("s"),
+		metric.WithDescription("Measures the time from getting a connection for an outbound HTTP request to receiving the first byte of its response."),
+	)
+	if err != nil {
+		otel.Handle(err)
+		pm.timeToFirstByte = noop.Float64Histogram{}
+	}
+	return &pm
+}
+
+// phaseRecorder records the duration of the phases of a request. Its hooks
+// may be called concurrently, e.g. when connecting to several addresses.
+type phaseRecorder struct {
+	ctx     context.Context
+	metrics *phaseMetrics
+
+	mu           sync.Mutex
+	phases       map[string]phase
+	getConnStart time.Time
+	server       []attribute.KeyValue
+	recorded     bool
+}
+
+// phase is a started phase of a request.
+type phase struct {
+	start time.Time
+	attrs []attribute.KeyValue
+}
+
+func newPhaseRecorder(ctx context.Context, metrics *phaseMetrics) *phaseRecorder {
+	return &phaseRecorder{
+		ctx:     ctx,
+		metrics: metrics,
+		phases:  make(map[string]phase),
+	}
+}
+
+// getConn records the start of the request to hostPort.
+func (pr *phaseRecorder) getConn(hostPort string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if pr.getConnStart.IsZero() {
+		pr.getConnStart = time.Now()
+		pr.server = serverAttrs(hostPort)
+	}
+}
+
+// start records the start of the phase identified by hook, whose duration
+// is annotated with attrs.
+func (pr *phaseRecorder) start(hook string, attrs ...attribute.KeyValue) {
+	pr.mu.Lock()
+	pr.phases[hook] = phase{start: time.Now(), attrs: attrs}
+	pr.mu.Unlock()
+}
+
+// end records the duration of the phase identified by hook with h, if it
+// started, annotated with the error.type attribute if it failed with err.
+func (pr *phaseRecorder) end(h metric.Float64Histogram, hook string, err error) {
+	pr.mu.Lock()
+	p, ok := pr.phases[hook]
+	delete(pr.phases, hook)
+	server := pr.server
+	pr.mu.Unlock()
+	if !ok {
+		return
+	}
+	attrs := append(p.attrs, server...)
+	if err != nil {
+		attrs = append(attrs, errorType(err))
+	}
+	h.Record(pr.ctx, time.Since(p.start).Seconds(), metric.WithAttributeSet(attribute.NewSet(attrs...)))
+}
+
+// firstByte records the time to the first response byte, once per request.
+func (pr *phaseRecorder) firstByte() {
+	pr.mu.Lock()
+	start, server := pr.getConnStart, pr.server
+	ok := !start.IsZero() && !pr.recorded
+	pr.recorded = true
+	pr.mu.Unlock()
+	if !ok {
+		return
+	}
+	pr.metrics.timeToFirstByte.Record(pr.ctx, time.Since(start).Seconds(), metric.WithAttributeSet(attribute.NewSet(server...)))
+}
+
+// peerAttrs returns the network.peer.address and network.peer.port
+// attributes of addr, the address connected to.
+func peerAttrs(addr string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return []attribute.KeyValue{semconv.NetworkPeerAddress(addr)}
+	}
+	attrs := []attribute.KeyValue{semconv.NetworkPeerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetworkPeerPort(p))
+	}
+	return attrs
+}
+
+// serverAttrs returns the server.address and server.port attributes of
+// hostPort, the host and port of the request URL.
+func serverAttrs(hostPort string) []attribute.KeyValue {
+	host, port, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		return []attribute.KeyValue{semconv.ServerAddress(hostPort)}
+	}
+	attrs := []attribute.KeyValue{semconv.ServerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.ServerPort(p))
+	}
+	return attrs
+}
+
+// errorType returns the error.type attribute of a failed phase.
+func errorType(err error) attribute.KeyValue {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return semconv.ErrorTypeKey.String("timeout")
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return semconv.ErrorTypeKey.String("host_not_found")
+	}
+	return semconv.ErrorTypeOther
+}
+
+// WithMeterProvider specifies a meter provider for creating the instruments
+// recording the duration of the phases of requests, regardless of the spans
+// recorded:
+//
+//   - dns.lookup.duration: the DNS lookups, with the dns.question.name
+//     attribute.
+//   - http.client.connect.duration: the connects, with the
+//     network.peer.address and network.peer.port attributes of the address
+//     connected to.
+//   - http.client.tls.handshake.duration: the TLS handshakes.
+//   - http.client.time_to_first_byte: the time from getting a connection to
+//     receiving the first response byte, including the lookup, connect and
+//     handshake of new connections.
+//
+// All metrics have the server.address and server.port attributes of the
+// request URL, and the phases the error.type attribute if they failed. The
+// global provider is used if none is specified.
+func WithMeterProvider(provider metric.MeterProvider) ClientTraceOption {
+	return clientTraceOptionFunc(func(ct *clientTracer) {
+		if provider != nil {
+			ct.meterProvider = provider
+		}
+	})
+}
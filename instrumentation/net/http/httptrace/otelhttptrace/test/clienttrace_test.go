@@ -6,6 +6,7 @@ package test
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httptrace"
@@ -352,11 +353,16 @@ func TestWithoutSubSpans(t *testing.T) {
 			)
 			// value is dynamic, just verify we have the attribute
 			assert.Contains(t, got, attribute.Key("http.local"))
+			assert.Contains(t, got, otelhttptrace.HTTPPhaseDuration)
 		}},
 		{"http.send.start", nil},
-		{"http.send.done", nil},
+		{"http.send.done", func(t *testing.T, got attrMap) {
+			assert.Contains(t, got, otelhttptrace.HTTPPhaseDuration)
+		}},
 		{"http.receive.start", nil},
-		{"http.receive.done", nil},
+		{"http.receive.done", func(t *testing.T, got attrMap) {
+			assert.Contains(t, got, otelhttptrace.HTTPPhaseDuration)
+		}},
 	}
 	require.Len(t, recSpan.Events(), len(expectedEvents))
 	for i, e := range recSpan.Events() {
@@ -377,6 +383,45 @@ func TestWithoutSubSpans(t *testing.T) {
 	}
 }
 
+func TestWithoutSubSpansPhaseDuration(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(sr))
+
+	ctx, span := tp.Tracer("oteltest").Start(context.Background(), "root")
+	ct := otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithoutSubSpans())
+	// Ending a phase that did not start records no duration.
+	ct.TLSHandshakeDone(tls.ConnectionState{}, nil)
+	ct.DNSStart(httptrace.DNSStartInfo{Host: "example.com"})
+	ct.DNSDone(httptrace.DNSDoneInfo{})
+	span.End()
+
+	require.Len(t, sr.Ended(), 1)
+	events := sr.Ended()[0].Events()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "http.tls.done", events[0].Name)
+	assert.Empty(t, events[0].Attributes)
+
+	start, done := events[1], events[2]
+	assert.Equal(t, "http.dns.start", start.Name)
+	assert.Equal(t, "http.dns.done", done.Name)
+	var duration attribute.Value
+	for _, kv := range done.Attributes {
+		if kv.Key == otelhttptrace.HTTPPhaseDuration {
+			duration = kv.Value
+		}
+	}
+	assert.Equal(t, done.Time.Sub(start.Time).Seconds(), duration.AsFloat64())
+}
+
+func TestWithoutSubSpansNoSpan(t *testing.T) {
+	ct := otelhttptrace.NewClientTrace(context.Background(), otelhttptrace.WithoutSubSpans())
+	assert.NotPanics(t, func() {
+		ct.DNSDone(httptrace.DNSDoneInfo{})
+		ct.Got100Continue()
+	})
+}
+
 func TestWithRedactedHeaders(t *testing.T) {
 	fixture := prepareClientTraceTest(t)
 
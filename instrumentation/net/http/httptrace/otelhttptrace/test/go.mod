@@ -7,6 +7,7 @@ require (
 	go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace v0.53.0
 	go.opentelemetry.io/otel v1.28.0
 	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/sdk/metric v1.28.0
 )
 
 require (
@@ -24,3 +25,5 @@ require (
 replace go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace => ../
 
 replace go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp => ../../../otelhttp
+
+replace go.opentelemetry.io/contrib/instrumentation/matcher => ../../../../../matcher
@@ -0,0 +1,144 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/httptrace/otelhttptrace"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func collectHistograms(t *testing.T, reader sdkmetric.Reader) map[string]metricdata.Histogram[float64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	histograms := make(map[string]metricdata.Histogram[float64])
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		h, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok, "%s is not a float64 histogram", m.Name)
+		histograms[m.Name] = h
+	}
+	return histograms
+}
+
+func TestPhaseMetrics(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+	client := ts.Client()
+	// The certificate of the server is valid for example.com, not localhost.
+	client.Transport.(*http.Transport).TLSClientConfig.ServerName = "example.com"
+
+	_, port, err := net.SplitHostPort(ts.Listener.Addr().String())
+	require.NoError(t, err)
+	portNum, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	ctx := context.Background()
+	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithMeterProvider(mp)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://localhost:"+port, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	histograms := collectHistograms(t, reader)
+	server := []attribute.KeyValue{
+		attribute.String("server.address", "localhost"),
+		attribute.Int("server.port", portNum),
+	}
+	for _, name := range []string{
+		"dns.lookup.duration",
+		"http.client.connect.duration",
+		"http.client.tls.handshake.duration",
+		"http.client.time_to_first_byte",
+	} {
+		t.Run(name, func(t *testing.T) {
+			h, ok := histograms[name]
+			require.True(t, ok, "missing %s", name)
+			require.NotEmpty(t, h.DataPoints)
+			var count uint64
+			for _, dp := range h.DataPoints {
+				count += dp.Count
+				for _, kv := range server {
+					got, ok := dp.Attributes.Value(kv.Key)
+					assert.True(t, ok, "missing %s", kv.Key)
+					assert.Equal(t, kv.Value, got)
+				}
+				_, failed := dp.Attributes.Value("error.type")
+				assert.False(t, failed)
+			}
+			if name != "http.client.connect.duration" {
+				// Both IPv4 and IPv6 connects may be attempted.
+				assert.Equal(t, uint64(1), count)
+			}
+		})
+	}
+
+	dp := histograms["dns.lookup.duration"].DataPoints[0]
+	got, _ := dp.Attributes.Value("dns.question.name")
+	assert.Equal(t, "localhost", got.AsString())
+	for _, dp := range histograms["http.client.connect.duration"].DataPoints {
+		got, ok := dp.Attributes.Value("network.peer.address")
+		assert.True(t, ok, "missing network.peer.address")
+		assert.Contains(t, []string{"127.0.0.1", "::1"}, got.AsString())
+	}
+}
+
+func TestPhaseMetricsAcrossRequests(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	for i := 0; i < 3; i++ {
+		ctx := context.Background()
+		ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx, otelhttptrace.WithMeterProvider(mp)))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+		require.NoError(t, err)
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	h := collectHistograms(t, reader)["http.client.time_to_first_byte"]
+	require.Len(t, h.DataPoints, 1)
+	assert.Equal(t, uint64(3), h.DataPoints[0].Count)
+}
+
+func TestPhaseMetricsError(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	ct := otelhttptrace.NewClientTrace(context.Background(), otelhttptrace.WithMeterProvider(mp))
+	ct.GetConn("invalid.example:443")
+	ct.DNSStart(httptrace.DNSStartInfo{Host: "invalid.example"})
+	ct.DNSDone(httptrace.DNSDoneInfo{Err: &net.DNSError{Err: "no such host", Name: "invalid.example", IsNotFound: true}})
+
+	h := collectHistograms(t, reader)["dns.lookup.duration"]
+	require.Len(t, h.DataPoints, 1)
+	assert.Equal(t, attribute.NewSet(
+		attribute.String("dns.question.name", "invalid.example"),
+		attribute.String("error.type", "host_not_found"),
+		attribute.String("server.address", "invalid.example"),
+		attribute.Int("server.port", 443),
+	), h.DataPoints[0].Attributes)
+}
@@ -0,0 +1,44 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggageAttrs returns the members of the baggage of ctx named by keys as
+// attributes.
+func baggageAttrs(ctx context.Context, keys []string) []attribute.KeyValue {
+	if len(keys) == 0 {
+		return nil
+	}
+	b := baggage.FromContext(ctx)
+	if b.Len() == 0 {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	for _, key := range keys {
+		if m := b.Member(key); m.Key() != "" {
+			attrs = append(attrs, attribute.String(key, m.Value()))
+		}
+	}
+	return attrs
+}
+
+// WithBaggageMetricAttributes configures the Handler and Transport to add the
+// members of the baggage of requests named by keys, e.g. "tenant", to the
+// attributes of their duration and size metrics, under the same keys. The
+// Handler reads the baggage extracted from the request by the propagators,
+// see WithPropagators, which must include propagation.Baggage.
+//
+// Only members with a low cardinality should be listed, as every
+// combination of values is a distinct series.
+func WithBaggageMetricAttributes(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.BaggageMetricAttributes = append(c.BaggageMetricAttributes, keys...)
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/redact"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultBodySizeLimit is the default maximum number of bytes of a request or
+// response body recorded when body capture is enabled, see WithBodyCapture.
+const DefaultBodySizeLimit = 4096
+
+// DefaultBodyContentTypes are the media types of the request and response
+// bodies recorded by default when body capture is enabled, see
+// WithBodyCapture.
+var DefaultBodyContentTypes = []string{
+	"application/json",
+	"application/*+json",
+	"text/*",
+}
+
+// bodyCapturer creates the bodyCaptures of the requests and responses of a
+// Handler or Transport.
+type bodyCapturer struct {
+	limit        int
+	contentTypes []string
+	// all is true if bodies of any media type are recorded.
+	all bool
+
+	scrubPaths      []redact.JSONPath
+	scrubFormFields []string
+}
+
+// newBodyCapturer returns the bodyCapturer configured by c, or nil if body
+// capture is disabled.
+func newBodyCapturer(c *config) *bodyCapturer {
+	if !c.BodyCapture {
+		return nil
+	}
+	return &bodyCapturer{
+		limit:           c.BodySizeLimit,
+		contentTypes:    c.BodyContentTypes,
+		scrubPaths:      c.BodyScrubPaths,
+		scrubFormFields: c.BodyScrubFormFields,
+	}
+}
+
+// newDebugBodyCapturer returns the bodyCapturer of the requests debugged
+// with the header configured by c, see WithDebugHeader. It records bodies of
+// any media type, scrubbed as configured by c.
+func newDebugBodyCapturer(c *config) *bodyCapturer {
+	return &bodyCapturer{
+		limit:           c.BodySizeLimit,
+		all:             true,
+		scrubPaths:      c.BodyScrubPaths,
+		scrubFormFields: c.BodyScrubFormFields,
+	}
+}
+
+// capture returns a bodyCapture recording a body with the media type
+// contentType under key, or nil if bodies of that media type are not
+// recorded.
+func (bc *bodyCapturer) capture(key attribute.Key, contentType string) *bodyCapture {
+	if bc == nil || !bc.allowed(contentType) {
+		return nil
+	}
+	return &bodyCapture{key: key, limit: bc.limit, scrub: bc.scrubber(contentType)}
+}
+
+// allowed reports whether bodies with the media type contentType are
+// recorded.
+func (bc *bodyCapturer) allowed(contentType string) bool {
+	if bc.all {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range bc.contentTypes {
+		if ok, _ := path.Match(strings.ToLower(pattern), mediaType); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyCapture records a copy of the body of a request or response, up to a
+// size limit.
+type bodyCapture struct {
+	key   attribute.Key
+	limit int
+	// scrub redacts the recorded body, if not nil.
+	scrub func(string) string
+
+	mu        sync.Mutex
+	buf       []byte
+	truncated bool
+	recorded  atomic.Bool
+}
+
+// write appends p to the recorded body. Bytes exceeding the size limit are
+// dropped and the body is marked as truncated.
+func (c *bodyCapture) write(p []byte) {
+	if c == nil || len(p) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.limit > 0 && len(c.buf)+len(p) > c.limit {
+		p = p[:c.limit-len(c.buf)]
+		c.truncated = true
+	}
+	c.buf = append(c.buf, p...)
+}
+
+// record adds the recorded body as an event to span. It does nothing if
+// the body is empty or has already been recorded.
+func (c *bodyCapture) record(span trace.Span) {
+	if c == nil || !c.recorded.CompareAndSwap(false, true) {
+		return
+	}
+
+	c.mu.Lock()
+	body, truncated := c.buf, c.truncated
+	c.mu.Unlock()
+	if len(body) == 0 {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 2)
+	data := string(body)
+	if truncated {
+		data = redact.TrimPartialRune(data)
+		attrs = append(attrs, BodyTruncatedKey.Bool(true))
+	}
+	if c.scrub != nil {
+		data = c.scrub(data)
+	}
+	attrs = append(attrs, c.key.String(data))
+	span.AddEvent(string(c.key), trace.WithAttributes(attrs...))
+}
+
+// captureReader records the data read from a body with a bodyCapture.
+type captureReader struct {
+	io.ReadCloser
+	capture *bodyCapture
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.capture.write(p[:n])
+	return n, err
+}
+
+// WithBodyCapture configures the Handler and Transport to record request and
+// response bodies as span events named after the http.request.body and
+// http.response.body attributes holding them. Only bodies with a media type
+// allowed by WithBodyContentTypes are recorded, and only the part of a body
+// that is read or written through the instrumentation. Bodies exceeding the
+// limit set by WithBodySizeLimit are truncated and the event is annotated
+// with the http.body.truncated attribute.
+//
+// Bodies may contain sensitive data, body capture is disabled by default.
+// Sensitive values of JSON and form bodies can be redacted with
+// WithBodyScrubJSONPaths and WithBodyScrubFormFields.
+func WithBodyCapture() Option {
+	return optionFunc(func(c *config) {
+		c.BodyCapture = true
+	})
+}
+
+// WithBodySizeLimit sets the maximum number of bytes of a request or response
+// body recorded by WithBodyCapture. If n is less than or equal to zero,
+// bodies are recorded in full. The default is DefaultBodySizeLimit.
+func WithBodySizeLimit(n int) Option {
+	return optionFunc(func(c *config) {
+		c.BodySizeLimit = n
+	})
+}
+
+// WithBodyContentTypes sets the media types of the request and response
+// bodies recorded by WithBodyCapture. Media types may contain the wildcards
+// supported by path.Match, e.g. "text/*". The default is
+// DefaultBodyContentTypes.
+func WithBodyContentTypes(types ...string) Option {
+	return optionFunc(func(c *config) {
+		c.BodyContentTypes = types
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Cache statuses recorded by the http.cache.status attribute, see
+// WithCacheStatus.
+const (
+	CacheStatusHit         = "hit"         // the response was served by a cache
+	CacheStatusMiss        = "miss"        // the response was not in a cache
+	CacheStatusRevalidated = "revalidated" // the response of a cache was validated by the origin
+	CacheStatusExpired     = "expired"     // the response of a cache was stale and replaced
+	CacheStatusStale       = "stale"       // a stale response was served by a cache
+	CacheStatusBypass      = "bypass"      // the cache was not used for the request
+	CacheStatusDynamic     = "dynamic"     // the response is not cacheable
+)
+
+// CacheStatusHeader is a response header reporting the cache status of the
+// response, and the function returning the status its value reports, one
+// of the CacheStatus constants, or an empty string if it is unknown.
+type CacheStatusHeader struct {
+	Name   string
+	Status func(value string) string
+}
+
+// DefaultCacheStatusHeaders are the headers used by default to find the
+// cache status of responses, see WithCacheStatus: the Cache-Status header
+// of RFC 9211, and the CF-Cache-Status, X-Cache and X-Cache-Status headers
+// of common CDNs and proxies.
+var DefaultCacheStatusHeaders = []CacheStatusHeader{
+	{Name: "Cache-Status", Status: CacheStatusFromRFC9211},
+	{Name: "CF-Cache-Status", Status: CacheStatusFromToken},
+	{Name: "X-Cache", Status: CacheStatusFromToken},
+	{Name: "X-Cache-Status", Status: CacheStatusFromToken},
+}
+
+// CacheStatusFromToken returns the cache status reported by a header value
+// containing a word like HIT or MISS, e.g. "HIT" for CF-Cache-Status,
+// "Hit from cloudfront" or "TCP_MISS from squid" for X-Cache.
+func CacheStatusFromToken(value string) string {
+	words := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		switch word {
+		case "hit", "miss", "revalidated", "expired", "stale", "bypass", "dynamic":
+			return word
+		case "refreshhit", "updating":
+			return CacheStatusRevalidated
+		}
+	}
+	return ""
+}
+
+// CacheStatusFromRFC9211 returns the cache status reported by the value of
+// a Cache-Status header, defined by RFC 9211, e.g.
+// "ExampleCache; fwd=uri-miss". The status is that of the cache closest to
+// the client, the last one of the header.
+func CacheStatusFromRFC9211(value string) string {
+	entries := strings.Split(value, ",")
+	params := strings.Split(entries[len(entries)-1], ";")
+	if len(params) < 2 {
+		return ""
+	}
+
+	var fwd, fwdStatus string
+	for _, param := range params[1:] {
+		key, v, _ := strings.Cut(strings.TrimSpace(param), "=")
+		v = strings.Trim(v, `"`)
+		switch strings.ToLower(key) {
+		case "hit":
+			return CacheStatusHit
+		case "fwd":
+			fwd = strings.ToLower(v)
+		case "fwd-status":
+			fwdStatus = v
+		}
+	}
+	switch fwd {
+	case "":
+		return ""
+	case "bypass", "method", "request":
+		return CacheStatusBypass
+	case "stale":
+		if fwdStatus == strconv.Itoa(http.StatusNotModified) {
+			return CacheStatusRevalidated
+		}
+		return CacheStatusExpired
+	default:
+		return CacheStatusMiss
+	}
+}
+
+// cacheAttrs returns the http.cache.status and http.cache.age attributes
+// of res, finding its cache status with headers.
+func cacheAttrs(headers []CacheStatusHeader, res *http.Response) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	var status string
+	for _, h := range headers {
+		if v := res.Header.Get(h.Name); v != "" {
+			if status = h.Status(v); status != "" {
+				break
+			}
+		}
+	}
+	if status == "" && res.StatusCode == http.StatusNotModified {
+		status = CacheStatusRevalidated
+	}
+	if status != "" {
+		attrs = append(attrs, CacheStatusKey.String(status))
+	}
+	if age, err := strconv.ParseInt(res.Header.Get("Age"), 10, 64); err == nil && age >= 0 {
+		attrs = append(attrs, CacheAgeKey.Int64(age))
+	}
+	return attrs
+}
+
+// WithCacheStatus configures the Transport to annotate the spans of
+// requests with the cache status of their response, e.g. "hit" or "miss",
+// as the http.cache.status attribute, and its Age header, the seconds it
+// spent in caches, as the http.cache.age attribute.
+//
+// The cache status is reported by the first of headers present in the
+// response and with a known status, or by DefaultCacheStatusHeaders if
+// headers is empty. The status of 304 Not Modified responses is
+// "revalidated" if none of the headers report it.
+func WithCacheStatus(headers ...CacheStatusHeader) Option {
+	return optionFunc(func(c *config) {
+		c.CacheStatusHeaders = headers
+		if len(headers) == 0 {
+			c.CacheStatusHeaders = DefaultCacheStatusHeaders
+		}
+	})
+}
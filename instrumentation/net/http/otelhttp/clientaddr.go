@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// clientAddressResolver finds the address of the client that sent a request
+// through trusted proxies.
+type clientAddressResolver struct {
+	trusted []netip.Prefix
+}
+
+func newClientAddressResolver(trusted []netip.Prefix) *clientAddressResolver {
+	if len(trusted) == 0 {
+		return nil
+	}
+	return &clientAddressResolver{trusted: trusted}
+}
+
+// resolve returns the address of the client that sent r. The addresses
+// recorded by the Forwarded, X-Forwarded-For or X-Real-IP header, in this
+// order of precedence, are only used if they were added by trusted proxies:
+// the result is the last address of the chain, starting from the peer, that
+// is not a trusted proxy.
+func (c *clientAddressResolver) resolve(r *http.Request) string {
+	addr := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if !c.isTrusted(addr) {
+		return addr
+	}
+
+	hops := forwardedFor(r.Header.Values("Forwarded"))
+	if len(hops) == 0 {
+		hops = xForwardedFor(r.Header.Values("X-Forwarded-For"))
+	}
+	if len(hops) == 0 {
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			hops = []string{realIP}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr = hops[i]
+		if !c.isTrusted(addr) {
+			break
+		}
+	}
+	return addr
+}
+
+func (c *clientAddressResolver) isTrusted(addr string) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range c.trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// xForwardedFor returns the addresses of the X-Forwarded-For header values,
+// from the client to the last proxy.
+func xForwardedFor(values []string) []string {
+	var hops []string
+	for _, v := range values {
+		for _, hop := range strings.Split(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// forwardedFor returns the addresses of the "for" parameters of the
+// Forwarded header values, defined by RFC 7239, from the client to the last
+// proxy, without their port.
+func forwardedFor(values []string) []string {
+	var hops []string
+	for _, v := range values {
+		for _, elem := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok || !strings.EqualFold(key, "for") {
+					continue
+				}
+				value = strings.Trim(value, `"`)
+				if host, _, err := net.SplitHostPort(value); err == nil {
+					value = host
+				}
+				value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+				if value != "" {
+					hops = append(hops, value)
+				}
+			}
+		}
+	}
+	return hops
+}
+
+// WithTrustedProxies configures the Handler to derive the client.address
+// attribute (http.client_ip with the old semantic conventions) of spans from
+// the Forwarded, X-Forwarded-For or X-Real-IP header of requests, instead of
+// from the first X-Forwarded-For address.
+//
+// The headers are only used when the peer of the connection, e.g. a load
+// balancer, is in one of proxies, and are read from the right, skipping the
+// addresses of the other proxies in proxies: the client address is the first
+// one that is not trusted, so that it cannot be spoofed by the client. A
+// single address can be trusted with a prefix of its full length, e.g.
+// netip.PrefixFrom(addr, addr.BitLen()).
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return optionFunc(func(c *config) {
+		c.TrustedProxies = append(c.TrustedProxies, proxies...)
+	})
+}
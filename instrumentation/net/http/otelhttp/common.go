@@ -16,6 +16,57 @@ const (
 	ReadErrorKey  = attribute.Key("http.read_error")  // If an error occurred while reading a request, the string of the error (io.EOF is not recorded)
 	WroteBytesKey = attribute.Key("http.wrote_bytes") // if anything was written to the response writer, the total number of bytes written
 	WriteErrorKey = attribute.Key("http.write_error") // if an error occurred while writing a reply, the string of the error (io.EOF is not recorded)
+
+	RequestBodyKey   = attribute.Key("http.request.body")   // the recorded request body, see WithBodyCapture
+	ResponseBodyKey  = attribute.Key("http.response.body")  // the recorded response body, see WithBodyCapture
+	BodyTruncatedKey = attribute.Key("http.body.truncated") // if the recorded body exceeded the size limit and was truncated
+
+	UpgradeKey = attribute.Key("http.upgrade") // the protocol the connection was upgraded to, e.g. websocket
+
+	SamplingHintKey = attribute.Key("http.sampling_hint") // the hint given to the sampler, see WithSamplingHint
+	DebugKey        = attribute.Key("http.debug")         // if the request asked to be debugged, see WithDebugHeader
+)
+
+// Attribute keys of the events recorded by the connection trace, see
+// WithConnectionTrace.
+const (
+	DNSAddrsKey     = attribute.Key("http.dns.addrs")     // the addresses a host name resolved to
+	ConnReusedKey   = attribute.Key("http.conn.reused")   // if the connection was previously used for another request
+	ConnWasIdleKey  = attribute.Key("http.conn.wasidle")  // if the connection was obtained from the idle pool
+	ConnIdleTimeKey = attribute.Key("http.conn.idletime") // how long the connection was idle, if it was
+)
+
+// Attribute keys of stream.progress events, see WithStreamProgressInterval.
+const (
+	StreamBytesSentKey      = attribute.Key("stream.bytes_sent")      // the response bytes written so far
+	StreamEventsSentKey     = attribute.Key("stream.events_sent")     // the Server-Sent Events written so far
+	StreamSendThroughputKey = attribute.Key("stream.send_throughput") // the response bytes per second written since the previous progress event
+)
+
+// Attribute keys of hijacked connections, see WithHijackedConnTracking.
+const (
+	ConnectionHijackedKey    = attribute.Key("connection.hijacked")                // if the handler hijacked the connection
+	HijackedBytesSentKey     = attribute.Key("connection.hijacked.bytes_sent")     // the bytes written to the hijacked connection
+	HijackedBytesReceivedKey = attribute.Key("connection.hijacked.bytes_received") // the bytes read from the hijacked connection
+)
+
+// Attribute keys of the cache status of responses, see WithCacheStatus.
+const (
+	CacheStatusKey = attribute.Key("http.cache.status") // the cache status of the response, e.g. hit or miss
+	CacheAgeKey    = attribute.Key("http.cache.age")    // the Age of the response, the seconds it spent in caches
+)
+
+// Attribute keys of upload.progress events, see WithUploadProgressInterval.
+const (
+	UploadBytesReceivedKey     = attribute.Key("upload.bytes_received")     // the request body bytes read so far
+	UploadReceiveThroughputKey = attribute.Key("upload.receive_throughput") // the request body bytes per second read since the previous progress event
+	UploadWaitingKey           = attribute.Key("upload.waiting")            // if the handler is waiting for the client to send more of the body
+)
+
+// Attribute keys of slow_request events, see WithSlowRequestThreshold.
+const (
+	SlowRequestThresholdKey = attribute.Key("http.slow_request.threshold") // the slow request threshold, in seconds
+	SlowRequestDurationKey  = attribute.Key("http.slow_request.duration")  // the duration of the request, in seconds
 )
 
 // Client HTTP metrics.
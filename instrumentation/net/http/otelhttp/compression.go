@@ -0,0 +1,298 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// DefaultCompressionSizeLimit is the default maximum number of bytes a
+// compressed body is decompressed to when compression metrics are enabled,
+// see WithCompressionSizeLimit.
+const DefaultCompressionSizeLimit = 16 << 20
+
+// compressedChunks is the number of chunks of a compressed body that may be
+// waiting to be decompressed. The measurement of a body whose decompression
+// falls further behind is abandoned, so reading or writing the body never
+// waits for it.
+const compressedChunks = 64
+
+// errDecompressionStopped is the error of the decompression of a body that
+// was abandoned, because its content exceeds the size limit or because
+// decompression fell behind the transfer of the body.
+var errDecompressionStopped = errors.New("decompression stopped")
+
+// compressionMetrics measures the compressed and uncompressed sizes of the
+// compressed bodies of requests and responses.
+type compressionMetrics struct {
+	requestCompressed    metric.Int64Histogram
+	requestUncompressed  metric.Int64Histogram
+	responseCompressed   metric.Int64Histogram
+	responseUncompressed metric.Int64Histogram
+
+	// limit is the maximum number of bytes a body is decompressed to.
+	limit int64
+}
+
+// newCompressionMetrics returns the compressionMetrics of a server or
+// client, whose metric names start with prefix, e.g. "http.server". Bodies
+// are decompressed to at most limit bytes, or DefaultCompressionSizeLimit if
+// limit is not positive.
+func newCompressionMetrics(meter metric.Meter, prefix string, limit int) *compressionMetrics {
+	histogram := func(name, description string) metric.Int64Histogram {
+		h, err := meter.Int64Histogram(prefix+name, metric.WithUnit("By"), metric.WithDescription(description))
+		if err != nil {
+			handleErr(err)
+			return noop.Int64Histogram{}
+		}
+		return h
+	}
+	if limit <= 0 {
+		limit = DefaultCompressionSizeLimit
+	}
+	return &compressionMetrics{
+		requestCompressed:    histogram(".request.body.compressed_size", "Measures the size of compressed HTTP request bodies, as transferred."),
+		requestUncompressed:  histogram(".request.body.uncompressed_size", "Measures the size of compressed HTTP request bodies, once decompressed."),
+		responseCompressed:   histogram(".response.body.compressed_size", "Measures the size of compressed HTTP response bodies, as transferred."),
+		responseUncompressed: histogram(".response.body.uncompressed_size", "Measures the size of compressed HTTP response bodies, once decompressed."),
+		limit:                int64(limit),
+	}
+}
+
+// newBody returns a compressedBody counting the bytes of a body compressed
+// with the content coding encoding, or nil if the coding is not supported.
+func (m *compressionMetrics) newBody(encoding string) *compressedBody {
+	return newCompressedBody(encoding, m.limit)
+}
+
+// record measures the sizes of the request body req and the response body
+// res, if they are compressed and were fully transferred. Only the
+// compressed size of a body whose decompression was stopped is measured.
+func (m *compressionMetrics) record(ctx context.Context, req, res *compressedBody, opt metric.RecordOption) {
+	if compressed, uncompressed, err := req.sizes(); err == nil || errors.Is(err, errDecompressionStopped) {
+		m.requestCompressed.Record(ctx, compressed, opt)
+		if err == nil {
+			m.requestUncompressed.Record(ctx, uncompressed, opt)
+		}
+	}
+	if compressed, uncompressed, err := res.sizes(); err == nil || errors.Is(err, errDecompressionStopped) {
+		m.responseCompressed.Record(ctx, compressed, opt)
+		if err == nil {
+			m.responseUncompressed.Record(ctx, uncompressed, opt)
+		}
+	}
+}
+
+// compressedBody counts the bytes of a compressed body, as they are read or
+// written, and of its content, decompressed as it is transferred.
+type compressedBody struct {
+	// compressed is updated by the reader or writer of the body, e.g. the
+	// goroutine of a Transport writing a request.
+	compressed atomic.Int64
+
+	// mu guards the sends to chunks, which is closed once the body is
+	// transferred or its decompression is stopped.
+	mu      sync.Mutex
+	chunks  chan []byte
+	closed  bool
+	stopped bool
+
+	done chan struct{}
+	// uncompressed and err are set before done is closed.
+	uncompressed int64
+	err          error
+}
+
+// newCompressedBody returns a compressedBody counting the bytes of a body
+// compressed with the content coding encoding, or nil if the coding is not
+// supported. Only gzip and deflate are. The body is decompressed on a
+// separate goroutine to at most limit bytes.
+func newCompressedBody(encoding string, limit int64) *compressedBody {
+	var newReader func(io.Reader) (io.Reader, error)
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		newReader = func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }
+	case "deflate":
+		newReader = func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }
+	default:
+		return nil
+	}
+
+	b := &compressedBody{chunks: make(chan []byte, compressedChunks), done: make(chan struct{})}
+	go func() {
+		defer close(b.done)
+		zr, err := newReader(&chunkReader{chunks: b.chunks})
+		if err == nil {
+			// Reading one byte past the limit tells whether the content
+			// exceeds it.
+			b.uncompressed, err = io.Copy(io.Discard, io.LimitReader(zr, limit+1))
+			if err == nil && b.uncompressed > limit {
+				err = errDecompressionStopped
+			}
+		}
+		if err == nil {
+			// Trailing data after the compressed content is not measured.
+			err = b.stoppedErr()
+		}
+		b.err = err
+		if err != nil {
+			// The writes of the rest of the body, if any, are discarded.
+			b.stop()
+		}
+	}()
+	return b
+}
+
+// write counts p, the next bytes of the body, and queues a copy of them to
+// be decompressed. If too many bytes are already queued, the decompression
+// is stopped instead of waiting for it.
+func (b *compressedBody) write(p []byte) {
+	if b == nil || len(p) == 0 {
+		return
+	}
+	b.compressed.Add(int64(len(p)))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	select {
+	case b.chunks <- bytes.Clone(p):
+	default:
+		b.stopped = true
+		b.closed = true
+		close(b.chunks)
+	}
+}
+
+// sizes returns the sizes of the body and of its content. The error is
+// errDecompressionStopped if the decompression was stopped, or the error
+// decompressing the content, e.g. if it was not fully transferred. It must
+// be called once the body is transferred.
+func (b *compressedBody) sizes() (compressed, uncompressed int64, err error) {
+	if b == nil {
+		return 0, 0, errors.New("body not compressed")
+	}
+	b.end()
+	<-b.done
+	return b.compressed.Load(), b.uncompressed, b.err
+}
+
+// end signals that the whole body has been transferred, e.g. when it is not
+// transferred at all.
+func (b *compressedBody) end() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.chunks)
+	}
+}
+
+// stop stops the decompression of the body, e.g. if it is not transferred.
+func (b *compressedBody) stop() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	if !b.closed {
+		b.closed = true
+		close(b.chunks)
+	}
+}
+
+// stoppedErr returns errDecompressionStopped if the decompression of the
+// body was stopped.
+func (b *compressedBody) stoppedErr() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		return errDecompressionStopped
+	}
+	return nil
+}
+
+// chunkReader reads the chunks of a body queued by compressedBody.write.
+type chunkReader struct {
+	chunks <-chan []byte
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, ok := <-r.chunks
+		if !ok {
+			return 0, io.EOF
+		}
+		r.buf = chunk
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// compressedReader counts the bytes read from a compressed body.
+type compressedReader struct {
+	io.ReadCloser
+	body *compressedBody
+}
+
+func (r *compressedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.body.write(p[:n])
+	return n, err
+}
+
+// WithCompressionMetrics configures the Handler or Transport to measure the
+// sizes of the request and response bodies compressed with the gzip or
+// deflate content coding, as indicated by their Content-Encoding header, both
+// as transferred and once decompressed. The sizes are measured by the
+// http.server.request.body.compressed_size,
+// http.server.request.body.uncompressed_size,
+// http.server.response.body.compressed_size and
+// http.server.response.body.uncompressed_size histograms of a Handler, and
+// their http.client counterparts for a Transport, with the attributes of
+// the duration metric, once a body is fully transferred.
+//
+// Bodies are decompressed only to be measured, on a separate goroutine, as
+// they are read or written, to at most the limit set by
+// WithCompressionSizeLimit. Reading or writing a body never waits for its
+// decompression: if the content exceeds the limit, or if its decompression
+// falls behind, only the compressed size of the body is measured. When the Transport, e.g. http.DefaultTransport,
+// decompresses a response itself, its compressed size is unknown and only
+// its uncompressed size is measured.
+func WithCompressionMetrics() Option {
+	return optionFunc(func(c *config) {
+		c.CompressionMetrics = true
+	})
+}
+
+// WithCompressionSizeLimit sets the maximum number of bytes a compressed body
+// is decompressed to by WithCompressionMetrics. The uncompressed size of
+// bodies whose content is larger is not measured, so that small bodies of a
+// high compression ratio cannot make the Handler or Transport spend
+// unbounded time decompressing them. If n is less than or equal to zero, the
+// default DefaultCompressionSizeLimit is used.
+func WithCompressionSizeLimit(n int) Option {
+	return optionFunc(func(c *config) {
+		c.CompressionSizeLimit = n
+	})
+}
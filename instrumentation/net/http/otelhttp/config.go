@@ -5,10 +5,15 @@ package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"net/http/httptrace"
+	"net/netip"
+	"time"
 
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/redact"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -32,6 +37,60 @@ type config struct {
 	Filters           []Filter
 	SpanNameFormatter func(string, *http.Request) string
 	ClientTrace       func(context.Context) *httptrace.ClientTrace
+	ConnectionTrace   bool
+
+	ConnectionPoolMetrics bool
+	BodyCapture           bool
+	BodySizeLimit         int
+	BodyContentTypes      []string
+	BodyScrubPaths        []redact.JSONPath
+	BodyScrubFormFields   []string
+
+	CapturedRequestHeaders  []string
+	CapturedResponseHeaders []string
+
+	CapturedResponseTrailers []string
+
+	RouteFunc RouteFunc
+
+	StreamProgressInterval time.Duration
+	UploadProgressInterval time.Duration
+
+	PanicRecovery bool
+	Repanic       bool
+
+	RetryPolicy RetryPolicy
+
+	RequestAttributeExtractors  []RequestAttributeExtractor
+	ResponseAttributeExtractors []ResponseAttributeExtractor
+
+	SamplingRules []samplingRule
+
+	DebugHeader string
+	DebugSecret string
+
+	SpanStatusMapper SpanStatusMapper
+
+	MetricAttributesFn      func(*http.Request) []attribute.KeyValue
+	BaggageMetricAttributes []string
+
+	URLTemplateFunc URLTemplateFunc
+
+	GraphQLPath string
+
+	SlowRequestThreshold time.Duration
+	SlowRequestLogger    *slog.Logger
+
+	TrustedProxies []netip.Prefix
+
+	HijackedConnTracking bool
+
+	CompressionMetrics   bool
+	CompressionSizeLimit int
+
+	HostMetricsLimit int
+
+	CacheStatusHeaders []CacheStatusHeader
 
 	TracerProvider trace.TracerProvider
 	MeterProvider  metric.MeterProvider
@@ -51,8 +110,10 @@ func (o optionFunc) apply(c *config) {
 // newConfig creates a new config struct and applies opts to it.
 func newConfig(opts ...Option) *config {
 	c := &config{
-		Propagators:   otel.GetTextMapPropagator(),
-		MeterProvider: otel.GetMeterProvider(),
+		Propagators:      otel.GetTextMapPropagator(),
+		MeterProvider:    otel.GetMeterProvider(),
+		BodySizeLimit:    DefaultBodySizeLimit,
+		BodyContentTypes: DefaultBodyContentTypes,
 	}
 	for _, opt := range opts {
 		opt.apply(c)
@@ -0,0 +1,174 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Names of the spans and events recorded by the connection trace, see
+// WithConnectionTrace.
+const (
+	dnsSpanName           = "http.dns"
+	connectSpanName       = "http.connect"
+	tlsSpanName           = "http.tls"
+	gotConnEventName      = "http.got_conn"
+	firstByteEventName    = "http.first_response_byte"
+	wroteRequestEventName = "http.wrote_request"
+)
+
+// tlsVersions maps TLS versions to the tls.protocol.version attribute
+// values.
+var tlsVersions = map[uint16]string{
+	tls.VersionTLS10: "1.0",
+	tls.VersionTLS11: "1.1",
+	tls.VersionTLS12: "1.2",
+	tls.VersionTLS13: "1.3",
+}
+
+// connTracer records the phases of establishing the connection of a request
+// as child spans and events of its span.
+type connTracer struct {
+	ctx    context.Context
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	spans map[string]trace.Span
+}
+
+// newConnectionTrace returns an httptrace.ClientTrace recording the DNS
+// lookup, connect and TLS handshake of the connection of a request as child
+// spans of the span of ctx, and getting the connection, writing the request
+// and receiving the first response byte as its events.
+func newConnectionTrace(ctx context.Context, tracer trace.Tracer) *httptrace.ClientTrace {
+	ct := &connTracer{ctx: ctx, tracer: tracer, spans: make(map[string]trace.Span)}
+	return &httptrace.ClientTrace{
+		DNSStart: func(info httptrace.DNSStartInfo) {
+			ct.start(dnsSpanName, dnsSpanName, semconv.ServerAddress(info.Host))
+		},
+		DNSDone: func(info httptrace.DNSDoneInfo) {
+			addrs := make([]string, len(info.Addrs))
+			for i, addr := range info.Addrs {
+				addrs[i] = addr.String()
+			}
+			ct.end(dnsSpanName, info.Err, DNSAddrsKey.StringSlice(addrs))
+		},
+		ConnectStart: func(network, addr string) {
+			// Several addresses may be dialed concurrently.
+			ct.start(connectSpanName+" "+network+" "+addr, connectSpanName, peerAttrs(network, addr)...)
+		},
+		ConnectDone: func(network, addr string, err error) {
+			ct.end(connectSpanName+" "+network+" "+addr, err)
+		},
+		TLSHandshakeStart: func() {
+			ct.start(tlsSpanName, tlsSpanName)
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			var attrs []attribute.KeyValue
+			if err == nil {
+				attrs = append(attrs,
+					semconv.TLSResumed(state.DidResume),
+					semconv.TLSCipher(tls.CipherSuiteName(state.CipherSuite)),
+				)
+				if v, ok := tlsVersions[state.Version]; ok {
+					attrs = append(attrs, semconv.TLSProtocolVersion(v))
+				}
+			}
+			ct.end(tlsSpanName, err, attrs...)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			attrs := []attribute.KeyValue{
+				ConnReusedKey.Bool(info.Reused),
+				ConnWasIdleKey.Bool(info.WasIdle),
+			}
+			if info.WasIdle {
+				attrs = append(attrs, ConnIdleTimeKey.String(info.IdleTime.String()))
+			}
+			ct.event(gotConnEventName, attrs...)
+		},
+		WroteRequest: func(info httptrace.WroteRequestInfo) {
+			if info.Err != nil {
+				ct.event(wroteRequestEventName, attribute.String("error", info.Err.Error()))
+				return
+			}
+			ct.event(wroteRequestEventName)
+		},
+		GotFirstResponseByte: func() {
+			ct.event(firstByteEventName)
+		},
+	}
+}
+
+// peerAttrs returns the attributes of the peer at the address addr of the
+// network.
+func peerAttrs(network, addr string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.NetworkTransportKey.String(network)}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return append(attrs, semconv.NetworkPeerAddress(addr))
+	}
+	attrs = append(attrs, semconv.NetworkPeerAddress(host))
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetworkPeerPort(p))
+	}
+	return attrs
+}
+
+// start starts a child span named name, tracked as key until it is ended.
+func (ct *connTracer) start(key, name string, attrs ...attribute.KeyValue) {
+	_, span := ct.tracer.Start(ct.ctx, name,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(attrs...),
+	)
+	ct.mu.Lock()
+	ct.spans[key] = span
+	ct.mu.Unlock()
+}
+
+// end ends the child span tracked as key, recording err if it is not nil.
+func (ct *connTracer) end(key string, err error, attrs ...attribute.KeyValue) {
+	ct.mu.Lock()
+	span, ok := ct.spans[key]
+	delete(ct.spans, key)
+	ct.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// event adds an event to the span of the request.
+func (ct *connTracer) event(name string, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ct.ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// WithConnectionTrace configures the Transport to record the DNS lookup,
+// connect and TLS handshake of the connection of a request as http.dns,
+// http.connect and http.tls child spans of its span, and getting the
+// connection, writing the request and receiving the first response byte as
+// http.got_conn, http.wrote_request and http.first_response_byte span
+// events, so client latency can be broken down without using
+// otelhttptrace. It is combined with the httptrace.ClientTrace set by
+// WithClientTrace, if any.
+func WithConnectionTrace() Option {
+	return optionFunc(func(c *config) {
+		c.ConnectionTrace = true
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// debugTrigger detects the requests asking to be debugged with a header,
+// see WithDebugHeader.
+type debugTrigger struct {
+	header string
+	secret []byte
+	bodies *bodyCapturer
+}
+
+// newDebugTrigger returns the debugTrigger configured by c, or nil if
+// debugging requests is disabled.
+func newDebugTrigger(c *config) *debugTrigger {
+	if c.DebugHeader == "" || c.DebugSecret == "" {
+		return nil
+	}
+	return &debugTrigger{
+		header: http.CanonicalHeaderKey(c.DebugHeader),
+		secret: []byte(c.DebugSecret),
+		bodies: newDebugBodyCapturer(c),
+	}
+}
+
+// match reports whether the request with the header h asks to be debugged.
+func (d *debugTrigger) match(h http.Header) bool {
+	if d == nil {
+		return false
+	}
+	for _, v := range h.Values(d.header) {
+		if subtle.ConstantTimeCompare([]byte(v), d.secret) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// attrs returns the attributes of the span of a debugged request.
+func (d *debugTrigger) attrs() []attribute.KeyValue {
+	return []attribute.KeyValue{
+		DebugKey.Bool(true),
+		SamplingHintKey.String(string(SamplingHintRecord)),
+	}
+}
+
+// WithDebugHeader configures the Handler to debug the requests with the
+// header name set to secret, e.g. "X-Debug-Trace". Their span is given the
+// http.debug attribute and the SamplingHintRecord sampling hint, see
+// WithSamplingHint, and their
+// request and response bodies are recorded whatever their media type, up to
+// the size limit set with WithBodySizeLimit, as done by WithBodyCapture.
+// They are scrubbed as configured with WithBodyScrubJSONPaths and
+// WithBodyScrubFormFields.
+//
+// Whether a span is recorded is decided by the sampler of the tracer, which
+// must honor the sampling hint for the spans of debugged requests to be
+// recorded, e.g. when their parent is not sampled. The sampler returned by
+// HintBased of the go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler
+// module does it:
+//
+//	sdktrace.NewTracerProvider(sdktrace.WithSampler(
+//		sampler.HintBased(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.1))),
+//	))
+//
+// The value of the header is compared to secret in constant time, and is
+// redacted if the header is captured, see WithCapturedRequestHeaders.
+// Requests are not debugged if name or secret is empty.
+func WithDebugHeader(name, secret string) Option {
+	return optionFunc(func(c *config) {
+		c.DebugHeader = name
+		c.DebugSecret = secret
+	})
+}
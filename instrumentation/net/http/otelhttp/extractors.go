@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// RequestAttributeExtractor returns attributes describing a request, added
+// to its span.
+type RequestAttributeExtractor func(*http.Request) []attribute.KeyValue
+
+// ResponseAttributeExtractor returns attributes describing a response, added
+// to the span of its request.
+type ResponseAttributeExtractor func(*http.Response) []attribute.KeyValue
+
+func extractRequestAttributes(extractors []RequestAttributeExtractor, r *http.Request) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, extract := range extractors {
+		attrs = append(attrs, extract(r)...)
+	}
+	return attrs
+}
+
+func extractResponseAttributes(extractors []ResponseAttributeExtractor, res *http.Response) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, extract := range extractors {
+		attrs = append(attrs, extract(res)...)
+	}
+	return attrs
+}
+
+// WithRequestAttributeExtractor adds fn to the extractors of the attributes
+// of requests, e.g. the tenant or API key ID of the caller. The attributes
+// are added to the span of every request when it starts, so they are
+// available to samplers. They are not added to metrics.
+func WithRequestAttributeExtractor(fn RequestAttributeExtractor) Option {
+	return optionFunc(func(c *config) {
+		c.RequestAttributeExtractors = append(c.RequestAttributeExtractors, fn)
+	})
+}
+
+// WithResponseAttributeExtractor adds fn to the extractors of the attributes
+// of responses. The attributes are added to the span of every request once
+// its response is known. They are not added to metrics.
+//
+// For a Handler, the response passed to fn is made of the status code and
+// header written by the handler, its Request field being the request
+// handled, and has no body.
+func WithResponseAttributeExtractor(fn ResponseAttributeExtractor) Option {
+	return optionFunc(func(c *config) {
+		c.ResponseAttributeExtractors = append(c.ResponseAttributeExtractors, fn)
+	})
+}
@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/filters"
+
+import (
+	"net"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/matcher"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Matching returns a Filter that returns true if the request is matched by
+// m, so that only the requests matched by m are traced.
+//
+// The requests are described to m by their method, URL path, peer and
+// header. Their route is unknown. Their peer is the host of their
+// RemoteAddr, the client address, for requests received by a Handler, or
+// the host of their URL for requests sent by a Transport.
+func Matching(m matcher.Matcher) otelhttp.Filter {
+	return func(r *http.Request) bool {
+		return m(matcherRequest(r))
+	}
+}
+
+// NotMatching returns a Filter that returns true if the request is not
+// matched by m, so that the requests matched by m are not traced. The
+// requests are described to m as by Matching.
+func NotMatching(m matcher.Matcher) otelhttp.Filter {
+	return func(r *http.Request) bool {
+		return !m(matcherRequest(r))
+	}
+}
+
+func matcherRequest(r *http.Request) *matcher.Request {
+	peer := r.URL.Hostname()
+	if r.RemoteAddr != "" {
+		peer = r.RemoteAddr
+		if host, _, err := net.SplitHostPort(peer); err == nil {
+			peer = host
+		}
+	}
+	return &matcher.Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Peer:   peer,
+		Header: r.Header.Values,
+	}
+}
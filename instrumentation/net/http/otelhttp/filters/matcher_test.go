@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"net/http"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"go.opentelemetry.io/contrib/instrumentation/matcher"
+)
+
+func TestMatching(t *testing.T) {
+	healthChecks := matcher.Or(
+		matcher.Path("/healthz", "/probes/*"),
+		matcher.Header("User-Agent", "kube-probe/*"),
+	)
+	lb := matcher.Peer(netip.MustParsePrefix("10.0.0.0/8"))
+
+	for _, s := range []scenario{
+		{
+			name:   "matching path",
+			filter: Matching(healthChecks),
+			req:    &http.Request{URL: &url.URL{Path: "/probes/live"}},
+			exp:    true,
+		},
+		{
+			name:   "matching header",
+			filter: Matching(healthChecks),
+			req: &http.Request{
+				URL:    &url.URL{Path: "/users"},
+				Header: http.Header{"User-Agent": []string{"kube-probe/1.30"}},
+			},
+			exp: true,
+		},
+		{
+			name:   "not matching",
+			filter: Matching(healthChecks),
+			req:    &http.Request{URL: &url.URL{Path: "/users"}},
+			exp:    false,
+		},
+		{
+			name:   "not matching negated",
+			filter: NotMatching(healthChecks),
+			req:    &http.Request{URL: &url.URL{Path: "/users"}},
+			exp:    true,
+		},
+		{
+			name:   "matching remote address",
+			filter: Matching(lb),
+			req:    &http.Request{URL: &url.URL{Path: "/users"}, RemoteAddr: "10.1.2.3:4242"},
+			exp:    true,
+		},
+		{
+			name:   "matching URL host",
+			filter: Matching(matcher.PeerName("api.example.com")),
+			req:    &http.Request{URL: &url.URL{Host: "api.example.com:443", Path: "/users"}},
+			exp:    true,
+		},
+	} {
+		res := s.filter(s.req)
+		if s.exp != res {
+			t.Errorf("Failed testing %q. Expected %t, got %t", s.name, s.exp, res)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/filters"
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/matcher"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// Route returns a Filter that returns true if the request matches
+// the pattern "[METHOD ]PATH". If METHOD is omitted, requests of any
+// method match. METHOD and PATH are matched as by matcher.Method and
+// matcher.Path: PATH is matched against the request's path with
+// path.Match, e.g. "/users/*/orders" matches "/users/42/orders", except
+// that a PATH ending with "/*" also matches all the paths below it,
+// e.g. "/admin/*" matches "/admin/users/42".
+//
+// Route panics if the pattern is malformed.
+func Route(pattern string) otelhttp.Filter {
+	method, p, found := strings.Cut(pattern, " ")
+	if !found {
+		method, p = "", pattern
+	}
+	m := matcher.Path(strings.TrimSpace(p))
+	if method != "" {
+		m = matcher.And(matcher.Method(method), m)
+	}
+	return Matching(m)
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filters
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute(t *testing.T) {
+	req := func(method, p string) *http.Request {
+		return &http.Request{Method: method, URL: &url.URL{Path: p}}
+	}
+
+	for _, s := range []scenario{
+		{
+			name:   "exact path",
+			filter: Route("/healthz"),
+			req:    req(http.MethodGet, "/healthz"),
+			exp:    true,
+		},
+		{
+			name:   "other path",
+			filter: Route("/healthz"),
+			req:    req(http.MethodGet, "/healthz/live"),
+			exp:    false,
+		},
+		{
+			name:   "matching method",
+			filter: Route("POST /users"),
+			req:    req(http.MethodPost, "/users"),
+			exp:    true,
+		},
+		{
+			name:   "other method",
+			filter: Route("POST /users"),
+			req:    req(http.MethodGet, "/users"),
+			exp:    false,
+		},
+		{
+			name:   "segment wildcard",
+			filter: Route("GET /users/*/orders"),
+			req:    req(http.MethodGet, "/users/42/orders"),
+			exp:    true,
+		},
+		{
+			name:   "subtree",
+			filter: Route("/admin/*"),
+			req:    req(http.MethodDelete, "/admin/users/42"),
+			exp:    true,
+		},
+		{
+			name:   "subtree with wildcard",
+			filter: Route("/v*/admin/*"),
+			req:    req(http.MethodGet, "/v2/admin/users/42"),
+			exp:    true,
+		},
+		{
+			name:   "subtree root",
+			filter: Route("/admin/*"),
+			req:    req(http.MethodGet, "/admin"),
+			exp:    false,
+		},
+		{
+			name:   "other subtree",
+			filter: Route("/admin/*"),
+			req:    req(http.MethodGet, "/administrators/1"),
+			exp:    false,
+		},
+	} {
+		res := s.filter(s.req)
+		if s.exp != res {
+			t.Errorf("Failed testing %q. Expected %t, got %t", s.name, s.exp, res)
+		}
+	}
+
+	assert.Panics(t, func() { Route("/[") })
+}
@@ -5,6 +5,7 @@ go 1.21
 require (
 	github.com/felixge/httpsnoop v1.0.4
 	github.com/stretchr/testify v1.9.0
+	go.opentelemetry.io/contrib/instrumentation/matcher v0.53.0
 	go.opentelemetry.io/otel v1.28.0
 	go.opentelemetry.io/otel/metric v1.28.0
 	go.opentelemetry.io/otel/trace v1.28.0
@@ -17,3 +18,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace go.opentelemetry.io/contrib/instrumentation/matcher => ../../../matcher
@@ -0,0 +1,230 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// maxGraphQLBodySize is the maximum number of bytes of the body of a GraphQL
+// request read to find its operation. The operation of larger requests is
+// not recorded.
+const maxGraphQLBodySize = 64 << 10
+
+// graphQLOperation is the operation executed by a GraphQL request.
+type graphQLOperation struct {
+	typ  string
+	name string
+}
+
+// spanName returns the name of the span of the operation, following the
+// semantic conventions.
+func (op graphQLOperation) spanName() string {
+	if op.name == "" {
+		return op.typ
+	}
+	return op.typ + " " + op.name
+}
+
+func (op graphQLOperation) attrs() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.GraphqlOperationTypeKey.String(op.typ)}
+	if op.name != "" {
+		attrs = append(attrs, semconv.GraphqlOperationName(op.name))
+	}
+	return attrs
+}
+
+// graphQLInspector finds the operations executed by the GraphQL requests
+// sent to a path.
+type graphQLInspector struct {
+	path string
+}
+
+func newGraphQLInspector(path string) *graphQLInspector {
+	if path == "" {
+		return nil
+	}
+	return &graphQLInspector{path: path}
+}
+
+// inspect returns the operation executed by r, if r is a GraphQL request.
+// The body of r is read up to maxGraphQLBodySize bytes, and replaced by a
+// body returning the same content.
+func (g *graphQLInspector) inspect(r *http.Request) (graphQLOperation, bool) {
+	if g == nil || r.URL.Path != g.path {
+		return graphQLOperation{}, false
+	}
+
+	var document, name string
+	switch r.Method {
+	case http.MethodGet:
+		q := r.URL.Query()
+		document, name = q.Get("query"), q.Get("operationName")
+	case http.MethodPost:
+		if r.Body == nil || r.Body == http.NoBody {
+			return graphQLOperation{}, false
+		}
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || (mediaType != "application/json" && mediaType != "application/graphql") {
+			return graphQLOperation{}, false
+		}
+		buf, err := io.ReadAll(io.LimitReader(r.Body, maxGraphQLBodySize+1))
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), r.Body), r.Body}
+		if err != nil || len(buf) > maxGraphQLBodySize {
+			return graphQLOperation{}, false
+		}
+
+		if mediaType == "application/graphql" {
+			document, name = string(buf), r.URL.Query().Get("operationName")
+			break
+		}
+		var params struct {
+			Query         string `json:"query"`
+			OperationName string `json:"operationName"`
+		}
+		if json.Unmarshal(buf, &params) != nil {
+			return graphQLOperation{}, false
+		}
+		document, name = params.Query, params.OperationName
+	default:
+		return graphQLOperation{}, false
+	}
+	return parseGraphQLOperation(document, name)
+}
+
+// parseGraphQLOperation returns the operation of the GraphQL document named
+// name, or its first operation if name is empty.
+func parseGraphQLOperation(document, name string) (graphQLOperation, bool) {
+	var (
+		depth      int  // of braces, outside parentheses
+		parenDepth int  // of parentheses, e.g. variable definitions
+		pending    bool // a definition starts, its selection set is next
+		first      *graphQLOperation
+	)
+	found := func(op graphQLOperation) (graphQLOperation, bool) {
+		if op.name == name {
+			return op, true
+		}
+		if first == nil {
+			first = &op
+		}
+		return graphQLOperation{}, false
+	}
+
+	for i := 0; i < len(document); i++ {
+		c := document[i]
+		switch {
+		case c == '#':
+			// A comment, up to the end of the line.
+			for i < len(document) && document[i] != '\n' && document[i] != '\r' {
+				i++
+			}
+		case c == '"':
+			i = skipGraphQLString(document, i)
+		case c == '(':
+			parenDepth++
+		case c == ')':
+			parenDepth--
+		case parenDepth > 0:
+		case c == '{':
+			if depth == 0 && !pending {
+				// The shorthand of an anonymous query.
+				if op, ok := found(graphQLOperation{typ: "query"}); ok {
+					return op, true
+				}
+			}
+			pending = false
+			depth++
+		case c == '}':
+			depth--
+		case depth == 0 && !pending && isGraphQLNameStart(c):
+			keyword := graphQLName(document, i)
+			i += len(keyword) - 1
+			switch keyword {
+			case "query", "mutation", "subscription":
+				op := graphQLOperation{typ: keyword}
+				j := i + 1
+				for j < len(document) && strings.IndexByte(" \t\n\r,", document[j]) >= 0 {
+					j++
+				}
+				if j < len(document) && isGraphQLNameStart(document[j]) {
+					op.name = graphQLName(document, j)
+					i = j + len(op.name) - 1
+				}
+				if op, ok := found(op); ok {
+					return op, true
+				}
+				pending = true
+			case "fragment":
+				pending = true
+			}
+		}
+	}
+
+	if name == "" && first != nil {
+		return *first, true
+	}
+	return graphQLOperation{}, false
+}
+
+// skipGraphQLString returns the index of the end of the string or block
+// string starting at the index i of document.
+func skipGraphQLString(document string, i int) int {
+	if strings.HasPrefix(document[i:], `"""`) {
+		end := strings.Index(document[i+3:], `"""`)
+		if end < 0 {
+			return len(document)
+		}
+		return i + 3 + end + 2
+	}
+	for i++; i < len(document); i++ {
+		switch document[i] {
+		case '\\':
+			i++
+		case '"', '\n':
+			return i
+		}
+	}
+	return i
+}
+
+func isGraphQLNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// graphQLName returns the name starting at the index i of document.
+func graphQLName(document string, i int) string {
+	j := i
+	for j < len(document) && (isGraphQLNameStart(document[j]) || (document[j] >= '0' && document[j] <= '9')) {
+		j++
+	}
+	return document[i:j]
+}
+
+// WithGraphQLPath configures the Handler to name the spans of the GraphQL
+// requests sent to path, e.g. "/graphql", after the operation they execute,
+// e.g. "query GetUser", and annotate them with the graphql.operation.type
+// and graphql.operation.name attributes, following the semantic
+// conventions.
+//
+// The operation is read from the query parameters of GET requests, and from
+// the body of POST requests with the application/json or
+// application/graphql media type, if it is not larger than 64 KiB. The
+// handler reads the same body.
+func WithGraphQLPath(path string) Option {
+	return optionFunc(func(c *config) {
+		c.GraphQLPath = path
+	})
+}
@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGraphQLOperation(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		opName   string
+		want     graphQLOperation
+		wantOK   bool
+	}{
+		{
+			name:     "named query",
+			document: `query GetUser($id: ID!) { user(id: $id) { name } }`,
+			want:     graphQLOperation{typ: "query", name: "GetUser"},
+			wantOK:   true,
+		},
+		{
+			name:     "shorthand query",
+			document: `{ user { name } }`,
+			want:     graphQLOperation{typ: "query"},
+			wantOK:   true,
+		},
+		{
+			name:     "anonymous mutation",
+			document: `mutation { like(id: 1) }`,
+			want:     graphQLOperation{typ: "mutation"},
+			wantOK:   true,
+		},
+		{
+			name: "selected operation",
+			document: `
+				# query Commented { x }
+				fragment F on User { name }
+				query A { a }
+				subscription B($f: Filter = {query: "mutation C { }"}) { b(s: """{ query D""") }
+			`,
+			opName: "B",
+			want:   graphQLOperation{typ: "subscription", name: "B"},
+			wantOK: true,
+		},
+		{
+			name:     "first operation",
+			document: `fragment F on User { name } query A { ...F } mutation B { b }`,
+			want:     graphQLOperation{typ: "query", name: "A"},
+			wantOK:   true,
+		},
+		{
+			name:     "missing operation",
+			document: `query A { a }`,
+			opName:   "B",
+		},
+		{
+			name:     "not a document",
+			document: `fragment F on User { name }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseGraphQLOperation(tt.document, tt.opName)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
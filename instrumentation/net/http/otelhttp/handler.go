@@ -4,7 +4,12 @@
 package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 
 import (
+	"bufio"
+	"context"
+	"net"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/felixge/httpsnoop"
@@ -12,6 +17,9 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/request"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconv"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -21,15 +29,38 @@ type middleware struct {
 	operation string
 	server    string
 
-	tracer            trace.Tracer
-	propagators       propagation.TextMapPropagator
-	spanStartOptions  []trace.SpanStartOption
-	readEvent         bool
-	writeEvent        bool
-	filters           []Filter
-	spanNameFormatter func(string, *http.Request) string
-	publicEndpoint    bool
-	publicEndpointFn  func(*http.Request) bool
+	tracer             trace.Tracer
+	propagators        propagation.TextMapPropagator
+	spanStartOptions   []trace.SpanStartOption
+	readEvent          bool
+	writeEvent         bool
+	filters            []Filter
+	spanNameFormatter  func(string, *http.Request) string
+	publicEndpoint     bool
+	publicEndpointFn   func(*http.Request) bool
+	bodies             *bodyCapturer
+	requestHeaders     *headerCapturer
+	responseHeaders    *headerCapturer
+	responseTrailers   *headerCapturer
+	routeFunc          RouteFunc
+	streamProgress     time.Duration
+	uploadProgress     time.Duration
+	uploadThroughput   metric.Float64Histogram
+	panicRecovery      bool
+	repanic            bool
+	panicCounter       metric.Int64Counter
+	requestExtractors  []RequestAttributeExtractor
+	responseExtractors []ResponseAttributeExtractor
+	samplingRules      []samplingRule
+	debug              *debugTrigger
+	statusMapper       SpanStatusMapper
+	metricAttributesFn func(*http.Request) []attribute.KeyValue
+	baggageKeys        []string
+	graphQL            *graphQLInspector
+	slowRequests       *slowRequests
+	clientAddress      *clientAddressResolver
+	trackHijackedConns bool
+	compression        *compressionMetrics
 
 	semconv semconv.HTTPServer
 }
@@ -78,9 +109,60 @@ func (h *middleware) configure(c *config) {
 	h.publicEndpoint = c.PublicEndpoint
 	h.publicEndpointFn = c.PublicEndpointFn
 	h.server = c.ServerName
+	h.bodies = newBodyCapturer(c)
+	h.requestHeaders = newHeaderCapturer("http.request.header.", c.CapturedRequestHeaders)
+	h.responseHeaders = newHeaderCapturer("http.response.header.", c.CapturedResponseHeaders)
+	h.responseTrailers = newHeaderCapturer("http.response.trailer.", c.CapturedResponseTrailers)
+	h.routeFunc = c.RouteFunc
+	h.streamProgress = c.StreamProgressInterval
+	h.uploadProgress = c.UploadProgressInterval
+	if h.uploadProgress > 0 {
+		h.uploadThroughput = newUploadThroughputHistogram(c.Meter)
+	}
+	h.panicRecovery = c.PanicRecovery
+	h.repanic = c.Repanic
+	if c.PanicRecovery {
+		h.panicCounter = newPanicCounter(c.Meter)
+	}
+	h.requestExtractors = c.RequestAttributeExtractors
+	h.responseExtractors = c.ResponseAttributeExtractors
+	h.samplingRules = c.SamplingRules
+	h.debug = newDebugTrigger(c)
+	h.statusMapper = c.SpanStatusMapper
+	h.metricAttributesFn = c.MetricAttributesFn
+	h.baggageKeys = c.BaggageMetricAttributes
+	h.graphQL = newGraphQLInspector(c.GraphQLPath)
+	h.slowRequests = newSlowRequests(c)
+	h.clientAddress = newClientAddressResolver(c.TrustedProxies)
+	h.trackHijackedConns = c.HijackedConnTracking
+	if c.CompressionMetrics {
+		h.compression = newCompressionMetrics(c.Meter, "http.server", c.CompressionSizeLimit)
+	}
+	if h.debug != nil {
+		h.requestHeaders.redact(h.debug.header)
+	}
 	h.semconv = semconv.NewHTTPServer(c.Meter)
 }
 
+// metricAttributes returns the additional attributes of the metrics of r,
+// whose baggage is in ctx.
+func (h *middleware) metricAttributes(ctx context.Context, r *http.Request, labeler *Labeler) []attribute.KeyValue {
+	attrs := append(labeler.Get(), baggageAttrs(ctx, h.baggageKeys)...)
+	if h.metricAttributesFn != nil {
+		attrs = append(attrs, h.metricAttributesFn(r)...)
+	}
+	return attrs
+}
+
+// spanStatus returns the status of the span of r, whose response has the
+// status code statusCode.
+func (h *middleware) spanStatus(r *http.Request, statusCode int) (codes.Code, string) {
+	if h.statusMapper != nil {
+		return h.statusMapper(r, statusCode)
+	}
+	return h.semconv.Status(statusCode)
+}
+
 func handleErr(err error) {
 	if err != nil {
 		otel.Handle(err)
@@ -102,6 +184,32 @@ func (h *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http
 	ctx := h.propagators.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 	opts := []trace.SpanStartOption{
 		trace.WithAttributes(h.semconv.RequestTraceAttrs(h.server, r)...),
+		trace.WithAttributes(h.requestHeaders.attrs(r.Header)...),
+		trace.WithAttributes(extractRequestAttributes(h.requestExtractors, r)...),
+	}
+	if h.clientAddress != nil {
+		// Overrides the client address of the X-Forwarded-For header.
+		opts = append(opts, trace.WithAttributes(h.semconv.ClientAddress(h.clientAddress.resolve(r))...))
+	}
+	bodies := h.bodies
+	if h.debug.match(r.Header) {
+		opts = append(opts, trace.WithAttributes(h.debug.attrs()...))
+		bodies = h.debug.bodies
+	} else {
+		opts = append(opts, trace.WithAttributes(samplingHintAttrs(h.samplingRules, r)...))
+	}
+	spanName := h.spanNameFormatter(h.operation, r)
+	var route string
+	if h.routeFunc != nil {
+		route = h.routeFunc(r)
+	}
+	if route != "" {
+		opts = append(opts, trace.WithAttributes(h.semconv.Route(route)))
+		spanName = routeSpanName(r.Method, route)
+	}
+	if op, ok := h.graphQL.inspect(r); ok {
+		opts = append(opts, trace.WithAttributes(op.attrs()...))
+		spanName = op.spanName()
 	}
 
 	opts = append(opts, h.spanStartOptions...)
@@ -123,8 +231,21 @@ func (h *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http
 		}
 	}
 
-	ctx, span := tracer.Start(ctx, h.spanNameFormatter(h.operation, r), opts...)
-	defer span.End()
+	ctx, span := tracer.Start(ctx, spanName, opts...)
+	h.semconv.AddActiveRequests(ctx, r, 1)
+
+	// ended is set once end, defined below, has recorded the telemetry of
+	// the request. tracked is set when the connection is hijacked and
+	// tracked, end is then called when the connection is closed, which can
+	// be after the handler returns.
+	var ended, tracked atomic.Bool
+	defer func() {
+		// The handler panicked and the panic is not recovered.
+		if !ended.Load() && !tracked.Load() {
+			span.End()
+			h.semconv.AddActiveRequests(ctx, r, -1)
+		}
+	}()
 
 	readRecordFunc := func(int64) {}
 	if h.readEvent {
@@ -136,6 +257,26 @@ func (h *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http
 	// if request body is nil or NoBody, we don't want to mutate the body as it
 	// will affect the identity of it in an unforeseeable way because we assert
 	// ReadCloser fulfills a certain interface and it is indeed nil or NoBody.
+	var reqBody *bodyCapture
+	if r.Body != nil && r.Body != http.NoBody {
+		reqBody = bodies.capture(RequestBodyKey, r.Header.Get("Content-Type"))
+		if reqBody != nil {
+			r.Body = &captureReader{ReadCloser: r.Body, capture: reqBody}
+		}
+	}
+	var reqCompressed *compressedBody
+	if h.compression != nil && r.Body != nil && r.Body != http.NoBody {
+		reqCompressed = h.compression.newBody(r.Header.Get("Content-Encoding"))
+		if reqCompressed != nil {
+			r.Body = &compressedReader{ReadCloser: r.Body, body: reqCompressed}
+		}
+	}
+	var upload *uploadProgress
+	if h.uploadProgress > 0 && r.Body != nil && r.Body != http.NoBody {
+		upload = newUploadProgress(r.Body, h.uploadProgress, span)
+		r.Body = upload
+	}
+	defer upload.finish()
 	bw := request.NewBodyWrapper(r.Body, readRecordFunc)
 	if r.Body != nil && r.Body != http.NoBody {
 		r.Body = bw
@@ -150,6 +291,124 @@ func (h *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http
 
 	rww := request.NewRespWriterWrapper(w, writeRecordFunc)
 
+	var stream *streamProgress
+	if h.streamProgress > 0 {
+		stream = newStreamProgress(h.streamProgress, span)
+	}
+	// The reporting stops even if the handler panics and the request is
+	// not ended.
+	defer stream.finish()
+
+	write, flush := rww.Write, rww.Flush
+	var respBody *bodyCapture
+	var respCompressed *compressedBody
+	if bodies != nil || stream != nil || h.compression != nil {
+		var checked bool
+		write = func(p []byte) (int, error) {
+			if !checked {
+				// The media type is known once the first byte is written,
+				// net/http sniffs it if the handler does not set it.
+				checked = true
+				contentType := rww.Header().Get("Content-Type")
+				if contentType == "" {
+					contentType = http.DetectContentType(p)
+				}
+				respBody = bodies.capture(ResponseBodyKey, contentType)
+				stream.setContentType(contentType)
+				if h.compression != nil {
+					respCompressed = h.compression.newBody(rww.Header().Get("Content-Encoding"))
+				}
+			}
+			n, err := rww.Write(p)
+			respBody.write(p[:n])
+			stream.write(p[:n])
+			respCompressed.write(p[:n])
+			return n, err
+		}
+	}
+	if stream != nil {
+		flush = func() {
+			rww.Flush()
+			stream.start()
+		}
+	}
+
+	labeler, found := LabelerFromContext(ctx)
+	if !found {
+		ctx = ContextWithLabeler(ctx, labeler)
+	}
+	if route != "" {
+		labeler.Add(h.semconv.Route(route))
+	}
+
+	// end records the telemetry of the request once it is done, which is
+	// either when the handler returns or panics, or when the connection is
+	// upgraded. If err is not nil, it sets the span status.
+	end := func(statusCode int, err error) {
+		if !ended.CompareAndSwap(false, true) {
+			return
+		}
+
+		stream.end()
+		reqBody.record(span)
+		respBody.record(span)
+
+		bytesWritten := rww.BytesWritten()
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		} else if statusCode != 0 {
+			// The status of hijacked connections can be unknown.
+			span.SetStatus(h.spanStatus(r, statusCode))
+		}
+		span.SetAttributes(h.responseHeaders.attrs(rww.Header())...)
+		if h.responseTrailers != nil {
+			span.SetAttributes(h.responseTrailers.attrs(handlerTrailers(rww.Header()))...)
+		}
+		span.SetAttributes(h.semconv.ResponseTraceAttrs(semconv.ResponseTelemetry{
+			StatusCode: statusCode,
+			ReadBytes:  bw.BytesRead(),
+			ReadError:  bw.Error(),
+			WriteBytes: bytesWritten,
+			WriteError: rww.Error(),
+		})...)
+		if len(h.responseExtractors) > 0 {
+			span.SetAttributes(extractResponseAttributes(h.responseExtractors, &http.Response{
+				Status:     strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+				StatusCode: statusCode,
+				Proto:      r.Proto,
+				ProtoMajor: r.ProtoMajor,
+				ProtoMinor: r.ProtoMinor,
+				Header:     rww.Header(),
+				Request:    r,
+			})...)
+		}
+
+		elapsed := time.Since(requestStartTime)
+		// Use floating point division here for higher precision (instead of Millisecond method).
+		elapsedTime := float64(elapsed) / float64(time.Millisecond)
+
+		metricAttrs := h.metricAttributes(ctx, r, labeler)
+		if h.slowRequests != nil || upload != nil || h.compression != nil {
+			attrs := h.semconv.MetricAttributes(h.server, r, statusCode, metricAttrs)
+			h.slowRequests.record(ctx, span, r, statusCode, elapsed, attrs)
+			upload.end(ctx, h.uploadThroughput, attrs)
+			if h.compression != nil {
+				h.compression.record(ctx, reqCompressed, respCompressed, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+			}
+		}
+		h.semconv.RecordMetrics(ctx, semconv.MetricData{
+			ServerName:           h.server,
+			Req:                  r,
+			StatusCode:           statusCode,
+			AdditionalAttributes: metricAttrs,
+			RequestSize:          bw.BytesRead(),
+			ResponseSize:         bytesWritten,
+			ElapsedTime:          elapsedTime,
+		})
+		span.End()
+		h.semconv.AddActiveRequests(ctx, r, -1)
+	}
+
 	// Wrap w to use our ResponseWriter methods while also exposing
 	// other interfaces that w may implement (http.CloseNotifier,
 	// http.Flusher, http.Hijacker, http.Pusher, io.ReaderFrom).
@@ -159,50 +418,89 @@ func (h *middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http
 			return rww.Header
 		},
 		Write: func(httpsnoop.WriteFunc) httpsnoop.WriteFunc {
-			return rww.Write
+			return write
 		},
 		WriteHeader: func(httpsnoop.WriteHeaderFunc) httpsnoop.WriteHeaderFunc {
 			return rww.WriteHeader
 		},
 		Flush: func(httpsnoop.FlushFunc) httpsnoop.FlushFunc {
-			return rww.Flush
+			return flush
+		},
+		Hijack: func(hijack httpsnoop.HijackFunc) httpsnoop.HijackFunc {
+			return func() (net.Conn, *bufio.ReadWriter, error) {
+				conn, brw, err := hijack()
+				if err != nil {
+					return conn, brw, err
+				}
+				span.SetAttributes(ConnectionHijackedKey.Bool(true))
+
+				// The span of an upgraded connection ends with the upgrade,
+				// see StartUpgradedConnSpan.
+				if protocol := upgradeProtocol(r); protocol != "" {
+					span.SetAttributes(UpgradeKey.String(protocol))
+					end(http.StatusSwitchingProtocols, nil)
+					return conn, brw, err
+				}
+				if !h.trackHijackedConns {
+					// The response is written to conn, its status is
+					// unknown.
+					end(0, nil)
+					return conn, brw, err
+				}
+
+				tracked.Store(true)
+				hc := newHijackedConn(conn, func(hc *hijackedConn) {
+					span.SetAttributes(
+						HijackedBytesSentKey.Int64(hc.sent.Load()),
+						HijackedBytesReceivedKey.Int64(hc.received.Load()),
+					)
+					end(hc.statusCode(), nil)
+				})
+				return hc, hc.wrap(brw), nil
+			}
 		},
 	})
 
-	labeler, found := LabelerFromContext(ctx)
-	if !found {
-		ctx = ContextWithLabeler(ctx, labeler)
+	if h.panicRecovery {
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler { // nolint: errorlint  // The value is compared, as net/http does.
+				end(rww.StatusCode(), nil)
+				panic(v)
+			}
+
+			err := &PanicError{Value: v}
+			span.RecordError(err, trace.WithStackTrace(true))
+			h.panicCounter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(
+				h.semconv.MetricAttributes(h.server, r, 0, labeler.Get())...,
+			)))
+			if h.repanic {
+				end(http.StatusInternalServerError, err)
+				panic(v)
+			}
+			if !rww.WroteHeader() {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			end(rww.StatusCode(), err)
+		}()
 	}
 
 	next.ServeHTTP(w, r.WithContext(ctx))
 
-	statusCode := rww.StatusCode()
-	bytesWritten := rww.BytesWritten()
-	span.SetStatus(h.semconv.Status(statusCode))
-	span.SetAttributes(h.semconv.ResponseTraceAttrs(semconv.ResponseTelemetry{
-		StatusCode: statusCode,
-		ReadBytes:  bw.BytesRead(),
-		ReadError:  bw.Error(),
-		WriteBytes: bytesWritten,
-		WriteError: rww.Error(),
-	})...)
-
-	// Use floating point division here for higher precision (instead of Millisecond method).
-	elapsedTime := float64(time.Since(requestStartTime)) / float64(time.Millisecond)
-
-	h.semconv.RecordMetrics(ctx, semconv.MetricData{
-		ServerName:           h.server,
-		Req:                  r,
-		StatusCode:           statusCode,
-		AdditionalAttributes: labeler.Get(),
-		RequestSize:          bw.BytesRead(),
-		ResponseSize:         bytesWritten,
-		ElapsedTime:          elapsedTime,
-	})
+	// The span of a tracked hijacked connection ends when it is closed.
+	if !tracked.Load() {
+		end(rww.StatusCode(), nil)
+	}
 }
 
 // WithRouteTag annotates spans and metrics with the provided route name
 // with HTTP route attribute.
+//
+// To annotate requests with the route matched by a router instead of
+// wrapping every handler, see NewRouteMiddleware and WithRouteFunc.
 func WithRouteTag(route string, h http.Handler) http.Handler {
 	attr := semconv.NewHTTPServer(nil).Route(route)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
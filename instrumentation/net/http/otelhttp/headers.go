@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// redactedHeaderValue replaces the values of captured headers carrying
+// credentials.
+const redactedHeaderValue = "REDACTED"
+
+// redactedHeaders are the canonical names of the headers whose values are
+// always redacted when captured.
+var redactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// headerCapturer records selected headers as attributes.
+type headerCapturer struct {
+	// names maps the canonical names of the captured headers to the
+	// attribute keys recording them.
+	names map[string]attribute.Key
+	// redacted holds the canonical names of additional headers whose
+	// values are redacted.
+	redacted map[string]bool
+}
+
+// newHeaderCapturer returns a headerCapturer recording the headers named by
+// names under attribute keys prefixed with prefix, or nil if names is
+// empty.
+func newHeaderCapturer(prefix string, names []string) *headerCapturer {
+	if len(names) == 0 {
+		return nil
+	}
+	hc := &headerCapturer{names: make(map[string]attribute.Key, len(names))}
+	for _, name := range names {
+		hc.names[http.CanonicalHeaderKey(name)] = attribute.Key(prefix + strings.ToLower(name))
+	}
+	return hc
+}
+
+// attrs returns the attributes recording the captured headers present in h.
+func (hc *headerCapturer) attrs(h http.Header) []attribute.KeyValue {
+	if hc == nil {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	for name, key := range hc.names {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		if redactedHeaders[name] || hc.redacted[name] {
+			redacted := make([]string, len(values))
+			for i := range redacted {
+				redacted[i] = redactedHeaderValue
+			}
+			values = redacted
+		}
+		attrs = append(attrs, key.StringSlice(values))
+	}
+	return attrs
+}
+
+// redact redacts the values of the header name when captured.
+func (hc *headerCapturer) redact(name string) {
+	if hc == nil {
+		return
+	}
+	if hc.redacted == nil {
+		hc.redacted = make(map[string]bool)
+	}
+	hc.redacted[http.CanonicalHeaderKey(name)] = true
+}
+
+// WithCapturedRequestHeaders configures the Handler and Transport to record
+// the request headers named by headers as http.request.header.<key> span
+// attributes, where <key> is the lowercase header name, following the
+// semantic conventions. The values of the Authorization,
+// Proxy-Authorization and Cookie headers are redacted.
+func WithCapturedRequestHeaders(headers ...string) Option {
+	return optionFunc(func(c *config) {
+		c.CapturedRequestHeaders = append(c.CapturedRequestHeaders, headers...)
+	})
+}
+
+// WithCapturedResponseHeaders configures the Handler and Transport to record
+// the response headers named by headers as http.response.header.<key> span
+// attributes, where <key> is the lowercase header name, following the
+// semantic conventions. The values of the Set-Cookie header are redacted.
+func WithCapturedResponseHeaders(headers ...string) Option {
+	return optionFunc(func(c *config) {
+		c.CapturedResponseHeaders = append(c.CapturedResponseHeaders, headers...)
+	})
+}
@@ -0,0 +1,132 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// maxStatusLineSize is the maximum size of the status line written to a
+// hijacked connection that is parsed for its status code.
+const maxStatusLineSize = 1024
+
+// hijackedConn is a connection hijacked from a Handler, whose bytes are
+// counted until it is closed.
+type hijackedConn struct {
+	net.Conn
+
+	sent     atomic.Int64
+	received atomic.Int64
+
+	// statusLine is the start of the data written to the connection, until
+	// the end of its first line, if it is a response.
+	mu         sync.Mutex
+	statusLine []byte
+	statusDone bool
+	status     int
+
+	closeOnce sync.Once
+	onClose   func(*hijackedConn)
+}
+
+func newHijackedConn(conn net.Conn, onClose func(*hijackedConn)) *hijackedConn {
+	return &hijackedConn{Conn: conn, onClose: onClose}
+}
+
+// wrap returns a bufio.ReadWriter reading and writing through c what brw,
+// returned with c by the hijack, reads and writes.
+func (c *hijackedConn) wrap(brw *bufio.ReadWriter) *bufio.ReadWriter {
+	// The data buffered by brw before the hijack, e.g. the start of a
+	// tunneled stream, is read first.
+	buffered, _ := brw.Reader.Peek(brw.Reader.Buffered())
+	c.received.Add(int64(len(buffered)))
+	var r io.Reader = c
+	if len(buffered) > 0 {
+		r = io.MultiReader(bytes.NewReader(bytes.Clone(buffered)), c)
+	}
+
+	w := brw.Writer
+	if w.Buffered() == 0 {
+		w = bufio.NewWriter(c)
+	}
+	return bufio.NewReadWriter(bufio.NewReader(r), w)
+}
+
+func (c *hijackedConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.received.Add(int64(n))
+	return n, err
+}
+
+func (c *hijackedConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.sent.Add(int64(n))
+	c.parseStatus(p[:n])
+	return n, err
+}
+
+// parseStatus parses the status code of the response written to c, e.g.
+// "HTTP/1.1 200 Connection established" in response to CONNECT, from the
+// data p written to it.
+func (c *hijackedConn) parseStatus(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.statusDone {
+		return
+	}
+	end := bytes.IndexByte(p, '\n')
+	if end < 0 {
+		c.statusLine = append(c.statusLine, p...)
+		if len(c.statusLine) > maxStatusLineSize {
+			c.statusDone = true
+		}
+		return
+	}
+	c.statusLine = append(c.statusLine, p[:end]...)
+	c.statusDone = true
+
+	fields := strings.Fields(string(c.statusLine))
+	if len(fields) >= 2 && strings.HasPrefix(fields[0], "HTTP/") {
+		if code, err := strconv.Atoi(fields[1]); err == nil && code >= 100 && code < 600 {
+			c.status = code
+		}
+	}
+}
+
+// statusCode returns the status code of the response written to c, or zero
+// if it is unknown.
+func (c *hijackedConn) statusCode() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+func (c *hijackedConn) Close() error {
+	err := c.Conn.Close()
+	c.closeOnce.Do(func() { c.onClose(c) })
+	return err
+}
+
+// WithHijackedConnTracking configures the Handler to keep the span of a
+// request whose connection is hijacked, e.g. to tunnel a CONNECT request,
+// until the handler closes the connection, instead of ending it with the
+// hijack. The bytes sent and received on the connection are recorded by the
+// connection.hijacked.bytes_sent and connection.hijacked.bytes_received
+// attributes, and the status code of the response the handler writes to the
+// connection, if any, as for other requests.
+//
+// The spans of upgraded connections, see StartUpgradedConnSpan, still end
+// with the upgrade.
+func WithHijackedConnTracking() Option {
+	return optionFunc(func(c *config) {
+		c.HijackedConnTracking = true
+	})
+}
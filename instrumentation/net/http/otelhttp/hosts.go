@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconvutil"
+	"go.opentelemetry.io/otel/attribute"
+	semconvOld "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// OtherHost is the server.address attribute of the metrics of the requests
+// sent to hosts beyond the limit set by WithHostMetricsLimit.
+const OtherHost = "_other"
+
+// hostLimiter bounds the number of distinct hosts of the metrics of a
+// Transport.
+type hostLimiter struct {
+	max int
+
+	mu    sync.Mutex
+	hosts map[string]struct{}
+}
+
+func newHostLimiter(max int) *hostLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &hostLimiter{max: max, hosts: make(map[string]struct{}, max)}
+}
+
+// host returns host if it is one of the first max distinct hosts seen, or
+// OtherHost.
+func (l *hostLimiter) host(host string) (string, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.hosts[host]; ok {
+		return host, true
+	}
+	if len(l.hosts) >= l.max {
+		return OtherHost, false
+	}
+	l.hosts[host] = struct{}{}
+	return host, true
+}
+
+// requestMetricAttrs returns the attributes of the metrics of the request r
+// describing it. If the hosts of the metrics are limited, r is described by
+// its server.address, instead of its net.peer.name and net.peer.port.
+func requestMetricAttrs(r *http.Request, hosts *hostLimiter) []attribute.KeyValue {
+	attrs := semconvutil.HTTPClientRequestMetrics(r)
+	if hosts == nil {
+		return attrs
+	}
+
+	host, _ := hosts.host(r.URL.Hostname())
+	filtered := attrs[:0]
+	for _, kv := range attrs {
+		if kv.Key != semconvOld.NetPeerNameKey && kv.Key != semconvOld.NetPeerPortKey {
+			filtered = append(filtered, kv)
+		}
+	}
+	return append(filtered, semconv.ServerAddress(host))
+}
+
+// WithHostMetricsLimit configures the Transport to break down its metrics by
+// the server.address attribute, the host requests are sent to, for up to max
+// distinct hosts. The metrics of the requests sent to other hosts have the
+// OtherHost server.address, so that clients of many backends get the
+// metrics of each of them while their cardinality is bounded. The hosts
+// counted are the first ones requests are sent to.
+//
+// The net.peer.name and net.peer.port attributes, which are not bounded,
+// are not added to the metrics when the hosts are limited, and the
+// server.port attribute of the connection pool metrics, see
+// WithConnectionPoolMetrics, is not added to those of the other hosts.
+//
+// Hosts are not limited if max is not positive, which is the default.
+func WithHostMetricsLimit(max int) Option {
+	return optionFunc(func(c *config) {
+		c.HostMetricsLimit = max
+	})
+}
@@ -95,6 +95,14 @@ func (w *RespWriterWrapper) BytesWritten() int64 {
 	return w.written
 }
 
+// WroteHeader reports whether the header has been written.
+func (w *RespWriterWrapper) WroteHeader() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.wroteHeader
+}
+
 // BytesWritten returns the HTTP status code that was sent.
 func (w *RespWriterWrapper) StatusCode() int {
 	w.mu.RLock()
@@ -30,6 +30,8 @@ type HTTPServer struct {
 	requestBytesCounter  metric.Int64Counter
 	responseBytesCounter metric.Int64Counter
 	serverLatencyMeasure metric.Float64Histogram
+
+	activeRequestsCounter metric.Int64UpDownCounter
 }
 
 // RequestTraceAttrs returns trace attributes for an HTTP request received by a
@@ -70,6 +72,21 @@ func (s HTTPServer) Route(route string) attribute.KeyValue {
 	return oldHTTPServer{}.Route(route)
 }
 
+// ClientAddress returns the attributes of the address of the client that
+// sent a request, e.g. as forwarded by a proxy.
+func (s HTTPServer) ClientAddress(addr string) []attribute.KeyValue {
+	if s.duplicate {
+		return []attribute.KeyValue{oldHTTPServer{}.ClientAddress(addr), newHTTPServer{}.ClientAddress(addr)}
+	}
+	return []attribute.KeyValue{oldHTTPServer{}.ClientAddress(addr)}
+}
+
+// MetricAttributes returns the attributes of the metrics of an HTTP request
+// received by a server. A statusCode of zero is not recorded.
+func (s HTTPServer) MetricAttributes(server string, req *http.Request, statusCode int, additionalAttributes []attribute.KeyValue) []attribute.KeyValue {
+	return oldHTTPServer{}.MetricAttributes(server, req, statusCode, additionalAttributes)
+}
+
 // Status returns a span status code and message for an HTTP status code
 // value returned by a server. Status codes in the 400-499 range are not
 // returned as errors.
@@ -110,6 +127,18 @@ func (s HTTPServer) RecordMetrics(ctx context.Context, md MetricData) {
 	// TODO: Duplicate Metrics
 }
 
+// AddActiveRequests adds incr to the number of in-flight requests received
+// by the server, using the method and scheme of req as attributes.
+func (s HTTPServer) AddActiveRequests(ctx context.Context, req *http.Request, incr int64) {
+	if s.activeRequestsCounter == nil {
+		// This will happen if an HTTPServer{} is used insted of NewHTTPServer.
+		return
+	}
+
+	attributes := oldHTTPServer{}.ActiveRequestsAttributes(req)
+	s.activeRequestsCounter.Add(ctx, incr, metric.WithAttributeSet(attribute.NewSet(attributes...)))
+}
+
 func NewHTTPServer(meter metric.Meter) HTTPServer {
 	env := strings.ToLower(os.Getenv("OTEL_HTTP_CLIENT_COMPATIBILITY_MODE"))
 	duplicate := env == "http/dup"
@@ -117,6 +146,7 @@ func NewHTTPServer(meter metric.Meter) HTTPServer {
 		duplicate: duplicate,
 	}
 	server.requestBytesCounter, server.responseBytesCounter, server.serverLatencyMeasure = oldHTTPServer{}.createMeasures(meter)
+	server.activeRequestsCounter = oldHTTPServer{}.createActiveRequestsCounter(meter)
 	return server
 }
 
@@ -146,6 +176,20 @@ func (c HTTPClient) ResponseTraceAttrs(resp *http.Response) []attribute.KeyValue
 	return oldHTTPClient{}.ResponseTraceAttrs(resp)
 }
 
+// ProtocolVersion returns the attributes of the version of HTTP resp was
+// received with, e.g. "2" or "3", which is unknown until the request is
+// sent. It returns no attributes if the version is unknown.
+func (c HTTPClient) ProtocolVersion(resp *http.Response) []attribute.KeyValue {
+	version := responseProtocolVersion(resp)
+	if version == "" {
+		return nil
+	}
+	if c.duplicate {
+		return []attribute.KeyValue{oldHTTPClient{}.ProtocolVersion(version), newHTTPClient{}.ProtocolVersion(version)}
+	}
+	return []attribute.KeyValue{oldHTTPClient{}.ProtocolVersion(version)}
+}
+
 func (c HTTPClient) Status(code int) (codes.Code, string) {
 	if code < 100 || code >= 600 {
 		return codes.Error, fmt.Sprintf("Invalid HTTP status code %d", code)
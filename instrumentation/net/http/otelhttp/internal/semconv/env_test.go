@@ -53,6 +53,7 @@ func TestHTTPServerDoesNotPanic(t *testing.T) {
 
 type testInst struct {
 	embedded.Int64Counter
+	embedded.Int64UpDownCounter
 	embedded.Float64Histogram
 
 	intValue   int64
@@ -79,5 +80,31 @@ func NewTestHTTPServer() HTTPServer {
 		requestBytesCounter:  &testInst{},
 		responseBytesCounter: &testInst{},
 		serverLatencyMeasure: &testInst{},
+
+		activeRequestsCounter: &testInst{},
+	}
+}
+
+func TestHTTPClientProtocolVersion(t *testing.T) {
+	tests := []struct {
+		major, minor int
+		want         string
+	}{
+		{0, 0, ""},
+		{1, 0, "1.0"},
+		{1, 1, "1.1"},
+		{2, 0, "2"},
+		{3, 0, "3"},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{ProtoMajor: tt.major, ProtoMinor: tt.minor}
+
+		var want, wantDup []attribute.KeyValue
+		if tt.want != "" {
+			want = []attribute.KeyValue{attribute.String("net.protocol.version", tt.want)}
+			wantDup = append(want, attribute.String("network.protocol.version", tt.want))
+		}
+		require.Equal(t, want, HTTPClient{}.ProtocolVersion(resp))
+		require.Equal(t, wantDup, HTTPClient{duplicate: true}.ProtocolVersion(resp))
 	}
 }
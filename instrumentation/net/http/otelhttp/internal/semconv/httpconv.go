@@ -135,6 +135,11 @@ func (n newHTTPServer) RequestTraceAttrs(server string, req *http.Request) []att
 	return attrs
 }
 
+// ClientAddress returns the attribute for the address of the client.
+func (n newHTTPServer) ClientAddress(addr string) attribute.KeyValue {
+	return semconvNew.ClientAddress(addr)
+}
+
 func (n newHTTPServer) method(method string) (attribute.KeyValue, attribute.KeyValue) {
 	if method == "" {
 		return semconvNew.HTTPRequestMethodGet, attribute.KeyValue{}
@@ -311,6 +316,12 @@ func (n newHTTPClient) ResponseTraceAttrs(resp *http.Response) []attribute.KeyVa
 	return attrs
 }
 
+// ProtocolVersion returns the attribute of the HTTP version of a response
+// received by a client.
+func (n newHTTPClient) ProtocolVersion(version string) attribute.KeyValue {
+	return semconvNew.NetworkProtocolVersion(version)
+}
+
 func (n newHTTPClient) ErrorType(err error) attribute.KeyValue {
 	t := reflect.TypeOf(err)
 	var value string
@@ -79,6 +79,20 @@ func netProtocol(proto string) (name string, version string) {
 	return name, version
 }
 
+// responseProtocolVersion returns the version of HTTP resp was received
+// with, as recorded by semantic conventions, e.g. "1.1", "2" or "3". It
+// returns an empty string if the version is unknown.
+func responseProtocolVersion(resp *http.Response) string {
+	switch {
+	case resp.ProtoMajor <= 0:
+		return ""
+	case resp.ProtoMajor == 1:
+		return "1." + strconv.Itoa(resp.ProtoMinor)
+	default:
+		return strconv.Itoa(resp.ProtoMajor)
+	}
+}
+
 var methodLookup = map[string]attribute.KeyValue{
 	http.MethodConnect: semconvNew.HTTPRequestMethodConnect,
 	http.MethodDelete:  semconvNew.HTTPRequestMethodDelete,
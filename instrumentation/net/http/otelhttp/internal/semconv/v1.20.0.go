@@ -71,6 +71,11 @@ func (o oldHTTPServer) Route(route string) attribute.KeyValue {
 	return semconv.HTTPRoute(route)
 }
 
+// ClientAddress returns the attribute for the address of the client.
+func (o oldHTTPServer) ClientAddress(addr string) attribute.KeyValue {
+	return semconv.HTTPClientIP(addr)
+}
+
 // HTTPStatusCode returns the attribute for the HTTP status code.
 // This is a temporary function needed by metrics.  This will be removed when MetricsRequest is added.
 func HTTPStatusCode(status int) attribute.KeyValue {
@@ -82,6 +87,7 @@ const (
 	serverRequestSize  = "http.server.request.size"  // Incoming request bytes total
 	serverResponseSize = "http.server.response.size" // Incoming response bytes total
 	serverDuration     = "http.server.duration"      // Incoming end to end duration, milliseconds
+	serverActive       = "http.server.active_requests"
 )
 
 func (h oldHTTPServer) createMeasures(meter metric.Meter) (metric.Int64Counter, metric.Int64Counter, metric.Float64Histogram) {
@@ -113,6 +119,27 @@ func (h oldHTTPServer) createMeasures(meter metric.Meter) (metric.Int64Counter,
 	return requestBytesCounter, responseBytesCounter, serverLatencyMeasure
 }
 
+func (h oldHTTPServer) createActiveRequestsCounter(meter metric.Meter) metric.Int64UpDownCounter {
+	if meter == nil {
+		return noop.Int64UpDownCounter{}
+	}
+	activeRequestsCounter, err := meter.Int64UpDownCounter(
+		serverActive,
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Measures the number of concurrent HTTP requests that are currently in-flight."),
+	)
+	handleErr(err)
+
+	return activeRequestsCounter
+}
+
+func (o oldHTTPServer) ActiveRequestsAttributes(req *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		o.methodMetric(req.Method),
+		o.scheme(req.TLS != nil),
+	}
+}
+
 func (o oldHTTPServer) MetricAttributes(server string, req *http.Request, statusCode int, additionalAttributes []attribute.KeyValue) []attribute.KeyValue {
 	n := len(additionalAttributes) + 3
 	var host string
@@ -190,3 +217,7 @@ func (o oldHTTPClient) RequestTraceAttrs(req *http.Request) []attribute.KeyValue
 func (o oldHTTPClient) ResponseTraceAttrs(resp *http.Response) []attribute.KeyValue {
 	return semconvutil.HTTPClientResponse(resp)
 }
+
+func (o oldHTTPClient) ProtocolVersion(version string) attribute.KeyValue {
+	return semconv.NetProtocolVersion(version)
+}
@@ -5,6 +5,7 @@ package semconv
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"strings"
@@ -124,6 +125,22 @@ func TestV120RecordMetrics(t *testing.T) {
 	assert.ElementsMatch(t, want, server.serverLatencyMeasure.(*testInst).attributes)
 }
 
+func TestV120AddActiveRequests(t *testing.T) {
+	server := NewTestHTTPServer()
+	req, err := http.NewRequest("PURGE", "https://example.com", nil)
+	assert.NoError(t, err)
+	req.TLS = &tls.ConnectionState{}
+
+	server.AddActiveRequests(context.Background(), req, -1)
+
+	assert.Equal(t, int64(-1), server.activeRequestsCounter.(*testInst).intValue)
+	want := []attribute.KeyValue{
+		attribute.String("http.scheme", "https"),
+		attribute.String("http.method", "_OTHER"),
+	}
+	assert.ElementsMatch(t, want, server.activeRequestsCounter.(*testInst).attributes)
+}
+
 func TestV120ClientRequest(t *testing.T) {
 	body := strings.NewReader("Hello, world!")
 	url := "https://example.com:8888/foo/bar?stuff=morestuff"
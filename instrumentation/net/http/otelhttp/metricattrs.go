@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// WithMetricAttributesFn configures the Handler and Transport to add the
+// attributes returned by fn for every request, e.g. the API version or
+// authentication type, to its duration and size metrics. The attributes must
+// have a low cardinality, as every combination of values is a distinct
+// series.
+//
+// The attributes are added to those added with the Labeler of the request.
+func WithMetricAttributesFn(fn func(r *http.Request) []attribute.KeyValue) Option {
+	return optionFunc(func(c *config) {
+		c.MetricAttributesFn = fn
+	})
+}
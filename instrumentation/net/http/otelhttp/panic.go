@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// serverPanics is the name of the metric counting the panics recovered from
+// handlers, see WithPanicRecovery.
+const serverPanics = "http.server.panics"
+
+// PanicError is the error recorded on the span of a request whose handler
+// panicked, see WithPanicRecovery.
+type PanicError struct {
+	// Value is the value the handler panicked with.
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns Value if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+func newPanicCounter(meter metric.Meter) metric.Int64Counter {
+	counter, err := meter.Int64Counter(
+		serverPanics,
+		metric.WithUnit("{panic}"),
+		metric.WithDescription("Measures the number of panics recovered from HTTP handlers."),
+	)
+	if err != nil {
+		handleErr(err)
+		return noop.Int64Counter{}
+	}
+	return counter
+}
+
+// WithPanicRecovery configures the Handler to recover panics of the wrapped
+// handler. A recovered panic is recorded as an exception event with its
+// stack trace on the span, whose status is set to error with a PanicError,
+// and counted by the http.server.panics metric. If repanic is true, the
+// handler panics again with the recovered value once the telemetry of the
+// request is recorded, so the panic is handled by net/http or other
+// middlewares as without recovery. Otherwise, the request ends with a 500
+// Internal Server Error response, unless the handler already wrote a
+// response header.
+//
+// Panics with http.ErrAbortHandler, used to abort a response, are not
+// recorded and always propagated.
+func WithPanicRecovery(repanic bool) Option {
+	return optionFunc(func(c *config) {
+		c.PanicRecovery = true
+		c.Repanic = repanic
+	})
+}
@@ -0,0 +1,196 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Client connection pool metrics, see WithConnectionPoolMetrics.
+const (
+	clientOpenConnections    = "http.client.open_connections"     // Connections currently in use or idle
+	clientConnectionAcquired = "http.client.connection.acquired"  // Connections obtained for requests, new or reused
+	clientConnectionDials    = "http.client.connection.dials"     // Connection attempts
+	clientConnectionWaitTime = "http.client.connection.wait_time" // Time waiting to obtain a connection, seconds
+)
+
+// connectionReusedKey tells whether a connection acquired by a request was
+// previously used by another one.
+const connectionReusedKey = attribute.Key("http.connection.reused")
+
+// poolMetrics are the instruments recording the use of the connection pool
+// of a Transport.
+type poolMetrics struct {
+	openConnections metric.Int64UpDownCounter
+	acquired        metric.Int64Counter
+	dials           metric.Int64Counter
+	waitTime        metric.Float64Histogram
+
+	// hosts limits the server.address attributes, see WithHostMetricsLimit.
+	hosts *hostLimiter
+}
+
+func newPoolMetrics(meter metric.Meter) *poolMetrics {
+	var (
+		pm  poolMetrics
+		err error
+	)
+	pm.openConnections, err = meter.Int64UpDownCounter(
+		clientOpenConnections,
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("Number of outbound HTTP connections that are currently active or idle on the client."),
+	)
+	handleErr(err)
+
+	pm.acquired, err = meter.Int64Counter(
+		clientConnectionAcquired,
+		metric.WithUnit("{connection}"),
+		metric.WithDescription("Number of connections obtained for outbound HTTP requests, new or reused."),
+	)
+	handleErr(err)
+
+	pm.dials, err = meter.Int64Counter(
+		clientConnectionDials,
+		metric.WithUnit("{dial}"),
+		metric.WithDescription("Number of outbound HTTP connection attempts."),
+	)
+	handleErr(err)
+
+	pm.waitTime, err = meter.Float64Histogram(
+		clientConnectionWaitTime,
+		metric.WithUnit("s"),
+		metric.WithDescription("Measures the time outbound HTTP requests wait to obtain a connection, including dialing it."),
+	)
+	handleErr(err)
+
+	return &pm
+}
+
+// poolTracer records the use of the connection pool by a request.
+type poolTracer struct {
+	pm    *poolMetrics
+	ctx   context.Context
+	attrs []attribute.KeyValue
+
+	getConnStart time.Time
+	active       atomic.Bool
+}
+
+// trace returns an httptrace.ClientTrace recording the use of the connection
+// pool by the request r, and the poolTracer whose release method must be
+// called once the request is done with its connection.
+func (pm *poolMetrics) trace(ctx context.Context, r *http.Request) (*httptrace.ClientTrace, *poolTracer) {
+	pt := &poolTracer{pm: pm, ctx: ctx, attrs: serverAttrs(r, pm.hosts)}
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			pt.getConnStart = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			if !pt.getConnStart.IsZero() {
+				pm.waitTime.Record(ctx, time.Since(pt.getConnStart).Seconds(), pt.option())
+			}
+			pm.acquired.Add(ctx, 1, pt.option(connectionReusedKey.Bool(info.Reused)))
+			if info.WasIdle {
+				pm.openConnections.Add(ctx, -1, pt.option(semconv.HTTPConnectionStateIdle))
+			}
+			pm.openConnections.Add(ctx, 1, pt.option(semconv.HTTPConnectionStateActive))
+			pt.active.Store(true)
+		},
+		PutIdleConn: func(err error) {
+			if err == nil {
+				pm.openConnections.Add(ctx, 1, pt.option(semconv.HTTPConnectionStateIdle))
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err != nil {
+				pm.dials.Add(ctx, 1, pt.option(dialErrorType(err)))
+				return
+			}
+			pm.dials.Add(ctx, 1, pt.option())
+		},
+	}, pt
+}
+
+// release records that the request is done with its connection, if it got
+// one. It is safe to call release multiple times and on a nil poolTracer.
+func (pt *poolTracer) release() {
+	if pt == nil || !pt.active.CompareAndSwap(true, false) {
+		return
+	}
+	pt.pm.openConnections.Add(pt.ctx, -1, pt.option(semconv.HTTPConnectionStateActive))
+}
+
+func (pt *poolTracer) option(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributeSet(attribute.NewSet(append(attrs, pt.attrs...)...))
+}
+
+// serverAttrs returns the server.address and server.port attributes of the
+// server r is sent to, whose host is limited by hosts, if not nil.
+func serverAttrs(r *http.Request, hosts *hostLimiter) []attribute.KeyValue {
+	host := r.URL.Hostname()
+	if hosts != nil {
+		var ok bool
+		if host, ok = hosts.host(host); !ok {
+			return []attribute.KeyValue{semconv.ServerAddress(host)}
+		}
+	}
+	port := r.URL.Port()
+	if port == "" {
+		port = "80"
+		if r.URL.Scheme == "https" {
+			port = "443"
+		}
+	}
+	attrs := []attribute.KeyValue{semconv.ServerAddress(host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.ServerPort(p))
+	}
+	return attrs
+}
+
+// dialErrorType returns the error.type attribute of a failed dial.
+func dialErrorType(err error) attribute.KeyValue {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return semconv.ErrorTypeKey.String("timeout")
+	}
+	return semconv.ErrorTypeOther
+}
+
+// WithConnectionPoolMetrics configures the Transport to record the use of the
+// connection pool of its base http.RoundTripper with the following metrics,
+// so pool exhaustion, e.g. due to a too low
+// http.Transport.MaxIdleConnsPerHost, is visible:
+//
+//   - http.client.open_connections: the number of connections in use by a
+//     request (http.connection.state=active) or returned to the pool
+//     (http.connection.state=idle).
+//   - http.client.connection.acquired: the number of connections obtained by
+//     requests, with the http.connection.reused attribute telling whether
+//     they were reused, from which the reuse ratio is derived.
+//   - http.client.connection.dials: the number of connection attempts, with
+//     the error.type attribute if they failed.
+//   - http.client.connection.wait_time: the time requests wait to obtain a
+//     connection, including dialing it.
+//
+// All metrics have the server.address and server.port attributes. The
+// metrics are derived from net/http/httptrace hooks: idle connections closed
+// by the pool, e.g. after http.Transport.IdleConnTimeout, and connections of
+// HTTP/2, which are not returned to the idle pool, are not observed as idle.
+func WithConnectionPoolMetrics() Option {
+	return optionFunc(func(c *config) {
+		c.ConnectionPoolMetrics = true
+	})
+}
@@ -0,0 +1,43 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redirectCount returns the number of redirects followed by an http.Client
+// before sending r, the request of one of its hops.
+func redirectCount(r *http.Request) int {
+	var n int
+	for req := r; req != nil && req.Response != nil; req = req.Response.Request {
+		n++
+	}
+	return n
+}
+
+// redirectLinks returns the link of the span of r, sent by an http.Client
+// following a redirect, to the span of the previous hop, whose response
+// redirected the client, if it is known. The link has the
+// http.response.status_code attribute of the redirect response.
+func redirectLinks(r *http.Request) []trace.Link {
+	if r.Response == nil || r.Response.Request == nil {
+		return nil
+	}
+	// The Request of a response is the one given to the base RoundTripper,
+	// which holds the span of the previous hop, for http.Transport.
+	prev := trace.SpanContextFromContext(r.Response.Request.Context())
+	if !prev.IsValid() || prev.Equal(trace.SpanContextFromContext(r.Context())) {
+		// The previous hop was not traced by a Transport.
+		return nil
+	}
+	return []trace.Link{{
+		SpanContext: prev,
+		Attributes:  []attribute.KeyValue{semconv.HTTPResponseStatusCode(r.Response.StatusCode)},
+	}}
+}
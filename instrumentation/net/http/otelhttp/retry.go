@@ -0,0 +1,183 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxDrainBytes is the number of bytes of the response of a retried attempt
+// read before it is closed, so its connection can be reused.
+const maxDrainBytes = 4096
+
+// RetryPolicy decides whether the request req is sent again after an attempt
+// returning res or err, and after which delay. attempt is the number of
+// attempts made so far, starting at 1. The policy must not read or close the
+// body of res, which is closed by the Transport before the request is sent
+// again.
+type RetryPolicy func(req *http.Request, attempt int, res *http.Response, err error) (delay time.Duration, retry bool)
+
+// RetryIdempotent returns a RetryPolicy retrying idempotent requests up to
+// maxRetries times when they fail with a transport error or with a 429, 502,
+// 503 or 504 status code. The delay between attempts is the one requested by
+// the Retry-After header of the response, if any, or else starts at backoff
+// and doubles with every attempt.
+//
+// Requests are idempotent if their method is GET, HEAD, OPTIONS, TRACE, PUT
+// or DELETE, or if they have an Idempotency-Key or X-Idempotency-Key header,
+// as for the retries of net/http.
+func RetryIdempotent(maxRetries int, backoff time.Duration) RetryPolicy {
+	return func(req *http.Request, attempt int, res *http.Response, err error) (time.Duration, bool) {
+		if attempt > maxRetries || !isIdempotent(req) {
+			return 0, false
+		}
+		delay := backoff << (attempt - 1)
+		if err != nil {
+			return delay, req.Context().Err() == nil
+		}
+		switch res.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			if d, ok := retryAfter(res.Header.Get("Retry-After")); ok {
+				delay = d
+			}
+			return delay, true
+		}
+		return 0, false
+	}
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header["Idempotency-Key"]
+	if !ok {
+		_, ok = req.Header["X-Idempotency-Key"]
+	}
+	return ok
+}
+
+// retryAfter parses the value of a Retry-After header, either a number of
+// seconds or an HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(s) * time.Second, s >= 0
+	}
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(t), 0), true
+}
+
+type resendCountKey struct{}
+
+// ContextWithResendCount returns a copy of ctx recording that requests made
+// with it are sent for the count-th time after the first attempt, e.g. by a
+// retry library. The Transport adds the http.request.resend_count attribute
+// to the spans of these requests if count is positive.
+//
+// Retries made by the Transport itself, see WithRetryPolicy, are counted
+// without it.
+func ContextWithResendCount(ctx context.Context, count int) context.Context {
+	return context.WithValue(ctx, resendCountKey{}, count)
+}
+
+func resendCount(ctx context.Context) int {
+	count, _ := ctx.Value(resendCountKey{}).(int)
+	return count
+}
+
+// roundTripWithRetries sends r until t.retryPolicy does not retry it. If
+// traced, the attempts are sent with roundTrip within a span covering all of
+// them, whose spans are its children. Otherwise, r was rejected by the filters
+// and the attempts are sent with the base RoundTripper, without telemetry.
+func (t *Transport) roundTripWithRetries(r *http.Request, traced bool) (*http.Response, error) {
+	send := t.rt.RoundTrip
+	ctx, span := r.Context(), trace.SpanFromContext(context.Background()) // a non-recording span
+	if traced {
+		send = t.roundTrip
+		spanName := t.spanNameFormatter("", r)
+		if tmpl := t.urlTemplate(r); tmpl != "" {
+			spanName = routeSpanName(r.Method, tmpl)
+		}
+		ctx, span = t.getTracer(ctx).Start(ctx, spanName, trace.WithSpanKind(trace.SpanKindInternal))
+		defer span.End()
+	}
+
+	// Requests with a body are only sent again if it can be rewound.
+	rewindable := r.Body == nil || r.Body == http.NoBody || r.GetBody != nil
+
+	req := r.WithContext(ctx)
+	for attempt := 1; ; attempt++ {
+		res, err := send(req)
+
+		delay, retry := t.retryPolicy(r, attempt, res, err)
+		if !retry || !rewindable {
+			span.SetAttributes(semconv.HTTPRequestResendCount(attempt - 1))
+			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
+			} else {
+				span.SetStatus(t.spanStatus(r, res.StatusCode))
+			}
+			return res, err
+		}
+
+		next := r.Clone(ContextWithResendCount(ctx, attempt))
+		if r.GetBody != nil {
+			body, bodyErr := r.GetBody()
+			if bodyErr != nil {
+				span.SetStatus(codes.Error, bodyErr.Error())
+				if res != nil {
+					_ = res.Body.Close()
+				}
+				return nil, bodyErr
+			}
+			next.Body = body
+		}
+		if res != nil {
+			_, _ = io.CopyN(io.Discard, res.Body, maxDrainBytes)
+			_ = res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			span.SetStatus(codes.Error, ctx.Err().Error())
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		req = next
+	}
+}
+
+// WithRetryPolicy configures the Transport to send requests again as decided
+// by policy, e.g. RetryIdempotent. The policy also applies to the requests
+// rejected by the filters, see WithFilter, which are sent again without
+// telemetry. Every attempt of the other requests is recorded as a client span
+// with its metrics, and the attempts after the first have the
+// http.request.resend_count attribute. The spans of the attempts are children
+// of an internal span covering all of them, which ends when the Transport
+// returns the response of the last attempt.
+//
+// Requests with a body are only sent again if their GetBody field is set, as
+// done by http.NewRequest for common body types.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return optionFunc(func(c *config) {
+		c.RetryPolicy = policy
+	})
+}
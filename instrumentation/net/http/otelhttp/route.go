@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RouteFunc returns the route template matched by a request, e.g.
+// "/users/{id}", or an empty string if the request did not match a route.
+//
+// Routers only expose the matched route to the handlers they dispatch
+// requests to. The RouteFunc of such routers is used with
+// NewRouteMiddleware, e.g. for github.com/gorilla/mux:
+//
+//	router.Use(otelhttp.NewRouteMiddleware(func(r *http.Request) string {
+//		tmpl, _ := mux.CurrentRoute(r).GetPathTemplate()
+//		return tmpl
+//	}))
+//
+// The routes of an http.ServeMux can be looked up before the request is
+// dispatched, see ServeMuxRoute and WithRouteFunc.
+type RouteFunc func(*http.Request) string
+
+// ServeMuxRoute returns a RouteFunc returning the path of the pattern of
+// mux matched by a request. The method and host of Go 1.22 patterns, e.g.
+// "GET example.com/users/{id}", are not part of the route.
+func ServeMuxRoute(mux *http.ServeMux) RouteFunc {
+	return func(r *http.Request) string {
+		_, pattern := mux.Handler(r)
+		if i := strings.IndexByte(pattern, ' '); i >= 0 {
+			pattern = strings.TrimLeft(pattern[i+1:], " \t")
+		}
+		if i := strings.IndexByte(pattern, '/'); i > 0 {
+			pattern = pattern[i:]
+		}
+		return pattern
+	}
+}
+
+// routeSpanName returns the low-cardinality name of the span of a request
+// with method matching route.
+func routeSpanName(method, route string) string {
+	return method + " " + route
+}
+
+// setRoute annotates the span and metrics of r with the route it matched
+// and names the span after it.
+func setRoute(r *http.Request, route string) {
+	attr := semconv.NewHTTPServer(nil).Route(route)
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(attr)
+	span.SetName(routeSpanName(r.Method, route))
+
+	labeler, _ := LabelerFromContext(r.Context())
+	labeler.Add(attr)
+}
+
+// NewRouteMiddleware returns a middleware that annotates the spans and
+// metrics of the Handler with the route returned by fn, and names the span
+// "{method} {route}". It is meant to be registered with routers that only
+// expose the matched route to the handlers they dispatch requests to, and
+// replaces WithRouteTag calls on every handler. Requests for which fn
+// returns an empty string are not annotated.
+func NewRouteMiddleware(fn RouteFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if route := fn(r); route != "" {
+				setRoute(r, route)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WithRouteFunc configures the Handler to annotate spans and metrics with
+// the route returned by fn for a request before it is handled, e.g. by
+// ServeMuxRoute, and name spans "{method} {route}", taking precedence over
+// WithSpanNameFormatter. The route is available to samplers. Requests for
+// which fn returns an empty string are not annotated.
+func WithRouteFunc(fn RouteFunc) Option {
+	return optionFunc(func(c *config) {
+		c.RouteFunc = fn
+	})
+}
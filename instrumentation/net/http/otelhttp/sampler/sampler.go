@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sampler provides the sampler honoring the sampling hints given to
+// the spans of requests by otelhttp, see otelhttp.WithSamplingHint and
+// otelhttp.WithDebugHeader.
+//
+// This package is in a separate module from otelhttp to isolate the
+// dependency of the default SDK.
+package sampler // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type hintBased struct {
+	delegate sdktrace.Sampler
+}
+
+// HintBased returns a Sampler sampling the spans of requests as hinted by
+// their http.sampling_hint attribute: spans hinted with
+// otelhttp.SamplingHintRecord are always recorded and sampled, even if their
+// parent is not sampled, and spans hinted with otelhttp.SamplingHintDrop are
+// never sampled. The other spans are sampled by delegate, e.g.
+// sdktrace.ParentBased(sdktrace.TraceIDRatioBased(0.1)).
+//
+// It is required for the requests debugged with otelhttp.WithDebugHeader to
+// be recorded whatever the sampling decision of delegate.
+func HintBased(delegate sdktrace.Sampler) sdktrace.Sampler {
+	return hintBased{delegate: delegate}
+}
+
+func (s hintBased) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	for _, kv := range p.Attributes {
+		if kv.Key != otelhttp.SamplingHintKey {
+			continue
+		}
+		switch otelhttp.SamplingHint(kv.Value.AsString()) {
+		case otelhttp.SamplingHintDrop:
+			return sdktrace.NeverSample().ShouldSample(p)
+		case otelhttp.SamplingHintRecord:
+			return sdktrace.AlwaysSample().ShouldSample(p)
+		}
+	}
+	return s.delegate.ShouldSample(p)
+}
+
+func (s hintBased) Description() string {
+	return fmt.Sprintf("HintBased{%s}", s.delegate.Description())
+}
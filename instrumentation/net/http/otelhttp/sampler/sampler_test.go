@@ -0,0 +1,92 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHintBased(t *testing.T) {
+	testCases := []struct {
+		name     string
+		delegate sdktrace.Sampler
+		attrs    []attribute.KeyValue
+		want     sdktrace.SamplingDecision
+	}{
+		{
+			name:     "record",
+			delegate: sdktrace.NeverSample(),
+			attrs:    []attribute.KeyValue{otelhttp.SamplingHintKey.String(string(otelhttp.SamplingHintRecord))},
+			want:     sdktrace.RecordAndSample,
+		},
+		{
+			name:     "drop",
+			delegate: sdktrace.AlwaysSample(),
+			attrs:    []attribute.KeyValue{otelhttp.SamplingHintKey.String(string(otelhttp.SamplingHintDrop))},
+			want:     sdktrace.Drop,
+		},
+		{
+			name:     "unknown hint",
+			delegate: sdktrace.AlwaysSample(),
+			attrs:    []attribute.KeyValue{otelhttp.SamplingHintKey.String("maybe")},
+			want:     sdktrace.RecordAndSample,
+		},
+		{
+			name:     "no hint",
+			delegate: sdktrace.NeverSample(),
+			want:     sdktrace.Drop,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := HintBased(tc.delegate).ShouldSample(sdktrace.SamplingParameters{Attributes: tc.attrs})
+			assert.Equal(t, tc.want, res.Decision)
+		})
+	}
+}
+
+func TestHintBasedDescription(t *testing.T) {
+	assert.Equal(t, "HintBased{AlwaysOnSampler}", HintBased(sdktrace.AlwaysSample()).Description())
+}
+
+func TestHintBasedDebugHeaderUnsampledParent(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sr),
+		sdktrace.WithSampler(HintBased(sdktrace.ParentBased(sdktrace.AlwaysSample()))),
+	)
+	h := otelhttp.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithPropagators(propagation.TraceContext{}),
+		otelhttp.WithDebugHeader("X-Debug-Trace", "s3cret"),
+	)
+
+	for _, debug := range []bool{false, true} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		// The parent is not sampled.
+		req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+		if debug {
+			req.Header.Set("X-Debug-Trace", "s3cret")
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes(), otelhttp.DebugKey.Bool(true))
+	assert.Equal(t, trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}, spans[0].SpanContext().TraceID())
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SamplingHint is a hint given to the sampler of the tracer about the span
+// of a request, see WithSamplingHint.
+type SamplingHint string
+
+// Sampling hints.
+const (
+	// SamplingHintDrop hints that the span should not be sampled, e.g. for
+	// health checks.
+	SamplingHintDrop SamplingHint = "drop"
+	// SamplingHintRecord hints that the span should always be sampled, e.g.
+	// for administrative operations.
+	SamplingHintRecord SamplingHint = "record"
+)
+
+type samplingRule struct {
+	filter Filter
+	hint   SamplingHint
+}
+
+// samplingHintAttrs returns the attribute holding the hint of the first rule
+// whose filter matches r, if any.
+func samplingHintAttrs(rules []samplingRule, r *http.Request) []attribute.KeyValue {
+	for _, rule := range rules {
+		if rule.filter(r) {
+			return []attribute.KeyValue{SamplingHintKey.String(string(rule.hint))}
+		}
+	}
+	return nil
+}
+
+// WithSamplingHint adds a rule giving hint to the sampler of the tracer for
+// the spans of the requests for which f returns true, e.g. a filter of the
+// filters package such as filters.Route("/admin/*"). The hint of the first
+// matching rule is set as the http.sampling_hint attribute when the span
+// starts, so it is available to the sampler, which decides how to honor it.
+// The sampler returned by HintBased of the
+// go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler
+// module honors the hints, and samples the other spans with the sampler it
+// wraps.
+//
+// Unlike WithFilter, requests hinted to be dropped are still instrumented,
+// and their metrics recorded.
+func WithSamplingHint(f Filter, hint SamplingHint) Option {
+	return optionFunc(func(c *config) {
+		c.SamplingRules = append(c.SamplingRules, samplingRule{filter: f, hint: hint})
+	})
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"mime"
+	"strings"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/redact"
+)
+
+// scrubber returns the function redacting the recorded bodies with the media
+// type contentType, or nil if they are not redacted.
+func (bc *bodyCapturer) scrubber(contentType string) func(string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if len(bc.scrubPaths) > 0 {
+			return func(data string) string { return redact.JSON(data, bc.scrubPaths) }
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		if len(bc.scrubFormFields) > 0 {
+			return func(data string) string { return redact.Form(data, bc.scrubFormFields) }
+		}
+	}
+	return nil
+}
+
+// WithBodyScrubJSONPaths configures the Handler and Transport to replace the
+// values selected by paths in the JSON bodies recorded by WithBodyCapture,
+// those with the application/json media type or a +json suffix, with
+// "REDACTED". The paths have the format of those of the
+// WithPayloadScrubJSONPaths option of otelgrpc, so that the same rules can
+// be used for both.
+//
+// Paths use a subset of JSONPath: they start with "$" followed by member
+// names (".credentials" or "['credentials']"), array indices ("[0]") and
+// wildcards (".*" or "[*]"), e.g. "$.credentials.*" or "$.items[*].token".
+// Invalid paths are reported to the global error handler and ignored.
+//
+// Scrubbed bodies are re-encoded, which sorts object members by name. Bodies
+// that are not valid JSON, including those truncated by WithBodySizeLimit,
+// are replaced with "REDACTED" entirely. Passing this option multiple times
+// adds all the paths.
+func WithBodyScrubJSONPaths(paths ...string) Option {
+	return optionFunc(func(c *config) {
+		for _, p := range paths {
+			path, err := redact.ParseJSONPath(p)
+			if err != nil {
+				handleErr(err)
+				continue
+			}
+			c.BodyScrubPaths = append(c.BodyScrubPaths, path)
+		}
+	})
+}
+
+// WithBodyScrubFormFields configures the Handler and Transport to replace
+// the values of the fields named names, ignoring case, in the URL-encoded
+// form bodies recorded by WithBodyCapture, those with the
+// application/x-www-form-urlencoded media type, with "REDACTED", e.g.
+// "password". Form bodies are only recorded if WithBodyContentTypes allows
+// their media type, which is not one of DefaultBodyContentTypes. Passing
+// this option multiple times adds all the names.
+func WithBodyScrubFormFields(names ...string) Option {
+	return optionFunc(func(c *config) {
+		c.BodyScrubFormFields = append(c.BodyScrubFormFields, names...)
+	})
+}
@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// slowRequestEventName is the name of the event added to the spans of slow
+// requests, see WithSlowRequestThreshold.
+const slowRequestEventName = "slow_request"
+
+// serverSlowRequests is the name of the metric counting the slow requests,
+// see WithSlowRequestThreshold.
+const serverSlowRequests = "http.server.slow_requests"
+
+// slowRequests reports the requests taking longer than a threshold.
+type slowRequests struct {
+	threshold time.Duration
+	logger    *slog.Logger
+	counter   metric.Int64Counter
+}
+
+// newSlowRequests returns the slowRequests configured by c, or nil if slow
+// requests are not reported.
+func newSlowRequests(c *config) *slowRequests {
+	if c.SlowRequestThreshold <= 0 {
+		return nil
+	}
+	counter, err := c.Meter.Int64Counter(
+		serverSlowRequests,
+		metric.WithUnit("{request}"),
+		metric.WithDescription("Measures the number of HTTP requests taking longer than the slow request threshold."),
+	)
+	if err != nil {
+		handleErr(err)
+		counter = noop.Int64Counter{}
+	}
+	return &slowRequests{threshold: c.SlowRequestThreshold, logger: c.SlowRequestLogger, counter: counter}
+}
+
+// record reports the request r, whose span is span, if it took longer than
+// the threshold. attrs are the attributes of the metrics of r.
+func (s *slowRequests) record(ctx context.Context, span trace.Span, r *http.Request, statusCode int, elapsed time.Duration, attrs []attribute.KeyValue) {
+	if s == nil || elapsed < s.threshold {
+		return
+	}
+
+	span.AddEvent(slowRequestEventName, trace.WithAttributes(
+		SlowRequestThresholdKey.Float64(s.threshold.Seconds()),
+		SlowRequestDurationKey.Float64(elapsed.Seconds()),
+	))
+	s.counter.Add(ctx, 1, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+	if s.logger != nil {
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "slow HTTP request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", statusCode),
+			slog.Duration("duration", elapsed),
+			slog.Duration("threshold", s.threshold),
+		)
+	}
+}
+
+// WithSlowRequestThreshold configures the Handler to report the requests
+// taking longer than d, until their response is written or their
+// connection upgraded. A slow_request event is added to their span and they
+// are counted by the http.server.slow_requests metric, with the attributes
+// of the duration metric.
+//
+// Slow requests are not reported if d is not positive, which is the
+// default.
+func WithSlowRequestThreshold(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.SlowRequestThreshold = d
+	})
+}
+
+// WithSlowRequestLogger configures the Handler to log the requests reported
+// as slow, see WithSlowRequestThreshold, to logger at the warning level,
+// e.g. a logger of the log bridge go.opentelemetry.io/contrib/bridges/otelslog
+// to emit them as log records correlated with the span of the request.
+func WithSlowRequestLogger(logger *slog.Logger) Option {
+	return optionFunc(func(c *config) {
+		c.SlowRequestLogger = logger
+	})
+}
@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconv"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// SpanStatusMapper returns the status code and description of the span of
+// the request r, whose response has the HTTP status code statusCode, see
+// WithSpanStatusMapper.
+type SpanStatusMapper func(r *http.Request, statusCode int) (codes.Code, string)
+
+// ServerSpanStatus returns the status code and description of the span of a
+// request received by a Handler, whose response has the HTTP status code
+// statusCode, as done by default. Status codes in the 500-599 range, and
+// invalid status codes, are errors.
+func ServerSpanStatus(statusCode int) (codes.Code, string) {
+	return semconv.HTTPServer{}.Status(statusCode)
+}
+
+// ClientSpanStatus returns the status code and description of the span of a
+// request sent by a Transport, whose response has the HTTP status code
+// statusCode, as done by default. Status codes in the 400-599 range, and
+// invalid status codes, are errors.
+func ClientSpanStatus(statusCode int) (codes.Code, string) {
+	return semconv.HTTPClient{}.Status(statusCode)
+}
+
+// WithSpanStatusMapper configures the Handler or Transport to set the status
+// of the spans of requests with a response as returned by mapper, instead of
+// ServerSpanStatus or ClientSpanStatus respectively, e.g. to not report the
+// 404 responses of a lookup endpoint as errors:
+//
+//	otelhttp.WithSpanStatusMapper(func(r *http.Request, statusCode int) (codes.Code, string) {
+//		if statusCode == http.StatusNotFound && r.URL.Path == "/lookup" {
+//			return codes.Unset, ""
+//		}
+//		return otelhttp.ClientSpanStatus(statusCode)
+//	})
+//
+// The status of the spans of requests failing without a response is always
+// set to error.
+func WithSpanStatusMapper(mapper SpanStatusMapper) Option {
+	return optionFunc(func(c *config) {
+		c.SpanStatusMapper = mapper
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"mime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// streamProgressEventName is the name of the events reporting the progress
+// of streamed responses, see WithStreamProgressInterval.
+const streamProgressEventName = "stream.progress"
+
+// streamProgress reports the progress of a streamed response.
+type streamProgress struct {
+	interval time.Duration
+	span     trace.Span
+
+	// sse and newline are only accessed by the handler writing the
+	// response.
+	sse     bool
+	newline bool
+
+	// bytes and events are counted separately from the response writer,
+	// which is locked while a write blocks.
+	bytes  atomic.Int64
+	events atomic.Int64
+
+	// started is read by end, which can be called by the goroutine
+	// closing a hijacked connection.
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+func newStreamProgress(interval time.Duration, span trace.Span) *streamProgress {
+	return &streamProgress{interval: interval, span: span, stop: make(chan struct{})}
+}
+
+// setContentType starts reporting the progress of the response if its media
+// type contentType is that of Server-Sent Events, whose events are then
+// counted.
+func (s *streamProgress) setContentType(contentType string) {
+	if s == nil {
+		return
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && mediaType == "text/event-stream" {
+		s.sse = true
+		s.start()
+	}
+}
+
+// write counts the bytes and Server-Sent Events in p, written to the
+// response. An event ends with an empty line.
+func (s *streamProgress) write(p []byte) {
+	if s == nil {
+		return
+	}
+	s.bytes.Add(int64(len(p)))
+	if !s.sse {
+		return
+	}
+	for _, b := range p {
+		switch b {
+		case '\r':
+			continue
+		case '\n':
+			if s.newline {
+				s.events.Add(1)
+			}
+			s.newline = true
+		default:
+			s.newline = false
+		}
+	}
+}
+
+// start starts reporting the progress of the response, if it is not
+// already. It is called when the response is known to be streamed.
+func (s *streamProgress) start() {
+	if s == nil || !s.started.CompareAndSwap(false, true) {
+		return
+	}
+	go s.run()
+}
+
+func (s *streamProgress) run() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	var lastBytes int64
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(last).Seconds()
+			if elapsed <= 0 {
+				continue
+			}
+			bytes := s.bytes.Load()
+			s.span.AddEvent(streamProgressEventName, trace.WithTimestamp(now), trace.WithAttributes(
+				append(s.attrs(bytes), StreamSendThroughputKey.Float64(float64(bytes-lastBytes)/elapsed))...,
+			))
+			last, lastBytes = now, bytes
+		}
+	}
+}
+
+func (s *streamProgress) attrs(bytes int64) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{StreamBytesSentKey.Int64(bytes)}
+	if s.sse {
+		attrs = append(attrs, StreamEventsSentKey.Int64(s.events.Load()))
+	}
+	return attrs
+}
+
+// finish stops reporting the progress of the response. It is called when
+// the handler returns or panics, even if the request is not ended.
+func (s *streamProgress) finish() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+// end stops reporting the progress of the response and records its final
+// size on the span, if the response was streamed.
+func (s *streamProgress) end() {
+	if s == nil {
+		return
+	}
+	s.finish()
+	if s.started.Load() {
+		s.span.SetAttributes(s.attrs(s.bytes.Load())...)
+	}
+}
+
+// WithStreamProgressInterval configures the Handler to report the progress
+// of streamed responses, e.g. Server-Sent Events or chunked long-polls, at
+// the interval d while they are written. A response is streamed once the
+// handler flushes it or sets its media type to text/event-stream. Every
+// interval, a stream.progress event with the response bytes and Server-Sent
+// Events written so far, and the throughput since the previous report, is
+// added to the span. When the response ends, the final number of bytes and
+// events written are recorded as span attributes.
+//
+// Progress is not reported if d is not positive, which is the default.
+func WithStreamProgressInterval(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.StreamProgressInterval = d
+	})
+}
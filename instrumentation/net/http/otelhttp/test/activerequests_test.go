@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+func TestHandlerActiveRequests(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	activeRequests := func() metricdata.Aggregation {
+		rm := metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		require.Len(t, rm.ScopeMetrics, 1)
+		for _, m := range rm.ScopeMetrics[0].Metrics {
+			if m.Name == "http.server.active_requests" {
+				return m.Data
+			}
+		}
+		t.Fatal("missing http.server.active_requests metric")
+		return nil
+	}
+	want := func(get, post int64) metricdata.Sum[int64] {
+		return metricdata.Sum[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{
+				{Attributes: attribute.NewSet(semconv.HTTPMethod("GET"), semconv.HTTPSchemeHTTP), Value: get},
+				{Attributes: attribute.NewSet(semconv.HTTPMethod("POST"), semconv.HTTPSchemeHTTP), Value: post},
+			},
+			Temporality: metricdata.CumulativeTemporality,
+		}
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	}), "test_handler", otelhttp.WithMeterProvider(meterProvider))
+
+	done := make(chan struct{})
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodPost} {
+		go func(method string) {
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, "/", nil))
+			done <- struct{}{}
+		}(method)
+		<-started
+	}
+	metricdatatest.AssertAggregationsEqual(t, want(2, 1), activeRequests(), metricdatatest.IgnoreTimestamp())
+
+	for i := 0; i < 3; i++ {
+		release <- struct{}{}
+		<-done
+	}
+	metricdatatest.AssertAggregationsEqual(t, want(0, 0), activeRequests(), metricdatatest.IgnoreTimestamp())
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestHandlerBaggageMetricAttributes(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithPropagators(propagation.Baggage{}),
+		otelhttp.WithBaggageMetricAttributes("tenant", "region"),
+	)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Baggage", "tenant=acme,user=42")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assertMetricsAttribute(t, reader, attribute.String("tenant", "acme"))
+}
+
+func TestTransportBaggageMetricAttributes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithBaggageMetricAttributes("tenant"),
+	)}
+
+	m, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	b, err := baggage.New(m)
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(baggage.ContextWithBaggage(context.Background(), b), http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assertMetricsAttribute(t, reader, attribute.String("tenant", "acme"))
+}
@@ -0,0 +1,226 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func bodyEvents(span sdktrace.ReadOnlySpan) map[string][]attribute.KeyValue {
+	events := make(map[string][]attribute.KeyValue)
+	for _, e := range span.Events() {
+		if e.Name == string(otelhttp.RequestBodyKey) || e.Name == string(otelhttp.ResponseBodyKey) {
+			events[e.Name] = e.Attributes
+		}
+	}
+	return events
+}
+
+func TestHandlerBodyCapture(t *testing.T) {
+	testCases := []struct {
+		name         string
+		opts         []otelhttp.Option
+		reqType      string
+		reqBody      string
+		respType     string
+		respBody     string
+		wantReqBody  []attribute.KeyValue
+		wantRespBody []attribute.KeyValue
+	}{
+		{
+			name:     "disabled",
+			reqType:  "application/json",
+			reqBody:  `{"name":"foo"}`,
+			respType: "application/json",
+			respBody: `{"id":1}`,
+		},
+		{
+			name:         "json",
+			opts:         []otelhttp.Option{otelhttp.WithBodyCapture()},
+			reqType:      "application/json; charset=utf-8",
+			reqBody:      `{"name":"foo"}`,
+			respType:     "application/problem+json",
+			respBody:     `{"id":1}`,
+			wantReqBody:  []attribute.KeyValue{otelhttp.RequestBodyKey.String(`{"name":"foo"}`)},
+			wantRespBody: []attribute.KeyValue{otelhttp.ResponseBodyKey.String(`{"id":1}`)},
+		},
+		{
+			name:         "sniffed response",
+			opts:         []otelhttp.Option{otelhttp.WithBodyCapture()},
+			respBody:     "hello world",
+			wantRespBody: []attribute.KeyValue{otelhttp.ResponseBodyKey.String("hello world")},
+		},
+		{
+			name:     "content type not allowed",
+			opts:     []otelhttp.Option{otelhttp.WithBodyCapture()},
+			reqType:  "application/octet-stream",
+			reqBody:  "foo",
+			respType: "image/png",
+			respBody: "bar",
+		},
+		{
+			name: "custom content types",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodyContentTypes("application/x-www-form-urlencoded"),
+			},
+			reqType:     "application/x-www-form-urlencoded",
+			reqBody:     "a=1&b=2",
+			respType:    "text/plain",
+			respBody:    "ok",
+			wantReqBody: []attribute.KeyValue{otelhttp.RequestBodyKey.String("a=1&b=2")},
+		},
+		{
+			name: "scrubbed json",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodyScrubJSONPaths("$.password", "$.items[*].token"),
+			},
+			reqType:      "application/json",
+			reqBody:      `{"user":"alice","password":"s3cr3t"}`,
+			respType:     "application/vnd.api+json",
+			respBody:     `{"items":[{"id":1,"token":"a"}]}`,
+			wantReqBody:  []attribute.KeyValue{otelhttp.RequestBodyKey.String(`{"password":"REDACTED","user":"alice"}`)},
+			wantRespBody: []attribute.KeyValue{otelhttp.ResponseBodyKey.String(`{"items":[{"id":1,"token":"REDACTED"}]}`)},
+		},
+		{
+			name: "scrubbed form",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodyContentTypes("application/x-www-form-urlencoded", "text/*"),
+				otelhttp.WithBodyScrubJSONPaths("$.password"),
+				otelhttp.WithBodyScrubFormFields("password"),
+			},
+			reqType:      "application/x-www-form-urlencoded",
+			reqBody:      "user=alice&password=s3cr3t",
+			respType:     "text/plain",
+			respBody:     "password=s3cr3t",
+			wantReqBody:  []attribute.KeyValue{otelhttp.RequestBodyKey.String("user=alice&password=REDACTED")},
+			wantRespBody: []attribute.KeyValue{otelhttp.ResponseBodyKey.String("password=s3cr3t")},
+		},
+		{
+			name: "scrubbed truncated json",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodySizeLimit(8),
+				otelhttp.WithBodyScrubJSONPaths("$.password"),
+			},
+			reqType: "application/json",
+			reqBody: `{"password":"s3cr3t"}`,
+			wantReqBody: []attribute.KeyValue{
+				otelhttp.BodyTruncatedKey.Bool(true),
+				otelhttp.RequestBodyKey.String("REDACTED"),
+			},
+		},
+		{
+			name: "truncated",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodySizeLimit(4),
+			},
+			reqType:  "text/plain",
+			reqBody:  "abcdef",
+			respType: "text/plain",
+			respBody: "añbc",
+			wantReqBody: []attribute.KeyValue{
+				otelhttp.BodyTruncatedKey.Bool(true),
+				otelhttp.RequestBodyKey.String("abcd"),
+			},
+			wantRespBody: []attribute.KeyValue{
+				otelhttp.BodyTruncatedKey.Bool(true),
+				otelhttp.ResponseBodyKey.String("añb"),
+			},
+		},
+		{
+			name: "truncated rune",
+			opts: []otelhttp.Option{
+				otelhttp.WithBodyCapture(),
+				otelhttp.WithBodySizeLimit(2),
+			},
+			respType: "text/plain",
+			respBody: "añb",
+			wantRespBody: []attribute.KeyValue{
+				otelhttp.BodyTruncatedKey.Bool(true),
+				otelhttp.ResponseBodyKey.String("a"),
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := otelhttp.NewHandler(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, err := io.ReadAll(r.Body)
+					assert.NoError(t, err)
+					if tc.respType != "" {
+						w.Header().Set("Content-Type", tc.respType)
+					}
+					_, err = io.WriteString(w, tc.respBody)
+					assert.NoError(t, err)
+				}), "test_handler",
+				append([]otelhttp.Option{otelhttp.WithTracerProvider(provider)}, tc.opts...)...,
+			)
+
+			r := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(tc.reqBody))
+			if tc.reqType != "" {
+				r.Header.Set("Content-Type", tc.reqType)
+			}
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, r)
+			assert.Equal(t, tc.respBody, rr.Body.String())
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			events := bodyEvents(spans[0])
+			assert.Equal(t, tc.wantReqBody, events[string(otelhttp.RequestBodyKey)])
+			assert.Equal(t, tc.wantRespBody, events[string(otelhttp.ResponseBodyKey)])
+		})
+	}
+}
+
+func TestTransportBodyCapture(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"name":"foo"}`, string(b))
+		w.Header().Set("Content-Type", "application/json")
+		_, err = io.WriteString(w, `{"id":1}`)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithBodyCapture(),
+	)}
+
+	res, err := c.Post(ts.URL, "application/json", strings.NewReader(`{"name":"foo"}`))
+	require.NoError(t, err)
+	b, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	assert.Equal(t, `{"id":1}`, string(b))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	events := bodyEvents(spans[0])
+	assert.Equal(t, []attribute.KeyValue{otelhttp.RequestBodyKey.String(`{"name":"foo"}`)}, events[string(otelhttp.RequestBodyKey)])
+	assert.Equal(t, []attribute.KeyValue{otelhttp.ResponseBodyKey.String(`{"id":1}`)}, events[string(otelhttp.ResponseBodyKey)])
+}
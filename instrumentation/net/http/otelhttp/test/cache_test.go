@@ -0,0 +1,127 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCacheStatusFromToken(t *testing.T) {
+	for value, want := range map[string]string{
+		"HIT":                        otelhttp.CacheStatusHit,
+		"Miss from cloudfront":       otelhttp.CacheStatusMiss,
+		"TCP_HIT from squid":         otelhttp.CacheStatusHit,
+		"RefreshHit from cloudfront": otelhttp.CacheStatusRevalidated,
+		"DYNAMIC":                    otelhttp.CacheStatusDynamic,
+		"Error from cloudfront":      "",
+	} {
+		assert.Equal(t, want, otelhttp.CacheStatusFromToken(value), value)
+	}
+}
+
+func TestCacheStatusFromRFC9211(t *testing.T) {
+	for value, want := range map[string]string{
+		"ExampleCache; hit":                           otelhttp.CacheStatusHit,
+		"OriginCache; hit, CDN; fwd=uri-miss; stored": otelhttp.CacheStatusMiss,
+		"CDN; fwd=uri-miss, OriginCache; hit; ttl=30": otelhttp.CacheStatusHit,
+		"CDN; fwd=stale; fwd-status=304":              otelhttp.CacheStatusRevalidated,
+		"CDN; fwd=stale; fwd-status=200":              otelhttp.CacheStatusExpired,
+		`CDN; fwd="bypass"`:                           otelhttp.CacheStatusBypass,
+		"CDN":                                         "",
+	} {
+		assert.Equal(t, want, otelhttp.CacheStatusFromRFC9211(value), value)
+	}
+}
+
+func TestTransportCacheStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range r.URL.Query() {
+			w.Header()[k] = v
+		}
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+		}
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name    string
+		query   string
+		etag    bool
+		headers []otelhttp.CacheStatusHeader
+		want    []attribute.KeyValue
+	}{
+		{
+			name:  "cf-cache-status",
+			query: "?Cf-Cache-Status=HIT&Age=42",
+			want:  []attribute.KeyValue{otelhttp.CacheStatusKey.String("hit"), otelhttp.CacheAgeKey.Int64(42)},
+		},
+		{
+			name:  "first header wins",
+			query: "?Cache-Status=CDN%3B+fwd=uri-miss&X-Cache=Hit+from+cloudfront",
+			want:  []attribute.KeyValue{otelhttp.CacheStatusKey.String("miss")},
+		},
+		{
+			name: "not modified",
+			etag: true,
+			want: []attribute.KeyValue{otelhttp.CacheStatusKey.String("revalidated")},
+		},
+		{
+			name:  "custom header",
+			query: "?X-Edge-Result=served",
+			headers: []otelhttp.CacheStatusHeader{{
+				Name: "X-Edge-Result",
+				Status: func(v string) string {
+					if v == "served" {
+						return otelhttp.CacheStatusHit
+					}
+					return ""
+				},
+			}},
+			want: []attribute.KeyValue{otelhttp.CacheStatusKey.String("hit")},
+		},
+		{
+			name: "no cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithCacheStatus(tt.headers...),
+			)}
+
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.query, nil)
+			require.NoError(t, err)
+			if tt.etag {
+				req.Header.Set("If-None-Match", `"v1"`)
+			}
+			res, err := c.Do(req)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			require.Len(t, sr.Ended(), 1)
+			var got []attribute.KeyValue
+			for _, kv := range sr.Ended()[0].Attributes() {
+				if kv.Key == otelhttp.CacheStatusKey || kv.Key == otelhttp.CacheAgeKey {
+					got = append(got, kv)
+				}
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
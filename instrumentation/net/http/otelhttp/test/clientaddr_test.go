@@ -0,0 +1,104 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerTrustedProxies(t *testing.T) {
+	const clientIPKey = attribute.Key("http.client_ip")
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     http.Header
+		want       string
+	}{
+		{
+			name:       "untrusted peer",
+			remoteAddr: "198.51.100.7:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.1"}},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "no header",
+			remoteAddr: "10.0.0.1:1234",
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "x-forwarded-for",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"192.0.2.9, 203.0.113.1", "10.0.0.2"}},
+			want:       "203.0.113.1",
+		},
+		{
+			name:       "x-forwarded-for all trusted",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			header: http.Header{
+				"Forwarded":       {`for=192.0.2.9;proto=https, For="[2001:db8::1]:4711";by=10.0.0.2`},
+				"X-Forwarded-For": {"198.51.100.7"},
+			},
+			want: "2001:db8::1",
+		},
+		{
+			name:       "x-real-ip",
+			remoteAddr: "10.0.0.1:1234",
+			header:     http.Header{"X-Real-Ip": {"203.0.113.1"}},
+			want:       "203.0.113.1",
+		},
+		{
+			name:       "single trusted address",
+			remoteAddr: "[2001:db8::2]:1234",
+			header:     http.Header{"X-Forwarded-For": {"203.0.113.1"}},
+			want:       "203.0.113.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			proxy := netip.MustParseAddr("2001:db8::2")
+			h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithTrustedProxies(netip.MustParsePrefix("10.0.0.0/8")),
+				otelhttp.WithTrustedProxies(netip.PrefixFrom(proxy, proxy.BitLen())),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.header {
+				req.Header[k] = v
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Len(t, sr.Ended(), 1)
+			var got []string
+			for _, kv := range sr.Ended()[0].Attributes() {
+				if kv.Key == clientIPKey {
+					got = append(got, kv.Value.AsString())
+				}
+			}
+			assert.Equal(t, []string{tt.want}, got)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+var uncompressedBody = strings.Repeat("compressible ", 100)
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := io.WriteString(zw, s)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// histogramSums returns the sums of the int64 histograms collected by
+// reader, by name.
+func histogramSums(t *testing.T, reader sdkmetric.Reader) map[string]int64 {
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	sums := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			hist, ok := m.Data.(metricdata.Histogram[int64])
+			if !ok {
+				continue
+			}
+			for _, dp := range hist.DataPoints {
+				sums[m.Name] += dp.Sum
+			}
+		}
+	}
+	return sums
+}
+
+func TestHandlerCompressionMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	compressed := gzipped(t, uncompressedBody)
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.Copy(io.Discard, r.Body)
+		assert.NoError(t, err)
+		w.Header().Set("Content-Encoding", "gzip")
+		_, err = w.Write(compressed)
+		assert.NoError(t, err)
+	}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithCompressionMetrics(),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	sums := histogramSums(t, reader)
+	assert.Equal(t, int64(len(compressed)), sums["http.server.request.body.compressed_size"])
+	assert.Equal(t, int64(len(uncompressedBody)), sums["http.server.request.body.uncompressed_size"])
+	assert.Equal(t, int64(len(compressed)), sums["http.server.response.body.compressed_size"])
+	assert.Equal(t, int64(len(uncompressedBody)), sums["http.server.response.body.uncompressed_size"])
+}
+
+func TestHandlerCompressionSizeLimit(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	// About 16 KiB decompressing to 8 MiB.
+	compressed := gzipped(t, strings.Repeat("\x00", 8<<20))
+	require.Less(t, len(compressed), 32<<10)
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.Copy(io.Discard, r.Body)
+		assert.NoError(t, err)
+	}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithCompressionMetrics(),
+		otelhttp.WithCompressionSizeLimit(1<<20),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(compressed))
+	req.Header.Set("Content-Encoding", "gzip")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	sums := histogramSums(t, reader)
+	assert.Equal(t, int64(len(compressed)), sums["http.server.request.body.compressed_size"])
+	assert.NotContains(t, sums, "http.server.request.body.uncompressed_size")
+}
+
+func TestTransportCompressionMetrics(t *testing.T) {
+	compressed := gzipped(t, uncompressedBody)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write(compressed)
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name               string
+		disableCompression bool
+		want               map[string]int64
+	}{
+		{
+			name:               "compressed response",
+			disableCompression: true,
+			want: map[string]int64{
+				"http.client.request.body.compressed_size":    int64(len(compressed)),
+				"http.client.request.body.uncompressed_size":  int64(len(uncompressedBody)),
+				"http.client.response.body.compressed_size":   int64(len(compressed)),
+				"http.client.response.body.uncompressed_size": int64(len(uncompressedBody)),
+			},
+		},
+		{
+			name: "response decompressed by the transport",
+			want: map[string]int64{
+				"http.client.request.body.compressed_size":    int64(len(compressed)),
+				"http.client.request.body.uncompressed_size":  int64(len(uncompressedBody)),
+				"http.client.response.body.uncompressed_size": int64(len(uncompressedBody)),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+			c := http.Client{Transport: otelhttp.NewTransport(
+				&http.Transport{DisableCompression: tt.disableCompression},
+				otelhttp.WithMeterProvider(meterProvider),
+				otelhttp.WithCompressionMetrics(),
+			)}
+
+			req, err := http.NewRequest(http.MethodPost, ts.URL, bytes.NewReader(compressed))
+			require.NoError(t, err)
+			req.Header.Set("Content-Encoding", "gzip")
+			res, err := c.Do(req)
+			require.NoError(t, err)
+			_, err = io.Copy(io.Discard, res.Body)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+
+			assert.Equal(t, tt.want, histogramSums(t, reader))
+		})
+	}
+}
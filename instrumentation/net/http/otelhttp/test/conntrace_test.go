@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTransportConnectionTrace(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	// Request localhost so its address is resolved.
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	u.Host = "localhost:" + u.Port()
+	base := ts.Client().Transport.(*http.Transport).Clone()
+	base.TLSClientConfig.InsecureSkipVerify = true // The certificate is not valid for localhost.
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(base,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithConnectionTrace(),
+	)}
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(u.String())
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	spans := sr.Ended()
+	var requests []sdktrace.ReadOnlySpan
+	children := make(map[string]int)
+	for _, s := range spans {
+		if s.Name() == "HTTP GET" {
+			requests = append(requests, s)
+			continue
+		}
+		children[s.Name()]++
+	}
+	require.Len(t, requests, 2)
+	// The second request reuses the connection of the first one.
+	assert.Equal(t, 1, children["http.dns"])
+	assert.GreaterOrEqual(t, children["http.connect"], 1)
+	assert.Equal(t, 1, children["http.tls"])
+	for _, s := range spans {
+		if s.Name() != "HTTP GET" {
+			assert.Equal(t, requests[0].SpanContext().SpanID(), s.Parent().SpanID(), s.Name())
+		}
+	}
+
+	for i, s := range requests {
+		events := make(map[string]bool)
+		for _, e := range s.Events() {
+			events[e.Name] = true
+			if e.Name == "http.got_conn" {
+				assert.Contains(t, e.Attributes, otelhttp.ConnReusedKey.Bool(i == 1))
+			}
+		}
+		assert.True(t, events["http.got_conn"])
+		assert.True(t, events["http.wrote_request"])
+		assert.True(t, events["http.first_response_byte"])
+	}
+}
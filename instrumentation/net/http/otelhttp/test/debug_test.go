@@ -0,0 +1,98 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerDebugHeader(t *testing.T) {
+	testCases := []struct {
+		name  string
+		value string
+		debug bool
+	}{
+		{name: "matching secret", value: "s3cret", debug: true},
+		{name: "wrong secret", value: "guess"},
+		{name: "no header"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(
+				sdktrace.WithSpanProcessor(sr),
+				sdktrace.WithSampler(sampler.HintBased(sdktrace.NeverSample())),
+			)
+
+			h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = w.Write([]byte{0x01, 0x02})
+			}), "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithDebugHeader("X-Debug-Trace", "s3cret"),
+				otelhttp.WithCapturedRequestHeaders("X-Debug-Trace"),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a=b"))
+			if tc.value != "" {
+				req.Header.Set("X-Debug-Trace", tc.value)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !tc.debug {
+				assert.Empty(t, sr.Ended())
+				return
+			}
+			require.Len(t, sr.Ended(), 1)
+			span := sr.Ended()[0]
+			assert.Contains(t, span.Attributes(), otelhttp.DebugKey.Bool(true))
+			assert.Contains(t, span.Attributes(), attribute.StringSlice("http.request.header.x-debug-trace", []string{"REDACTED"}))
+
+			events := bodyEvents(span)
+			assert.Contains(t, events[string(otelhttp.RequestBodyKey)], otelhttp.RequestBodyKey.String("a=b"))
+			assert.Contains(t, events[string(otelhttp.ResponseBodyKey)], otelhttp.ResponseBodyKey.String("\x01\x02"))
+		})
+	}
+}
+
+func TestHandlerDebugHeaderScrubsBodies(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		_, _ = w.Write([]byte("user=alice&password=hunter2"))
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithDebugHeader("X-Debug-Trace", "s3cret"),
+		otelhttp.WithBodyScrubJSONPaths("$.password"),
+		otelhttp.WithBodyScrubFormFields("password"),
+	)
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"password":"hunter2","user":"alice"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Debug-Trace", "s3cret")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, sr.Ended(), 1)
+	events := bodyEvents(sr.Ended()[0])
+	assert.Contains(t, events[string(otelhttp.RequestBodyKey)], otelhttp.RequestBodyKey.String(`{"password":"REDACTED","user":"alice"}`))
+	assert.Contains(t, events[string(otelhttp.ResponseBodyKey)], otelhttp.ResponseBodyKey.String("user=alice&password=REDACTED"))
+}
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func tenantAttrs(r *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("tenant", r.Header.Get("X-Tenant"))}
+}
+
+func cacheAttrs(res *http.Response) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("cache", res.Header.Get("X-Cache")),
+		attribute.Int("status", res.StatusCode),
+	}
+}
+
+func TestHandlerAttributeExtractors(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Cache", "hit")
+		w.WriteHeader(http.StatusAccepted)
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithRequestAttributeExtractor(tenantAttrs),
+		otelhttp.WithRequestAttributeExtractor(func(r *http.Request) []attribute.KeyValue {
+			return []attribute.KeyValue{attribute.String("path", r.URL.Path)}
+		}),
+		otelhttp.WithResponseAttributeExtractor(cacheAttrs),
+	)
+
+	req := httptest.NewRequest(http.MethodGet, "/foo", nil)
+	req.Header.Set("X-Tenant", "acme")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, attribute.String("tenant", "acme"))
+	assert.Contains(t, attrs, attribute.String("path", "/foo"))
+	assert.Contains(t, attrs, attribute.String("cache", "hit"))
+	assert.Contains(t, attrs, attribute.Int("status", http.StatusAccepted))
+}
+
+func TestTransportAttributeExtractors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Cache", "miss")
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithRequestAttributeExtractor(tenantAttrs),
+		otelhttp.WithResponseAttributeExtractor(cacheAttrs),
+	)}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Tenant", "acme")
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, attribute.String("tenant", "acme"))
+	assert.Contains(t, attrs, attribute.String("cache", "miss"))
+	assert.Contains(t, attrs, attribute.Int("status", http.StatusOK))
+}
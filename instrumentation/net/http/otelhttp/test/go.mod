@@ -3,8 +3,10 @@ module go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/test
 go 1.21
 
 require (
+	github.com/gorilla/mux v1.8.1
 	github.com/stretchr/testify v1.9.0
 	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp v0.53.0
+	go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler v0.53.0
 	go.opentelemetry.io/otel v1.28.0
 	go.opentelemetry.io/otel/sdk v1.28.0
 	go.opentelemetry.io/otel/sdk/metric v1.28.0
@@ -18,9 +20,14 @@ require (
 	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.opentelemetry.io/contrib/instrumentation/matcher v0.53.0 // indirect
 	go.opentelemetry.io/otel/metric v1.28.0 // indirect
 	golang.org/x/sys v0.24.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 replace go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp => ../
+
+replace go.opentelemetry.io/contrib/instrumentation/matcher => ../../../../matcher
+
+replace go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler => ../sampler
@@ -0,0 +1,113 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestHandlerGraphQL(t *testing.T) {
+	const body = `{"query": "query GetUser($id: ID!) { user(id: $id) { name } }", "operationName": "GetUser", "variables": {"id": 1}}`
+
+	testCases := []struct {
+		name     string
+		req      func() *http.Request
+		wantName string
+		want     []attribute.KeyValue
+	}{
+		{
+			name: "POST",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+				r.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return r
+			},
+			wantName: "query GetUser",
+			want:     []attribute.KeyValue{semconv.GraphqlOperationTypeQuery, semconv.GraphqlOperationName("GetUser")},
+		},
+		{
+			name: "GET",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape("mutation { like }"), nil)
+			},
+			wantName: "mutation",
+			want:     []attribute.KeyValue{semconv.GraphqlOperationTypeMutation},
+		},
+		{
+			name: "other path",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			wantName: "test_handler",
+		},
+		{
+			name: "too large",
+			req: func() *http.Request {
+				large := `{"query": "query Large { a }", "padding": "` + strings.Repeat("x", 64<<10) + `"}`
+				r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(large))
+				r.Header.Set("Content-Type", "application/json")
+				return r
+			},
+			wantName: "test_handler",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+			req := tc.req()
+			var want string
+			if req.Body != nil && req.Body != http.NoBody {
+				b, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				want = string(b)
+				req.Body = io.NopCloser(strings.NewReader(want))
+			}
+
+			var got string
+			h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				got = string(b)
+			}), "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithGraphQLPath("/graphql"),
+			)
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			// The handler reads the whole body.
+			assert.Equal(t, want, got)
+
+			require.Len(t, sr.Ended(), 1)
+			span := sr.Ended()[0]
+			assert.Equal(t, tc.wantName, span.Name())
+			for _, kv := range tc.want {
+				assert.Contains(t, span.Attributes(), kv)
+			}
+			if len(tc.want) == 0 {
+				for _, kv := range span.Attributes() {
+					assert.NotEqual(t, semconv.GraphqlOperationTypeKey, kv.Key)
+				}
+			}
+		})
+	}
+}
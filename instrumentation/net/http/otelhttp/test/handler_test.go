@@ -36,7 +36,7 @@ func assertScopeMetrics(t *testing.T, sm metricdata.ScopeMetrics, attrs attribut
 		Version: otelhttp.Version(),
 	}, sm.Scope)
 
-	require.Len(t, sm.Metrics, 3)
+	require.Len(t, sm.Metrics, 4)
 
 	want := metricdata.Metrics{
 		Name:        "http.server.request.size",
@@ -72,6 +72,20 @@ func assertScopeMetrics(t *testing.T, sm metricdata.ScopeMetrics, attrs attribut
 		},
 	}
 	metricdatatest.AssertEqual(t, want, sm.Metrics[2], metricdatatest.IgnoreTimestamp(), metricdatatest.IgnoreValue())
+
+	want = metricdata.Metrics{
+		Name:        "http.server.active_requests",
+		Description: "Measures the number of concurrent HTTP requests that are currently in-flight.",
+		Unit:        "{request}",
+		Data: metricdata.Sum[int64]{
+			DataPoints: []metricdata.DataPoint[int64]{{
+				Attributes: attribute.NewSet(semconv.HTTPMethod("GET"), semconv.HTTPSchemeHTTP),
+				Value:      0,
+			}},
+			Temporality: metricdata.CumulativeTemporality,
+		},
+	}
+	metricdatatest.AssertEqual(t, want, sm.Metrics[3], metricdatatest.IgnoreTimestamp())
 }
 
 func TestHandlerBasics(t *testing.T) {
@@ -498,6 +512,10 @@ func TestWithRouteTag(t *testing.T) {
 	gotMetrics := rm.ScopeMetrics[0].Metrics
 
 	for _, m := range gotMetrics {
+		if m.Name == "http.server.active_requests" {
+			// The route is not known when the request becomes active.
+			continue
+		}
 		switch d := m.Data.(type) {
 		case metricdata.Sum[int64]:
 			require.Len(t, d.DataPoints, 1, "metric '%v' should have exactly one data point", m.Name)
@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerCapturedHeaders(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	h := otelhttp.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Add("Set-Cookie", "session=secret")
+			w.Header().Set("X-Ignored", "ignored")
+			_, _ = io.WriteString(w, "ok")
+		}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedRequestHeaders("X-Tenant-ID", "authorization", "Cookie", "X-Missing"),
+		otelhttp.WithCapturedResponseHeaders("Content-Type", "Set-Cookie"),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "http://localhost/", nil)
+	r.Header.Add("X-Tenant-Id", "a")
+	r.Header.Add("X-Tenant-Id", "b")
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Set("Cookie", "session=secret")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	for _, want := range []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.x-tenant-id", []string{"a", "b"}),
+		attribute.StringSlice("http.request.header.authorization", []string{"REDACTED"}),
+		attribute.StringSlice("http.request.header.cookie", []string{"REDACTED"}),
+		attribute.StringSlice("http.response.header.content-type", []string{"text/plain"}),
+		attribute.StringSlice("http.response.header.set-cookie", []string{"REDACTED"}),
+	} {
+		assert.Contains(t, attrs, want)
+	}
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("http.request.header.x-missing"), kv.Key)
+		assert.NotEqual(t, attribute.Key("http.response.header.x-ignored"), kv.Key)
+	}
+}
+
+func TestTransportCapturedHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "42")
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(
+		http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedRequestHeaders("Accept", "Proxy-Authorization"),
+		otelhttp.WithCapturedResponseHeaders("X-Request-ID"),
+	)}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Proxy-Authorization", "Basic secret")
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	for _, want := range []attribute.KeyValue{
+		attribute.StringSlice("http.request.header.accept", []string{"application/json"}),
+		attribute.StringSlice("http.request.header.proxy-authorization", []string{"REDACTED"}),
+		attribute.StringSlice("http.response.header.x-request-id", []string{"42"}),
+	} {
+		assert.Contains(t, attrs, want)
+	}
+}
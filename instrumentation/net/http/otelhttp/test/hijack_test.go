@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const connectEstablished = "HTTP/1.1 200 Connection established\r\n\r\n"
+
+// tunnel returns a handler establishing a CONNECT tunnel echoing a line.
+func tunnel(t *testing.T, done chan<- struct{}, recording bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		assert.Equal(t, recording, trace.SpanFromContext(r.Context()).IsRecording())
+
+		_, err = brw.WriteString(connectEstablished)
+		require.NoError(t, err)
+		require.NoError(t, brw.Flush())
+		line, err := brw.ReadString('\n')
+		require.NoError(t, err)
+		_, err = brw.WriteString(line)
+		require.NoError(t, err)
+		require.NoError(t, brw.Flush())
+	})
+}
+
+// connect sends a CONNECT request to the server at url, followed by a line
+// to tunnel, and returns the response and the tunneled line sent back.
+func connect(t *testing.T, url string) (*http.Response, string) {
+	conn, err := net.Dial("tcp", strings.TrimPrefix(url, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = io.WriteString(conn, "CONNECT example.com:443 HTTP/1.1\r\nHost: example.com:443\r\n\r\nping\n")
+	require.NoError(t, err)
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, &http.Request{Method: http.MethodConnect})
+	require.NoError(t, err)
+	line, err := br.ReadString('\n')
+	require.NoError(t, err)
+	return res, line
+}
+
+func TestHandlerHijack(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	done := make(chan struct{})
+	ts := httptest.NewServer(otelhttp.NewHandler(tunnel(t, done, false), "test_handler", otelhttp.WithTracerProvider(provider)))
+	defer ts.Close()
+
+	res, line := connect(t, ts.URL)
+	<-done
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "ping\n", line)
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assert.Contains(t, span.Attributes(), otelhttp.ConnectionHijackedKey.Bool(true))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+	for _, kv := range span.Attributes() {
+		assert.NotEqual(t, semconv.HTTPStatusCodeKey, kv.Key)
+	}
+}
+
+func TestHandlerHijackedConnTracking(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	done := make(chan struct{})
+	ts := httptest.NewServer(otelhttp.NewHandler(tunnel(t, done, true), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithHijackedConnTracking(),
+	))
+	defer ts.Close()
+
+	res, line := connect(t, ts.URL)
+	<-done
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "ping\n", line)
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, otelhttp.ConnectionHijackedKey.Bool(true))
+	assert.Contains(t, attrs, otelhttp.HijackedBytesSentKey.Int64(int64(len(connectEstablished)+len("ping\n"))))
+	assert.Contains(t, attrs, otelhttp.HijackedBytesReceivedKey.Int64(int64(len("ping\n"))))
+	assert.Contains(t, attrs, semconv.HTTPStatusCode(http.StatusOK))
+}
+
+// activeRequests returns the sum of the http.server.active_requests metric
+// collected by reader.
+func activeRequests(t *testing.T, reader sdkmetric.Reader) int64 {
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	var active int64
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "http.server.active_requests" {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				active += dp.Value
+			}
+		}
+	}
+	return active
+}
+
+func TestHandlerHijackedConnTrackingAfterReturn(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	// The connection is served by a goroutine, which only starts once the
+	// instrumented handler has returned.
+	returned := make(chan struct{})
+	done := make(chan struct{})
+	handler := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		go func() {
+			defer close(done)
+			defer conn.Close()
+			<-returned
+
+			_, err := brw.WriteString(connectEstablished)
+			assert.NoError(t, err)
+			assert.NoError(t, brw.Flush())
+			line, err := brw.ReadString('\n')
+			assert.NoError(t, err)
+			_, err = brw.WriteString(line)
+			assert.NoError(t, err)
+			assert.NoError(t, brw.Flush())
+		}()
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithHijackedConnTracking(),
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		assert.Empty(t, sr.Ended(), "span ended before the connection is closed")
+		assert.Equal(t, int64(1), activeRequests(t, reader), "request inactive before the connection is closed")
+		close(returned)
+	}))
+	defer ts.Close()
+
+	res, line := connect(t, ts.URL)
+	<-done
+	assert.Equal(t, int64(0), activeRequests(t, reader))
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "ping\n", line)
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, otelhttp.ConnectionHijackedKey.Bool(true))
+	assert.Contains(t, attrs, otelhttp.HijackedBytesSentKey.Int64(int64(len(connectEstablished)+len("ping\n"))))
+	assert.Contains(t, attrs, otelhttp.HijackedBytesReceivedKey.Int64(int64(len("ping\n"))))
+	assert.Contains(t, attrs, semconv.HTTPStatusCode(http.StatusOK))
+}
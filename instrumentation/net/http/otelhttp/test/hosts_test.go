@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+func TestTransportHostMetricsLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	// Every host is served by ts.
+	base := &http.Transport{DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, strings.TrimPrefix(ts.URL, "http://"))
+	}}
+	c := http.Client{Transport: otelhttp.NewTransport(base,
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithHostMetricsLimit(2),
+	)}
+
+	for _, host := range []string{"a.test", "b.test", "a.test", "c.test", "d.test"} {
+		res, err := c.Get("http://" + host + "/")
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	counts := map[string]uint64{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.client.duration" {
+			continue
+		}
+		hist, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		for _, dp := range hist.DataPoints {
+			_, ok := dp.Attributes.Value(semconv.NetPeerNameKey)
+			assert.False(t, ok, "net.peer.name recorded")
+			host, _ := dp.Attributes.Value(attribute.Key("server.address"))
+			counts[host.AsString()] += dp.Count
+		}
+	}
+	assert.Equal(t, map[string]uint64{"a.test": 2, "b.test": 1, otelhttp.OtherHost: 2}, counts)
+}
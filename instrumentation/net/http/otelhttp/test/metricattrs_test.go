@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func apiVersionAttrs(r *http.Request) []attribute.KeyValue {
+	return []attribute.KeyValue{attribute.String("api.version", r.Header.Get("X-API-Version"))}
+}
+
+// assertMetricsAttribute asserts that all the data points of the duration and
+// size metrics collected by reader have the attribute kv.
+func assertMetricsAttribute(t *testing.T, reader sdkmetric.Reader, kv attribute.KeyValue) {
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	var n int
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		switch d := m.Data.(type) {
+		case metricdata.Sum[int64]:
+			if !d.IsMonotonic {
+				// Active requests are not recorded with the attributes.
+				continue
+			}
+			require.Len(t, d.DataPoints, 1)
+			assert.Contains(t, d.DataPoints[0].Attributes.ToSlice(), kv, m.Name)
+		case metricdata.Histogram[float64]:
+			require.Len(t, d.DataPoints, 1)
+			assert.Contains(t, d.DataPoints[0].Attributes.ToSlice(), kv, m.Name)
+		default:
+			continue
+		}
+		n++
+	}
+	assert.Equal(t, 3, n)
+}
+
+func TestHandlerMetricAttributesFn(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), "test_handler",
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithMetricAttributesFn(apiVersionAttrs),
+	)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Version", "v2")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	assertMetricsAttribute(t, reader, attribute.String("api.version", "v2"))
+}
+
+func TestTransportMetricAttributesFn(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithMetricAttributesFn(apiVersionAttrs),
+	)}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("X-API-Version", "v1")
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assertMetricsAttribute(t, reader, attribute.String("api.version", "v1"))
+}
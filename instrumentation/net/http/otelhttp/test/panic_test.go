@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerPanicRecovery(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	testCases := []struct {
+		name     string
+		repanic  bool
+		handler  http.HandlerFunc
+		wantCode int
+	}{
+		{
+			name:     "recovered",
+			handler:  func(http.ResponseWriter, *http.Request) { panic(errBoom) },
+			wantCode: http.StatusInternalServerError,
+		},
+		{
+			name: "recovered after header",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic(errBoom)
+			},
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:     "repanic",
+			repanic:  true,
+			handler:  func(http.ResponseWriter, *http.Request) { panic(errBoom) },
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			reader := sdkmetric.NewManualReader()
+			meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+			h := otelhttp.NewHandler(tc.handler, "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithMeterProvider(meterProvider),
+				otelhttp.WithPanicRecovery(tc.repanic),
+			)
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.repanic {
+				assert.PanicsWithValue(t, errBoom, func() { h.ServeHTTP(rr, req) })
+			} else {
+				assert.NotPanics(t, func() { h.ServeHTTP(rr, req) })
+				assert.Equal(t, tc.wantCode, rr.Code)
+			}
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, codes.Error, span.Status().Code)
+			assert.Equal(t, "panic: boom", span.Status().Description)
+
+			require.Len(t, span.Events(), 1)
+			event := span.Events()[0]
+			assert.Equal(t, "exception", event.Name)
+			var hasStack bool
+			for _, kv := range event.Attributes {
+				if kv.Key == "exception.stacktrace" {
+					hasStack = kv.Value.AsString() != ""
+				}
+			}
+			assert.True(t, hasStack, "missing exception.stacktrace")
+
+			rm := metricdata.ResourceMetrics{}
+			require.NoError(t, reader.Collect(context.Background(), &rm))
+			require.Len(t, rm.ScopeMetrics, 1)
+			var panics *metricdata.Sum[int64]
+			for _, m := range rm.ScopeMetrics[0].Metrics {
+				if m.Name == "http.server.panics" {
+					sum, ok := m.Data.(metricdata.Sum[int64])
+					require.True(t, ok)
+					panics = &sum
+				}
+			}
+			require.NotNil(t, panics)
+			require.Len(t, panics.DataPoints, 1)
+			assert.Equal(t, int64(1), panics.DataPoints[0].Value)
+		})
+	}
+}
+
+func TestHandlerPanicRecoveryAbortHandler(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic(http.ErrAbortHandler)
+	}), "test_handler", otelhttp.WithTracerProvider(provider), otelhttp.WithPanicRecovery(false))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Empty(t, spans[0].Events())
+}
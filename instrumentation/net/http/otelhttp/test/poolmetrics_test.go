@@ -0,0 +1,99 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestTransportConnectionPoolMetrics(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	base := &http.Transport{}
+	defer base.CloseIdleConnections()
+	c := http.Client{Transport: otelhttp.NewTransport(base,
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithConnectionPoolMetrics(),
+	)}
+
+	for i := 0; i < 2; i++ {
+		res, err := c.Get(ts.URL)
+		require.NoError(t, err)
+		_, err = io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	u, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+	server := []attribute.KeyValue{semconv.ServerAddress(u.Hostname()), semconv.ServerPort(port)}
+	attrs := func(kv ...attribute.KeyValue) attribute.Set {
+		return attribute.NewSet(append(kv, server...)...)
+	}
+
+	// Connections are returned to the pool after the response body is read.
+	metrics := make(map[string]metricdata.Metrics)
+	require.Eventually(t, func() bool {
+		rm := metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		require.Len(t, rm.ScopeMetrics, 1)
+		for _, m := range rm.ScopeMetrics[0].Metrics {
+			metrics[m.Name] = m
+		}
+		open, ok := metrics["http.client.open_connections"].Data.(metricdata.Sum[int64])
+		return ok && len(open.DataPoints) == 2 && open.DataPoints[0].Value+open.DataPoints[1].Value == 1
+	}, time.Second, 10*time.Millisecond)
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attrs(semconv.HTTPConnectionStateActive), Value: 0},
+			{Attributes: attrs(semconv.HTTPConnectionStateIdle), Value: 1},
+		},
+		Temporality: metricdata.CumulativeTemporality,
+	}, metrics["http.client.open_connections"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		DataPoints: []metricdata.DataPoint[int64]{
+			{Attributes: attrs(attribute.Bool("http.connection.reused", false)), Value: 1},
+			{Attributes: attrs(attribute.Bool("http.connection.reused", true)), Value: 1},
+		},
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}, metrics["http.client.connection.acquired"].Data, metricdatatest.IgnoreTimestamp())
+
+	metricdatatest.AssertAggregationsEqual(t, metricdata.Sum[int64]{
+		DataPoints:  []metricdata.DataPoint[int64]{{Attributes: attrs(), Value: 1}},
+		Temporality: metricdata.CumulativeTemporality,
+		IsMonotonic: true,
+	}, metrics["http.client.connection.dials"].Data, metricdatatest.IgnoreTimestamp())
+
+	waitTime, ok := metrics["http.client.connection.wait_time"].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	require.Len(t, waitTime.DataPoints, 1)
+	assert.Equal(t, uint64(2), waitTime.DataPoints[0].Count)
+}
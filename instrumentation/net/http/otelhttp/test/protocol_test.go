@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// http3RoundTripper mimics the responses of an HTTP/3 RoundTripper, such as
+// the http3.Transport of github.com/quic-go/quic-go.
+type http3RoundTripper struct{}
+
+func (http3RoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/3.0",
+		ProtoMajor: 3,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}, nil
+}
+
+func TestTransportProtocolVersion(t *testing.T) {
+	h2 := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	h1 := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer h1.Close()
+
+	testCases := []struct {
+		name string
+		base http.RoundTripper
+		url  string
+		want string
+	}{
+		{name: "HTTP/1.1", base: http.DefaultTransport, url: h1.URL, want: "1.1"},
+		{name: "HTTP/2", base: h2.Client().Transport, url: h2.URL, want: "2"},
+		{name: "HTTP/3", base: http3RoundTripper{}, url: "https://example.com", want: "3"},
+	}
+
+	for _, tc := range testCases {
+		for _, dup := range []bool{false, true} {
+			name := tc.name
+			if dup {
+				name += "/dup"
+			}
+			t.Run(name, func(t *testing.T) {
+				if dup {
+					t.Setenv("OTEL_HTTP_CLIENT_COMPATIBILITY_MODE", "http/dup")
+				}
+				sr := tracetest.NewSpanRecorder()
+				provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+				c := http.Client{Transport: otelhttp.NewTransport(tc.base, otelhttp.WithTracerProvider(provider))}
+
+				res, err := c.Get(tc.url)
+				require.NoError(t, err)
+				require.NoError(t, res.Body.Close())
+
+				require.Len(t, sr.Ended(), 1)
+				attrs := sr.Ended()[0].Attributes()
+				assert.Contains(t, attrs, semconv.NetProtocolVersion(tc.want))
+				if dup {
+					assert.Contains(t, attrs, semconvNew.NetworkProtocolVersion(tc.want))
+				} else {
+					for _, kv := range attrs {
+						assert.NotEqual(t, semconvNew.NetworkProtocolVersionKey, kv.Key)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTransportRedirects(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/old", http.RedirectHandler("/moved", http.StatusMovedPermanently))
+	mux.Handle("/moved", http.RedirectHandler("/new", http.StatusFound))
+	mux.HandleFunc("/new", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport, otelhttp.WithTracerProvider(provider))}
+
+	res, err := c.Get(ts.URL + "/old")
+	require.NoError(t, err)
+	_, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+	statusCodes := []int{http.StatusMovedPermanently, http.StatusFound, http.StatusOK}
+	for i, span := range spans {
+		assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", statusCodes[i]))
+		if i == 0 {
+			assert.Empty(t, span.Links())
+			for _, kv := range span.Attributes() {
+				assert.NotEqual(t, resendCountKey, kv.Key)
+			}
+			continue
+		}
+		assert.Contains(t, span.Attributes(), resendCountKey.Int(i))
+		require.Len(t, span.Links(), 1)
+		link := span.Links()[0]
+		assert.Equal(t, spans[i-1].SpanContext(), link.SpanContext)
+		assert.Equal(t, []attribute.KeyValue{attribute.Int("http.response.status_code", statusCodes[i-1])}, link.Attributes)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const resendCountKey = attribute.Key("http.request.resend_count")
+
+func TestTransportRetryPolicy(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithRetryPolicy(otelhttp.RetryIdempotent(3, time.Millisecond)),
+	)}
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader("payload"))
+	require.NoError(t, err)
+	req.Header.Set("Idempotency-Key", "42")
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+
+	// The span of the last attempt ends after its response body is read.
+	spans := sr.Ended()
+	require.Len(t, spans, 4)
+	attempts, parent := []sdktrace.ReadOnlySpan{spans[0], spans[1], spans[3]}, spans[2]
+	assert.Equal(t, "HTTP POST", parent.Name())
+	assert.Equal(t, trace.SpanKindInternal, parent.SpanKind())
+	assert.Contains(t, parent.Attributes(), resendCountKey.Int(2))
+	for i, span := range attempts {
+		assert.Equal(t, trace.SpanKindClient, span.SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		if i == 0 {
+			for _, kv := range span.Attributes() {
+				assert.NotEqual(t, resendCountKey, kv.Key)
+			}
+		} else {
+			assert.Contains(t, span.Attributes(), resendCountKey.Int(i))
+		}
+	}
+	assert.Equal(t, codes.Error, attempts[0].Status().Code)
+	assert.Equal(t, codes.Unset, attempts[2].Status().Code)
+}
+
+func TestTransportRetryPolicyNotRewindable(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithRetryPolicy(otelhttp.RetryIdempotent(3, time.Millisecond)),
+	)}
+
+	// The body cannot be rewound without GetBody.
+	req, err := http.NewRequest(http.MethodPut, ts.URL, io.NopCloser(strings.NewReader("payload")))
+	require.NoError(t, err)
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+	assert.Equal(t, 1, requests)
+	require.Len(t, sr.Ended(), 2)
+	assert.Equal(t, codes.Error, sr.Ended()[1].Status().Code)
+}
+
+func TestTransportResendCount(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport, otelhttp.WithTracerProvider(provider))}
+
+	ctx := otelhttp.ContextWithResendCount(context.Background(), 2)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.Len(t, sr.Ended(), 1)
+	assert.Contains(t, sr.Ended()[0].Attributes(), resendCountKey.Int(2))
+}
+
+func TestTransportRetryPolicyFiltered(t *testing.T) {
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithFilter(func(*http.Request) bool { return false }),
+		otelhttp.WithRetryPolicy(otelhttp.RetryIdempotent(3, time.Millisecond)),
+	)}
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL, nil)
+	require.NoError(t, err)
+	res, err := c.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	// Filtered requests are retried, but without telemetry.
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, requests)
+	assert.Empty(t, sr.Ended())
+}
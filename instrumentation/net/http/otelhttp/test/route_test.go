@@ -0,0 +1,124 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+)
+
+func TestServeMuxRoute(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("example.com/items/", func(http.ResponseWriter, *http.Request) {})
+	route := otelhttp.ServeMuxRoute(mux)
+
+	for target, want := range map[string]string{
+		"http://localhost/users/42":   "/users/",
+		"http://example.com/items/42": "/items/",
+		"http://localhost/missing":    "",
+	} {
+		assert.Equal(t, want, route(httptest.NewRequest(http.MethodGet, target, nil)), target)
+	}
+}
+
+func TestWithRouteFunc(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/users/", func(http.ResponseWriter, *http.Request) {})
+	h := otelhttp.NewHandler(mux, "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithRouteFunc(otelhttp.ServeMuxRoute(mux)),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/users/42", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/missing", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "GET /users/", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRoute("/users/"))
+	assert.Equal(t, "test_handler", spans[1].Name())
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	duration, ok := rm.ScopeMetrics[0].Metrics[2].Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+	var routed int
+	for _, dp := range duration.DataPoints {
+		if v, ok := dp.Attributes.Value(semconv.HTTPRouteKey); ok {
+			assert.Equal(t, "/users/", v.AsString())
+			routed++
+		}
+	}
+	assert.Equal(t, 1, routed)
+}
+
+func TestNewRouteMiddleware(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	// The middleware is registered with the router, after it matched the
+	// route of the request.
+	router := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Set("X-Route", "/jobs/{id}")
+			next.ServeHTTP(w, r)
+		})
+	}
+	mw := otelhttp.NewRouteMiddleware(func(r *http.Request) string {
+		return r.Header.Get("X-Route")
+	})
+	h := otelhttp.NewHandler(
+		router(mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))),
+		"test_handler",
+		otelhttp.WithTracerProvider(provider),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "http://localhost/jobs/42", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "POST /jobs/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRoute("/jobs/{id}"))
+}
+
+func TestNewRouteMiddlewareGorillaMux(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	router := mux.NewRouter()
+	router.Use(otelhttp.NewRouteMiddleware(func(r *http.Request) string {
+		tmpl, _ := mux.CurrentRoute(r).GetPathTemplate()
+		return tmpl
+	}))
+	router.HandleFunc("/users/{id}", func(http.ResponseWriter, *http.Request) {})
+	h := otelhttp.NewHandler(router, "test_handler", otelhttp.WithTracerProvider(provider))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/users/42", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /users/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), semconv.HTTPRoute("/users/{id}"))
+}
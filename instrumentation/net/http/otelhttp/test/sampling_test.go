@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/filters"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/sampler"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerSamplingHint(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sr),
+		sdktrace.WithSampler(sampler.HintBased(sdktrace.TraceIDRatioBased(0.5))),
+	)
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSamplingHint(filters.Route("GET /healthz"), otelhttp.SamplingHintDrop),
+		otelhttp.WithSamplingHint(filters.Route("/admin/*"), otelhttp.SamplingHintRecord),
+		otelhttp.WithSamplingHint(filters.Route("/*"), otelhttp.SamplingHintDrop),
+	)
+
+	for i := 0; i < 10; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/admin/users/42", nil))
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 10)
+	for _, span := range spans {
+		assert.Contains(t, span.Attributes(), otelhttp.SamplingHintKey.String("record"))
+	}
+}
+
+func TestTransportSamplingHint(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(sr),
+		sdktrace.WithSampler(sampler.HintBased(sdktrace.AlwaysSample())),
+	)
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSamplingHint(filters.Route("/metrics"), otelhttp.SamplingHintDrop),
+	)}
+
+	for _, p := range []string{"/metrics", "/orders"} {
+		res, err := c.Get(ts.URL + p)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 1)
+	for _, kv := range spans[0].Attributes() {
+		assert.NotEqual(t, otelhttp.SamplingHintKey, kv.Key)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerSlowRequestThreshold(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	var logs bytes.Buffer
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(20 * time.Millisecond)
+		}
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithSlowRequestThreshold(10*time.Millisecond),
+		otelhttp.WithSlowRequestLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Empty(t, spans[0].Events())
+	require.Len(t, spans[1].Events(), 1)
+	event := spans[1].Events()[0]
+	assert.Equal(t, "slow_request", event.Name)
+	assert.Contains(t, event.Attributes, otelhttp.SlowRequestThresholdKey.Float64(0.01))
+	for _, kv := range event.Attributes {
+		if kv.Key == otelhttp.SlowRequestDurationKey {
+			assert.GreaterOrEqual(t, kv.Value.AsFloat64(), 0.02)
+		}
+	}
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	var found bool
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.slow_requests" {
+			continue
+		}
+		found = true
+		sum, ok := m.Data.(metricdata.Sum[int64])
+		require.True(t, ok)
+		require.Len(t, sum.DataPoints, 1)
+		assert.Equal(t, int64(1), sum.DataPoints[0].Value)
+	}
+	assert.True(t, found, "missing http.server.slow_requests metric")
+
+	assert.Contains(t, logs.String(), `level=WARN msg="slow HTTP request" method=GET path=/slow status=200`)
+}
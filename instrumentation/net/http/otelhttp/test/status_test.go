@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerSpanStatusMapper(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanStatusMapper(func(r *http.Request, statusCode int) (codes.Code, string) {
+			if statusCode == http.StatusTooManyRequests {
+				return codes.Error, "rate limited"
+			}
+			return otelhttp.ServerSpanStatus(statusCode)
+		}),
+	)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/limited", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, sdktrace.Status{Code: codes.Error, Description: "rate limited"}, spans[0].Status())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestTransportSpanStatusMapper(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithSpanStatusMapper(func(r *http.Request, statusCode int) (codes.Code, string) {
+			if statusCode == http.StatusNotFound && r.URL.Path == "/lookup" {
+				return codes.Unset, ""
+			}
+			return otelhttp.ClientSpanStatus(statusCode)
+		}),
+	)}
+
+	for _, p := range []string{"/lookup", "/other"} {
+		res, err := c.Get(ts.URL + p)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestDefaultSpanStatus(t *testing.T) {
+	code, _ := otelhttp.ServerSpanStatus(http.StatusNotFound)
+	assert.Equal(t, codes.Unset, code)
+	code, _ = otelhttp.ClientSpanStatus(http.StatusNotFound)
+	assert.Equal(t, codes.Error, code)
+	code, desc := otelhttp.ServerSpanStatus(999)
+	assert.Equal(t, codes.Error, code)
+	assert.Equal(t, "Invalid HTTP status code 999", desc)
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestHandlerStreamProgress(t *testing.T) {
+	testCases := []struct {
+		name      string
+		handler   http.HandlerFunc
+		wantAttrs []attribute.KeyValue
+		streamed  bool
+	}{
+		{
+			name: "server-sent events",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/event-stream")
+				for i := 0; i < 3; i++ {
+					_, _ = fmt.Fprintf(w, "id: %d\r\ndata: tick\r\n\r\n", i)
+					time.Sleep(20 * time.Millisecond)
+				}
+			},
+			wantAttrs: []attribute.KeyValue{
+				otelhttp.StreamBytesSentKey.Int64(3 * 21),
+				otelhttp.StreamEventsSentKey.Int64(3),
+			},
+			streamed: true,
+		},
+		{
+			name: "flushed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				for i := 0; i < 3; i++ {
+					_, _ = io.WriteString(w, "chunk")
+					w.(http.Flusher).Flush()
+					time.Sleep(20 * time.Millisecond)
+				}
+			},
+			wantAttrs: []attribute.KeyValue{otelhttp.StreamBytesSentKey.Int64(15)},
+			streamed:  true,
+		},
+		{
+			name: "not streamed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "hello")
+				time.Sleep(20 * time.Millisecond)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sr := tracetest.NewSpanRecorder()
+			provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+			h := otelhttp.NewHandler(tc.handler, "test_handler",
+				otelhttp.WithTracerProvider(provider),
+				otelhttp.WithStreamProgressInterval(5*time.Millisecond),
+			)
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+
+			spans := sr.Ended()
+			require.Len(t, spans, 1)
+			for _, kv := range tc.wantAttrs {
+				assert.Contains(t, spans[0].Attributes(), kv)
+			}
+			var progress int
+			for _, e := range spans[0].Events() {
+				if e.Name == "stream.progress" {
+					progress++
+				}
+			}
+			if tc.streamed {
+				assert.Positive(t, progress)
+			} else {
+				assert.Zero(t, progress)
+				for _, kv := range spans[0].Attributes() {
+					assert.NotEqual(t, otelhttp.StreamBytesSentKey, kv.Key)
+				}
+			}
+		})
+	}
+}
+
+// serveAborted serves req with h, whose handler aborts with
+// http.ErrAbortHandler, recovering from the panic as net/http does.
+func serveAborted(t *testing.T, h http.Handler, req *http.Request) {
+	t.Helper()
+	defer func() {
+		assert.Equal(t, http.ErrAbortHandler, recover())
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// assertGoroutinesStopped asserts that the number of goroutines goes back
+// to before, the number before the requests. It is polled without
+// assert.Eventually, which runs the condition in its own goroutine.
+func assertGoroutinesStopped(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before, "progress reporting not stopped")
+}
+
+func TestHandlerStreamProgressAborted(t *testing.T) {
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "chunk")
+		w.(http.Flusher).Flush()
+		panic(http.ErrAbortHandler)
+	}), "test_handler",
+		otelhttp.WithTracerProvider(sdktrace.NewTracerProvider()),
+		otelhttp.WithStreamProgressInterval(time.Millisecond),
+	)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		serveAborted(t, h, httptest.NewRequest(http.MethodGet, "http://localhost/", nil))
+	}
+	assertGoroutinesStopped(t, before)
+}
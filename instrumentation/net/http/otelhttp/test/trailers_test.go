@@ -0,0 +1,70 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// trailersHandler writes a gRPC-Web like response with its status in
+// trailers, both declared and prefixed.
+var trailersHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Trailer", "Grpc-Status")
+	_, _ = io.WriteString(w, "payload")
+	w.Header().Set("Grpc-Status", "5")
+	w.Header().Set(http.TrailerPrefix+"Grpc-Message", "not found")
+})
+
+func TestHandlerResponseTrailers(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(trailersHandler, "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedResponseTrailers("grpc-status", "Grpc-Message", "Grpc-Status-Details-Bin"),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil))
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.trailer.grpc-status", []string{"5"}))
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.trailer.grpc-message", []string{"not found"}))
+	for _, kv := range attrs {
+		assert.NotEqual(t, attribute.Key("http.response.trailer.grpc-status-details-bin"), kv.Key)
+	}
+}
+
+func TestTransportResponseTrailers(t *testing.T) {
+	ts := httptest.NewServer(trailersHandler)
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithCapturedResponseTrailers("Grpc-Status", "Grpc-Message"),
+	)}
+
+	res, err := c.Post(ts.URL, "application/grpc-web", nil)
+	require.NoError(t, err)
+	_, err = io.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	require.Len(t, sr.Ended(), 1)
+	attrs := sr.Ended()[0].Attributes()
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.trailer.grpc-status", []string{"5"}))
+	assert.Contains(t, attrs, attribute.StringSlice("http.response.trailer.grpc-message", []string{"not found"}))
+}
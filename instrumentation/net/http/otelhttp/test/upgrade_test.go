@@ -0,0 +1,96 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHandlerUpgrade(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	done := make(chan struct{})
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		conn, brw, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		defer conn.Close()
+
+		// The span of the request ends with the upgrade.
+		assert.False(t, trace.SpanFromContext(r.Context()).IsRecording())
+
+		_, span := otelhttp.StartUpgradedConnSpan(r.Context(), r)
+		_, err = brw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n\r\n")
+		require.NoError(t, err)
+		require.NoError(t, brw.Flush())
+
+		msg, err := brw.ReadString('\n')
+		require.NoError(t, err)
+		span.MessageReceived(len(msg))
+		_, err = brw.WriteString(msg)
+		require.NoError(t, err)
+		require.NoError(t, brw.Flush())
+		span.MessageSent(len(msg), attribute.String("websocket.message.type", "text"))
+		span.End(nil)
+	}), "test_handler", otelhttp.WithTracerProvider(provider))
+
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(ts.URL, "http://"))
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = io.WriteString(conn, "GET /chat HTTP/1.1\r\nHost: localhost\r\nConnection: keep-alive, Upgrade\r\nUpgrade: websocket\r\n\r\n")
+	require.NoError(t, err)
+	br := bufio.NewReader(conn)
+	res, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusSwitchingProtocols, res.StatusCode)
+	_, err = io.WriteString(conn, "hello\n")
+	require.NoError(t, err)
+	echo, err := br.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", echo)
+	<-done
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	httpSpan, connSpan := spans[0], spans[1]
+
+	assert.Equal(t, "test_handler", httpSpan.Name())
+	assert.Contains(t, httpSpan.Attributes(), otelhttp.UpgradeKey.String("websocket"))
+	assert.Contains(t, httpSpan.Attributes(), semconv.HTTPStatusCode(http.StatusSwitchingProtocols))
+
+	assert.Equal(t, "websocket connection", connSpan.Name())
+	assert.Equal(t, httpSpan.SpanContext().SpanID(), connSpan.Parent().SpanID())
+	require.Len(t, connSpan.Events(), 2)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("message.type", "RECEIVED"),
+		attribute.Int64("message.id", 1),
+		attribute.Int("message.uncompressed_size", 6),
+	}, connSpan.Events()[0].Attributes)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("message.type", "SENT"),
+		attribute.Int64("message.id", 1),
+		attribute.Int("message.uncompressed_size", 6),
+		attribute.String("websocket.message.type", "text"),
+	}, connSpan.Events()[1].Attributes)
+}
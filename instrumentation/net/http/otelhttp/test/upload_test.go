@@ -0,0 +1,123 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// slowBody returns chunks of a body, waiting delay before each of them.
+type slowBody struct {
+	chunks []string
+	delay  time.Duration
+}
+
+func (b *slowBody) Read(p []byte) (int, error) {
+	if len(b.chunks) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(b.delay)
+	n := copy(p, b.chunks[0])
+	b.chunks = b.chunks[1:]
+	return n, nil
+}
+
+func TestHandlerUploadProgress(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := io.Copy(io.Discard, r.Body)
+		assert.NoError(t, err)
+	}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithMeterProvider(meterProvider),
+		otelhttp.WithUploadProgressInterval(10*time.Millisecond),
+	)
+	body := &slowBody{chunks: []string{"abcd", "efgh", "ijkl"}, delay: 30 * time.Millisecond}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/upload", body))
+
+	require.Len(t, sr.Ended(), 1)
+	span := sr.Ended()[0]
+	assert.Contains(t, span.Attributes(), otelhttp.UploadBytesReceivedKey.Int64(12))
+
+	var events int
+	for _, event := range span.Events() {
+		if event.Name != "upload.progress" {
+			continue
+		}
+		events++
+		// The handler only waits for the slow body.
+		assert.Contains(t, event.Attributes, otelhttp.UploadWaitingKey.Bool(true))
+	}
+	assert.Positive(t, events)
+
+	rm := metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	var found bool
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name != "http.server.request.body.throughput" {
+			continue
+		}
+		found = true
+		hist, ok := m.Data.(metricdata.Histogram[float64])
+		require.True(t, ok)
+		require.Len(t, hist.DataPoints, 1)
+		assert.Equal(t, uint64(1), hist.DataPoints[0].Count)
+		assert.Positive(t, hist.DataPoints[0].Sum)
+	}
+	assert.True(t, found, "missing http.server.request.body.throughput metric")
+}
+
+func TestHandlerUploadProgressNoBody(t *testing.T) {
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), "test_handler",
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithUploadProgressInterval(time.Millisecond),
+	)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	require.Len(t, sr.Ended(), 1)
+	assert.Empty(t, sr.Ended()[0].Events())
+	for _, kv := range sr.Ended()[0].Attributes() {
+		assert.NotEqual(t, otelhttp.UploadBytesReceivedKey, kv.Key)
+	}
+}
+
+func TestHandlerUploadProgressAborted(t *testing.T) {
+	h := otelhttp.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = r.Body.Read(make([]byte, 1))
+		panic(http.ErrAbortHandler)
+	}), "test_handler",
+		otelhttp.WithTracerProvider(sdktrace.NewTracerProvider()),
+		otelhttp.WithUploadProgressInterval(time.Millisecond),
+	)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 50; i++ {
+		serveAborted(t, h, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("hello")))
+	}
+	assertGoroutinesStopped(t, before)
+}
@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+func TestIDTemplate(t *testing.T) {
+	for path, want := range map[string]string{
+		"/":                  "/",
+		"/v1/jobs/42":        "/v1/jobs/{id}",
+		"/v1/jobs/42/logs/7": "/v1/jobs/{id}/logs/{id}",
+		"/users/0b8a3c1e-5f6d-4e2a-9c7b-1d2e3f4a5b6c":       "/users/{id}",
+		"/commits/9fceb02d0ae598e95dc970b74767f19372d61af8": "/commits/{id}",
+		"/cafe/beef":        "/cafe/beef",
+		"/v2/items/abc-123": "/v2/items/abc-123",
+	} {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		assert.Equal(t, want, otelhttp.IDTemplate(r), path)
+	}
+}
+
+func TestTransportURLTemplate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer ts.Close()
+
+	sr := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	c := http.Client{Transport: otelhttp.NewTransport(http.DefaultTransport,
+		otelhttp.WithTracerProvider(provider),
+		otelhttp.WithURLTemplateFunc(otelhttp.IDTemplate),
+	)}
+
+	for _, id := range []string{"1", "2"} {
+		res, err := c.Get(ts.URL + "/v1/jobs/" + id)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "GET /v1/jobs/{id}", span.Name())
+		assert.Contains(t, span.Attributes(), semconv.URLTemplate("/v1/jobs/{id}"))
+	}
+}
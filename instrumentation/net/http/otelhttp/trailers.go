@@ -0,0 +1,52 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strings"
+)
+
+// handlerTrailers returns the trailers set by a handler in the header h of
+// its response, either declared in the Trailer header or prefixed with
+// http.TrailerPrefix.
+func handlerTrailers(h http.Header) http.Header {
+	var trailers http.Header
+	add := func(name string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		if trailers == nil {
+			trailers = make(http.Header)
+		}
+		trailers[http.CanonicalHeaderKey(name)] = values
+	}
+	for _, v := range h.Values("Trailer") {
+		for _, name := range strings.Split(v, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			add(name, h[name])
+		}
+	}
+	for name, values := range h {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			add(strings.TrimPrefix(name, http.TrailerPrefix), values)
+		}
+	}
+	return trailers
+}
+
+// WithCapturedResponseTrailers configures the Handler and Transport to record
+// the response trailers named by trailers, e.g. the Grpc-Status trailer of
+// gRPC-Web, as http.response.trailer.<key> span attributes, where <key> is
+// the lowercase trailer name.
+//
+// The Transport records the trailers once the response body is fully read,
+// or closed. The Handler records the trailers set by the handler when it
+// returns, either declared in the Trailer header or prefixed with
+// http.TrailerPrefix.
+func WithCapturedResponseTrailers(trailers ...string) Option {
+	return optionFunc(func(c *config) {
+		c.CapturedResponseTrailers = append(c.CapturedResponseTrailers, trailers...)
+	})
+}
@@ -13,13 +13,12 @@ import (
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/request"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconv"
-	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp/internal/semconvutil"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
-
+	semconvNew "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
@@ -35,11 +34,29 @@ type Transport struct {
 	filters           []Filter
 	spanNameFormatter func(string, *http.Request) string
 	clientTrace       func(context.Context) *httptrace.ClientTrace
+	connectionTrace   bool
+	bodies            *bodyCapturer
+	requestHeaders    *headerCapturer
+	responseHeaders   *headerCapturer
+	responseTrailers  *headerCapturer
+	retryPolicy       RetryPolicy
+
+	requestExtractors  []RequestAttributeExtractor
+	responseExtractors []ResponseAttributeExtractor
+	samplingRules      []samplingRule
+	statusMapper       SpanStatusMapper
+	metricAttributesFn func(*http.Request) []attribute.KeyValue
+	baggageKeys        []string
+	urlTemplateFunc    URLTemplateFunc
 
 	semconv              semconv.HTTPClient
 	requestBytesCounter  metric.Int64Counter
 	responseBytesCounter metric.Int64Counter
 	latencyMeasure       metric.Float64Histogram
+	poolMetrics          *poolMetrics
+	compression          *compressionMetrics
+	hosts                *hostLimiter
+	cacheStatusHeaders   []CacheStatusHeader
 }
 
 var _ http.RoundTripper = &Transport{}
@@ -50,6 +67,21 @@ var _ http.RoundTripper = &Transport{}
 //
 // If the provided http.RoundTripper is nil, http.DefaultTransport will be used
 // as the base http.RoundTripper.
+//
+// When an http.Client follows redirects, every hop is recorded by its own
+// span, with the status code of its response. The spans of the hops after
+// the first have the http.request.resend_count attribute, counting the
+// redirects followed, and a link to the span of the previous hop, with the
+// http.response.status_code of the redirect, if the base http.RoundTripper
+// sets the Request of its responses, as http.Transport does.
+//
+// The base http.RoundTripper can use any version of HTTP, e.g. HTTP/3 with
+// the http3.Transport of github.com/quic-go/quic-go, which the otelhttp3
+// module also records the QUIC handshakes of. The version a response was
+// received with is recorded as the protocol version attribute of the span.
+// The connection trace and pool metrics, see WithConnectionTrace and
+// WithConnectionPoolMetrics, are only recorded for RoundTrippers reporting
+// the events of net/http/httptrace, such as http.Transport.
 func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
 	if base == nil {
 		base = http.DefaultTransport
@@ -68,6 +100,13 @@ func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
 	c := newConfig(append(defaultOpts, opts...)...)
 	t.applyConfig(c)
 	t.createMeasures()
+	if c.ConnectionPoolMetrics {
+		t.poolMetrics = newPoolMetrics(t.meter)
+		t.poolMetrics.hosts = t.hosts
+	}
+	if c.CompressionMetrics {
+		t.compression = newCompressionMetrics(t.meter, "http.client", c.CompressionSizeLimit)
+	}
 
 	return &t
 }
@@ -80,6 +119,21 @@ func (t *Transport) applyConfig(c *config) {
 	t.filters = c.Filters
 	t.spanNameFormatter = c.SpanNameFormatter
 	t.clientTrace = c.ClientTrace
+	t.connectionTrace = c.ConnectionTrace
+	t.bodies = newBodyCapturer(c)
+	t.requestHeaders = newHeaderCapturer("http.request.header.", c.CapturedRequestHeaders)
+	t.responseHeaders = newHeaderCapturer("http.response.header.", c.CapturedResponseHeaders)
+	t.responseTrailers = newHeaderCapturer("http.response.trailer.", c.CapturedResponseTrailers)
+	t.retryPolicy = c.RetryPolicy
+	t.requestExtractors = c.RequestAttributeExtractors
+	t.responseExtractors = c.ResponseAttributeExtractors
+	t.samplingRules = c.SamplingRules
+	t.statusMapper = c.SpanStatusMapper
+	t.metricAttributesFn = c.MetricAttributesFn
+	t.baggageKeys = c.BaggageMetricAttributes
+	t.urlTemplateFunc = c.URLTemplateFunc
+	t.hosts = newHostLimiter(c.HostMetricsLimit)
+	t.cacheStatusHeaders = c.CacheStatusHeaders
 }
 
 func (t *Transport) createMeasures() {
@@ -110,35 +164,94 @@ func defaultTransportFormatter(_ string, r *http.Request) string {
 	return "HTTP " + r.Method
 }
 
+// urlTemplate returns the template of the URL of r, if known.
+func (t *Transport) urlTemplate(r *http.Request) string {
+	if t.urlTemplateFunc == nil {
+		return ""
+	}
+	return t.urlTemplateFunc(r)
+}
+
+// spanStatus returns the status of the span of r, whose response has the
+// status code statusCode.
+func (t *Transport) spanStatus(r *http.Request, statusCode int) (codes.Code, string) {
+	if t.statusMapper != nil {
+		return t.statusMapper(r, statusCode)
+	}
+	return t.semconv.Status(statusCode)
+}
+
 // RoundTrip creates a Span and propagates its context via the provided request's headers
 // before handing the request to the configured base RoundTripper. The created span will
 // end when the response body is closed or when a read from the body returns io.EOF.
 func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
-	requestStartTime := time.Now()
+	traced := true
 	for _, f := range t.filters {
 		if !f(r) {
 			// Simply pass through to the base RoundTripper if a filter rejects the request
-			return t.rt.RoundTrip(r)
+			traced = false
+			break
 		}
 	}
 
-	tracer := t.tracer
+	if t.retryPolicy != nil {
+		return t.roundTripWithRetries(r, traced)
+	}
+	if !traced {
+		return t.rt.RoundTrip(r)
+	}
+	return t.roundTrip(r)
+}
 
-	if tracer == nil {
-		if span := trace.SpanFromContext(r.Context()); span.SpanContext().IsValid() {
-			tracer = newTracer(span.TracerProvider())
-		} else {
-			tracer = newTracer(otel.GetTracerProvider())
-		}
+func (t *Transport) getTracer(ctx context.Context) trace.Tracer {
+	if t.tracer != nil {
+		return t.tracer
+	}
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		return newTracer(span.TracerProvider())
 	}
+	return newTracer(otel.GetTracerProvider())
+}
+
+// roundTrip sends r, a request accepted by the filters, within a span.
+func (t *Transport) roundTrip(r *http.Request) (*http.Response, error) {
+	requestStartTime := time.Now()
+	tracer := t.getTracer(r.Context())
 
 	opts := append([]trace.SpanStartOption{}, t.spanStartOptions...) // start with the configured options
+	if count := resendCount(r.Context()) + redirectCount(r); count > 0 {
+		opts = append(opts, trace.WithAttributes(semconvNew.HTTPRequestResendCount(count)))
+	}
+	if links := redirectLinks(r); len(links) > 0 {
+		opts = append(opts, trace.WithLinks(links...))
+	}
+	if len(t.requestExtractors) > 0 {
+		opts = append(opts, trace.WithAttributes(extractRequestAttributes(t.requestExtractors, r)...))
+	}
+	if len(t.samplingRules) > 0 {
+		opts = append(opts, trace.WithAttributes(samplingHintAttrs(t.samplingRules, r)...))
+	}
+
+	spanName := t.spanNameFormatter("", r)
+	if tmpl := t.urlTemplate(r); tmpl != "" {
+		spanName = routeSpanName(r.Method, tmpl)
+		opts = append(opts, trace.WithAttributes(semconvNew.URLTemplate(tmpl)))
+	}
 
-	ctx, span := tracer.Start(r.Context(), t.spanNameFormatter("", r), opts...)
+	ctx, span := tracer.Start(r.Context(), spanName, opts...)
 
 	if t.clientTrace != nil {
 		ctx = httptrace.WithClientTrace(ctx, t.clientTrace(ctx))
 	}
+	if t.connectionTrace {
+		ctx = httptrace.WithClientTrace(ctx, newConnectionTrace(ctx, tracer))
+	}
+	var pool *poolTracer
+	if t.poolMetrics != nil {
+		var ct *httptrace.ClientTrace
+		ct, pool = t.poolMetrics.trace(ctx, r)
+		ctx = httptrace.WithClientTrace(ctx, ct)
+	}
 
 	labeler, found := LabelerFromContext(ctx)
 	if !found {
@@ -150,15 +263,31 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 	// if request body is nil or NoBody, we don't want to mutate the body as it
 	// will affect the identity of it in an unforeseeable way because we assert
 	// ReadCloser fulfills a certain interface and it is indeed nil or NoBody.
+	var reqBody *bodyCapture
+	if r.Body != nil && r.Body != http.NoBody {
+		reqBody = t.bodies.capture(RequestBodyKey, r.Header.Get("Content-Type"))
+		if reqBody != nil {
+			r.Body = &captureReader{ReadCloser: r.Body, capture: reqBody}
+		}
+	}
+	var reqCompressed *compressedBody
+	if t.compression != nil && r.Body != nil && r.Body != http.NoBody {
+		reqCompressed = t.compression.newBody(r.Header.Get("Content-Encoding"))
+		if reqCompressed != nil {
+			r.Body = &compressedReader{ReadCloser: r.Body, body: reqCompressed}
+		}
+	}
 	bw := request.NewBodyWrapper(r.Body, func(int64) {})
 	if r.Body != nil && r.Body != http.NoBody {
 		r.Body = bw
 	}
 
 	span.SetAttributes(t.semconv.RequestTraceAttrs(r)...)
+	span.SetAttributes(t.requestHeaders.attrs(r.Header)...)
 	t.propagators.Inject(ctx, propagation.HeaderCarrier(r.Header))
 
 	res, err := t.rt.RoundTrip(r)
+	reqBody.record(span)
 	if err != nil {
 		// set error type attribute if the error is part of the predefined
 		// error types.
@@ -171,27 +300,61 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 
 		span.SetStatus(codes.Error, err.Error())
 		span.End()
+		pool.release()
+		reqCompressed.stop()
 		return res, err
 	}
 
 	// metrics
-	metricAttrs := append(labeler.Get(), semconvutil.HTTPClientRequestMetrics(r)...)
+	metricAttrs := append(labeler.Get(), requestMetricAttrs(r, t.hosts)...)
+	if t.metricAttributesFn != nil {
+		metricAttrs = append(metricAttrs, t.metricAttributesFn(r)...)
+	}
+	metricAttrs = append(metricAttrs, baggageAttrs(ctx, t.baggageKeys)...)
 	if res.StatusCode > 0 {
 		metricAttrs = append(metricAttrs, semconv.HTTPStatusCode(res.StatusCode))
 	}
 	o := metric.WithAttributeSet(attribute.NewSet(metricAttrs...))
 
 	t.requestBytesCounter.Add(ctx, bw.BytesRead(), o)
+	var respCompressed *compressedBody
+	if _, ok := res.Body.(io.ReadWriteCloser); t.compression != nil && !ok {
+		respCompressed = t.compression.newBody(res.Header.Get("Content-Encoding"))
+		if respCompressed != nil {
+			res.Body = &compressedReader{ReadCloser: res.Body, body: respCompressed}
+		}
+	}
 	// For handling response bytes we leverage a callback when the client reads the http response
 	readRecordFunc := func(n int64) {
 		t.responseBytesCounter.Add(ctx, n, o)
+		pool.release()
+		// The trailers are received after the body.
+		span.SetAttributes(t.responseTrailers.attrs(res.Trailer)...)
+		if t.compression != nil {
+			t.compression.record(ctx, reqCompressed, respCompressed, o)
+			if res.Uncompressed {
+				t.compression.responseUncompressed.Record(ctx, n, o)
+			}
+		}
 	}
 
 	// traces
 	span.SetAttributes(t.semconv.ResponseTraceAttrs(res)...)
-	span.SetStatus(t.semconv.Status(res.StatusCode))
+	span.SetAttributes(t.semconv.ProtocolVersion(res)...)
+	span.SetAttributes(t.responseHeaders.attrs(res.Header)...)
+	span.SetAttributes(extractResponseAttributes(t.responseExtractors, res)...)
+	if len(t.cacheStatusHeaders) > 0 {
+		span.SetAttributes(cacheAttrs(t.cacheStatusHeaders, res)...)
+	}
+	span.SetStatus(t.spanStatus(r, res.StatusCode))
 
-	res.Body = newWrappedBody(span, readRecordFunc, res.Body)
+	var respBody *bodyCapture
+	if _, ok := res.Body.(io.ReadWriteCloser); !ok {
+		// Bodies of protocol switches are not captured, they are
+		// connections.
+		respBody = t.bodies.capture(ResponseBodyKey, res.Header.Get("Content-Type"))
+	}
+	res.Body = newWrappedBody(span, readRecordFunc, respBody, res.Body)
 
 	// Use floating point division here for higher precision (instead of Millisecond method).
 	elapsedTime := float64(time.Since(requestStartTime)) / float64(time.Millisecond)
@@ -204,17 +367,17 @@ func (t *Transport) RoundTrip(r *http.Request) (*http.Response, error) {
 // newWrappedBody returns a new and appropriately scoped *wrappedBody as an
 // io.ReadCloser. If the passed body implements io.Writer, the returned value
 // will implement io.ReadWriteCloser.
-func newWrappedBody(span trace.Span, record func(n int64), body io.ReadCloser) io.ReadCloser {
+func newWrappedBody(span trace.Span, record func(n int64), capture *bodyCapture, body io.ReadCloser) io.ReadCloser {
 	// The successful protocol switch responses will have a body that
 	// implement an io.ReadWriteCloser. Ensure this interface type continues
 	// to be satisfied if that is the case.
 	if _, ok := body.(io.ReadWriteCloser); ok {
-		return &wrappedBody{span: span, record: record, body: body}
+		return &wrappedBody{span: span, record: record, capture: capture, body: body}
 	}
 
 	// Remove the implementation of the io.ReadWriteCloser and only implement
 	// the io.ReadCloser.
-	return struct{ io.ReadCloser }{&wrappedBody{span: span, record: record, body: body}}
+	return struct{ io.ReadCloser }{&wrappedBody{span: span, record: record, capture: capture, body: body}}
 }
 
 // wrappedBody is the response body type returned by the transport
@@ -229,6 +392,7 @@ type wrappedBody struct {
 	span     trace.Span
 	recorded atomic.Bool
 	record   func(n int64)
+	capture  *bodyCapture
 	body     io.ReadCloser
 	read     atomic.Int64
 }
@@ -249,12 +413,16 @@ func (wb *wrappedBody) Read(b []byte) (int, error) {
 	n, err := wb.body.Read(b)
 	// Record the number of bytes read
 	wb.read.Add(int64(n))
+	if wb.capture != nil {
+		wb.capture.write(b[:n])
+	}
 
 	switch err {
 	case nil:
 		// nothing to do here but fall through to the return
 	case io.EOF:
 		wb.recordBytesRead()
+		wb.capture.record(wb.span)
 		wb.span.End()
 	default:
 		wb.span.RecordError(err)
@@ -277,6 +445,7 @@ func (wb *wrappedBody) recordBytesRead() {
 
 func (wb *wrappedBody) Close() error {
 	wb.recordBytesRead()
+	wb.capture.record(wb.span)
 	wb.span.End()
 	if wb.body != nil {
 		return wb.body.Close()
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Message events of upgraded connections, see UpgradedConnSpan.
+const (
+	messageEventName    = "message"
+	messageTypeKey      = attribute.Key("message.type")
+	messageIDKey        = attribute.Key("message.id")
+	messageSizeKey      = attribute.Key("message.uncompressed_size")
+	messageTypeSent     = "SENT"
+	messageTypeReceived = "RECEIVED"
+)
+
+// upgradeProtocol returns the lowercase name of the protocol r asks to
+// upgrade the connection to, e.g. "websocket", or an empty string if r is
+// not an upgrade request.
+func upgradeProtocol(r *http.Request) string {
+	if !headerHasToken(r.Header, "Connection", "upgrade") {
+		return ""
+	}
+	protocol, _, _ := strings.Cut(r.Header.Get("Upgrade"), ",")
+	protocol, _, _ = strings.Cut(strings.TrimSpace(protocol), "/")
+	return strings.ToLower(protocol)
+}
+
+// headerHasToken reports whether the comma-separated values of the header
+// name of h contain token, ignoring case.
+func headerHasToken(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpgradedConnSpan is a span covering the lifetime of a connection upgraded
+// from HTTP, e.g. a WebSocket, recording the messages exchanged on it as
+// events.
+type UpgradedConnSpan struct {
+	span       trace.Span
+	sentID     atomic.Int64
+	receivedID atomic.Int64
+}
+
+// StartUpgradedConnSpan starts a span covering the lifetime of the
+// connection upgraded by r, a request handled by a Handler, and returns it
+// together with a context holding it. The span is a child of the span of
+// the request in ctx, which ends with the upgrade and is annotated with the
+// http.upgrade attribute. It is named after the protocol the connection was
+// upgraded to, e.g. "websocket connection".
+//
+// The handler of the request calls StartUpgradedConnSpan after upgrading
+// the connection, e.g. with github.com/gorilla/websocket:
+//
+//	conn, err := upgrader.Upgrade(w, r, nil)
+//	if err != nil {
+//		return
+//	}
+//	_, span := otelhttp.StartUpgradedConnSpan(r.Context(), r)
+//	for {
+//		_, msg, err := conn.ReadMessage()
+//		if err != nil {
+//			span.End(err)
+//			return
+//		}
+//		span.MessageReceived(len(msg))
+//	}
+func StartUpgradedConnSpan(ctx context.Context, r *http.Request, opts ...trace.SpanStartOption) (context.Context, *UpgradedConnSpan) {
+	protocol := upgradeProtocol(r)
+	if protocol == "" {
+		protocol = "upgraded"
+	}
+
+	opts = append([]trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.NetworkProtocolName(protocol)),
+	}, opts...)
+	tracer := newTracer(trace.SpanFromContext(ctx).TracerProvider())
+	ctx, span := tracer.Start(ctx, protocol+" connection", opts...)
+	return ctx, &UpgradedConnSpan{span: span}
+}
+
+// Span returns the span of the connection.
+func (s *UpgradedConnSpan) Span() trace.Span {
+	return s.span
+}
+
+// MessageSent records a message of size bytes sent on the connection as a
+// SENT message event. Additional attributes describing the message, e.g.
+// its type, are added to the event.
+func (s *UpgradedConnSpan) MessageSent(size int, attrs ...attribute.KeyValue) {
+	s.message(messageTypeSent, s.sentID.Add(1), size, attrs)
+}
+
+// MessageReceived records a message of size bytes received on the
+// connection as a RECEIVED message event. Additional attributes describing
+// the message, e.g. its type, are added to the event.
+func (s *UpgradedConnSpan) MessageReceived(size int, attrs ...attribute.KeyValue) {
+	s.message(messageTypeReceived, s.receivedID.Add(1), size, attrs)
+}
+
+func (s *UpgradedConnSpan) message(typ string, id int64, size int, attrs []attribute.KeyValue) {
+	attrs = append([]attribute.KeyValue{
+		messageTypeKey.String(typ),
+		messageIDKey.Int64(id),
+		messageSizeKey.Int(size),
+	}, attrs...)
+	s.span.AddEvent(messageEventName, trace.WithAttributes(attrs...))
+}
+
+// End ends the span of the connection. If err is not nil, it is recorded
+// and the span status is set to error.
+func (s *UpgradedConnSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
@@ -0,0 +1,163 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// uploadProgressEventName is the name of the events reporting the progress
+// of request bodies, see WithUploadProgressInterval.
+const uploadProgressEventName = "upload.progress"
+
+// serverRequestBodyThroughput is the name of the metric measuring the
+// throughput of request bodies, see WithUploadProgressInterval.
+const serverRequestBodyThroughput = "http.server.request.body.throughput"
+
+func newUploadThroughputHistogram(meter metric.Meter) metric.Float64Histogram {
+	histogram, err := meter.Float64Histogram(
+		serverRequestBodyThroughput,
+		metric.WithUnit("By/s"),
+		metric.WithDescription("Measures the throughput of HTTP request bodies, from their first to their last read."),
+	)
+	if err != nil {
+		handleErr(err)
+		histogram = noop.Float64Histogram{}
+	}
+	return histogram
+}
+
+// uploadProgress reports the progress of a request body while it is read.
+type uploadProgress struct {
+	io.ReadCloser
+
+	interval time.Duration
+	span     trace.Span
+
+	// bytes and reading are read by the reporting goroutine, reading being
+	// the number of reads blocked waiting for the client.
+	bytes   atomic.Int64
+	reading atomic.Int32
+
+	mu        sync.Mutex
+	firstRead time.Time
+	lastRead  time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+}
+
+func newUploadProgress(body io.ReadCloser, interval time.Duration, span trace.Span) *uploadProgress {
+	return &uploadProgress{ReadCloser: body, interval: interval, span: span, stop: make(chan struct{})}
+}
+
+// Read reads the body, reporting its progress from the first read until it
+// is fully read.
+func (u *uploadProgress) Read(p []byte) (int, error) {
+	u.startOnce.Do(func() {
+		u.mu.Lock()
+		u.firstRead = time.Now()
+		u.mu.Unlock()
+		go u.run()
+	})
+
+	u.reading.Add(1)
+	n, err := u.ReadCloser.Read(p)
+	u.reading.Add(-1)
+	if n > 0 {
+		u.bytes.Add(int64(n))
+		u.mu.Lock()
+		u.lastRead = time.Now()
+		u.mu.Unlock()
+	}
+	if err != nil {
+		u.finish()
+	}
+	return n, err
+}
+
+func (u *uploadProgress) run() {
+	ticker := time.NewTicker(u.interval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	var lastBytes int64
+	for {
+		select {
+		case <-u.stop:
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(last).Seconds()
+			if elapsed <= 0 {
+				continue
+			}
+			bytes := u.bytes.Load()
+			u.span.AddEvent(uploadProgressEventName, trace.WithTimestamp(now), trace.WithAttributes(
+				UploadBytesReceivedKey.Int64(bytes),
+				UploadReceiveThroughputKey.Float64(float64(bytes-lastBytes)/elapsed),
+				UploadWaitingKey.Bool(u.reading.Load() > 0),
+			))
+			last, lastBytes = now, bytes
+		}
+	}
+}
+
+// finish stops reporting the progress of the body. It is called when the
+// body is fully read and when the handler returns or panics.
+func (u *uploadProgress) finish() {
+	if u == nil {
+		return
+	}
+	u.stopOnce.Do(func() { close(u.stop) })
+}
+
+// end stops reporting the progress of the body and, if it was read, records
+// its size on the span and its throughput with the metric attributes attrs.
+func (u *uploadProgress) end(ctx context.Context, histogram metric.Float64Histogram, attrs []attribute.KeyValue) {
+	if u == nil {
+		return
+	}
+	u.finish()
+
+	u.mu.Lock()
+	firstRead, lastRead := u.firstRead, u.lastRead
+	u.mu.Unlock()
+	if firstRead.IsZero() {
+		return
+	}
+	bytes := u.bytes.Load()
+	u.span.SetAttributes(UploadBytesReceivedKey.Int64(bytes))
+	if d := lastRead.Sub(firstRead).Seconds(); bytes > 0 && d > 0 {
+		histogram.Record(ctx, float64(bytes)/d, metric.WithAttributeSet(attribute.NewSet(attrs...)))
+	}
+}
+
+// WithUploadProgressInterval configures the Handler to report the progress
+// of request bodies, e.g. file uploads, at the interval d while the handler
+// reads them. Every interval, an upload.progress event with the body bytes
+// received so far, the throughput since the previous report, and whether the
+// handler is waiting for the client to send more, is added to the span: a
+// stalled upload keeps the handler waiting, while a slow handler does not.
+//
+// When the request ends, the number of body bytes read is recorded as a span
+// attribute, and the throughput of the body, from its first to its last
+// read, is measured by the http.server.request.body.throughput metric, with
+// the attributes of the duration metric.
+//
+// Progress is not reported if d is not positive, which is the default.
+func WithUploadProgressInterval(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.UploadProgressInterval = d
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelhttp // import "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+
+import (
+	"net/http"
+	"strings"
+)
+
+// idSegment replaces the path segments identifying resources in the URL
+// templates returned by IDTemplate.
+const idSegment = "{id}"
+
+// minHexIDLen is the minimum length of hexadecimal path segments considered
+// to be identifiers by IDTemplate, so words such as "cafe" are kept.
+const minHexIDLen = 16
+
+// URLTemplateFunc returns the low-cardinality template of the URL of a
+// request sent by a Transport, e.g. "/v1/jobs/{id}", or an empty string if it
+// is not known.
+type URLTemplateFunc func(*http.Request) string
+
+// IDTemplate is a URLTemplateFunc returning the path of the URL of a request
+// in which the segments that look like identifiers, numbers, UUIDs and
+// hexadecimal strings of at least 16 digits, are replaced by "{id}", e.g.
+// "/v1/jobs/{id}" for "/v1/jobs/42".
+func IDTemplate(r *http.Request) string {
+	segments := strings.Split(r.URL.EscapedPath(), "/")
+	for i, s := range segments {
+		if isID(s) {
+			segments[i] = idSegment
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// isID reports whether the path segment s looks like an identifier.
+func isID(s string) bool {
+	if s == "" {
+		return false
+	}
+	digits := true
+	var dashes int
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+		case c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+			digits = false
+		case c == '-':
+			digits = false
+			dashes++
+		default:
+			return false
+		}
+	}
+	if digits {
+		return true
+	}
+	if dashes == 4 && len(s) == 36 {
+		// A UUID.
+		return true
+	}
+	return dashes == 0 && len(s) >= minHexIDLen
+}
+
+// WithURLTemplateFunc configures the Transport to name the spans of requests
+// after the template of their URL returned by fn, e.g. IDTemplate, as
+// "{method} {url.template}", and to record it as the url.template
+// attribute, instead of using the span name formatter. This keeps the
+// number of span names low when calling APIs with identifiers in their
+// paths.
+func WithURLTemplateFunc(fn URLTemplateFunc) Option {
+	return optionFunc(func(c *config) {
+		c.URLTemplateFunc = fn
+	})
+}